@@ -0,0 +1,77 @@
+package golog
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSignAWSRequest checks the example of the AWS Signature Version 4
+// documentation.
+func TestSignAWSRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	creds := AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signAWSRequest(req, nil, creds, "us-east-1", "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("X-Amz-Date = %q", got)
+	}
+}
+
+func TestAWSURIEscape(t *testing.T) {
+	if got := awsURIEscape("a b/c~d*"); got != "a%20b%2Fc~d%2A" {
+		t.Errorf("awsURIEscape = %q", got)
+	}
+	if got := awsCanonicalURI("/logs-2024.01.02/_bulk"); got != "/logs-2024.01.02/_bulk" {
+		t.Errorf("awsCanonicalURI = %q", got)
+	}
+}
+
+// testAWSCredentials signs the requests sent to the fake AWS services.
+func testAWSCredentials(context.Context) (AWSCredentials, error) {
+	return AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+}
+
+// clearAWSRegion hides the region from the environment for the rest of t.
+func clearAWSRegion(t *testing.T) {
+	t.Helper()
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+}
+
+// testAWSProviderErrors logs through opt, whose fake service fails every
+// call with a permanent error containing wantErr: Sync must report it after
+// a single call, as counted by calls, and both the "lost" entry and one
+// carrying tooBig bytes must be counted as dropped.
+func testAWSProviderErrors(t *testing.T, opt LoggerOption, tooBig int, wantErr string, calls func() int) {
+	t.Helper()
+	logger, err := NewLogger(opt)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("lost")
+	logger.Info("too big", String("blob", strings.Repeat("x", tooBig)))
+	if err := logger.Sync(); err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Errorf("sync error = %v", err)
+	}
+	logger.Close()
+	if n := calls(); n != 1 {
+		t.Errorf("permanent errors should not be retried: %d calls", n)
+	}
+	drops := logger.DroppedEntries()
+	if drops[DropProviderError] != 1 || drops[DropOversized] != 1 {
+		t.Errorf("drops = %v", drops)
+	}
+}
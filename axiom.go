@@ -0,0 +1,209 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                                Axiom Provider                               */
+/* -------------------------------------------------------------------------- */
+
+// AxiomConfig configures WithAxiomProvider. The embedded HTTPConfig covers
+// TLS, proxy, headers, timeout and batching; Compression defaults to gzip.
+type AxiomConfig struct {
+	HTTPConfig
+	// Token is an API token with ingest permission for the dataset, or a
+	// personal token together with OrgID (default AXIOM_TOKEN).
+	Token string
+	// OrgID is the organization of a personal token (default
+	// AXIOM_ORG_ID).
+	OrgID string
+	// Endpoint is the API or edge deployment URL (default AXIOM_URL, then
+	// https://api.axiom.co).
+	Endpoint string
+}
+
+func (c AxiomConfig) withDefaults() AxiomConfig {
+	if c.Token == "" {
+		c.Token = os.Getenv("AXIOM_TOKEN")
+	}
+	if c.OrgID == "" {
+		c.OrgID = os.Getenv("AXIOM_ORG_ID")
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = os.Getenv("AXIOM_URL")
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = "https://api.axiom.co"
+	}
+	if c.Compression == NoCompression {
+		c.Compression = GzipCompression
+	}
+	return c
+}
+
+// WithAxiomProvider ingests entries into an Axiom dataset as
+// newline-delimited JSON:
+//
+//	golog.WithAxiomProvider("app-logs", golog.AxiomConfig{
+//		Token: os.Getenv("AXIOM_TOKEN"),
+//	})
+//
+// Entries carry their time under "_time", which Axiom uses as the event
+// time, and the message under "message"; WithProviderEncoderConfig adjusts
+// the keys. Failed batches, and events Axiom reports as failed, are dropped
+// and counted as DropProviderError. Rate limited batches return a retryable
+// error, so WithRetry can resend them.
+func WithAxiomProvider(dataset string, cfg AxiomConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&axiomProvider{
+			providerBase: providerBase{name: "axiom:" + dataset, encoders: jsonEncoderOnly},
+			dataset:      dataset,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type axiomProvider struct {
+	providerBase
+	dataset string
+	cfg     AxiomConfig
+	batch   *batchWriter
+}
+
+// axiomEncoding are the event keys applied before those of
+// WithProviderEncoderConfig.
+func axiomEncoding(c *zapcore.EncoderConfig) {
+	c.TimeKey = "_time"
+	c.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	c.MessageKey = "message"
+}
+
+func (p *axiomProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	cfg := p.cfg.withDefaults()
+	if p.dataset == "" {
+		return nil, errors.New("axiom provider: dataset is required")
+	}
+	if cfg.Token == "" {
+		return nil, errors.New("axiom provider: token is required; set AxiomConfig.Token or AXIOM_TOKEN")
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("axiom provider: invalid endpoint %q", cfg.Endpoint)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/v1/datasets/" + p.dataset + "/ingest"
+	sender, err := newHTTPSender(cfg.HTTPConfig)
+	if err != nil {
+		return nil, fmt.Errorf("axiom provider: %w", err)
+	}
+	own := mergeEncoderConfig(&encoderConfigFuncs{fns: []func(*zapcore.EncoderConfig){axiomEncoding}}, p.encoderConfig.funcs())
+	enc, err := p.tel.buildEncoder(JSONEncoder, own)
+	if err != nil {
+		return nil, err
+	}
+	client := &axiomClient{endpoint: u.String(), cfg: cfg, sender: sender, drops: p.dropped, report: p.report}
+	p.batch = newBatchWriter(sender.cfg.BatchSize, sender.cfg.FlushInterval, client.ingest, p.dropped, p.report)
+	return zapcore.NewCore(enc, p.batch, level), nil
+}
+
+func (p *axiomProvider) close() error {
+	if p.batch == nil {
+		return nil
+	}
+	return p.batch.close()
+}
+
+func (p *axiomProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: JSONEncoder}
+}
+
+/* -------------------------------------------------------------------------- */
+/*                                Ingest Requests                              */
+/* -------------------------------------------------------------------------- */
+
+type axiomClient struct {
+	endpoint string
+	cfg      AxiomConfig
+	sender   *httpSender
+	drops    func(DropReason, int)
+	report   func(error)
+}
+
+// axiomIngestStatus is the body of an ingest response.
+type axiomIngestStatus struct {
+	Ingested int `json:"ingested"`
+	Failed   int `json:"failed"`
+	Failures []struct {
+		Error string `json:"error"`
+	} `json:"failures"`
+}
+
+// ingest posts a batch of events. Events Axiom fails are counted and
+// reported here, so only failures of the whole batch are returned.
+func (c *axiomClient) ingest(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.sender.cfg.Timeout)
+	defer cancel()
+
+	cfg := c.sender.cfg
+	payload, err := cfg.Compression.compress(body)
+	if err != nil {
+		return PermanentError(fmt.Errorf("axiom: %w", err))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return PermanentError(fmt.Errorf("axiom: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if cfg.Compression != NoCompression {
+		req.Header.Set("Content-Encoding", string(cfg.Compression))
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	if c.cfg.OrgID != "" {
+		req.Header.Set("X-Axiom-Org-Id", c.cfg.OrgID)
+	}
+
+	resp, err := c.sender.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("axiom: %w", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 != 2 {
+		var result struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(data, &result)
+		if result.Message == "" {
+			result.Message = string(bytes.TrimSpace(data))
+		}
+		err := fmt.Errorf("axiom: %s: %s", resp.Status, result.Message)
+		if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return PermanentError(err)
+		}
+		return err
+	}
+	var status axiomIngestStatus
+	if json.Unmarshal(data, &status) == nil && status.Failed > 0 {
+		reason := "unknown error"
+		if len(status.Failures) > 0 {
+			reason = status.Failures[0].Error
+		}
+		c.drops(DropProviderError, status.Failed)
+		c.report(fmt.Errorf("axiom: %d of %d events failed: %s", status.Failed, status.Failed+status.Ingested, reason))
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package golog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeAxiom answers ingest requests, failing the events whose message is
+// "bad", or the whole request with status when set.
+type fakeAxiom struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	events   []map[string]interface{}
+	status   int
+}
+
+func (f *fakeAxiom) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r)
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		io.WriteString(w, `{"code":403,"message":"forbidden"}`)
+		return
+	}
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	ingested, failed := 0, 0
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		var event map[string]interface{}
+		json.Unmarshal(scanner.Bytes(), &event)
+		if event["message"] == "bad" {
+			failed++
+			continue
+		}
+		ingested++
+		f.events = append(f.events, event)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ingested": ingested, "failed": failed,
+		"failures": []map[string]string{{"error": "invalid field"}},
+	})
+}
+
+func TestAxiomProvider_Ingest(t *testing.T) {
+	fake := &fakeAxiom{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	at := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	logger, err := NewLogger(
+		WithAxiomProvider("app logs", AxiomConfig{
+			HTTPConfig: HTTPConfig{BatchSize: 3, FlushInterval: time.Hour},
+			Token:      "xaat-123",
+			OrgID:      "acme",
+			Endpoint:   srv.URL + "/",
+		}),
+		WithZapOptions(zap.WithClock(fixedClock{at})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("hello", Int("status", 200))
+	logger.Info("bad")
+	logger.Warn("third")
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(fake.requests))
+	}
+	req := fake.requests[0]
+	for header, want := range map[string]string{
+		"Authorization":    "Bearer xaat-123",
+		"X-Axiom-Org-Id":   "acme",
+		"Content-Type":     "application/x-ndjson",
+		"Content-Encoding": "gzip",
+	} {
+		if got := req.Header.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+	if req.URL.EscapedPath() != "/v1/datasets/app%20logs/ingest" {
+		t.Errorf("path = %s", req.URL.EscapedPath())
+	}
+	if len(fake.events) != 2 {
+		t.Fatalf("got %d events, want 2", len(fake.events))
+	}
+	if e := fake.events[0]; e["_time"] != "2026-10-16T09:30:00Z" || e["message"] != "hello" || e["status"] != float64(200) {
+		t.Errorf("event = %v", e)
+	}
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+}
+
+func TestAxiomProvider_Errors(t *testing.T) {
+	fake := &fakeAxiom{status: http.StatusForbidden}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithAxiomProvider("logs", AxiomConfig{Token: "t", Endpoint: srv.URL}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("lost")
+	if err := logger.Sync(); err == nil || !strings.Contains(err.Error(), "forbidden") || isRetryable(err) {
+		t.Errorf("sync error = %v", err)
+	}
+	logger.Close()
+
+	t.Setenv("AXIOM_TOKEN", "")
+	if _, err := NewLogger(WithAxiomProvider("logs", AxiomConfig{})); err == nil || !strings.Contains(err.Error(), "token") {
+		t.Errorf("expected a token error, got %v", err)
+	}
+	if _, err := NewLogger(WithAxiomProvider("logs", AxiomConfig{Token: "t"}, WithProviderEncoder(ConsoleEncoder))); err == nil {
+		t.Error("expected an error for a console encoder")
+	}
+}
@@ -0,0 +1,154 @@
+package golog
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                             Generic Item Batching                           */
+/* -------------------------------------------------------------------------- */
+
+// batcher collects items and hands them to send in batches of at most limit
+// items and, if maxBytes is set, maxBytes bytes as counted by measure. A
+// full batch is sent from add and pending items on Sync, so errors reach the
+// caller; the flush loop sends partial batches and passes failures to report
+// instead.
+//
+// Sends run outside the lock add takes, so logging goroutines are not held
+// up by a batch in flight; sendMu keeps the batches in order and send
+// serialised.
+//
+// Items measure rejects are counted as DropOversized. Failed batches are
+// counted as DropProviderError: all of their items, unless send returns a
+// *batchDropError with the number actually lost.
+//
+// The fields are set before start and not changed afterwards.
+type batcher[T any] struct {
+	limit    int
+	maxBytes int
+	// measure returns an item's size towards maxBytes, or an error if it
+	// can never be sent. Nil counts every item as zero bytes.
+	measure func(T) (int, error)
+	send    func([]T) error
+	drops   func(DropReason, int)
+	report  func(error)
+
+	mu    sync.Mutex
+	items []T
+	size  int
+	// full holds the batches completed by add, oldest first.
+	full [][]T
+
+	sendMu sync.Mutex
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// start runs the loop flushing partial batches every interval.
+func (b *batcher[T]) start(interval time.Duration) {
+	b.done = make(chan struct{})
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := b.Sync(); err != nil {
+					b.report(err)
+				}
+			case <-b.done:
+				return
+			}
+		}
+	}()
+}
+
+func (b *batcher[T]) add(item T) error {
+	size := 0
+	if b.measure != nil {
+		var err error
+		if size, err = b.measure(item); err != nil {
+			b.drops(DropOversized, 1)
+			return err
+		}
+	}
+	b.mu.Lock()
+	if len(b.items) > 0 && b.maxBytes > 0 && b.size+size > b.maxBytes {
+		b.cutLocked()
+	}
+	b.items = append(b.items, item)
+	b.size += size
+	if len(b.items) >= b.limit {
+		b.cutLocked()
+	}
+	full := len(b.full) > 0
+	b.mu.Unlock()
+	if !full {
+		return nil
+	}
+	return b.flush(false)
+}
+
+func (b *batcher[T]) Sync() error { return b.flush(true) }
+
+// cutLocked completes the pending batch.
+func (b *batcher[T]) cutLocked() {
+	if len(b.items) == 0 {
+		return
+	}
+	b.full = append(b.full, b.items)
+	b.items, b.size = nil, 0
+}
+
+// flush sends the completed batches and, if partial is set, the pending
+// one. Batches are taken only once sendMu is held, so they go out in the
+// order they were completed.
+func (b *batcher[T]) flush(partial bool) error {
+	b.sendMu.Lock()
+	defer b.sendMu.Unlock()
+	b.mu.Lock()
+	if partial {
+		b.cutLocked()
+	}
+	batches := b.full
+	b.full = nil
+	b.mu.Unlock()
+
+	var errs []error
+	for _, items := range batches {
+		if err := b.send(items); err != nil {
+			lost := len(items)
+			var dropErr *batchDropError
+			if errors.As(err, &dropErr) {
+				lost = dropErr.n
+			}
+			b.drops(DropProviderError, lost)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// close stops the flush loop and sends whatever is left. It must be called
+// once.
+func (b *batcher[T]) close() error {
+	close(b.done)
+	b.wg.Wait()
+	return b.Sync()
+}
+
+// batchDropError reports how many items a failed send lost, which differs
+// from the batch size when some of them were delivered or one item carries
+// several entries.
+type batchDropError struct {
+	n   int
+	err error
+}
+
+func (e *batchDropError) Error() string { return e.err.Error() }
+func (e *batchDropError) Unwrap() error { return e.err }
@@ -0,0 +1,63 @@
+package golog
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatcher_SendsOutsideLock(t *testing.T) {
+	release := make(chan struct{})
+	var (
+		mu      sync.Mutex
+		batches [][]int
+	)
+	b := &batcher[int]{
+		limit: 2,
+		send: func(items []int) error {
+			<-release
+			mu.Lock()
+			batches = append(batches, items)
+			mu.Unlock()
+			return nil
+		},
+		drops:  func(DropReason, int) {},
+		report: func(error) {},
+	}
+	b.start(time.Hour)
+
+	b.add(1)
+	sent := make(chan error)
+	go func() { sent <- b.add(2) }()
+	// Wait for the full batch to be handed to send.
+	for {
+		b.mu.Lock()
+		taken := len(b.full) == 0 && len(b.items) == 0
+		b.mu.Unlock()
+		if taken {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	added := make(chan struct{})
+	go func() {
+		b.add(3)
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(time.Second):
+		t.Fatal("add blocked while a batch was in flight")
+	}
+	close(release)
+	if err := <-sent; err != nil {
+		t.Fatal(err)
+	}
+	if err := b.close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 || len(batches[0]) != 2 || batches[1][0] != 3 {
+		t.Errorf("batches = %v", batches)
+	}
+}
@@ -0,0 +1,574 @@
+package golog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/api/option"
+	gtransport "google.golang.org/api/transport/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                      BigQuery Storage Write API Provider                    */
+/* -------------------------------------------------------------------------- */
+
+const (
+	bigQueryEndpoint   = "bigquerystorage.googleapis.com:443"
+	bigQueryAppendRows = "/google.cloud.bigquery.storage.v1.BigQueryWrite/AppendRows"
+	// bigQueryAttempts bounds the appends of one batch on transient errors.
+	bigQueryAttempts = 4
+)
+
+// BigQueryType is the type of a BigQuery column written by golog.
+type BigQueryType string
+
+// The column types a field can be mapped to.
+const (
+	BigQueryString    BigQueryType = "STRING"
+	BigQueryInt64     BigQueryType = "INT64"
+	BigQueryFloat64   BigQueryType = "FLOAT64"
+	BigQueryBool      BigQueryType = "BOOL"
+	BigQueryTimestamp BigQueryType = "TIMESTAMP"
+	BigQueryJSON      BigQueryType = "JSON"
+)
+
+// BigQueryColumn copies a field into a column of its own.
+type BigQueryColumn struct {
+	// Field is the field key, with dots for nested objects ("http.status").
+	Field string
+	// Column defaults to Field with dots replaced by underscores.
+	Column string
+	// Type defaults to BigQueryString. Values that do not convert, e.g. a
+	// string in an INT64 column, leave the column NULL.
+	Type BigQueryType
+}
+
+// BigQueryConfig configures WithBigQueryProvider. Zero values fall back to
+// the defaults noted on each field.
+type BigQueryConfig struct {
+	// Columns maps fields to columns of the table.
+	Columns []BigQueryColumn
+	// FieldsColumn is the JSON column holding all fields (default
+	// "fields"); "-" leaves it out.
+	FieldsColumn string
+	// BatchSize is the number of rows per append (default 500);
+	// FlushInterval bounds how long a row waits for its batch (default 1s).
+	BatchSize     int
+	FlushInterval time.Duration
+	// Timeout bounds one append (default 30s).
+	Timeout time.Duration
+	// ClientOptions are passed to the gRPC transport after golog's own,
+	// e.g. option.WithCredentialsFile.
+	ClientOptions []option.ClientOption
+}
+
+func (c BigQueryConfig) withDefaults() BigQueryConfig {
+	if c.FieldsColumn == "" {
+		c.FieldsColumn = "fields"
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 500
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 30 * time.Second
+	}
+	return c
+}
+
+// WithBigQueryProvider streams entries into a BigQuery table through the
+// Storage Write API's default stream, authenticating with Application
+// Default Credentials unless ClientOptions say otherwise. The table needs
+// these columns, plus one per BigQueryColumn:
+//
+//	CREATE TABLE logs.entries (
+//	  timestamp TIMESTAMP, level STRING, logger STRING, message STRING,
+//	  caller STRING, stack STRING, fields JSON
+//	) PARTITION BY DATE(timestamp);
+//
+// Rows are appended in batches; a batch that fails after retrying
+// transient errors is counted as DropProviderError.
+func WithBigQueryProvider(projectID, dataset, table string, cfg BigQueryConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		path := fmt.Sprintf("projects/%s/datasets/%s/tables/%s", projectID, dataset, table)
+		c.providers = append(c.providers, applyProviderOptions(&bigQueryProvider{
+			providerBase: providerBase{name: "bigquery:" + path},
+			table:        path,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type bigQueryProvider struct {
+	providerBase
+	table string
+	cfg   BigQueryConfig
+
+	client *bigQueryClient
+	batch  *batchWriter
+}
+
+func (p *bigQueryProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	cfg := p.cfg.withDefaults()
+	columns, err := bigQueryColumns(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("bigquery provider: %w", err)
+	}
+	schema, err := bigQuerySchema(columns)
+	if err != nil {
+		return nil, fmt.Errorf("bigquery provider: %w", err)
+	}
+	opts := append([]option.ClientOption{
+		option.WithEndpoint(bigQueryEndpoint),
+		option.WithScopes("https://www.googleapis.com/auth/bigquery.insertdata"),
+	}, cfg.ClientOptions...)
+	conn, err := gtransport.Dial(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("bigquery provider: %w", err)
+	}
+	p.client = &bigQueryClient{conn: conn, writeStream: p.table + "/streams/_default", schema: schema, timeout: cfg.Timeout}
+	p.batch = newBatchWriter(cfg.BatchSize, cfg.FlushInterval, p.client.append, p.dropped, p.report)
+	return &bigQueryCore{LevelEnabler: level, tree: newTreeEncoder(), columns: columns, out: p.batch}, nil
+}
+
+func (p *bigQueryProvider) close() error {
+	if p.batch == nil {
+		return nil
+	}
+	return errors.Join(p.batch.close(), p.client.close())
+}
+
+func (p *bigQueryProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name}
+}
+
+/* -------------------------------------------------------------------------- */
+/*                                Rows & Schema                               */
+/* -------------------------------------------------------------------------- */
+
+// bigQueryColumn is a column of the row message, numbered from 1 in order.
+type bigQueryColumn struct {
+	name string
+	typ  BigQueryType
+	// value returns the column's value for an entry, nil for NULL.
+	value func(ent zapcore.Entry, tree *fieldTree) interface{}
+}
+
+func bigQueryColumns(cfg BigQueryConfig) ([]bigQueryColumn, error) {
+	columns := []bigQueryColumn{
+		{"timestamp", BigQueryTimestamp, func(ent zapcore.Entry, _ *fieldTree) interface{} { return ent.Time }},
+		{"level", BigQueryString, func(ent zapcore.Entry, _ *fieldTree) interface{} { return zapLevelName(ent.Level) }},
+		{"logger", BigQueryString, func(ent zapcore.Entry, _ *fieldTree) interface{} { return nullString(ent.LoggerName) }},
+		{"message", BigQueryString, func(ent zapcore.Entry, _ *fieldTree) interface{} { return ent.Message }},
+		{"caller", BigQueryString, func(ent zapcore.Entry, _ *fieldTree) interface{} {
+			if !ent.Caller.Defined {
+				return nil
+			}
+			return ent.Caller.TrimmedPath()
+		}},
+		{"stack", BigQueryString, func(ent zapcore.Entry, _ *fieldTree) interface{} { return nullString(ent.Stack) }},
+	}
+	if cfg.FieldsColumn != "-" {
+		columns = append(columns, bigQueryColumn{cfg.FieldsColumn, BigQueryJSON, func(_ zapcore.Entry, tree *fieldTree) interface{} {
+			if len(tree.keys) == 0 {
+				return nil
+			}
+			return tree
+		}})
+	}
+	seen := map[string]bool{}
+	for _, col := range columns {
+		seen[col.name] = true
+	}
+	for _, c := range cfg.Columns {
+		if c.Column == "" {
+			c.Column = strings.ReplaceAll(c.Field, ".", "_")
+		}
+		if c.Type == "" {
+			c.Type = BigQueryString
+		}
+		name := strings.ToLower(c.Column)
+		if !validBigQueryColumn(name) {
+			return nil, fmt.Errorf("invalid column name %q", c.Column)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column %q", c.Column)
+		}
+		seen[name] = true
+		if _, ok := bigQueryProtoTypes[c.Type]; !ok {
+			return nil, fmt.Errorf("column %q: unknown type %q", c.Column, c.Type)
+		}
+		field := c.Field
+		columns = append(columns, bigQueryColumn{name, c.Type, func(_ zapcore.Entry, tree *fieldTree) interface{} {
+			v, _ := treeLookup(tree, field)
+			return v
+		}})
+	}
+	return columns, nil
+}
+
+// nullString leaves a column NULL rather than empty.
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func validBigQueryColumn(name string) bool {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+var bigQueryProtoTypes = map[BigQueryType]descriptorpb.FieldDescriptorProto_Type{
+	BigQueryString:    descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	BigQueryJSON:      descriptorpb.FieldDescriptorProto_TYPE_STRING,
+	BigQueryInt64:     descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	BigQueryTimestamp: descriptorpb.FieldDescriptorProto_TYPE_INT64,
+	BigQueryFloat64:   descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+	BigQueryBool:      descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+}
+
+// bigQuerySchema returns the DescriptorProto of the row message, which the
+// service matches to the table's columns by name.
+func bigQuerySchema(columns []bigQueryColumn) ([]byte, error) {
+	desc := &descriptorpb.DescriptorProto{Name: proto.String("Entry")}
+	for i, col := range columns {
+		desc.Field = append(desc.Field, &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(col.name),
+			Number: proto.Int32(int32(i + 1)),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   bigQueryProtoTypes[col.typ].Enum(),
+		})
+	}
+	return proto.Marshal(desc)
+}
+
+// treeLookup finds the value at a dotted path, preferring a key that
+// contains the dots itself.
+func treeLookup(tree *fieldTree, path string) (interface{}, bool) {
+	var found interface{}
+	ok := false
+	for i, k := range tree.keys {
+		if k == path {
+			found, ok = tree.vals[i], true
+		} else if sub, isTree := tree.vals[i].(*fieldTree); isTree && strings.HasPrefix(path, k+".") {
+			if v, subOK := treeLookup(sub, path[len(k)+1:]); subOK {
+				found, ok = v, true
+			}
+		}
+	}
+	return found, ok
+}
+
+type bigQueryCore struct {
+	zapcore.LevelEnabler
+	tree    *treeEncoder
+	columns []bigQueryColumn
+	out     *batchWriter
+}
+
+func (c *bigQueryCore) With(fields []zapcore.Field) zapcore.Core {
+	tree := c.tree.cloneTree()
+	for _, f := range fields {
+		f.AddTo(tree)
+	}
+	return &bigQueryCore{LevelEnabler: c.LevelEnabler, tree: tree, columns: c.columns, out: c.out}
+}
+
+func (c *bigQueryCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write adds the row as one serialized_rows element of a ProtoRows message,
+// so that a batch is the concatenation of its rows.
+func (c *bigQueryCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	tree := c.tree.fieldsWith(fields)
+	var row []byte
+	for i, col := range c.columns {
+		row = appendBigQueryValue(row, protowire.Number(i+1), col.typ, col.value(ent, tree))
+	}
+	_, err := c.out.Write(appendProtoMessage(nil, 1, row))
+	return err
+}
+
+func (c *bigQueryCore) Sync() error { return c.out.Sync() }
+
+// appendBigQueryValue encodes v for a column of type typ, leaving the
+// column NULL when v is nil or does not convert.
+func appendBigQueryValue(b []byte, num protowire.Number, typ BigQueryType, v interface{}) []byte {
+	if v == nil {
+		return b
+	}
+	switch typ {
+	case BigQueryString:
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		return protowire.AppendString(b, columnText(v))
+	case BigQueryJSON:
+		buf := encoderPool.Get()
+		defer buf.Free()
+		appendTreeJSON(buf, v)
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		return protowire.AppendBytes(b, buf.Bytes())
+	case BigQueryTimestamp:
+		t, ok := v.(time.Time)
+		if !ok || t.IsZero() {
+			return b
+		}
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		return protowire.AppendVarint(b, uint64(t.UnixMicro()))
+	case BigQueryInt64:
+		n, ok := bigQueryInt(v)
+		if !ok {
+			return b
+		}
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		return protowire.AppendVarint(b, uint64(n))
+	case BigQueryFloat64:
+		f, ok := bigQueryFloat(v)
+		if !ok {
+			return b
+		}
+		b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(f))
+	case BigQueryBool:
+		x, ok := v.(bool)
+		if s, isString := v.(string); isString {
+			var err error
+			x, err = strconv.ParseBool(s)
+			ok = err == nil
+		}
+		if !ok {
+			return b
+		}
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(x))
+	}
+	return b
+}
+
+func bigQueryInt(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case float32:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	case time.Duration:
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+func bigQueryFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case time.Duration:
+		return v.Seconds(), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+/* -------------------------------------------------------------------------- */
+/*                              AppendRows Stream                             */
+/* -------------------------------------------------------------------------- */
+
+// bigQueryClient appends batches on one bidirectional AppendRows stream,
+// waiting for each response before the next append. It is only used from
+// the batchWriter, which serialises calls.
+type bigQueryClient struct {
+	conn        *grpc.ClientConn
+	writeStream string
+	schema      []byte
+	timeout     time.Duration
+
+	stream grpc.ClientStream
+	cancel context.CancelFunc
+}
+
+// append sends rows, a serialized ProtoRows message, retrying transient
+// errors with backoff.
+func (c *bigQueryClient) append(rows []byte) error {
+	// Every request names the stream and schema, so a request on a new
+	// stream needs nothing from earlier ones.
+	req := appendProtoString(nil, 1, c.writeStream)
+	data := appendProtoMessage(nil, 1, appendProtoMessage(nil, 1, c.schema))
+	data = appendProtoMessage(data, 2, rows)
+	req = appendProtoMessage(req, 4, data)
+
+	backoff := 100 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err := c.appendOnce(req)
+		if err == nil || !retryableGRPCStatus(err) || attempt == bigQueryAttempts {
+			if err != nil {
+				return fmt.Errorf("bigquery: %w", err)
+			}
+			return nil
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, grpcMaxBackoff)
+	}
+}
+
+func (c *bigQueryClient) appendOnce(req []byte) error {
+	if c.stream == nil {
+		ctx := metadata.AppendToOutgoingContext(context.Background(),
+			"x-goog-request-params", "write_stream="+url.QueryEscape(c.writeStream))
+		ctx, cancel := context.WithCancel(ctx)
+		stream, err := c.conn.NewStream(ctx, &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}, bigQueryAppendRows, grpc.ForceCodec(rawCodec{}))
+		if err != nil {
+			cancel()
+			return err
+		}
+		c.stream, c.cancel = stream, cancel
+	}
+	// A stuck append ends the stream; the next one opens a new stream.
+	timer := time.AfterFunc(c.timeout, c.cancel)
+	defer timer.Stop()
+	err := c.stream.SendMsg(req)
+	var resp []byte
+	if err == nil || errors.Is(err, io.EOF) {
+		// After io.EOF the stream's status comes with RecvMsg.
+		err = c.stream.RecvMsg(&resp)
+	}
+	if err != nil {
+		c.cancel()
+		c.stream = nil
+		return err
+	}
+	return bigQueryResponseError(resp)
+}
+
+// bigQueryResponseError returns the error of an AppendRowsResponse, if any.
+// Row errors reject the whole batch.
+func bigQueryResponseError(resp []byte) error {
+	var failed error
+	var rowErrors []string
+	err := consumeProtoFields(resp, func(num protowire.Number, _ uint64, msg []byte) {
+		switch num {
+		case 2: // error, a google.rpc.Status
+			var code uint64
+			var message string
+			consumeProtoFields(msg, func(num protowire.Number, v uint64, b []byte) {
+				switch num {
+				case 1:
+					code = v
+				case 2:
+					message = string(b)
+				}
+			})
+			failed = status.Error(codes.Code(code), message)
+		case 4: // row_errors
+			var index uint64
+			var message string
+			consumeProtoFields(msg, func(num protowire.Number, v uint64, b []byte) {
+				switch num {
+				case 1:
+					index = v
+				case 3:
+					message = string(b)
+				}
+			})
+			rowErrors = append(rowErrors, fmt.Sprintf("row %d: %s", index, message))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(rowErrors) > 0 {
+		return fmt.Errorf("rows rejected: %s", strings.Join(rowErrors, "; "))
+	}
+	return failed
+}
+
+// consumeProtoFields calls fn with the value of each varint field and the
+// bytes of each length-delimited field of msg, skipping other types.
+func consumeProtoFields(msg []byte, fn func(num protowire.Number, v uint64, b []byte)) error {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+		switch typ {
+		case protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(msg)
+			if n >= 0 {
+				fn(num, v, nil)
+			}
+		case protowire.BytesType:
+			var b []byte
+			b, n = protowire.ConsumeBytes(msg)
+			if n >= 0 {
+				fn(num, 0, b)
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, msg)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+	}
+	return nil
+}
+
+func (c *bigQueryClient) close() error {
+	if c.stream != nil {
+		c.stream.CloseSend()
+		c.cancel()
+		c.stream = nil
+	}
+	return c.conn.Close()
+}
@@ -0,0 +1,209 @@
+package golog
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// fakeBigQueryWrite implements BigQueryWrite/AppendRows, decoding the rows
+// with the schema sent along.
+type fakeBigQueryWrite struct {
+	mu      sync.Mutex
+	streams []string
+	params  []string
+	rows    []*dynamicpb.Message
+	// responses are sent, in order, instead of a success.
+	responses [][]byte
+}
+
+func (f *fakeBigQueryWrite) appendRows(_ interface{}, stream grpc.ServerStream) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	for {
+		var req []byte
+		if err := stream.RecvMsg(&req); err != nil {
+			return nil
+		}
+		var writeStream string
+		var schema, rows []byte
+		consumeProtoFields(req, func(num protowire.Number, _ uint64, b []byte) {
+			switch num {
+			case 1:
+				writeStream = string(b)
+			case 4:
+				consumeProtoFields(b, func(num protowire.Number, _ uint64, b []byte) {
+					switch num {
+					case 1:
+						consumeProtoFields(b, func(_ protowire.Number, _ uint64, b []byte) { schema = b })
+					case 2:
+						rows = b
+					}
+				})
+			}
+		})
+		f.mu.Lock()
+		f.streams = append(f.streams, writeStream)
+		f.params = append(f.params, md.Get("x-goog-request-params")...)
+		resp := appendProtoMessage(nil, 1, nil)
+		if len(f.responses) > 0 {
+			resp, f.responses = f.responses[0], f.responses[1:]
+		} else {
+			desc := &descriptorpb.DescriptorProto{}
+			if err := proto.Unmarshal(schema, desc); err != nil {
+				f.mu.Unlock()
+				return err
+			}
+			file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+				Name: proto.String("entry.proto"), MessageType: []*descriptorpb.DescriptorProto{desc},
+			}, nil)
+			if err != nil {
+				f.mu.Unlock()
+				return err
+			}
+			consumeProtoFields(rows, func(_ protowire.Number, _ uint64, b []byte) {
+				msg := dynamicpb.NewMessage(file.Messages().Get(0))
+				if proto.Unmarshal(b, msg) == nil {
+					f.rows = append(f.rows, msg)
+				}
+			})
+		}
+		f.mu.Unlock()
+		if err := stream.SendMsg(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func startFakeBigQuery(t *testing.T) (*fakeBigQueryWrite, []option.ClientOption) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeBigQueryWrite{}
+	srv := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}))
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "google.cloud.bigquery.storage.v1.BigQueryWrite",
+		HandlerType: (*interface{})(nil),
+		Streams:     []grpc.StreamDesc{{StreamName: "AppendRows", Handler: fake.appendRows, ClientStreams: true, ServerStreams: true}},
+	}, struct{}{})
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+	return fake, []option.ClientOption{
+		option.WithEndpoint(ln.Addr().String()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	}
+}
+
+func rowValue(msg *dynamicpb.Message, name string) interface{} {
+	fd := msg.Descriptor().Fields().ByName(protoreflect.Name(name))
+	if fd == nil || !msg.Has(fd) {
+		return nil
+	}
+	return msg.Get(fd).Interface()
+}
+
+func TestBigQueryProvider_Rows(t *testing.T) {
+	fake, opts := startFakeBigQuery(t)
+	at := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	logger, err := NewLogger(
+		WithBigQueryProvider("proj", "logs", "entries", BigQueryConfig{
+			Columns: []BigQueryColumn{
+				{Field: "http.status", Type: BigQueryInt64},
+				{Field: "latency", Column: "latency_s", Type: BigQueryFloat64},
+				{Field: "user", Type: BigQueryJSON},
+				{Field: "cached", Type: BigQueryBool},
+			},
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+			ClientOptions: opts,
+		}),
+		WithZapOptions(zap.WithClock(fixedClock{at})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Named("api").Info("request",
+		Dict("http", Int("status", 200)),
+		Duration("latency", 1500*time.Millisecond),
+		Dict("user", String("id", "u1")),
+		String("cached", "true"),
+	)
+	logger.Warn("bare")
+	logger.Error("third")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.streams) != 2 || fake.streams[0] != "projects/proj/datasets/logs/tables/entries/streams/_default" {
+		t.Errorf("write streams = %v", fake.streams)
+	}
+	if len(fake.params) == 0 || fake.params[0] != "write_stream=projects%2Fproj%2Fdatasets%2Flogs%2Ftables%2Fentries%2Fstreams%2F_default" {
+		t.Errorf("request params = %v", fake.params)
+	}
+	if len(fake.rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(fake.rows))
+	}
+	row := fake.rows[0]
+	for name, want := range map[string]interface{}{
+		"timestamp": at.UnixMicro(), "level": "info", "logger": "api", "message": "request",
+		"http_status": int64(200), "latency_s": 1.5, "user": `{"id":"u1"}`, "cached": true,
+		"fields": `{"http":{"status":200},"latency":"1.5s","user":{"id":"u1"},"cached":"true"}`,
+	} {
+		if got := rowValue(row, name); got != want {
+			t.Errorf("%s = %v (%T), want %v", name, got, got, want)
+		}
+	}
+	if got := rowValue(fake.rows[1], "fields"); got != nil {
+		t.Errorf("fields of a bare entry = %v, want NULL", got)
+	}
+	if got := rowValue(fake.rows[1], "stack"); got != nil {
+		t.Errorf("stack = %v, want NULL", got)
+	}
+}
+
+func TestBigQueryProvider_Errors(t *testing.T) {
+	fake, opts := startFakeBigQuery(t)
+	unavailable := appendProtoMessage(nil, 2, protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), 14))
+	rowError := appendProtoMessage(nil, 4, appendProtoString(protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), 0), 3, "bad value"))
+	fake.responses = [][]byte{unavailable, rowError}
+
+	logger, err := NewLogger(WithBigQueryProvider("p", "d", "t", BigQueryConfig{BatchSize: 1, ClientOptions: opts}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("retried, then rejected")
+	logger.Info("appended")
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.streams) != 3 || len(fake.rows) != 1 {
+		t.Errorf("appends = %d, rows = %d", len(fake.streams), len(fake.rows))
+	}
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+
+	for _, col := range []BigQueryColumn{{Field: "a-b"}, {Field: "level"}, {Field: "x", Type: "GEOGRAPHY"}} {
+		if _, err := NewLogger(WithBigQueryProvider("p", "d", "t", BigQueryConfig{Columns: []BigQueryColumn{col}, ClientOptions: opts})); err == nil {
+			t.Errorf("%+v: expected an error", col)
+		}
+	}
+}
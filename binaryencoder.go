@@ -0,0 +1,355 @@
+package golog
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                    Binary Encoders: MessagePack & Protobuf                  */
+/* -------------------------------------------------------------------------- */
+
+const (
+	// MsgpackEncoder writes each entry as a MessagePack map with the keys of
+	// the JSON encoder (level, ts, logger, caller, msg, stacktrace) followed
+	// by the fields. ts uses the timestamp extension type and durations are
+	// integer nanoseconds. Entries are self-delimiting, so a stream of them
+	// needs no framing.
+	MsgpackEncoder EncoderType = "msgpack"
+	// ProtobufEncoder writes each entry as a golog.v1.Entry message (see
+	// entry.proto) prefixed with its varint length, the framing read by
+	// protodelim.UnmarshalFrom.
+	ProtobufEncoder EncoderType = "protobuf"
+)
+
+// binaryEncoder renders entries with one of the binary formats.
+type binaryEncoder struct {
+	*treeEncoder
+	protobuf bool
+}
+
+func (e *binaryEncoder) Clone() zapcore.Encoder {
+	return &binaryEncoder{treeEncoder: e.cloneTree(), protobuf: e.protobuf}
+}
+
+func (e *binaryEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	tree := e.fieldsWith(fields)
+	var b []byte
+	if e.protobuf {
+		msg := appendProtoEntry(nil, ent, tree)
+		b = protowire.AppendVarint(make([]byte, 0, len(msg)+binary.MaxVarintLen64), uint64(len(msg)))
+		b = append(b, msg...)
+	} else {
+		b = appendMsgpackEntry(nil, ent, tree, true)
+	}
+	buf := encoderPool.Get()
+	_, _ = buf.Write(b)
+	return buf, nil
+}
+
+/* ------------------------------- MessagePack ------------------------------ */
+
+// appendMsgpackEntry writes the entry as a map, without the ts key unless
+// withTime is set.
+func appendMsgpackEntry(b []byte, ent zapcore.Entry, tree *fieldTree, withTime bool) []byte {
+	type kv struct {
+		key string
+		val interface{}
+	}
+	meta := []kv{{"level", zapLevelName(ent.Level)}}
+	if withTime {
+		meta = append(meta, kv{"ts", ent.Time})
+	}
+	if ent.LoggerName != "" {
+		meta = append(meta, kv{"logger", ent.LoggerName})
+	}
+	if ent.Caller.Defined {
+		meta = append(meta, kv{"caller", ent.Caller.TrimmedPath()})
+	}
+	meta = append(meta, kv{"msg", ent.Message})
+	if ent.Stack != "" {
+		meta = append(meta, kv{"stacktrace", ent.Stack})
+	}
+
+	b = appendMsgpackHeader(b, len(meta)+len(tree.keys), 0x80, 0xde)
+	for _, m := range meta {
+		b = appendMsgpackString(b, m.key)
+		b = appendMsgpackValue(b, m.val)
+	}
+	for i, k := range tree.keys {
+		b = appendMsgpackString(b, k)
+		b = appendMsgpackValue(b, tree.vals[i])
+	}
+	return b
+}
+
+// appendMsgpackHeader writes a map or array header for n elements: the fix
+// form below 16, else the 16- or 32-bit form (code16, code16+1).
+func appendMsgpackHeader(b []byte, n int, fix, code16 byte) []byte {
+	switch {
+	case n < 16:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code16+1), uint32(n))
+	}
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendMsgpackUint(b []byte, v uint64) []byte {
+	switch {
+	case v < 128:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+	}
+}
+
+func appendMsgpackInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendMsgpackUint(b, uint64(v))
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(v))
+	}
+}
+
+func appendMsgpackValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if v {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case int64:
+		return appendMsgpackInt(b, v)
+	case uint64:
+		return appendMsgpackUint(b, v)
+	case float32:
+		return binary.BigEndian.AppendUint32(append(b, 0xca), math.Float32bits(v))
+	case float64:
+		return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(v))
+	case string:
+		return appendMsgpackString(b, v)
+	case []byte:
+		switch n := len(v); {
+		case n <= math.MaxUint8:
+			b = append(b, 0xc4, byte(n))
+		case n <= math.MaxUint16:
+			b = binary.BigEndian.AppendUint16(append(b, 0xc5), uint16(n))
+		default:
+			b = binary.BigEndian.AppendUint32(append(b, 0xc6), uint32(n))
+		}
+		return append(b, v...)
+	case time.Time:
+		// Timestamp extension (type -1), 96-bit form.
+		b = append(b, 0xc7, 12, 0xff)
+		b = binary.BigEndian.AppendUint32(b, uint32(v.Nanosecond()))
+		return binary.BigEndian.AppendUint64(b, uint64(v.Unix()))
+	case time.Duration:
+		return appendMsgpackInt(b, int64(v))
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return appendMsgpackInt(b, i)
+		}
+		f, _ := v.Float64()
+		return appendMsgpackValue(b, f)
+	case *fieldTree:
+		b = appendMsgpackHeader(b, len(v.keys), 0x80, 0xde)
+		for i, k := range v.keys {
+			b = appendMsgpackString(b, k)
+			b = appendMsgpackValue(b, v.vals[i])
+		}
+		return b
+	case map[string]interface{}:
+		b = appendMsgpackHeader(b, len(v), 0x80, 0xde)
+		for _, k := range sortedKeys(v) {
+			b = appendMsgpackString(b, k)
+			b = appendMsgpackValue(b, v[k])
+		}
+		return b
+	case []interface{}:
+		b = appendMsgpackHeader(b, len(v), 0x90, 0xdc)
+		for _, elem := range v {
+			b = appendMsgpackValue(b, elem)
+		}
+		return b
+	default:
+		return appendMsgpackString(b, formatScalar(v))
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/* -------------------------------- Protobuf -------------------------------- */
+
+// Field numbers of entry.proto.
+const (
+	protoEntryTime       protowire.Number = 1
+	protoEntryLevel      protowire.Number = 2
+	protoEntryLogger     protowire.Number = 3
+	protoEntryCaller     protowire.Number = 4
+	protoEntryMessage    protowire.Number = 5
+	protoEntryStacktrace protowire.Number = 6
+	protoEntryFields     protowire.Number = 7
+
+	protoFieldKey   protowire.Number = 1
+	protoFieldValue protowire.Number = 2
+
+	protoValueString   protowire.Number = 1
+	protoValueBool     protowire.Number = 2
+	protoValueInt      protowire.Number = 3
+	protoValueUint     protowire.Number = 4
+	protoValueDouble   protowire.Number = 5
+	protoValueBytes    protowire.Number = 6
+	protoValueObject   protowire.Number = 7
+	protoValueArray    protowire.Number = 8
+	protoValueTime     protowire.Number = 9
+	protoValueDuration protowire.Number = 10
+
+	protoObjectFields protowire.Number = 1
+	protoArrayValues  protowire.Number = 1
+)
+
+func appendProtoEntry(b []byte, ent zapcore.Entry, tree *fieldTree) []byte {
+	if !ent.Time.IsZero() {
+		b = protowire.AppendTag(b, protoEntryTime, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(ent.Time.UnixNano()))
+	}
+	b = appendProtoString(b, protoEntryLevel, zapLevelName(ent.Level))
+	b = appendProtoString(b, protoEntryLogger, ent.LoggerName)
+	if ent.Caller.Defined {
+		b = appendProtoString(b, protoEntryCaller, ent.Caller.TrimmedPath())
+	}
+	b = appendProtoString(b, protoEntryMessage, ent.Message)
+	b = appendProtoString(b, protoEntryStacktrace, ent.Stack)
+	for i, k := range tree.keys {
+		b = appendProtoMessage(b, protoEntryFields, appendProtoField(nil, k, tree.vals[i]))
+	}
+	return b
+}
+
+// appendProtoString writes a string field, omitting it when empty as proto3
+// does.
+func appendProtoString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendProtoMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+func appendProtoField(b []byte, key string, v interface{}) []byte {
+	b = protowire.AppendTag(b, protoFieldKey, protowire.BytesType)
+	b = protowire.AppendString(b, key)
+	return appendProtoMessage(b, protoFieldValue, appendProtoValue(nil, v))
+}
+
+// appendProtoValue encodes v as a Value message; nil leaves every member of
+// the oneof unset.
+func appendProtoValue(b []byte, v interface{}) []byte {
+	varint := func(num protowire.Number, x uint64) []byte {
+		b = protowire.AppendTag(b, num, protowire.VarintType)
+		return protowire.AppendVarint(b, x)
+	}
+	switch v := v.(type) {
+	case nil:
+		return b
+	case string:
+		b = protowire.AppendTag(b, protoValueString, protowire.BytesType)
+		return protowire.AppendString(b, v)
+	case bool:
+		return varint(protoValueBool, protowire.EncodeBool(v))
+	case int64:
+		return varint(protoValueInt, protowire.EncodeZigZag(v))
+	case uint64:
+		return varint(protoValueUint, v)
+	case float32:
+		return appendProtoValue(b, float64(v))
+	case float64:
+		b = protowire.AppendTag(b, protoValueDouble, protowire.Fixed64Type)
+		return protowire.AppendFixed64(b, math.Float64bits(v))
+	case []byte:
+		b = protowire.AppendTag(b, protoValueBytes, protowire.BytesType)
+		return protowire.AppendBytes(b, v)
+	case time.Time:
+		return varint(protoValueTime, uint64(v.UnixNano()))
+	case time.Duration:
+		return varint(protoValueDuration, uint64(v))
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return appendProtoValue(b, i)
+		}
+		f, _ := v.Float64()
+		return appendProtoValue(b, f)
+	case *fieldTree:
+		var obj []byte
+		for i, k := range v.keys {
+			obj = appendProtoMessage(obj, protoObjectFields, appendProtoField(nil, k, v.vals[i]))
+		}
+		return appendProtoMessage(b, protoValueObject, obj)
+	case map[string]interface{}:
+		var obj []byte
+		for _, k := range sortedKeys(v) {
+			obj = appendProtoMessage(obj, protoObjectFields, appendProtoField(nil, k, v[k]))
+		}
+		return appendProtoMessage(b, protoValueObject, obj)
+	case []interface{}:
+		var arr []byte
+		for _, elem := range v {
+			arr = appendProtoMessage(arr, protoArrayValues, appendProtoValue(nil, elem))
+		}
+		return appendProtoMessage(b, protoValueArray, arr)
+	default:
+		return appendProtoValue(b, formatScalar(v))
+	}
+}
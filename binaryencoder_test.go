@@ -0,0 +1,150 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestMsgpackValues(t *testing.T) {
+	cases := []struct {
+		in   interface{}
+		want string
+	}{
+		{nil, "c0"},
+		{true, "c3"},
+		{int64(5), "05"},
+		{int64(-1), "ff"},
+		{int64(-33), "d0df"},
+		{int64(-1000), "d1fc18"},
+		{uint64(200), "ccc8"},
+		{uint64(1 << 40), "cf0000010000000000"},
+		{float64(1.5), "cb3ff8000000000000"},
+		{"hi", "a26869"},
+		{[]byte{1, 2}, "c4020102"},
+		{time.Duration(300), "cd012c"},
+		{time.Unix(1, 2), "c70cff000000020000000000000001"},
+		{[]interface{}{int64(1), "a"}, "9201a161"},
+	}
+	for _, tc := range cases {
+		if got := hex.EncodeToString(appendMsgpackValue(nil, tc.in)); got != tc.want {
+			t.Errorf("%#v: got %s, want %s", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestMsgpackEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, MsgpackEncoder), WithoutCaller())
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("hi", Dict("d", Int("n", 1)))
+
+	out := buf.Bytes()
+	// A map of level, ts, msg and d.
+	if len(out) == 0 || out[0] != 0x84 {
+		t.Fatalf("expected a 4-entry map, got % x", out)
+	}
+	for _, want := range []string{
+		"a56c6576656ca4696e666f", // "level": "info"
+		"a36d7367a26869",         // "msg": "hi"
+		"a16481a16e01",           // "d": {"n": 1}
+	} {
+		if !bytes.Contains(out, mustHex(t, want)) {
+			t.Errorf("expected %s in % x", want, out)
+		}
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// protoFields decodes one level of a message into its fields by number,
+// bytes fields as raw bytes and varints as uint64.
+func protoFields(t *testing.T, b []byte) map[protowire.Number][]interface{} {
+	t.Helper()
+	out := map[protowire.Number][]interface{}{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("bad tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		var v interface{}
+		switch typ {
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(b)
+		default:
+			t.Fatalf("unexpected wire type %v", typ)
+		}
+		if n < 0 {
+			t.Fatalf("bad value: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		out[num] = append(out[num], v)
+	}
+	return out
+}
+
+func TestProtobufEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, ProtobufEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Named("api").Warn("first", Int("n", -2), Strings("tags", []string{"a"}))
+	logger.Info("second")
+
+	data := buf.Bytes()
+	var msgs [][]byte
+	for len(data) > 0 {
+		size, n := protowire.ConsumeVarint(data)
+		if n < 0 || int(size) > len(data)-n {
+			t.Fatalf("bad length prefix in % x", data)
+		}
+		msgs = append(msgs, data[n:n+int(size)])
+		data = data[n+int(size):]
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+
+	entry := protoFields(t, msgs[0])
+	if string(entry[protoEntryLevel][0].([]byte)) != "warn" || string(entry[protoEntryLogger][0].([]byte)) != "api" ||
+		string(entry[protoEntryMessage][0].([]byte)) != "first" || len(entry[protoEntryCaller]) != 1 || len(entry[protoEntryTime]) != 1 {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	fields := entry[protoEntryFields]
+	if len(fields) != 2 {
+		t.Fatalf("expected 2 fields, got %d", len(fields))
+	}
+	n := protoFields(t, fields[0].([]byte))
+	value := protoFields(t, n[protoFieldValue][0].([]byte))
+	if string(n[protoFieldKey][0].([]byte)) != "n" || protowire.DecodeZigZag(value[protoValueInt][0].(uint64)) != -2 {
+		t.Errorf("unexpected field n: %v / %v", n, value)
+	}
+	tags := protoFields(t, protoFields(t, fields[1].([]byte))[protoFieldValue][0].([]byte))
+	arr := protoFields(t, tags[protoValueArray][0].([]byte))
+	elem := protoFields(t, arr[protoArrayValues][0].([]byte))
+	if string(elem[protoValueString][0].([]byte)) != "a" {
+		t.Errorf("unexpected array element %v", elem)
+	}
+}
@@ -0,0 +1,261 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                         Circuit Breaker Wrapper                             */
+/* -------------------------------------------------------------------------- */
+
+// BreakerState is the state of a provider's circuit breaker.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerEvent is reported through the logger's error handler whenever a
+// circuit breaker changes state. Err is the failure that caused the
+// transition, if any.
+type BreakerEvent struct {
+	Provider string
+	From, To BreakerState
+	Err      error
+}
+
+func (e *BreakerEvent) Error() string {
+	msg := fmt.Sprintf("circuit breaker %s: %s -> %s", e.Provider, e.From, e.To)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *BreakerEvent) Unwrap() error { return e.Err }
+
+// CircuitBreakerConfig configures WithCircuitBreaker. Zero values fall back to
+// the defaults noted on each field.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// breaker (default 5).
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before a single probe
+	// entry is let through (default 30s).
+	OpenTimeout time.Duration
+	// Fallback, if set, registers the providers that receive entries while
+	// the breaker is open. Without it those entries are dropped.
+	Fallback LoggerOption
+}
+
+// breaker is the closed → open → half-open state machine shared by the
+// circuit-breaker and failover wrappers.
+type breaker struct {
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	threshold int
+	timeout   time.Duration
+
+	// onChange is invoked (outside the lock) for every transition.
+	onChange func(from, to BreakerState, err error)
+	now      func() time.Time
+}
+
+func newBreaker(threshold int, timeout time.Duration) *breaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &breaker{state: BreakerClosed, threshold: threshold, timeout: timeout, now: time.Now}
+}
+
+// allow reports whether a write may be attempted. While half-open only one
+// probe is in flight at a time.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	switch b.state {
+	case BreakerClosed:
+		b.mu.Unlock()
+		return true
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.timeout {
+			b.mu.Unlock()
+			return false
+		}
+		b.state, b.probing = BreakerHalfOpen, true
+		b.mu.Unlock()
+		b.notify(BreakerOpen, BreakerHalfOpen, nil)
+		return true
+	default: // half-open
+		if b.probing {
+			b.mu.Unlock()
+			return false
+		}
+		b.probing = true
+		b.mu.Unlock()
+		return true
+	}
+}
+
+// record feeds the outcome of an allowed write back into the breaker.
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	from := b.state
+	switch {
+	case b.state == BreakerHalfOpen:
+		b.probing = false
+		if err == nil {
+			b.state, b.failures = BreakerClosed, 0
+		} else {
+			b.state, b.openedAt = BreakerOpen, b.now()
+		}
+	case b.state == BreakerOpen:
+		// A write admitted before the breaker tripped; its outcome is stale.
+	case err == nil:
+		b.failures = 0
+	default:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.state, b.openedAt = BreakerOpen, b.now()
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	if from != to {
+		b.notify(from, to, err)
+	}
+}
+
+func (b *breaker) current() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *breaker) notify(from, to BreakerState, err error) {
+	if b.onChange != nil {
+		b.onChange(from, to, err)
+	}
+}
+
+// WithCircuitBreaker wraps every provider registered by opt with a circuit
+// breaker: after cfg.FailureThreshold consecutive write failures the
+// provider is skipped for cfg.OpenTimeout, then probed with a single entry.
+// State changes are reported as *BreakerEvent through the error handler.
+func WithCircuitBreaker(opt LoggerOption, cfg CircuitBreakerConfig) LoggerOption {
+	return wrapProviders(opt, func(p provider) provider {
+		return &breakerProvider{
+			inner:    p,
+			fallback: providersOf(cfg.Fallback),
+			breaker:  newBreaker(cfg.FailureThreshold, cfg.OpenTimeout),
+		}
+	})
+}
+
+// providersOf returns the providers opt registers, without applying any of its
+// other settings. Each call yields fresh provider instances.
+func providersOf(opt LoggerOption) []provider {
+	if opt == nil {
+		return nil
+	}
+	scratch := &loggerConfig{}
+	opt(scratch)
+	return scratch.providers
+}
+
+type breakerProvider struct {
+	inner    provider
+	fallback []provider
+	breaker  *breaker
+	tel      *telemetry
+}
+
+func (p *breakerProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	core, err := p.inner.newCore(level)
+	if err != nil {
+		return nil, err
+	}
+	c := &breakerCore{Core: core, p: p}
+	if c.fallback, err = coresOf(p.fallback, level); err != nil {
+		return nil, fmt.Errorf("circuit breaker fallback: %w", err)
+	}
+	return c, nil
+}
+
+func (p *breakerProvider) close() error {
+	errs := []error{p.inner.close()}
+	for _, f := range p.fallback {
+		errs = append(errs, f.close())
+	}
+	return errors.Join(errs...)
+}
+
+func (p *breakerProvider) instrument(t *telemetry) {
+	p.tel = t
+	name := describeProvider(p.inner).Name
+	p.breaker.onChange = func(from, to BreakerState, err error) {
+		t.errs.report(&BreakerEvent{Provider: name, From: from, To: to, Err: err})
+	}
+	for _, q := range append([]provider{p.inner}, p.fallback...) {
+		if ip, ok := q.(instrumentedProvider); ok {
+			ip.instrument(t)
+		}
+	}
+}
+
+func (p *breakerProvider) describe() ProviderInfo {
+	info := describeProvider(p.inner)
+	info.Name = "breaker(" + info.Name + ")"
+	return info
+}
+
+type breakerCore struct {
+	zapcore.Core
+	// fallback is written through writeEnabled, so its providers keep
+	// their own levels.
+	fallback []zapcore.Core
+	p        *breakerProvider
+}
+
+func (c *breakerCore) With(fields []zapcore.Field) zapcore.Core {
+	return &breakerCore{Core: c.Core.With(fields), fallback: withAll(c.fallback, fields), p: c.p}
+}
+
+func (c *breakerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *breakerCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.p.breaker.allow() {
+		if len(c.fallback) > 0 {
+			return writeEnabled(c.fallback, ent, fields)
+		}
+		if c.p.tel != nil {
+			c.p.tel.drops.record(DropCircuitOpen, 1)
+		}
+		return nil
+	}
+	err := c.Core.Write(ent, fields)
+	c.p.breaker.record(err)
+	return err
+}
+
+func (c *breakerCore) Sync() error {
+	return errors.Join(c.Core.Sync(), syncAll(c.fallback))
+}
@@ -0,0 +1,136 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBreaker_StateMachine(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	var transitions []string
+	b.onChange = func(from, to BreakerState, _ error) {
+		transitions = append(transitions, string(from)+"->"+string(to))
+	}
+
+	fail := errors.New("boom")
+	b.record(fail)
+	if b.current() != BreakerClosed {
+		t.Fatalf("breaker opened before reaching the threshold")
+	}
+	b.record(fail)
+	if b.current() != BreakerOpen || b.allow() {
+		t.Fatalf("breaker should be open and rejecting writes")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.allow() {
+		t.Fatalf("expected a probe after the open timeout")
+	}
+	if b.allow() {
+		t.Fatalf("only one probe may be in flight while half-open")
+	}
+	b.record(fail)
+	if b.current() != BreakerOpen {
+		t.Fatalf("failed probe should reopen the breaker")
+	}
+
+	now = now.Add(time.Minute)
+	b.allow()
+	b.record(nil)
+	if b.current() != BreakerClosed || !b.allow() {
+		t.Fatalf("successful probe should close the breaker")
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if strings.Join(transitions, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected transitions:\n got %v\nwant %v", transitions, want)
+	}
+}
+
+func TestWithCircuitBreaker_FallbackAndEvents(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []*BreakerEvent
+	)
+	var fallback bytes.Buffer
+	logger, err := NewLogger(
+		WithCircuitBreaker(WithWriterProvider(failingWriter{}, JSONEncoder), CircuitBreakerConfig{
+			FailureThreshold: 2,
+			OpenTimeout:      time.Hour,
+			Fallback:         WithWriterProvider(&fallback, JSONEncoder),
+		}),
+		WithErrorHandler(func(err error) {
+			var ev *BreakerEvent
+			if errors.As(err, &ev) {
+				mu.Lock()
+				events = append(events, ev)
+				mu.Unlock()
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("fails 1")
+	logger.Info("fails 2") // trips the breaker
+	logger.Info("diverted")
+
+	if out := fallback.String(); !strings.Contains(out, "diverted") || strings.Contains(out, "fails") {
+		t.Fatalf("expected only the post-trip entry in the fallback, got %q", out)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 || events[0].From != BreakerClosed || events[0].To != BreakerOpen || events[0].Provider != "writer" {
+		t.Fatalf("unexpected breaker events: %+v", events)
+	}
+}
+
+func TestWithCircuitBreaker_FallbackLevel(t *testing.T) {
+	var fallback bytes.Buffer
+	logger, err := NewLogger(
+		WithCircuitBreaker(WithWriterProvider(failingWriter{}, JSONEncoder), CircuitBreakerConfig{
+			FailureThreshold: 1,
+			OpenTimeout:      time.Hour,
+			Fallback:         WithWriterProvider(&fallback, JSONEncoder, WithProviderLevel(WarnLevel)),
+		}),
+		WithErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("trips")
+	logger.Info("routine")
+	logger.Warn("diverted")
+	if out := fallback.String(); strings.Contains(out, "routine") || !strings.Contains(out, "diverted") {
+		t.Fatalf("fallback provider level ignored: %q", out)
+	}
+}
+
+func TestWithCircuitBreaker_DropsWithoutFallback(t *testing.T) {
+	logger, err := NewLogger(
+		WithCircuitBreaker(WithWriterProvider(failingWriter{}, JSONEncoder), CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour}),
+		WithErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	for i := 0; i < 4; i++ {
+		logger.Info("entry")
+	}
+	if got := logger.DroppedEntries()[DropCircuitOpen]; got != 3 {
+		t.Fatalf("expected 3 circuit_open drops, got %d", got)
+	}
+}
@@ -0,0 +1,167 @@
+package golog
+
+import (
+	"fmt"
+	"path"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                      Caller & Stack Trace Annotations                       */
+/* -------------------------------------------------------------------------- */
+
+// wrapperCallerSkip is the number of golog frames between the application's
+// call and zap: the Logger method itself.
+const wrapperCallerSkip = 1
+
+// WithCallerSkip reports the caller n frames further up the stack, for
+// applications that wrap golog in their own facade:
+//
+//	func (f *facade) Info(msg string) { f.logger.Info(msg) }
+//
+//	logger, err := golog.NewLogger(golog.WithCallerSkip(1))
+//
+// It applies to every entry of the logger and its children. n must not be
+// negative.
+func WithCallerSkip(n int) LoggerOption {
+	return func(cfg *loggerConfig) {
+		if n < 0 {
+			cfg.optionErrs = append(cfg.optionErrs, fmt.Errorf("WithCallerSkip: negative skip %d", n))
+			return
+		}
+		cfg.callerSkip = n
+	}
+}
+
+// WithoutCaller omits the caller annotation, saving the stack walk on every
+// entry.
+func WithoutCaller() LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.withoutCaller = true
+	}
+}
+
+// CallerFormat selects how the caller's file is written.
+type CallerFormat int
+
+const (
+	// CallerShort writes the file's directory and name, e.g.
+	// "http/server.go:42"; the default.
+	CallerShort CallerFormat = iota
+	// CallerModule writes the file's path relative to the main module's
+	// root, e.g. "internal/http/server.go:42"; files of other modules are
+	// written under their package's import path. The path is derived from
+	// the calling function's package, so it does not depend on where the
+	// binary was built.
+	CallerModule
+	// CallerFull writes the absolute path recorded at build time.
+	CallerFull
+)
+
+// WithCallerFormat sets how the JSON and console encoders write the caller,
+// and which file the GCP provider reports as source_file: the full path
+// unless CallerModule is chosen. The XML, pretty, CSV, LTSV, MessagePack and
+// Protobuf encoders always write the short form.
+func WithCallerFormat(format CallerFormat) LoggerOption {
+	return func(cfg *loggerConfig) {
+		if format < CallerShort || format > CallerFull {
+			cfg.optionErrs = append(cfg.optionErrs, fmt.Errorf("WithCallerFormat: unknown format %d", int(format)))
+			return
+		}
+		cfg.callerFormat = format
+	}
+}
+
+// WithCallerFunction adds the calling function's name under "func" to the
+// entries of the JSON and console encoders, e.g.
+// "github.com/acme/app/internal/http.(*Server).Serve".
+func WithCallerFunction() LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.callerFunction = true
+	}
+}
+
+// callerConfig returns the encoder config function applying format and
+// function, nil if there is nothing to change.
+func callerConfig(format CallerFormat, function bool) func(*zapcore.EncoderConfig) {
+	if format == CallerShort && !function {
+		return nil
+	}
+	return func(c *zapcore.EncoderConfig) {
+		switch format {
+		case CallerModule:
+			c.EncodeCaller = moduleCallerEncoder
+		case CallerFull:
+			c.EncodeCaller = zapcore.FullCallerEncoder
+		}
+		if function {
+			c.FunctionKey = "func"
+		}
+	}
+}
+
+func moduleCallerEncoder(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+	if !caller.Defined {
+		enc.AppendString("undefined")
+		return
+	}
+	enc.AppendString(moduleRelativeFile(caller) + ":" + strconv.Itoa(caller.Line))
+}
+
+// mainModule returns the import paths of the main module and main package.
+var mainModule = sync.OnceValues(func() (module, mainPkg string) {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Path, info.Path
+	}
+	return "", ""
+})
+
+// moduleRelativeFile returns caller's file relative to the main module's
+// root, or under its package's import path for other modules. Without a
+// function name it falls back to the short form.
+func moduleRelativeFile(caller zapcore.EntryCaller) string {
+	pkg := funcPackage(caller.Function)
+	if pkg == "" {
+		return strings.TrimSuffix(caller.TrimmedPath(), ":"+strconv.Itoa(caller.Line))
+	}
+	module, mainPkg := mainModule()
+	if pkg == "main" {
+		pkg = mainPkg
+	}
+	// Runtime file names use forward slashes on every platform.
+	file := path.Base(caller.File)
+	switch {
+	case module != "" && pkg == module:
+		return file
+	case module != "" && strings.HasPrefix(pkg, module+"/"):
+		return pkg[len(module)+1:] + "/" + file
+	}
+	return pkg + "/" + file
+}
+
+// funcPackage returns the import path of the package declaring the
+// function named fn, as reported by runtime.Frame.Function. The linker
+// escapes dots in the last path element, as in gopkg.in/yaml%2ev3.
+func funcPackage(fn string) string {
+	slash := strings.LastIndexByte(fn, '/')
+	dot := strings.IndexByte(fn[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return strings.ReplaceAll(fn[:slash+1+dot], "%2e", ".")
+}
+
+// WithStacktrace attaches a stack trace of the logging goroutine under
+// "stacktrace" to entries at or above level, typically ErrorLevel. The GCP
+// provider sends it as stack_trace for Error Reporting. Frames inside golog
+// and those skipped with WithCallerSkip are left out.
+func WithStacktrace(level Level) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.stacktrace = &level
+	}
+}
@@ -0,0 +1,188 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func callers(t *testing.T, buf *bytes.Buffer) []string {
+	t.Helper()
+	var out []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		caller, _ := entry["caller"].(string)
+		out = append(out, entry["msg"].(string)+" "+caller)
+	}
+	return out
+}
+
+func TestCallerIsCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	SetDefault(logger)
+	defer SetDefault(nil)
+
+	logger.Info("method")
+	logger.Infof("sugared")
+	logger.InfoCtx(context.Background(), "ctx")
+	logger.Log(InfoLevel, "log")
+	logger.Named("child").With(String("k", "v")).Info("child")
+	logger.Event(InfoLevel).Msg("event")
+	logger.Event(InfoLevel).Msgf("eventf")
+	Info("package")
+	logger.StdLogger(InfoLevel).Print("std")
+	_, _ = logger.Writer(InfoLevel).Write([]byte("writer\n"))
+
+	got := callers(t, &buf)
+	if len(got) != 10 {
+		t.Fatalf("expected 10 entries, got %v", got)
+	}
+	for _, c := range got {
+		if !strings.Contains(c, "caller_test.go:") {
+			t.Errorf("caller is not the call site: %s", c)
+		}
+	}
+}
+
+type facade struct{ logger *Logger }
+
+func (f facade) Info(msg string) { f.logger.Info(msg) }
+
+func TestWithCallerSkip(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithCallerSkip(1))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	facade{logger}.Info("wrapped")
+	if c := callers(t, &buf); len(c) != 1 || !strings.Contains(c[0], "caller_test.go:") {
+		t.Errorf("caller should be the facade's caller: %v", c)
+	}
+	if got := logger.Config().CallerSkip; got != 1 {
+		t.Errorf("Config().CallerSkip = %d", got)
+	}
+
+	if _, err := NewLogger(WithCallerSkip(-1)); err == nil {
+		t.Error("expected an error for a negative skip")
+	}
+}
+
+func TestWithoutCaller(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithoutCaller())
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("no caller")
+	if strings.Contains(buf.String(), `"caller"`) {
+		t.Errorf("caller annotated: %s", buf.String())
+	}
+	if logger.Config().Caller {
+		t.Error("Config().Caller should be false")
+	}
+}
+
+func TestWithStacktrace(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithStacktrace(ErrorLevel), WithRingBuffer(4))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Warn("no stack")
+	logger.Error("with stack")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", lines)
+	}
+	if strings.Contains(lines[0], "stacktrace") {
+		t.Errorf("warn entry carries a stack trace: %s", lines[0])
+	}
+	var entry struct{ Stacktrace string }
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !strings.HasPrefix(entry.Stacktrace, "github.com/evdnx/golog.TestWithStacktrace") {
+		t.Errorf("stack trace should start at the call site: %q", entry.Stacktrace)
+	}
+	if recent := logger.RecentEntries(); len(recent) != 2 || recent[1].Stack == "" {
+		t.Errorf("ring buffer entry lost the stack: %+v", recent)
+	}
+	if lvl := logger.Config().Stacktrace; lvl == nil || *lvl != ErrorLevel {
+		t.Errorf("Config().Stacktrace = %v", lvl)
+	}
+}
+
+func TestCallerFormat(t *testing.T) {
+	var short, module, full bytes.Buffer
+	newLogger := func(buf *bytes.Buffer, options ...LoggerOption) *Logger {
+		logger, err := NewLogger(append([]LoggerOption{WithWriterProvider(buf, JSONEncoder)}, options...)...)
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		t.Cleanup(func() { logger.Close() })
+		return logger
+	}
+	newLogger(&short).Info("short")
+	newLogger(&module, WithCallerFormat(CallerModule), WithCallerFunction()).Info("module")
+	newLogger(&full, WithCallerFormat(CallerFull)).Info("full")
+
+	// callers returns "message caller" per entry.
+	if got := callers(t, &short); len(got) != 1 || !strings.HasPrefix(got[0], "short ") || strings.Count(got[0], "/") != 1 {
+		t.Errorf("short caller = %q", got)
+	}
+	if got := callers(t, &module); len(got) != 1 || !strings.HasPrefix(got[0], "module caller_test.go:") {
+		t.Errorf("module caller = %q", got)
+	}
+	if !strings.Contains(module.String(), `"func":"github.com/evdnx/golog.TestCallerFormat"`) {
+		t.Errorf("function name missing: %s", module.String())
+	}
+	if got := callers(t, &full); len(got) != 1 || !strings.HasSuffix(fullPath(got[0]), "/caller_test.go") || !filepath.IsAbs(fullPath(got[0])) {
+		t.Errorf("full caller = %q", got)
+	}
+	if _, err := NewLogger(WithCallerFormat(CallerFormat(7))); err == nil {
+		t.Error("expected an error for an unknown caller format")
+	}
+}
+
+// fullPath extracts the file from a "message file:line" caller.
+func fullPath(caller string) string {
+	_, file, _ := strings.Cut(caller, " ")
+	return file[:strings.LastIndexByte(file, ':')]
+}
+
+func TestModuleRelativeFile(t *testing.T) {
+	for _, tc := range []struct {
+		function, file, want string
+	}{
+		{"github.com/evdnx/golog/gologsql.(*Driver).Open", "/build/src/golog/gologsql/driver.go", "gologsql/driver.go"},
+		{"github.com/evdnx/golog.NewLogger.func1", "/build/src/golog/main.go", "main.go"},
+		{"go.uber.org/zap.(*Logger).Info", "/go/pkg/mod/go.uber.org/zap@v1.27.0/logger.go", "go.uber.org/zap/logger.go"},
+		{"gopkg.in/yaml%2ev3.Unmarshal", "/go/pkg/mod/gopkg.in/yaml.v3@v3.0.1/yaml.go", "gopkg.in/yaml.v3/yaml.go"},
+		{"", "/build/src/golog/internal/term/term.go", "term/term.go"},
+	} {
+		caller := zapcore.EntryCaller{Defined: true, Function: tc.function, File: tc.file, Line: 3}
+		if got := moduleRelativeFile(caller); got != tc.want {
+			t.Errorf("moduleRelativeFile(%q) = %q, want %q", tc.function, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                           Chat Webhook Alert Provider                       */
+/* -------------------------------------------------------------------------- */
+
+// ChatPlatform selects the payload format of a chat webhook.
+type ChatPlatform string
+
+const (
+	// ChatSlack posts {"text": ...}, which Mattermost and Rocket.Chat
+	// accept as well.
+	ChatSlack ChatPlatform = "slack"
+	// ChatDiscord posts {"content": ...}, cut to Discord's 2000 characters.
+	ChatDiscord ChatPlatform = "discord"
+	// ChatTeams posts an Adaptive Card, for Teams workflow webhooks.
+	ChatTeams ChatPlatform = "teams"
+)
+
+// defaultChatTemplate renders "[ERROR] payments: charge failed" followed by
+// the fields on the next line.
+const defaultChatTemplate = "[{{.Level}}] {{with .Logger}}{{.}}: {{end}}{{.Message}}{{with .FieldsText}}\n{{.}}{{end}}"
+
+// ChatConfig configures WithChatProvider. The embedded HTTPConfig covers TLS,
+// proxy, headers and timeout; its batching settings are not used. Zero
+// values fall back to the defaults noted on each field.
+type ChatConfig struct {
+	HTTPConfig
+	// Platform defaults to the one the webhook host belongs to
+	// (hooks.slack.com, discord.com, *.webhook.office.com or
+	// *.logic.azure.com), else ChatSlack.
+	Platform ChatPlatform
+	// Template is a text/template rendering a ChatEntry into the message
+	// text (default "[{{.Level}}] {{with .Logger}}{{.}}: {{end}}{{.Message}}"
+	// followed by the fields).
+	Template string
+	// RateLimit is the number of messages allowed per RatePeriod (default
+	// 10 per minute). Entries beyond it are dropped and counted as
+	// DropRateLimited; the next message notes how many were suppressed.
+	RateLimit  int
+	RatePeriod time.Duration
+}
+
+func (c ChatConfig) withDefaults(webhookURL string) ChatConfig {
+	if c.Platform == "" {
+		c.Platform = chatPlatformOf(webhookURL)
+	}
+	if c.Template == "" {
+		c.Template = defaultChatTemplate
+	}
+	if c.RateLimit <= 0 {
+		c.RateLimit = 10
+	}
+	if c.RatePeriod <= 0 {
+		c.RatePeriod = time.Minute
+	}
+	return c
+}
+
+// ChatEntry is the data of a ChatConfig.Template, and of each entry of an
+// EmailDigest.
+type ChatEntry struct {
+	Time time.Time
+	// Level is upper case, e.g. "ERROR".
+	Level   string
+	Logger  string
+	Caller  string
+	Message string
+	Stack   string
+	// Fields maps the field keys, dotted for nested objects, to their
+	// values; FieldsText renders them as key=value pairs.
+	Fields     map[string]interface{}
+	FieldsText string
+}
+
+// WithChatProvider posts entries at or above minLevel to a Slack, Discord or
+// Microsoft Teams incoming webhook, e.g. for an alerts channel:
+//
+//	golog.WithChatProvider(os.Getenv("SLACK_WEBHOOK_URL"), golog.ErrorLevel, golog.ChatConfig{
+//		Template: "{{.Level}} in checkout: {{.Message}} (order {{index .Fields \"order_id\"}})",
+//	})
+//
+// Each entry is one message, posted before the logging call returns. The
+// rate limit protects the channel, and the webhook, from error storms;
+// a 429 from the webhook pauses posting for its Retry-After. Failed posts
+// are counted as DropProviderError.
+func WithChatProvider(webhookURL string, minLevel Level, cfg ChatConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		name := "chat"
+		if u, err := url.Parse(webhookURL); err == nil {
+			// The path of a webhook URL is its secret.
+			name += ":" + u.Host
+		}
+		c.providers = append(c.providers, applyProviderOptions(&chatProvider{
+			providerBase: providerBase{name: name},
+			url:          webhookURL,
+			min:          minLevel,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type chatProvider struct {
+	providerBase
+	url string
+	min Level
+	cfg ChatConfig
+	tel *telemetry
+}
+
+func (p *chatProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	cfg := p.cfg.withDefaults(p.url)
+	if u, err := url.Parse(p.url); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("chat provider: invalid webhook URL %q", p.url)
+	}
+	switch cfg.Platform {
+	case ChatSlack, ChatDiscord, ChatTeams:
+	default:
+		return nil, fmt.Errorf("chat provider: unknown platform %q", cfg.Platform)
+	}
+	tmpl, err := template.New("chat").Parse(cfg.Template)
+	if err != nil {
+		return nil, fmt.Errorf("chat provider: %w", err)
+	}
+	sender, err := newHTTPSender(cfg.HTTPConfig)
+	if err != nil {
+		return nil, fmt.Errorf("chat provider: %w", err)
+	}
+	if min := toZapLevel(p.min); min > level {
+		level = min
+	}
+	client := &chatClient{
+		url: p.url, platform: cfg.Platform, sender: sender, drops: p.dropped,
+		limiter: newRateLimiter(cfg.RateLimit, cfg.RatePeriod),
+	}
+	return &chatCore{LevelEnabler: level, tree: newTreeEncoder(), tmpl: tmpl, client: client}, nil
+}
+
+func (p *chatProvider) close() error { return nil }
+
+func (p *chatProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name}
+}
+
+// chatPlatformOf guesses the platform from the webhook host.
+func chatPlatformOf(webhookURL string) ChatPlatform {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return ChatSlack
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "discord.com" || host == "discordapp.com" || strings.HasSuffix(host, ".discord.com"):
+		return ChatDiscord
+	case strings.HasSuffix(host, ".webhook.office.com") || strings.HasSuffix(host, ".logic.azure.com"):
+		return ChatTeams
+	}
+	return ChatSlack
+}
+
+type chatCore struct {
+	zapcore.LevelEnabler
+	tree   *treeEncoder
+	tmpl   *template.Template
+	client *chatClient
+}
+
+func (c *chatCore) With(fields []zapcore.Field) zapcore.Core {
+	tree := c.tree.cloneTree()
+	for _, f := range fields {
+		f.AddTo(tree)
+	}
+	clone := *c
+	clone.tree = tree
+	return &clone
+}
+
+func (c *chatCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *chatCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var msg strings.Builder
+	if err := c.tmpl.Execute(&msg, newChatEntry(ent, c.tree.fieldsWith(fields))); err != nil {
+		return fmt.Errorf("chat: template: %w", err)
+	}
+	return c.client.post(msg.String())
+}
+
+func (c *chatCore) Sync() error { return nil }
+
+// newChatEntry returns the template data of an entry.
+func newChatEntry(ent zapcore.Entry, tree *fieldTree) ChatEntry {
+	data := ChatEntry{
+		Time:    ent.Time,
+		Level:   strings.ToUpper(zapLevelName(ent.Level)),
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+		Stack:   ent.Stack,
+		Fields:  map[string]interface{}{},
+	}
+	if ent.Caller.Defined {
+		data.Caller = ent.Caller.TrimmedPath()
+	}
+	var text strings.Builder
+	tree.flatten("", func(key string, val interface{}) {
+		data.Fields[key] = val
+		if text.Len() > 0 {
+			text.WriteByte(' ')
+		}
+		text.WriteString(key + "=" + prettyValue(val))
+	})
+	data.FieldsText = text.String()
+	return data
+}
+
+/* -------------------------------------------------------------------------- */
+/*                                Webhook Client                              */
+/* -------------------------------------------------------------------------- */
+
+type chatClient struct {
+	url      string
+	platform ChatPlatform
+	sender   *httpSender
+	drops    func(reason DropReason, n int)
+	limiter  *rateLimiter
+
+	mu         sync.Mutex
+	suppressed int
+	// pausedUntil is set from a 429 response.
+	pausedUntil time.Time
+}
+
+// post sends text unless rate-limited, prefixed with the number of
+// messages suppressed since the last one.
+func (c *chatClient) post(text string) error {
+	c.mu.Lock()
+	if time.Now().Before(c.pausedUntil) || !c.limiter.allow() {
+		c.suppressed++
+		c.mu.Unlock()
+		c.drops(DropRateLimited, 1)
+		return nil
+	}
+	suppressed := c.suppressed
+	c.suppressed = 0
+	c.mu.Unlock()
+	if suppressed > 0 {
+		text = fmt.Sprintf("(%d earlier alerts suppressed by the rate limit)\n%s", suppressed, text)
+	}
+
+	err := c.send(text)
+	if err != nil {
+		c.drops(DropProviderError, 1)
+	}
+	return err
+}
+
+func (c *chatClient) send(text string) error {
+	var payload interface{}
+	switch c.platform {
+	case ChatDiscord:
+		payload = map[string]string{"content": truncateUTF8(text, 2000)}
+	case ChatTeams:
+		payload = map[string]interface{}{
+			"type": "message",
+			"attachments": []interface{}{map[string]interface{}{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]interface{}{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body":    []interface{}{map[string]interface{}{"type": "TextBlock", "text": text, "wrap": true}},
+				},
+			}},
+		}
+	default:
+		payload = map[string]string{"text": truncateUTF8(text, 40000)}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("chat: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.sender.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("chat: %w", err)
+	}
+	for k, v := range c.sender.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.sender.client.Do(req)
+	if err != nil {
+		// The URL, which holds the webhook secret, is part of the error.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("chat: %w", err)
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := time.Minute
+		if secs, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil && secs > 0 {
+			wait = time.Duration(secs * float64(time.Second))
+		}
+		c.mu.Lock()
+		c.pausedUntil = time.Now().Add(wait)
+		c.mu.Unlock()
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("chat: %s: %s", resp.Status, bytes.TrimSpace(snippet))
+	}
+	return nil
+}
+
+// rateLimiter is a token bucket of n tokens refilled over period.
+type rateLimiter struct {
+	tokens float64
+	max    float64
+	rate   float64 // tokens per second
+	last   time.Time
+}
+
+func newRateLimiter(n int, period time.Duration) *rateLimiter {
+	return &rateLimiter{tokens: float64(n), max: float64(n), rate: float64(n) / period.Seconds(), last: time.Now()}
+}
+
+// allow takes a token if one is left. The caller serialises calls.
+func (l *rateLimiter) allow() bool {
+	now := time.Now()
+	l.tokens = min(l.max, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
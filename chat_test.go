@@ -0,0 +1,162 @@
+package golog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWebhook records the JSON bodies posted to it.
+type fakeWebhook struct {
+	mu     sync.Mutex
+	bodies []map[string]interface{}
+	status int
+}
+
+func (f *fakeWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.bodies = append(f.bodies, body)
+	if f.status != 0 {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(f.status)
+	}
+}
+
+func TestChatProvider_Slack(t *testing.T) {
+	fake := &fakeWebhook{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithChatProvider(srv.URL+"/services/T/B/secret", ErrorLevel, ChatConfig{}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Warn("not alerted")
+	logger.Named("payments").With(String("order", "A1")).Error("charge failed", Int("amount", 5))
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.bodies) != 1 {
+		t.Fatalf("got %d posts, want 1", len(fake.bodies))
+	}
+	if got, want := fake.bodies[0]["text"], "[ERROR] payments: charge failed\norder=A1 amount=5"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+}
+
+func TestChatProvider_TemplateAndPlatforms(t *testing.T) {
+	fake := &fakeWebhook{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	tmpl := "{{.Level}} {{.Message}} for {{index .Fields \"user.id\"}}"
+	logger, err := NewLogger(
+		WithChatProvider(srv.URL, WarnLevel, ChatConfig{Platform: ChatDiscord, Template: tmpl}),
+		WithChatProvider(srv.URL, WarnLevel, ChatConfig{Platform: ChatTeams, Template: tmpl}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Warn("quota low", Dict("user", Int("id", 7)))
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.bodies) != 2 {
+		t.Fatalf("got %d posts, want 2", len(fake.bodies))
+	}
+	if got := fake.bodies[0]["content"]; got != "WARN quota low for 7" {
+		t.Errorf("discord content = %q", got)
+	}
+	card := fake.bodies[1]["attachments"].([]interface{})[0].(map[string]interface{})["content"].(map[string]interface{})
+	block := card["body"].([]interface{})[0].(map[string]interface{})
+	if card["type"] != "AdaptiveCard" || block["text"] != "WARN quota low for 7" {
+		t.Errorf("teams card = %v", card)
+	}
+}
+
+func TestChatProvider_RateLimit(t *testing.T) {
+	fake := &fakeWebhook{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithChatProvider(srv.URL, ErrorLevel, ChatConfig{RateLimit: 2, RatePeriod: 200 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	for i := 0; i < 5; i++ {
+		logger.Error("storm")
+	}
+	if n := logger.DroppedEntries()[DropRateLimited]; n != 3 {
+		t.Errorf("rate limited drops = %d, want 3", n)
+	}
+	time.Sleep(250 * time.Millisecond)
+	logger.Error("after")
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.bodies) != 3 {
+		t.Fatalf("got %d posts, want 3", len(fake.bodies))
+	}
+	if got := fake.bodies[2]["text"].(string); !strings.HasPrefix(got, "(3 earlier alerts suppressed") || !strings.HasSuffix(got, "after") {
+		t.Errorf("text = %q", got)
+	}
+}
+
+func TestChatProvider_TooManyRequests(t *testing.T) {
+	fake := &fakeWebhook{status: http.StatusTooManyRequests}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithChatProvider(srv.URL, ErrorLevel, ChatConfig{}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Error("first")
+	logger.Error("second")
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.bodies) != 1 {
+		t.Errorf("got %d posts, want 1 while paused", len(fake.bodies))
+	}
+	drops := logger.DroppedEntries()
+	if drops[DropProviderError] != 1 || drops[DropRateLimited] != 1 {
+		t.Errorf("drops = %v", drops)
+	}
+}
+
+func TestChatProvider_Errors(t *testing.T) {
+	for _, cfg := range []ChatConfig{{Platform: "irc"}, {Template: "{{.Nope"}} {
+		if _, err := NewLogger(WithChatProvider("https://hooks.slack.com/x", ErrorLevel, cfg)); err == nil || !strings.Contains(err.Error(), "chat provider") {
+			t.Errorf("%+v: expected error, got %v", cfg, err)
+		}
+	}
+	if _, err := NewLogger(WithChatProvider("not a url", ErrorLevel, ChatConfig{})); err == nil {
+		t.Error("expected an invalid URL error")
+	}
+	for url, want := range map[string]ChatPlatform{
+		"https://hooks.slack.com/services/T/B/x":                     ChatSlack,
+		"https://discord.com/api/webhooks/1/x":                       ChatDiscord,
+		"https://acme.webhook.office.com/webhookb2/x":                ChatTeams,
+		"https://prod-1.westus.logic.azure.com/workflows/x/triggers": ChatTeams,
+		"https://chat.example.com/hooks/x":                           ChatSlack,
+	} {
+		if got := chatPlatformOf(url); got != want {
+			t.Errorf("chatPlatformOf(%s) = %s, want %s", url, got, want)
+		}
+	}
+}
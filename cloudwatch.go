@@ -0,0 +1,413 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          AWS CloudWatch Logs Provider                       */
+/* -------------------------------------------------------------------------- */
+
+// CloudWatch Logs limits on a PutLogEvents call.
+const (
+	cloudWatchMaxEvents     = 10000
+	cloudWatchMaxBatchBytes = 1 << 20
+	cloudWatchMaxEventBytes = 256 << 10
+	// cloudWatchEventOverhead is added to each message's size.
+	cloudWatchEventOverhead = 26
+	cloudWatchMaxSpan       = 24 * time.Hour
+)
+
+// CloudWatchConfig configures WithCloudWatchProvider. Zero values fall back
+// to the defaults noted on each field.
+type CloudWatchConfig struct {
+	// Region is the AWS region (default AWS_REGION, then
+	// AWS_DEFAULT_REGION).
+	Region string
+	// Credentials signs each request (default AWS_ACCESS_KEY_ID,
+	// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, read per request).
+	Credentials AWSCredentialsProvider
+	// Endpoint overrides https://logs.<region>.amazonaws.com, e.g. for a VPC
+	// endpoint or LocalStack.
+	Endpoint string
+	// CreateStream creates the log group and stream when they do not exist.
+	// It needs the logs:CreateLogGroup and logs:CreateLogStream permissions.
+	CreateStream bool
+	// BatchSize is the number of entries per PutLogEvents call (default
+	// 1000, at most 10000). Batches are also sent before exceeding the 1 MiB
+	// request limit.
+	BatchSize int
+	// FlushInterval sends a partial batch after this long (default 5s).
+	FlushInterval time.Duration
+	// Timeout bounds each call, retries after a stale sequence token
+	// included (default 10s).
+	Timeout time.Duration
+	// TLS and ProxyURL configure the connection as for HTTPConfig.
+	TLS      *TLSConfig
+	ProxyURL string
+}
+
+// WithCloudWatchProvider sends entries to the CloudWatch Logs stream of the
+// log group group, JSON-encoded unless WithProviderEncoder says otherwise:
+//
+//	golog.WithCloudWatchProvider("/app/api", hostname, golog.CloudWatchConfig{
+//		Region:       "eu-west-1",
+//		CreateStream: true,
+//	})
+//
+// Entries are batched by count, size and age and sent in time order. The
+// sequence token is tracked across calls and refreshed when CloudWatch
+// rejects it. Entries larger than the 256 KiB event limit are dropped and
+// counted as DropOversized; failed batches are dropped and counted as
+// DropProviderError, so combine with WithSpool or WithRetry for stronger
+// delivery.
+func WithCloudWatchProvider(group, stream string, cfg CloudWatchConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&cloudWatchProvider{
+			providerBase: providerBase{name: "cloudwatch:" + group + "/" + stream, encoders: anyEncoder},
+			group:        group,
+			stream:       stream,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+func (c CloudWatchConfig) withDefaults() CloudWatchConfig {
+	if c.Region == "" {
+		c.Region = envAWSRegion()
+	}
+	if c.Credentials == nil {
+		c.Credentials = envAWSCredentials
+	}
+	if c.Endpoint == "" && c.Region != "" {
+		c.Endpoint = "https://logs." + c.Region + ".amazonaws.com"
+		if strings.HasPrefix(c.Region, "cn-") {
+			c.Endpoint += ".cn"
+		}
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 1000
+	}
+	if c.BatchSize > cloudWatchMaxEvents {
+		c.BatchSize = cloudWatchMaxEvents
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = 5 * time.Second
+	}
+	return c
+}
+
+type cloudWatchProvider struct {
+	providerBase
+	group, stream string
+	cfg           CloudWatchConfig
+
+	batch *batcher[cloudWatchEvent]
+}
+
+func (p *cloudWatchProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	if p.group == "" || p.stream == "" {
+		return nil, errors.New("cloudwatch provider: log group and stream are required")
+	}
+	cfg := p.cfg.withDefaults()
+	if cfg.Region == "" {
+		return nil, errors.New("cloudwatch provider: no region; set CloudWatchConfig.Region or AWS_REGION")
+	}
+	if u, err := url.Parse(cfg.Endpoint); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("cloudwatch provider: invalid endpoint %q", cfg.Endpoint)
+	}
+	sender, err := newHTTPSender(HTTPConfig{TLS: cfg.TLS, ProxyURL: cfg.ProxyURL, Timeout: cfg.Timeout})
+	if err != nil {
+		return nil, fmt.Errorf("cloudwatch provider: %w", err)
+	}
+	enc, err := p.tel.buildEncoder(p.encoder(), p.encoderConfig)
+	if err != nil {
+		return nil, err
+	}
+	client := &cloudWatchClient{
+		cfg:     cfg,
+		group:   p.group,
+		stream:  p.stream,
+		http:    sender.client,
+		timeout: sender.cfg.Timeout,
+		report:  p.report,
+	}
+	p.batch = &batcher[cloudWatchEvent]{
+		limit:    cfg.BatchSize,
+		maxBytes: cloudWatchMaxBatchBytes,
+		measure:  measureCloudWatchEvent,
+		send:     client.sendEvents,
+		drops:    p.dropped,
+		report:   p.report,
+	}
+	p.batch.start(cfg.FlushInterval)
+	return &cloudWatchCore{LevelEnabler: level, enc: enc, batch: p.batch}, nil
+}
+
+func (p *cloudWatchProvider) close() error {
+	if p.batch == nil {
+		return nil
+	}
+	return p.batch.close()
+}
+
+func (p *cloudWatchProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: p.encoder()}
+}
+
+// cloudWatchCore encodes entries into events for the batch.
+type cloudWatchCore struct {
+	zapcore.LevelEnabler
+	enc   zapcore.Encoder
+	batch *batcher[cloudWatchEvent]
+}
+
+func (c *cloudWatchCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &cloudWatchCore{LevelEnabler: c.LevelEnabler, enc: enc, batch: c.batch}
+}
+
+func (c *cloudWatchCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *cloudWatchCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSuffix(buf.String(), "\n")
+	buf.Free()
+	return c.batch.add(cloudWatchEvent{Timestamp: ent.Time.UnixMilli(), Message: msg})
+}
+
+func (c *cloudWatchCore) Sync() error { return c.batch.Sync() }
+
+/* -------------------------------------------------------------------------- */
+/*                               Event Batching                                */
+/* -------------------------------------------------------------------------- */
+
+type cloudWatchEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// measureCloudWatchEvent sizes e as PutLogEvents counts it, rejecting
+// events over the event limit.
+func measureCloudWatchEvent(e cloudWatchEvent) (int, error) {
+	size := len(e.Message) + cloudWatchEventOverhead
+	if size > cloudWatchMaxEventBytes {
+		return 0, fmt.Errorf("cloudwatch: entry of %d bytes exceeds the %d byte event limit", len(e.Message), cloudWatchMaxEventBytes-cloudWatchEventOverhead)
+	}
+	return size, nil
+}
+
+// sendEvents puts a batch of events in time order, as PutLogEvents
+// requires, with one call per run of events spanning at most 24 hours. The
+// number of events lost is returned in a batchDropError.
+func (c *cloudWatchClient) sendEvents(events []cloudWatchEvent) error {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp < events[j].Timestamp })
+	var (
+		errs []error
+		lost int
+	)
+	for len(events) > 0 {
+		n := 1
+		for n < len(events) && events[n].Timestamp-events[0].Timestamp <= cloudWatchMaxSpan.Milliseconds() {
+			n++
+		}
+		if err := c.putLogEvents(events[:n]); err != nil {
+			lost += n
+			errs = append(errs, err)
+		}
+		events = events[n:]
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &batchDropError{n: lost, err: errors.Join(errs...)}
+}
+
+/* -------------------------------------------------------------------------- */
+/*                           CloudWatch Logs API Client                        */
+/* -------------------------------------------------------------------------- */
+
+type cloudWatchClient struct {
+	cfg           CloudWatchConfig
+	group, stream string
+	http          *http.Client
+	timeout       time.Duration
+	report        func(error)
+
+	// token is the sequence token for the next call; the batcher serialises
+	// calls.
+	token string
+}
+
+// cloudWatchError is an error response of the CloudWatch Logs API.
+type cloudWatchError struct {
+	Status  int
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+	// ExpectedSequenceToken accompanies InvalidSequenceTokenException and
+	// DataAlreadyAcceptedException.
+	ExpectedSequenceToken string `json:"expectedSequenceToken"`
+}
+
+func (e *cloudWatchError) Error() string {
+	return fmt.Sprintf("cloudwatch: %d %s: %s", e.Status, e.Type, e.Message)
+}
+
+// putLogEvents sends events, refreshing a stale sequence token and, with
+// CreateStream, creating a missing group and stream.
+func (c *cloudWatchClient) putLogEvents(events []cloudWatchEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	created := false
+	for attempt := 0; attempt < 3; attempt++ {
+		req := struct {
+			LogGroupName  string            `json:"logGroupName"`
+			LogStreamName string            `json:"logStreamName"`
+			LogEvents     []cloudWatchEvent `json:"logEvents"`
+			SequenceToken string            `json:"sequenceToken,omitempty"`
+		}{c.group, c.stream, events, c.token}
+		var resp struct {
+			NextSequenceToken     string          `json:"nextSequenceToken"`
+			RejectedLogEventsInfo json.RawMessage `json:"rejectedLogEventsInfo"`
+		}
+		err := c.call(ctx, "PutLogEvents", req, &resp)
+		if err == nil {
+			c.token = resp.NextSequenceToken
+			if len(resp.RejectedLogEventsInfo) > 0 {
+				c.report(fmt.Errorf("cloudwatch: events rejected as too old, too new or expired: %s", resp.RejectedLogEventsInfo))
+			}
+			return nil
+		}
+		var apiErr *cloudWatchError
+		if !errors.As(err, &apiErr) {
+			return err
+		}
+		switch apiErr.Type {
+		case "InvalidSequenceTokenException":
+			c.token = apiErr.ExpectedSequenceToken
+			continue
+		case "DataAlreadyAcceptedException":
+			c.token = apiErr.ExpectedSequenceToken
+			return nil
+		case "ResourceNotFoundException":
+			if c.cfg.CreateStream && !created {
+				if err := c.createStream(ctx); err != nil {
+					return err
+				}
+				created = true
+				continue
+			}
+		}
+		return classifyCloudWatchError(apiErr)
+	}
+	return errors.New("cloudwatch: sequence token kept changing")
+}
+
+// createStream creates the log group and stream, tolerating existing ones.
+func (c *cloudWatchClient) createStream(ctx context.Context) error {
+	calls := []struct {
+		action string
+		req    interface{}
+	}{
+		{"CreateLogGroup", map[string]string{"logGroupName": c.group}},
+		{"CreateLogStream", map[string]string{"logGroupName": c.group, "logStreamName": c.stream}},
+	}
+	for _, call := range calls {
+		err := c.call(ctx, call.action, call.req, nil)
+		var apiErr *cloudWatchError
+		if errors.As(err, &apiErr) && apiErr.Type == "ResourceAlreadyExistsException" {
+			continue
+		}
+		if err != nil {
+			return classifyCloudWatchError(err)
+		}
+	}
+	c.token = ""
+	return nil
+}
+
+// classifyCloudWatchError marks client errors other than throttling as
+// permanent.
+func classifyCloudWatchError(err error) error {
+	var apiErr *cloudWatchError
+	if errors.As(err, &apiErr) && apiErr.Status/100 == 4 && apiErr.Type != "ThrottlingException" {
+		return PermanentError(err)
+	}
+	return err
+}
+
+// call invokes action with the JSON request req and decodes the response
+// into resp, if not nil.
+func (c *cloudWatchClient) call(ctx context.Context, action string, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return PermanentError(fmt.Errorf("cloudwatch: %w", err))
+	}
+	creds, err := c.cfg.Credentials(ctx)
+	if err != nil {
+		return fmt.Errorf("cloudwatch: credentials: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return PermanentError(fmt.Errorf("cloudwatch: %w", err))
+	}
+	httpReq.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	httpReq.Header.Set("X-Amz-Target", "Logs_20140328."+action)
+	signAWSRequest(httpReq, body, creds, c.cfg.Region, "logs", time.Now())
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("cloudwatch: %w", err)
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("cloudwatch: %w", err)
+	}
+	if httpResp.StatusCode/100 != 2 {
+		apiErr := &cloudWatchError{Status: httpResp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Type == "" {
+			apiErr.Type = httpResp.Header.Get("X-Amzn-ErrorType")
+			apiErr.Message = string(bytes.TrimSpace(data))
+		}
+		// Types may carry a namespace, e.g. "com.amazonaws.logs#…".
+		if i := strings.LastIndexByte(apiErr.Type, '#'); i >= 0 {
+			apiErr.Type = apiErr.Type[i+1:]
+		}
+		if i := strings.IndexByte(apiErr.Type, ':'); i >= 0 {
+			apiErr.Type = apiErr.Type[:i]
+		}
+		return apiErr
+	}
+	if resp == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("cloudwatch: invalid response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package golog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCloudWatch implements enough of the CloudWatch Logs API for the
+// provider: a single stream whose sequence token advances on every put.
+type fakeCloudWatch struct {
+	mu       sync.Mutex
+	exists   bool
+	token    int
+	targets  []string
+	auth     []string
+	events   []cloudWatchEvent
+	sequence []string
+}
+
+func (f *fakeCloudWatch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	target := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "Logs_20140328.")
+	f.targets = append(f.targets, target)
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	fail := func(typ string, extra map[string]string) {
+		body := map[string]string{"__type": "com.amazonaws.logs#" + typ, "message": typ}
+		for k, v := range extra {
+			body[k] = v
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(body)
+	}
+	var req struct {
+		LogEvents     []cloudWatchEvent `json:"logEvents"`
+		SequenceToken string            `json:"sequenceToken"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	switch target {
+	case "CreateLogGroup":
+		fail("ResourceAlreadyExistsException", nil)
+	case "CreateLogStream":
+		f.exists = true
+		w.Write([]byte("{}"))
+	case "PutLogEvents":
+		if !f.exists {
+			fail("ResourceNotFoundException", nil)
+			return
+		}
+		expected := ""
+		if f.token > 0 {
+			expected = "token-" + string(rune('0'+f.token))
+		}
+		f.sequence = append(f.sequence, req.SequenceToken)
+		if req.SequenceToken != expected {
+			fail("InvalidSequenceTokenException", map[string]string{"expectedSequenceToken": expected})
+			return
+		}
+		f.events = append(f.events, req.LogEvents...)
+		f.token++
+		json.NewEncoder(w).Encode(map[string]string{"nextSequenceToken": "token-" + string(rune('0'+f.token))})
+	default:
+		fail("UnknownOperationException", nil)
+	}
+}
+
+func testCloudWatchConfig(url string) CloudWatchConfig {
+	return CloudWatchConfig{
+		Region:        "us-east-1",
+		Endpoint:      url,
+		FlushInterval: time.Hour,
+		Credentials:   testAWSCredentials,
+	}
+}
+
+func TestCloudWatchProvider_CreatesStreamAndTracksTokens(t *testing.T) {
+	fake := &fakeCloudWatch{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	cfg := testCloudWatchConfig(srv.URL)
+	cfg.CreateStream = true
+	cfg.BatchSize = 2
+	logger, err := NewLogger(WithCloudWatchProvider("/app", "host-1", cfg))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	want := "PutLogEvents,CreateLogGroup,CreateLogStream,PutLogEvents,PutLogEvents"
+	if got := strings.Join(fake.targets, ","); got != want {
+		t.Errorf("targets = %s, want %s", got, want)
+	}
+	for _, auth := range fake.auth {
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/us-east-1/logs/aws4_request") {
+			t.Errorf("unexpected Authorization header %q", auth)
+		}
+	}
+	if got := strings.Join(fake.sequence, ","); got != ",token-1" {
+		t.Errorf("sequence tokens = %q", got)
+	}
+	if len(fake.events) != 3 {
+		t.Fatalf("got %d events, want 3", len(fake.events))
+	}
+	for i, msg := range []string{"one", "two", "three"} {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(fake.events[i].Message), &entry); err != nil {
+			t.Fatalf("event %d is not JSON: %q", i, fake.events[i].Message)
+		}
+		if entry["msg"] != msg || fake.events[i].Timestamp == 0 {
+			t.Errorf("event %d = %+v", i, fake.events[i])
+		}
+	}
+}
+
+func TestCloudWatchProvider_RefreshesSequenceToken(t *testing.T) {
+	fake := &fakeCloudWatch{exists: true, token: 3}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithCloudWatchProvider("/app", "host-1", testCloudWatchConfig(srv.URL)))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("hello")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if got := strings.Join(fake.sequence, ","); got != ",token-3" {
+		t.Errorf("sequence tokens = %q", got)
+	}
+	if len(fake.events) != 1 {
+		t.Errorf("got %d events, want 1", len(fake.events))
+	}
+}
+
+func TestCloudWatchProvider_MissingStreamIsPermanent(t *testing.T) {
+	srv := httptest.NewServer(&fakeCloudWatch{})
+	defer srv.Close()
+
+	logger, err := NewLogger(WithCloudWatchProvider("/app", "host-1", testCloudWatchConfig(srv.URL)))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Info("lost")
+	err = logger.Sync()
+	if err == nil || !strings.Contains(err.Error(), "ResourceNotFoundException") || isRetryable(err) {
+		t.Errorf("expected permanent ResourceNotFoundException, got %v", err)
+	}
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+}
+
+func TestCloudWatchBatch_Limits(t *testing.T) {
+	var batches [][]cloudWatchEvent
+	var drops int
+	b := &batcher[cloudWatchEvent]{
+		limit:    10,
+		maxBytes: cloudWatchMaxBatchBytes,
+		measure:  measureCloudWatchEvent,
+		send: func(events []cloudWatchEvent) error {
+			batches = append(batches, events)
+			return nil
+		},
+		drops:  func(_ DropReason, n int) { drops += n },
+		report: func(error) {},
+	}
+	b.start(time.Hour)
+
+	if err := b.add(cloudWatchEvent{Message: strings.Repeat("x", cloudWatchMaxEventBytes)}); err == nil || drops != 1 {
+		t.Errorf("oversized event: err=%v drops=%d", err, drops)
+	}
+	big := strings.Repeat("x", 200<<10)
+	// Five 200 KiB events fit in 1 MiB, the sixth starts a new batch.
+	for i := 0; i < 6; i++ {
+		b.add(cloudWatchEvent{Timestamp: int64(10 - i), Message: big})
+	}
+	if err := b.close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 2 || len(batches[0]) != 5 || len(batches[1]) != 1 {
+		t.Fatalf("unexpected batches: %d", len(batches))
+	}
+}
+
+func TestCloudWatchClient_SendEventsSortsAndSplits(t *testing.T) {
+	fake := &fakeCloudWatch{exists: true}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	cfg := testCloudWatchConfig(srv.URL)
+	client := &cloudWatchClient{cfg: cfg, group: "/app", stream: "host-1", http: srv.Client(), timeout: time.Second, report: func(error) {}}
+	later := 10 + cloudWatchMaxSpan.Milliseconds() + 1
+	// Events more than 24 hours apart go in separate calls.
+	err := client.sendEvents([]cloudWatchEvent{{Timestamp: later, Message: "c"}, {Timestamp: 10, Message: "b"}, {Timestamp: 5, Message: "a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.targets) != 2 {
+		t.Errorf("calls = %v, want 2", fake.targets)
+	}
+	if len(fake.events) != 3 || fake.events[0].Message != "a" || fake.events[1].Message != "b" || fake.events[2].Message != "c" {
+		t.Errorf("events not sent in time order: %+v", fake.events)
+	}
+}
+
+func TestCloudWatchProvider_Errors(t *testing.T) {
+	clearAWSRegion(t)
+	if _, err := NewLogger(WithCloudWatchProvider("/app", "", CloudWatchConfig{Region: "us-east-1"})); err == nil {
+		t.Error("expected error for missing stream")
+	}
+	if _, err := NewLogger(WithCloudWatchProvider("/app", "s", CloudWatchConfig{})); err == nil || !strings.Contains(err.Error(), "region") {
+		t.Errorf("expected region error, got %v", err)
+	}
+	if got := (CloudWatchConfig{Region: "cn-north-1"}).withDefaults().Endpoint; got != "https://logs.cn-north-1.amazonaws.com.cn" {
+		t.Errorf("endpoint = %s", got)
+	}
+	if !isRetryable(classifyCloudWatchError(&cloudWatchError{Status: 400, Type: "ThrottlingException"})) {
+		t.Error("throttling must be retryable")
+	}
+}
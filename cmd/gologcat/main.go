@@ -0,0 +1,358 @@
+// Command gologcat pretty-prints golog JSON output – the reading-side
+// counterpart of the JSON encoder.
+//
+//	gologcat [flags] [file ...]
+//
+// It reads the named files, or stdin when none are given, and prints one
+// line per entry with a coloured level, a formatted timestamp, the caller,
+// the message and the remaining fields. Nested objects are expanded on
+// indented lines. Lines that are not JSON objects are passed through
+// unchanged.
+//
+//	-level warn          only show entries at or above warn
+//	-field key=value     only show entries whose field matches (repeatable)
+//	-time layout         Go time layout for timestamps (default RFC3339 with ms)
+//	-color auto|always|never   auto honours NO_COLOR and FORCE_COLOR
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evdnx/golog/internal/term"
+)
+
+// Keys written by golog's JSON encoder, with common alternatives so output
+// from presets and other zap-based loggers renders too.
+var (
+	levelKeys   = []string{"level", "severity"}
+	timeKeys    = []string{"ts", "time", "timestamp"}
+	messageKeys = []string{"msg", "message"}
+	callerKeys  = []string{"caller"}
+	loggerKeys  = []string{"logger"}
+	stackKeys   = []string{"stacktrace", "stack"}
+)
+
+var levelRank = map[string]int{
+	"trace": -2, "debug": -1, "info": 0, "warn": 1, "warning": 1,
+	"error": 2, "dpanic": 3, "panic": 4, "fatal": 5, "critical": 5,
+}
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiDim    = "\x1b[2m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+	ansiPurple = "\x1b[35m"
+	ansiCyan   = "\x1b[36m"
+)
+
+type fieldFilter struct{ key, value string }
+
+type fieldFilters []fieldFilter
+
+func (f *fieldFilters) String() string { return fmt.Sprint(*f) }
+
+func (f *fieldFilters) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	*f = append(*f, fieldFilter{key: key, value: value})
+	return nil
+}
+
+type options struct {
+	minLevel   string
+	fields     fieldFilters
+	timeLayout string
+	color      bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gologcat", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		opts  options
+		color string
+	)
+	fs.StringVar(&opts.minLevel, "level", "", "minimum level to show (debug, info, warn, error, fatal)")
+	fs.Var(&opts.fields, "field", "only show entries with `key=value` (repeatable)")
+	fs.StringVar(&opts.timeLayout, "time", "2006-01-02T15:04:05.000Z07:00", "Go time `layout` for timestamps")
+	fs.StringVar(&color, "color", "auto", "colorize output: auto, always or never")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if opts.minLevel != "" {
+		if _, ok := levelRank[strings.ToLower(opts.minLevel)]; !ok {
+			fmt.Fprintf(stderr, "gologcat: unknown level %q\n", opts.minLevel)
+			return 2
+		}
+	}
+	switch color {
+	case "always":
+		opts.color = true
+		if f, ok := stdout.(*os.File); ok {
+			term.EnableVirtualTerminal(f)
+		}
+	case "never":
+	case "auto":
+		if f, ok := stdout.(*os.File); ok {
+			opts.color = term.ColorEnabled(f, os.Getenv)
+		} else {
+			opts.color, _ = term.ForceColor(os.Getenv)
+		}
+	default:
+		fmt.Fprintf(stderr, "gologcat: -color must be auto, always or never\n")
+		return 2
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+
+	if fs.NArg() == 0 {
+		if err := process(stdin, out, opts); err != nil {
+			fmt.Fprintf(stderr, "gologcat: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	status := 0
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "gologcat: %v\n", err)
+			status = 1
+			continue
+		}
+		err = process(f, out, opts)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(stderr, "gologcat: %s: %v\n", name, err)
+			status = 1
+		}
+	}
+	return status
+}
+
+func process(r io.Reader, w *bufio.Writer, opts options) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var entry map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		if err := dec.Decode(&entry); err != nil || entry == nil {
+			w.Write(line)
+			w.WriteByte('\n')
+			continue
+		}
+		if !keep(entry, opts) {
+			continue
+		}
+		format(w, entry, opts)
+	}
+	return scanner.Err()
+}
+
+func keep(entry map[string]interface{}, opts options) bool {
+	if opts.minLevel != "" {
+		lvl, _ := take(entry, levelKeys, false).(string)
+		rank, ok := levelRank[strings.ToLower(lvl)]
+		if !ok || rank < levelRank[strings.ToLower(opts.minLevel)] {
+			return false
+		}
+	}
+	for _, f := range opts.fields {
+		v, ok := lookup(entry, f.key)
+		if !ok || text(v) != f.value {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup resolves dotted keys ("http.status") through nested objects.
+func lookup(entry map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := entry[key]; ok {
+		return v, true
+	}
+	head, rest, ok := strings.Cut(key, ".")
+	if !ok {
+		return nil, false
+	}
+	nested, ok := entry[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookup(nested, rest)
+}
+
+// take returns the first present key of keys, removing it when remove is set.
+func take(entry map[string]interface{}, keys []string, remove bool) interface{} {
+	for _, k := range keys {
+		if v, ok := entry[k]; ok {
+			if remove {
+				delete(entry, k)
+			}
+			return v
+		}
+	}
+	return nil
+}
+
+func format(w *bufio.Writer, entry map[string]interface{}, opts options) {
+	paint := func(code, s string) string {
+		if !opts.color || s == "" {
+			return s
+		}
+		return code + s + ansiReset
+	}
+
+	ts := formatTime(take(entry, timeKeys, true), opts.timeLayout)
+	lvl, _ := take(entry, levelKeys, true).(string)
+	msg := text(take(entry, messageKeys, true))
+	caller := text(take(entry, callerKeys, true))
+	name := text(take(entry, loggerKeys, true))
+	stack := text(take(entry, stackKeys, true))
+
+	var parts []string
+	if ts != "" {
+		parts = append(parts, paint(ansiDim, ts))
+	}
+	parts = append(parts, paint(levelColor(lvl), fmt.Sprintf("%-5s", strings.ToUpper(lvl))))
+	if name != "" {
+		parts = append(parts, paint(ansiBlue, name))
+	}
+	if caller != "" {
+		parts = append(parts, paint(ansiDim, caller))
+	}
+	parts = append(parts, paint(ansiBold, msg))
+
+	keys := make([]string, 0, len(entry))
+	for k := range entry {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var nested []string
+	for _, k := range keys {
+		if m, ok := entry[k].(map[string]interface{}); ok && len(m) > 0 {
+			nested = append(nested, k)
+			continue
+		}
+		parts = append(parts, paint(ansiCyan, k)+"="+scalar(entry[k]))
+	}
+	w.WriteString(strings.Join(parts, " "))
+	w.WriteByte('\n')
+	for _, k := range nested {
+		writeNested(w, k, entry[k].(map[string]interface{}), 1, paint)
+	}
+	if stack != "" {
+		for _, line := range strings.Split(stack, "\n") {
+			w.WriteString("    " + paint(ansiDim, line) + "\n")
+		}
+	}
+}
+
+func writeNested(w *bufio.Writer, key string, m map[string]interface{}, depth int, paint func(string, string) string) {
+	indent := strings.Repeat("    ", depth)
+	w.WriteString(indent + paint(ansiCyan, key) + ":\n")
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if sub, ok := m[k].(map[string]interface{}); ok && len(sub) > 0 {
+			writeNested(w, k, sub, depth+1, paint)
+			continue
+		}
+		w.WriteString(indent + "    " + paint(ansiCyan, k) + "=" + scalar(m[k]) + "\n")
+	}
+}
+
+func levelColor(lvl string) string {
+	switch strings.ToLower(lvl) {
+	case "trace", "debug":
+		return ansiPurple
+	case "info":
+		return ansiGreen
+	case "warn", "warning":
+		return ansiYellow
+	default:
+		return ansiRed
+	}
+}
+
+// formatTime renders epoch seconds (zap's default "ts") or RFC 3339 strings
+// using layout; anything else is printed as is.
+func formatTime(v interface{}, layout string) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case json.Number:
+		f, err := t.Float64()
+		if err != nil {
+			return t.String()
+		}
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(frac*1e9)).Format(layout)
+	case string:
+		if parsed, err := time.Parse(time.RFC3339Nano, t); err == nil {
+			return parsed.Format(layout)
+		}
+		return t
+	default:
+		return scalar(t)
+	}
+}
+
+// text renders strings verbatim and other values as scalar does.
+func text(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return scalar(v)
+}
+
+// scalar renders a decoded JSON value on one line, quoting strings that
+// would otherwise be ambiguous in key=value output.
+func scalar(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if strings.ContainsAny(x, " \t\n\"=") {
+			b, _ := json.Marshal(x)
+			return string(b)
+		}
+		return x
+	case json.Number:
+		return x.String()
+	case bool:
+		return fmt.Sprint(x)
+	default:
+		b, err := json.Marshal(x)
+		if err != nil {
+			return fmt.Sprint(x)
+		}
+		return string(b)
+	}
+}
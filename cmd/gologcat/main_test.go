@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const sample = `{"level":"debug","ts":1700000000.5,"caller":"app/main.go:10","msg":"starting"}
+{"level":"info","ts":1700000001,"caller":"app/main.go:12","msg":"request served","http":{"method":"GET","status":200},"user":"ann"}
+not json at all
+{"level":"error","ts":"2023-11-14T22:13:22Z","msg":"boom","error":"disk on fire","user":"bob","stacktrace":"main.main\n\tapp/main.go:20"}
+`
+
+func TestRun_PrettyPrint(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := run([]string{"-color", "never", "-time", "15:04:05"}, strings.NewReader(sample), &out, &errOut); code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut.String())
+	}
+	got := out.String()
+	for _, want := range []string{
+		"INFO  app/main.go:12 request served user=ann\n    http:\n        method=GET\n        status=200\n",
+		"not json at all\n",
+		`ERROR boom error="disk on fire" user=bob`,
+		"    main.main\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("unexpected colour codes with -color never")
+	}
+}
+
+func TestRun_Filters(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := run([]string{"-color", "never", "-level", "info", "-field", "http.status=200"}, strings.NewReader(sample), &out, &errOut); code != 0 {
+		t.Fatalf("exit %d: %s", code, errOut.String())
+	}
+	got := out.String()
+	if !strings.Contains(got, "request served") || strings.Contains(got, "starting") || strings.Contains(got, "boom") {
+		t.Errorf("unexpected filtered output:\n%s", got)
+	}
+}
+
+func TestRun_Color(t *testing.T) {
+	var out, errOut bytes.Buffer
+	run([]string{"-color", "always"}, strings.NewReader(sample), &out, &errOut)
+	if !strings.Contains(out.String(), ansiRed+"ERROR"+ansiReset) {
+		t.Errorf("expected coloured level:\n%q", out.String())
+	}
+	if code := run([]string{"-level", "loud"}, strings.NewReader(""), &out, &errOut); code != 2 {
+		t.Errorf("expected usage error for unknown level, got %d", code)
+	}
+}
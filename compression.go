@@ -0,0 +1,78 @@
+package golog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                        Payload Compression for HTTP                         */
+/* -------------------------------------------------------------------------- */
+
+// Compression is an HTTP Content-Encoding applied to request bodies.
+type Compression string
+
+const (
+	NoCompression   Compression = ""
+	GzipCompression Compression = "gzip"
+	ZstdCompression Compression = "zstd"
+)
+
+func (c Compression) validate() error {
+	switch c {
+	case NoCompression, GzipCompression, ZstdCompression:
+		return nil
+	default:
+		return fmt.Errorf("unsupported compression %q", string(c))
+	}
+}
+
+// zstdEncoder is shared; EncodeAll is safe for concurrent use.
+var zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+	return zstd.NewWriter(nil)
+})
+
+func (c Compression) compress(body []byte) ([]byte, error) {
+	switch c {
+	case GzipCompression:
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(body); err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		return buf.Bytes(), nil
+	case ZstdCompression:
+		enc, err := zstdEncoder()
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+		return enc.EncodeAll(body, make([]byte, 0, len(body)/4)), nil
+	default:
+		return body, nil
+	}
+}
+
+// fallback picks the encoding to use after the server rejected c. accept is
+// the rejecting response's Accept-Encoding header (RFC 7694); encodings in
+// tried, which includes c, are skipped. Without a usable entry the body is
+// sent uncompressed.
+func (c Compression) fallback(accept string, tried map[Compression]bool) Compression {
+	for _, part := range strings.Split(accept, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		switch candidate := Compression(strings.ToLower(name)); candidate {
+		case GzipCompression, ZstdCompression:
+			if candidate != c && !tried[candidate] {
+				return candidate
+			}
+		}
+	}
+	return NoCompression
+}
@@ -0,0 +1,117 @@
+package golog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestCompression_RoundTrip(t *testing.T) {
+	body := bytes.Repeat([]byte(`{"level":"info","msg":"hello"}`+"\n"), 50)
+
+	gz, err := GzipCompression.compress(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(gz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := io.ReadAll(zr); !bytes.Equal(got, body) || len(gz) >= len(body) {
+		t.Errorf("gzip round trip failed (%d -> %d bytes)", len(body), len(gz))
+	}
+
+	zs, err := ZstdCompression.compress(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, _ := zstd.NewReader(nil)
+	defer dec.Close()
+	if got, err := dec.DecodeAll(zs, nil); err != nil || !bytes.Equal(got, body) || len(zs) >= len(body) {
+		t.Errorf("zstd round trip failed: %v", err)
+	}
+}
+
+func TestCompression_Negotiation(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		encodings []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := r.Header.Get("Content-Encoding")
+		mu.Lock()
+		encodings = append(encodings, enc)
+		mu.Unlock()
+		if enc != "" && enc != "gzip" {
+			w.Header().Set("Accept-Encoding", "gzip")
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		}
+	}))
+	defer srv.Close()
+
+	sender, err := newHTTPSender(HTTPConfig{Compression: ZstdCompression})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := sender.post(srv.URL, "application/x-ndjson", []byte("{}\n")); err != nil {
+			t.Fatalf("post %d: %v", i, err)
+		}
+	}
+	// zstd is rejected once; gzip is remembered for later batches.
+	want := []string{"zstd", "gzip", "gzip"}
+	if len(encodings) != len(want) {
+		t.Fatalf("unexpected requests: %q", encodings)
+	}
+	for i := range want {
+		if encodings[i] != want[i] {
+			t.Errorf("request %d: got %q, want %q", i, encodings[i], want[i])
+		}
+	}
+
+	if Compression("br").validate() == nil {
+		t.Error("expected unsupported compression to be rejected")
+	}
+}
+
+func TestCompression_NegotiationGivesUp(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		encodings []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := r.Header.Get("Content-Encoding")
+		mu.Lock()
+		encodings = append(encodings, enc)
+		mu.Unlock()
+		if enc != "" {
+			w.Header().Set("Accept-Encoding", "gzip, zstd")
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		}
+	}))
+	defer srv.Close()
+
+	sender, err := newHTTPSender(HTTPConfig{Compression: GzipCompression})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sender.post(srv.URL, "application/x-ndjson", []byte("{}\n")); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	// Both advertised encodings are rejected once, then the body goes plain.
+	want := []string{"gzip", "zstd", ""}
+	if len(encodings) != len(want) {
+		t.Fatalf("unexpected requests: %q", encodings)
+	}
+	for i := range want {
+		if encodings[i] != want[i] {
+			t.Errorf("request %d: got %q, want %q", i, encodings[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package golog
+
+import (
+	"errors"
+	"time"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                       Configuration Snapshot & Cloning                      */
+/* -------------------------------------------------------------------------- */
+
+// Config is a snapshot of a logger's effective configuration, as returned by
+// Logger.Config.
+type Config struct {
+	Level Level
+	// Providers lists the providers the logger writes to, including routed
+	// ones and those attached with AddProvider.
+	Providers      []ProviderInfo
+	RingBufferSize int
+	// Sampling is nil when sampling is off.
+	Sampling    *SamplingConfig
+	SequenceKey string
+	LogID       bool
+	GoroutineID bool
+	Caller      bool
+	CallerSkip  int
+	// Stacktrace is the level from which entries carry a stack trace, nil
+	// when they never do.
+	Stacktrace        *Level
+	Filters           int
+	Transforms        int
+	Enrichers         int
+	Development       bool
+	SchemaValidation  bool
+	DeadLetterFile    string
+	FatalFlushTimeout time.Duration
+}
+
+// SamplingConfig mirrors the arguments of WithSampling.
+type SamplingConfig struct {
+	Tick       time.Duration `yaml:"tick"`
+	First      int           `yaml:"first"`
+	Thereafter int           `yaml:"thereafter"`
+}
+
+// snapshot records the settings of cfg that do not change after
+// construction.
+func (cfg *loggerConfig) snapshot() Config {
+	c := Config{
+		RingBufferSize:    cfg.ringBufferSize,
+		SequenceKey:       cfg.sequenceKey,
+		LogID:             cfg.logID,
+		GoroutineID:       cfg.goroutineID,
+		Caller:            !cfg.withoutCaller,
+		CallerSkip:        cfg.callerSkip,
+		Filters:           len(cfg.filters),
+		Transforms:        len(cfg.transforms),
+		Enrichers:         len(cfg.enrichers),
+		Development:       cfg.development,
+		SchemaValidation:  cfg.schema != nil,
+		FatalFlushTimeout: cfg.fatalFlushTimeout,
+	}
+	if cfg.stacktrace != nil {
+		lvl := *cfg.stacktrace
+		c.Stacktrace = &lvl
+	}
+	if sc := cfg.sampling; sc != nil {
+		c.Sampling = &SamplingConfig{Tick: sc.tick, First: sc.first, Thereafter: sc.thereafter}
+	}
+	if cfg.deadLetter != nil {
+		c.DeadLetterFile = cfg.deadLetter.Filename
+	}
+	return c
+}
+
+// Config returns the logger's effective configuration. Children report
+// the configuration of the logger they were derived from.
+func (l *Logger) Config() Config {
+	if l.root != nil {
+		return l.root.Config()
+	}
+	c := l.config
+	c.Level = l.Level()
+	c.Providers = l.providerInfos()
+	return c
+}
+
+// CloneWith builds a new logger from the options l was built with, those
+// later passed to AddProvider and then options, so later settings win:
+//
+//	debug, err := logger.CloneWith(golog.WithLevel(golog.DebugLevel))
+//	audit, err := logger.CloneWith(golog.WithFileProvider("audit.log", 10, 3, 28, true))
+//
+// The clone creates its own provider instances – files, connections,
+// subprocesses – and must be closed separately; avoid pointing two live
+// loggers at the same rotating file. Loggers returned by Tee cannot be
+// cloned.
+func (l *Logger) CloneWith(options ...LoggerOption) (*Logger, error) {
+	if l.root != nil {
+		return l.root.CloneWith(options...)
+	}
+	if l.options == nil {
+		return nil, errors.New("CloneWith: logger was not built from options")
+	}
+	all := append([]LoggerOption(nil), l.options...)
+	all = append(all, l.added.optionList()...)
+	return NewLogger(append(all, options...)...)
+}
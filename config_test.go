@@ -0,0 +1,72 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigSnapshot(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(
+		WithLevel(WarnLevel),
+		WithWriterProvider(&buf, JSONEncoder),
+		WithRingBuffer(16),
+		WithSampling(time.Second, 10, 5),
+		WithSequence("seq"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	cfg := logger.Named("child").Config()
+	if cfg.Level != WarnLevel || cfg.RingBufferSize != 16 || cfg.SequenceKey != "seq" || !cfg.Caller {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.Sampling == nil || *cfg.Sampling != (SamplingConfig{Tick: time.Second, First: 10, Thereafter: 5}) {
+		t.Errorf("unexpected sampling: %+v", cfg.Sampling)
+	}
+	if len(cfg.Providers) != 1 || cfg.Providers[0].Name != "writer" {
+		t.Errorf("unexpected providers: %+v", cfg.Providers)
+	}
+}
+
+func TestCloneWith(t *testing.T) {
+	var host, extra bytes.Buffer
+	logger, err := NewLogger(WithLevel(InfoLevel), WithWriterProvider(&host, JSONEncoder), WithSequence("seq"))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	if err := logger.AddProvider(WithWriterProvider(&extra, JSONEncoder)); err != nil {
+		t.Fatalf("AddProvider: %v", err)
+	}
+
+	clone, err := logger.CloneWith(WithLevel(DebugLevel))
+	if err != nil {
+		t.Fatalf("CloneWith: %v", err)
+	}
+	clone.Debug("clone debug")
+	if err := clone.Close(); err != nil {
+		t.Fatalf("close clone: %v", err)
+	}
+	logger.Debug("original debug")
+
+	for name, out := range map[string]string{"host": host.String(), "added": extra.String()} {
+		if !strings.Contains(out, "clone debug") || !strings.Contains(out, `"seq":1`) {
+			t.Errorf("%s sink missing clone entry: %s", name, out)
+		}
+		if strings.Contains(out, "original debug") {
+			t.Errorf("%s: original logger's level changed: %s", name, out)
+		}
+	}
+	if got := clone.Config(); got.Level != DebugLevel || len(got.Providers) != 2 {
+		t.Errorf("unexpected clone config: %+v", got)
+	}
+
+	if _, err := Tee(logger).CloneWith(); err == nil {
+		t.Error("expected cloning a Tee to fail")
+	}
+}
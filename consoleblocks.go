@@ -0,0 +1,148 @@
+package golog
+
+import (
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                      Multi-line Blocks for Console Output                   */
+/* -------------------------------------------------------------------------- */
+
+// WithConsoleBlocks renders stack traces and multi-line string fields, such
+// as SQL statements or the verbose form of errors, as indented blocks under
+// the entry instead of escaping them into its line:
+//
+//	2024-01-02T03:04:05.000Z	ERROR	db/query.go:42	query failed	{"rows": 0}
+//	    query:
+//	        SELECT *
+//	        FROM users
+//	    stacktrace:
+//	        main.main
+//	        	/src/app/main.go:12
+//
+// It applies to the console encoders, including the development ones; the
+// other encoders keep every entry on one line.
+func WithConsoleBlocks() LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.consoleBlocks = true
+	}
+}
+
+// consoleEncoders are the zap console encoders WithConsoleBlocks wraps.
+var consoleEncoders = map[EncoderType]bool{
+	ConsoleEncoder:          true,
+	colorConsoleEncoder:     true,
+	developmentEncoder:      true,
+	developmentColorEncoder: true,
+}
+
+const blockIndent = "    "
+
+// textBlock is a multi-line value written below the entry.
+type textBlock struct {
+	key, text string
+}
+
+// blockEncoder moves multi-line strings and the stack trace out of the
+// entry line of the console encoder it wraps.
+type blockEncoder struct {
+	zapcore.Encoder
+	// blocks are the multi-line context fields; namespace prefixes the keys
+	// of those added inside a namespace.
+	blocks    []textBlock
+	namespace string
+}
+
+func (e *blockEncoder) Clone() zapcore.Encoder {
+	return &blockEncoder{
+		Encoder:   e.Encoder.Clone(),
+		blocks:    e.blocks[:len(e.blocks):len(e.blocks)],
+		namespace: e.namespace,
+	}
+}
+
+func (e *blockEncoder) AddString(key, value string) {
+	if strings.Contains(value, "\n") {
+		e.blocks = append(e.blocks, textBlock{e.namespace + key, value})
+		return
+	}
+	e.Encoder.AddString(key, value)
+}
+
+func (e *blockEncoder) OpenNamespace(key string) {
+	e.namespace += key + "."
+	e.Encoder.OpenNamespace(key)
+}
+
+func (e *blockEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	blocks := e.blocks[:len(e.blocks):len(e.blocks)]
+	kept := make([]zapcore.Field, 0, len(fields))
+	namespace := e.namespace
+	for _, f := range fields {
+		switch f.Type {
+		case zapcore.NamespaceType:
+			namespace += f.Key + "."
+		case zapcore.StringType:
+			if strings.Contains(f.String, "\n") {
+				blocks = append(blocks, textBlock{namespace + f.Key, f.String})
+				continue
+			}
+		case zapcore.ErrorType, zapcore.StringerType:
+			if rest, found := splitBlocks(f, namespace); found != nil {
+				blocks = append(blocks, found...)
+				kept = append(kept, rest...)
+				continue
+			}
+		}
+		kept = append(kept, f)
+	}
+	if ent.Stack != "" {
+		blocks = append(blocks, textBlock{"stacktrace", ent.Stack})
+		ent.Stack = ""
+	}
+
+	buf, err := e.Encoder.EncodeEntry(ent, kept)
+	if err != nil || len(blocks) == 0 {
+		return buf, err
+	}
+	for _, b := range blocks {
+		buf.AppendString(blockIndent)
+		buf.AppendString(b.key)
+		buf.AppendString(":\n")
+		for _, line := range strings.Split(strings.TrimRight(b.text, "\n"), "\n") {
+			buf.AppendString(blockIndent + blockIndent)
+			buf.AppendString(line)
+			buf.AppendByte('\n')
+		}
+	}
+	return buf, nil
+}
+
+// splitBlocks encodes f and returns its multi-line strings as blocks and its
+// other values as fields, or no blocks if it has no multi-line strings.
+func splitBlocks(f zapcore.Field, namespace string) ([]zapcore.Field, []textBlock) {
+	m := zapcore.NewMapObjectEncoder()
+	f.AddTo(m)
+	keys := make([]string, 0, len(m.Fields))
+	for k := range m.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var (
+		rest   []zapcore.Field
+		blocks []textBlock
+	)
+	for _, k := range keys {
+		if s, ok := m.Fields[k].(string); ok && strings.Contains(s, "\n") {
+			blocks = append(blocks, textBlock{namespace + k, s})
+			continue
+		}
+		rest = append(rest, zap.Any(k, m.Fields[k]))
+	}
+	return rest, blocks
+}
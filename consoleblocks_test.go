@@ -0,0 +1,62 @@
+package golog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// verboseError has a multi-line verbose form, like pkg/errors errors.
+type verboseError struct{}
+
+func (verboseError) Error() string { return "boom" }
+
+func (e verboseError) Format(s fmt.State, verb rune) {
+	if s.Flag('+') {
+		io.WriteString(s, "boom\nmain.go:1\nmain.go:2")
+		return
+	}
+	io.WriteString(s, "boom")
+}
+
+func TestConsoleBlocks(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, ConsoleEncoder), WithConsoleBlocks(), WithStacktrace(ErrorLevel))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.With(String("query", "SELECT *\nFROM users\n")).Error("query failed", Int("rows", 0), Err(verboseError{}))
+
+	out := buf.String()
+	head, blocks, _ := strings.Cut(out, "\n")
+	if !strings.HasSuffix(head, "\tquery failed\t{\"rows\": 0, \"error\": \"boom\"}") {
+		t.Errorf("unexpected entry line: %q", head)
+	}
+	for _, want := range []string{
+		"    query:\n        SELECT *\n        FROM users\n",
+		"    errorVerbose:\n        boom\n        main.go:1\n        main.go:2\n",
+		"    stacktrace:\n        github.com/evdnx/golog.TestConsoleBlocks\n",
+	} {
+		if !strings.Contains(blocks, want) {
+			t.Errorf("output lacks block %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestConsoleBlocksSkipJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithConsoleBlocks())
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("msg", String("text", "a\nb"))
+	if out := buf.String(); strings.Count(out, "\n") != 1 || !strings.Contains(out, `"text":"a\nb"`) {
+		t.Errorf("JSON output should stay on one line: %q", out)
+	}
+}
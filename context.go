@@ -12,6 +12,7 @@ const (
 	UserIDKey        ContextKey = "user_id"
 	TraceIDKey       ContextKey = "trace_id"
 	SpanIDKey        ContextKey = "span_id"
+	WorkerIDKey      ContextKey = "worker_id"
 )
 
 // WithCorrelationID attaches a correlation identifier to the context so it can
@@ -55,6 +56,15 @@ func WithSpanID(ctx context.Context, id string) context.Context {
 	return context.WithValue(ctx, SpanIDKey, id)
 }
 
+// WithWorkerID records a worker identifier on the context, e.g. the index of
+// a pool worker, so concurrent log output can be told apart.
+func WithWorkerID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, WorkerIDKey, id)
+}
+
 // FieldsFromContext converts known context values into structured logging
 // fields. Missing values are ignored, allowing the result to be appended
 // directly to a log call: logger.Info("...", FieldsFromContext(ctx)...).
@@ -79,5 +89,8 @@ func FieldsFromContext(ctx context.Context) []Field {
 	if v, _ := ctx.Value(SpanIDKey).(string); v != "" {
 		fields = append(fields, String(string(SpanIDKey), v))
 	}
+	if v, _ := ctx.Value(WorkerIDKey).(string); v != "" {
+		fields = append(fields, String(string(WorkerIDKey), v))
+	}
 	return fields
 }
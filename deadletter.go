@@ -0,0 +1,120 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                             Dead-Letter File                                */
+/* -------------------------------------------------------------------------- */
+
+// deadLetter is one line of a dead-letter file: the undeliverable entry plus
+// why and where delivery failed.
+type deadLetter struct {
+	FailedAt time.Time   `json:"failed_at"`
+	Provider string      `json:"provider"`
+	Error    string      `json:"error"`
+	Attempts int         `json:"attempts"`
+	Entry    spoolRecord `json:"entry"`
+}
+
+// deadLetterWriter appends deadLetter records to a rotating file.
+type deadLetterWriter struct {
+	mu sync.Mutex
+	lj *lumberjack.Logger
+}
+
+func (w *deadLetterWriter) write(provider string, attempts int, cause error, ent zapcore.Entry, ctxFields, fields []zapcore.Field) error {
+	if w == nil {
+		return nil
+	}
+	b, err := json.Marshal(deadLetter{
+		FailedAt: time.Now(),
+		Provider: provider,
+		Error:    cause.Error(),
+		Attempts: attempts,
+		Entry:    newSpoolRecord(ent, ctxFields, fields),
+	})
+	if err != nil {
+		return fmt.Errorf("dead letter: encode: %w", err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := w.lj.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("dead letter: %w", err)
+	}
+	return nil
+}
+
+func (w *deadLetterWriter) close() error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lj.Close()
+}
+
+// WithDeadLetterFile records entries whose delivery was abandoned – retries
+// exhausted or a permanent error under WithRetry – in filename, one JSON
+// object per line carrying the entry and the failure details. Rotation
+// parameters match WithFileProvider.
+func WithDeadLetterFile(filename string, maxSize, maxBackups, maxAge int, compress bool) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.deadLetter = &lumberjack.Logger{
+			Filename:   filename,
+			MaxSize:    maxSize,
+			MaxBackups: maxBackups,
+			MaxAge:     maxAge,
+			Compress:   compress,
+		}
+	}
+}
+
+// ResubmitDeadLetters replays every entry in a dead-letter file through l,
+// preserving the original timestamps, levels, callers and fields. Build l
+// with only the provider that originally failed to avoid duplicating entries
+// elsewhere. It returns the number of entries resubmitted; malformed lines
+// are skipped and reported in the returned error.
+func (l *Logger) ResubmitDeadLetters(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var (
+		n    int
+		errs []error
+	)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var dl deadLetter
+		dec := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		dec.UseNumber()
+		if err := dec.Decode(&dl); err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", path, line, err))
+			continue
+		}
+		ent, fields := dl.Entry.entry()
+		if ce := l.zapLogger.Core().Check(ent, nil); ce != nil {
+			ce.Write(fields...)
+			n++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return n, errors.Join(errs...)
+}
@@ -0,0 +1,73 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeadLetterFile_RecordsAndResubmits(t *testing.T) {
+	dlPath := filepath.Join(t.TempDir(), "dead.log")
+
+	logger, err := NewLogger(
+		WithRetry(WithWriterProvider(failingWriter{}, JSONEncoder), fastRetry),
+		WithDeadLetterFile(dlPath, 1, 1, 1, false),
+		WithErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Warn("charge failed", String("component", "billing"), Int("amount", 42))
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(dlPath)
+	if err != nil {
+		t.Fatalf("reading dead-letter file: %v", err)
+	}
+	var dl map[string]interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(data), &dl); err != nil {
+		t.Fatalf("invalid dead-letter record %q: %v", data, err)
+	}
+	if dl["provider"] != "writer" || dl["attempts"] != float64(fastRetry.MaxAttempts) || !strings.Contains(dl["error"].(string), "disk on fire") {
+		t.Fatalf("unexpected failure metadata: %v", dl)
+	}
+
+	var buf bytes.Buffer
+	replay, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer replay.Close()
+
+	n, err := replay.ResubmitDeadLetters(dlPath)
+	if err != nil || n != 1 {
+		t.Fatalf("expected 1 resubmitted entry, got %d (%v)", n, err)
+	}
+	out := buf.String()
+	for _, exp := range []string{`"level":"warn"`, `"msg":"charge failed"`, `"component":"billing"`, `"amount":42`, `"caller":"`} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected resubmitted entry to contain %s, got %s", exp, out)
+		}
+	}
+}
+
+func TestResubmitDeadLetters_ReportsMalformedLines(t *testing.T) {
+	dlPath := filepath.Join(t.TempDir(), "dead.log")
+	if err := os.WriteFile(dlPath, []byte("not json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	logger, _ := newBufferLogger(t, InfoLevel)
+	defer logger.Close()
+
+	n, err := logger.ResubmitDeadLetters(dlPath)
+	var syntaxErr *json.SyntaxError
+	if n != 0 || !errors.As(err, &syntaxErr) {
+		t.Fatalf("expected a syntax error and no entries, got %d (%v)", n, err)
+	}
+}
@@ -0,0 +1,103 @@
+package golog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          /debug/golog Admin Handler                         */
+/* -------------------------------------------------------------------------- */
+
+// ProviderInfo describes a configured provider.
+type ProviderInfo struct {
+	Name    string      `json:"name"`
+	Encoder EncoderType `json:"encoder,omitempty"`
+	// Level is the provider's own minimum level (WithProviderLevel), if any.
+	Level string `json:"level,omitempty"`
+
+	// ownsLevel marks providers that apply their own level bounds and are
+	// therefore not gated by the logger's level.
+	ownsLevel bool
+}
+
+// describeProvider returns a short human-readable description of p.
+func describeProvider(p provider) ProviderInfo {
+	// Wrappers (retry, …) describe themselves in terms of what they wrap.
+	if d, ok := p.(interface{ describe() ProviderInfo }); ok {
+		return d.describe()
+	}
+	switch v := p.(type) {
+	case *stdOutProvider:
+		return ProviderInfo{Name: v.streamsName(), Encoder: v.encoderType}
+	case *writerProvider:
+		return ProviderInfo{Name: "writer", Encoder: v.encoderType}
+	case *gcpProvider:
+		return ProviderInfo{Name: "gcp:" + v.projectID + "/" + v.logName}
+	case *fileProvider:
+		return ProviderInfo{Name: "file:" + v.filename, Encoder: v.encoder()}
+	default:
+		return ProviderInfo{Name: fmt.Sprintf("%T", p)}
+	}
+}
+
+type debugConfig struct {
+	Level          string         `json:"level"`
+	Providers      []ProviderInfo `json:"providers"`
+	RingBufferSize int            `json:"ring_buffer_size,omitempty"`
+}
+
+type debugReport struct {
+	Config        debugConfig   `json:"config"`
+	Stats         Stats         `json:"stats"`
+	RecentErrors  []errorRecord `json:"recent_errors"`
+	RecentEntries []Entry       `json:"recent_entries,omitempty"`
+}
+
+// DebugHandler returns an http.Handler that reports the logger's
+// configuration, live Stats, recent internal errors and – if WithRingBuffer
+// was configured – the buffered entries, as JSON. It is meant to be mounted
+// on a debug-only mux:
+//
+//	mux.Handle("/debug/golog", logger.DebugHandler())
+func (l *Logger) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		report := debugReport{
+			Config: debugConfig{
+				Level:     l.Level().String(),
+				Providers: l.providerInfos(),
+			},
+			Stats:        l.Stats(),
+			RecentErrors: l.telemetry.errs.recentErrors(),
+		}
+		if l.ring != nil {
+			report.Config.RingBufferSize = len(l.ring.entries)
+			report.RecentEntries = l.ring.snapshot()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if r.Method == http.MethodHead {
+			return
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			l.telemetry.errs.report(fmt.Errorf("debug handler: %w", err))
+		}
+	})
+}
+
+// errorRecord is an internal error as listed by the debug handler.
+type errorRecord struct {
+	Time  time.Time `json:"time"`
+	Error string    `json:"error"`
+}
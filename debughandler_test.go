@@ -0,0 +1,79 @@
+package golog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRingBuffer_WrapsOldestFirst(t *testing.T) {
+	r := newRingBuffer(3)
+	for _, msg := range []string{"a", "b", "c", "d", "e"} {
+		r.add(Entry{Message: msg})
+	}
+	got := r.snapshot()
+	if len(got) != 3 || got[0].Message != "c" || got[1].Message != "d" || got[2].Message != "e" {
+		t.Fatalf("unexpected ring contents: %+v", got)
+	}
+}
+
+func TestLogger_DebugHandler(t *testing.T) {
+	logger, err := NewLogger(
+		WithWriterProvider(failingWriter{}, JSONEncoder),
+		WithErrorHandler(func(error) {}),
+		WithRingBuffer(2),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("first")
+	logger.Warn("second", String("k", "v"))
+
+	rec := httptest.NewRecorder()
+	logger.DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/golog", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+
+	var report struct {
+		Config struct {
+			Level     string `json:"level"`
+			Providers []struct {
+				Name    string `json:"name"`
+				Encoder string `json:"encoder"`
+			} `json:"providers"`
+			RingBufferSize int `json:"ring_buffer_size"`
+		} `json:"config"`
+		Stats         Stats         `json:"stats"`
+		RecentErrors  []errorRecord `json:"recent_errors"`
+		RecentEntries []struct {
+			Message string            `json:"message"`
+			Fields  map[string]string `json:"fields"`
+		} `json:"recent_entries"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rec.Body.String())
+	}
+
+	if report.Config.Level != "info" || report.Config.RingBufferSize != 2 {
+		t.Errorf("unexpected config: %+v", report.Config)
+	}
+	if len(report.Config.Providers) != 1 || report.Config.Providers[0].Name != "writer" || report.Config.Providers[0].Encoder != "json" {
+		t.Errorf("unexpected providers: %+v", report.Config.Providers)
+	}
+	if len(report.RecentErrors) != 2 {
+		t.Errorf("expected two recent errors, got %+v", report.RecentErrors)
+	}
+	if len(report.RecentEntries) != 2 || report.RecentEntries[1].Message != "second" || report.RecentEntries[1].Fields["k"] != "v" {
+		t.Errorf("unexpected recent entries: %+v", report.RecentEntries)
+	}
+
+	rec = httptest.NewRecorder()
+	logger.DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/golog", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for POST, got %d", rec.Code)
+	}
+}
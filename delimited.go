@@ -0,0 +1,231 @@
+package golog
+
+import (
+	"encoding/csv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          CSV and LTSV Encoders                              */
+/* -------------------------------------------------------------------------- */
+
+// LTSVEncoder writes Labeled Tab-separated Values, one entry per line:
+//
+//	time:2024-01-02T03:04:05Z	level:info	caller:app/main.go:42	message:served	http.status:200
+//
+// Nested objects are flattened into dotted labels and arrays written as
+// JSON. Tabs and newlines in values are escaped as \t and \n.
+const LTSVEncoder EncoderType = "ltsv"
+
+// csvEncoderPrefix starts the EncoderType returned by CSVEncoder.
+const csvEncoderPrefix = "csv:"
+
+// defaultCSVColumns is the layout of CSVEncoder without columns.
+var defaultCSVColumns = []string{"time", "level", "logger", "caller", "message", "fields"}
+
+// CSVEncoder writes one RFC 4180 record per entry with a fixed column
+// layout, for spreadsheet and awk tooling:
+//
+//	golog.WithWriterProvider(f, golog.CSVEncoder("time", "level", "message", "user", "http.status"))
+//
+// The columns time, level, logger, caller, message and stacktrace hold the
+// entry itself; "fields" holds every field not named by another column as a
+// JSON object; any other column is the field of that key, or of that dotted
+// path into nested objects, and empty when the entry has none. Without
+// columns the layout is time, level, logger, caller, message, fields. No
+// header row is written.
+func CSVEncoder(columns ...string) EncoderType {
+	return EncoderType(csvEncoderPrefix + strings.Join(columns, ","))
+}
+
+// delimitedEncoder renders entries as CSV records or LTSV lines.
+type delimitedEncoder struct {
+	*treeEncoder
+	// columns is the CSV layout; nil selects LTSV.
+	columns []string
+}
+
+// newDelimitedEncoder returns the encoder for t, a CSVEncoder or
+// LTSVEncoder type, and false for any other type.
+func newDelimitedEncoder(t EncoderType) (zapcore.Encoder, bool) {
+	if t == LTSVEncoder {
+		return &delimitedEncoder{treeEncoder: newTreeEncoder()}, true
+	}
+	spec, ok := strings.CutPrefix(string(t), csvEncoderPrefix)
+	if !ok && t != "csv" {
+		return nil, false
+	}
+	columns := defaultCSVColumns
+	if spec != "" {
+		columns = strings.Split(spec, ",")
+	}
+	return &delimitedEncoder{treeEncoder: newTreeEncoder(), columns: columns}, true
+}
+
+func (e *delimitedEncoder) Clone() zapcore.Encoder {
+	return &delimitedEncoder{treeEncoder: e.cloneTree(), columns: e.columns}
+}
+
+func (e *delimitedEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	tree := e.fieldsWith(fields)
+	buf := encoderPool.Get()
+	if e.columns == nil {
+		e.encodeLTSV(buf, ent, tree)
+		return buf, nil
+	}
+	if err := e.encodeCSV(buf, ent, tree); err != nil {
+		buf.Free()
+		return nil, err
+	}
+	return buf, nil
+}
+
+// entryColumn returns the value of the entry column name, if it is one.
+func entryColumn(ent zapcore.Entry, name string) (string, bool) {
+	switch name {
+	case "time":
+		return ent.Time.Format(time.RFC3339Nano), true
+	case "level":
+		return zapLevelName(ent.Level), true
+	case "logger":
+		return ent.LoggerName, true
+	case "caller":
+		if !ent.Caller.Defined {
+			return "", true
+		}
+		return ent.Caller.TrimmedPath(), true
+	case "message":
+		return ent.Message, true
+	case "stacktrace":
+		return ent.Stack, true
+	}
+	return "", false
+}
+
+func (e *delimitedEncoder) encodeCSV(buf *buffer.Buffer, ent zapcore.Entry, tree *fieldTree) error {
+	record := make([]string, len(e.columns))
+	used := make(map[string]bool)
+	fieldsColumn := -1
+	for i, col := range e.columns {
+		if v, ok := entryColumn(ent, col); ok {
+			record[i] = v
+			continue
+		}
+		if col == "fields" {
+			fieldsColumn = i
+			continue
+		}
+		if v, top, ok := lookupField(tree, col); ok {
+			used[top] = true
+			record[i] = columnText(v)
+		}
+	}
+	if fieldsColumn >= 0 {
+		rest := &fieldTree{}
+		for i, k := range tree.keys {
+			if !used[k] {
+				rest.add(k, tree.vals[i])
+			}
+		}
+		if len(rest.keys) > 0 {
+			record[fieldsColumn] = columnText(rest)
+		}
+	}
+	w := csv.NewWriter(buf)
+	if err := w.Write(record); err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// lookupField finds the field named key, trying it whole before as a
+// dotted path into nested objects, and returns the top-level key it was
+// found under. Only a whole key marks its top-level field as used.
+func lookupField(tree *fieldTree, key string) (val interface{}, top string, ok bool) {
+	for i, k := range tree.keys {
+		if k == key {
+			return tree.vals[i], k, true
+		}
+	}
+	head, rest, found := strings.Cut(key, ".")
+	if !found {
+		return nil, "", false
+	}
+	for i, k := range tree.keys {
+		if k != head {
+			continue
+		}
+		switch sub := tree.vals[i].(type) {
+		case *fieldTree:
+			if v, _, ok := lookupField(sub, rest); ok {
+				return v, "", true
+			}
+		case map[string]interface{}:
+			if v, ok := sub[rest]; ok {
+				return v, "", true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// columnText renders v for a single column: scalars as text, containers as
+// JSON.
+func columnText(v interface{}) string {
+	switch v.(type) {
+	case *fieldTree, []interface{}, map[string]interface{}:
+		buf := encoderPool.Get()
+		defer buf.Free()
+		appendTreeJSON(buf, v)
+		return buf.String()
+	}
+	return formatScalar(v)
+}
+
+var ltsvEscaper = strings.NewReplacer("\\", `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func (e *delimitedEncoder) encodeLTSV(buf *buffer.Buffer, ent zapcore.Entry, tree *fieldTree) {
+	first := true
+	label := func(name, value string) {
+		if !first {
+			buf.AppendByte('\t')
+		}
+		first = false
+		buf.AppendString(ltsvLabel(name))
+		buf.AppendByte(':')
+		buf.AppendString(ltsvEscaper.Replace(value))
+	}
+	for _, col := range []string{"time", "level", "logger", "caller", "message", "stacktrace"} {
+		if v, _ := entryColumn(ent, col); v != "" || col == "message" {
+			label(col, v)
+		}
+	}
+	var flatten func(prefix string, t *fieldTree)
+	flatten = func(prefix string, t *fieldTree) {
+		for i, k := range t.keys {
+			if sub, ok := t.vals[i].(*fieldTree); ok {
+				flatten(prefix+k+".", sub)
+				continue
+			}
+			label(prefix+k, columnText(t.vals[i]))
+		}
+	}
+	flatten("", tree)
+	buf.AppendByte('\n')
+}
+
+// ltsvLabel replaces the characters LTSV does not allow in labels.
+func ltsvLabel(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == '.', r == '-':
+			return r
+		}
+		return '_'
+	}, name)
+}
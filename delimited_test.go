@@ -0,0 +1,70 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestCSVEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, CSVEncoder("level", "message", "user", "http.status", "missing", "fields")))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.With(String("user", "alice")).Info("served, \"ok\"",
+		Dict("http", String("method", "GET"), Int("status", 200)), Int("n", 1))
+	logger.Warn("bare")
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	want := [][]string{
+		{"info", `served, "ok"`, "alice", "200", "", `{"http":{"method":"GET","status":200},"n":1}`},
+		{"warn", "bare", "", "", "", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %q", len(want), records)
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("record %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+func TestCSVEncoderDefaultColumns(t *testing.T) {
+	enc, err := buildEncoder(CSVEncoder())
+	if err != nil {
+		t.Fatalf("buildEncoder: %v", err)
+	}
+	if got := enc.(*delimitedEncoder).columns; strings.Join(got, ",") != "time,level,logger,caller,message,fields" {
+		t.Errorf("default columns = %v", got)
+	}
+}
+
+func TestLTSVEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, LTSVEncoder), WithoutCaller())
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Named("api").Info("line\tone\ntwo", Dict("http", Int("status", 200)), Strings("tags", []string{"a"}), String("odd key", "v"))
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	labels := strings.Split(line, "\t")
+	if !strings.HasPrefix(labels[0], "time:") {
+		t.Errorf("unexpected first label: %s", labels[0])
+	}
+	got := strings.Join(labels[1:], "\t")
+	want := "level:info\tlogger:api\tmessage:line\\tone\\ntwo\thttp.status:200\ttags:[\"a\"]\todd_key:v"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
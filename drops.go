@@ -0,0 +1,178 @@
+package golog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          Dropped-Entry Accounting                           */
+/* -------------------------------------------------------------------------- */
+
+// DropReason identifies why an entry never reached its destination.
+type DropReason string
+
+const (
+	DropSampled       DropReason = "sampled"
+	DropRateLimited   DropReason = "rate_limited"
+	DropQueueFull     DropReason = "queue_full"
+	DropProviderError DropReason = "provider_error"
+	DropCircuitOpen   DropReason = "circuit_open"
+	DropFiltered      DropReason = "filtered"
+)
+
+// dropCounter keeps lifetime totals per reason plus a resettable window used
+// for the periodic summary entry.
+type dropCounter struct {
+	mu      sync.Mutex
+	total   map[DropReason]uint64
+	window  map[DropReason]uint64
+	handler func(reason DropReason, count int)
+}
+
+func newDropCounter(handler func(DropReason, int)) *dropCounter {
+	return &dropCounter{
+		total:   make(map[DropReason]uint64),
+		window:  make(map[DropReason]uint64),
+		handler: handler,
+	}
+}
+
+// record accounts for n dropped entries. Safe to call on a nil receiver so
+// providers constructed outside NewLogger (e.g. in tests) need no guards.
+func (d *dropCounter) record(reason DropReason, n int) {
+	if d == nil || n <= 0 {
+		return
+	}
+	d.mu.Lock()
+	d.total[reason] += uint64(n)
+	d.window[reason] += uint64(n)
+	d.mu.Unlock()
+
+	if d.handler != nil {
+		d.handler(reason, n)
+	}
+}
+
+// totals returns a copy of the lifetime counters.
+func (d *dropCounter) totals() map[DropReason]uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make(map[DropReason]uint64, len(d.total))
+	for k, v := range d.total {
+		out[k] = v
+	}
+	return out
+}
+
+// drainWindow returns the counters accumulated since the previous call and
+// resets them.
+func (d *dropCounter) drainWindow() map[DropReason]uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := d.window
+	d.window = make(map[DropReason]uint64)
+	return out
+}
+
+// dropSummary renders counts as e.g.
+// "dropped 1532 entries in last 1m0s: queue_full=1500 sampled=32".
+func dropSummary(counts map[DropReason]uint64, interval time.Duration) (string, uint64) {
+	reasons := make([]string, 0, len(counts))
+	var sum uint64
+	for r, n := range counts {
+		if n == 0 {
+			continue
+		}
+		reasons = append(reasons, string(r))
+		sum += n
+	}
+	sort.Strings(reasons)
+
+	parts := make([]string, len(reasons))
+	for i, r := range reasons {
+		parts[i] = fmt.Sprintf("%s=%d", r, counts[DropReason(r)])
+	}
+	return fmt.Sprintf("dropped %d entries in last %s: %s", sum, interval, strings.Join(parts, " ")), sum
+}
+
+// runDropSummary periodically emits a Warn entry describing what was dropped
+// during the last interval. Quiet intervals produce no output.
+func (l *Logger) runDropSummary(interval time.Duration) {
+	defer l.bg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.emitDropSummary(interval)
+		}
+	}
+}
+
+func (l *Logger) emitDropSummary(interval time.Duration) {
+	counts := l.telemetry.drops.drainWindow()
+	msg, sum := dropSummary(counts, interval)
+	if sum == 0 {
+		return
+	}
+	fields := make([]Field, 0, len(counts)+1)
+	fields = append(fields, Any("dropped", sum))
+	for r, n := range counts {
+		fields = append(fields, Any("dropped_"+string(r), n))
+	}
+	l.Warn(msg, fields...)
+}
+
+// samplerHook feeds zap's sampling decisions into the drop counter.
+func samplerHook(d *dropCounter) zapcore.SamplerOption {
+	return zapcore.SamplerHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+		if dec&zapcore.LogDropped > 0 {
+			d.record(DropSampled, 1)
+		}
+	})
+}
+
+// WithSampling caps throughput per message: within every tick the first
+// entries with a given level and message are logged, then only every
+// thereafter-th one. Sampled-out entries are counted as DropSampled.
+func WithSampling(tick time.Duration, first, thereafter int) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.sampling = &samplingConfig{tick: tick, first: first, thereafter: thereafter}
+	}
+}
+
+type samplingConfig struct {
+	tick              time.Duration
+	first, thereafter int
+}
+
+// WithDropHandler registers a callback invoked whenever entries are dropped
+// (sampling, rate limiting, queue overflow, provider failures).
+func WithDropHandler(fn func(reason DropReason, count int)) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.dropHandler = fn
+	}
+}
+
+// WithDropSummary makes the logger emit a Warn summary of dropped entries
+// every interval, e.g. "dropped 1532 entries in last 1m0s: queue_full=1500
+// sampled=32". Intervals without drops are silent.
+func WithDropSummary(interval time.Duration) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.dropSummaryInterval = interval
+	}
+}
+
+// DroppedEntries returns the lifetime number of dropped entries per reason.
+func (l *Logger) DroppedEntries() map[DropReason]uint64 {
+	return l.telemetry.drops.totals()
+}
@@ -0,0 +1,88 @@
+package golog
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDropSummary_Format(t *testing.T) {
+	msg, sum := dropSummary(map[DropReason]uint64{
+		DropSampled:   32,
+		DropQueueFull: 1500,
+	}, time.Minute)
+	if sum != 1532 {
+		t.Fatalf("expected sum 1532, got %d", sum)
+	}
+	const want = "dropped 1532 entries in last 1m0s: queue_full=1500 sampled=32"
+	if msg != want {
+		t.Fatalf("unexpected summary:\n got %q\nwant %q", msg, want)
+	}
+}
+
+func TestLogger_SamplingCountsDrops(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		handled int
+	)
+	var buf concurrentBuffer
+	logger, err := NewLogger(
+		WithWriterProvider(&buf, JSONEncoder),
+		WithSampling(time.Hour, 2, 0), // log the first two, drop the rest
+		WithDropHandler(func(reason DropReason, n int) {
+			if reason != DropSampled {
+				t.Errorf("unexpected drop reason %q", reason)
+			}
+			mu.Lock()
+			handled += n
+			mu.Unlock()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	for i := 0; i < 10; i++ {
+		logger.Info("repeated")
+	}
+
+	if got := strings.Count(buf.String(), `"repeated"`); got != 2 {
+		t.Fatalf("expected 2 sampled entries, got %d", got)
+	}
+	if got := logger.DroppedEntries()[DropSampled]; got != 8 {
+		t.Fatalf("expected 8 sampled drops, got %d", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if handled != 8 {
+		t.Fatalf("expected drop handler to see 8 entries, got %d", handled)
+	}
+}
+
+func TestLogger_DropSummaryEntry(t *testing.T) {
+	var buf concurrentBuffer
+	logger, err := NewLogger(
+		WithWriterProvider(&buf, JSONEncoder),
+		WithDropSummary(time.Hour), // ticker never fires; emit manually below
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.emitDropSummary(time.Minute) // nothing dropped – must stay silent
+	logger.telemetry.drops.record(DropQueueFull, 3)
+	logger.emitDropSummary(time.Minute)
+
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 {
+		t.Fatalf("expected exactly one summary entry, got:\n%s", out)
+	}
+	for _, exp := range []string{`"dropped 3 entries in last 1m0s: queue_full=3"`, `"dropped":3`, `"dropped_queue_full":3`} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected output to contain %s, got %s", exp, out)
+		}
+	}
+}
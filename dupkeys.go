@@ -0,0 +1,136 @@
+package golog
+
+import (
+	"strconv"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                         Duplicate Field Key Policy                          */
+/* -------------------------------------------------------------------------- */
+
+// DuplicateKeyPolicy decides what happens when an entry carries the same
+// key twice, e.g. a field given to With and again at the call site.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysKeep writes every field, as zap does; JSON output may
+	// then contain repeated keys.
+	DuplicateKeysKeep DuplicateKeyPolicy = iota
+	// DuplicateKeysLastWins keeps the field given last, usually the
+	// call-site one.
+	DuplicateKeysLastWins
+	// DuplicateKeysFirstWins keeps the field given first, usually the
+	// logger's.
+	DuplicateKeysFirstWins
+	// DuplicateKeysSuffix keeps every field, renaming repeats to key_2,
+	// key_3, …
+	DuplicateKeysSuffix
+)
+
+// WithDuplicateKeys sets how repeated field keys are resolved, so strict
+// JSON parsers such as BigQuery ingestion accept every entry. Keys are
+// compared within each object, so a key inside a Namespace does not clash
+// with one outside it.
+func WithDuplicateKeys(policy DuplicateKeyPolicy) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.duplicateKeys = policy
+	}
+}
+
+// dedupCore resolves duplicate keys across the context and call-site fields.
+// It keeps context fields itself so both sets are seen together.
+type dedupCore struct {
+	cores  []zapcore.Core
+	policy DuplicateKeyPolicy
+	fields []zapcore.Field
+}
+
+func (c *dedupCore) Enabled(lvl zapcore.Level) bool {
+	return anyEnabled(c.cores, lvl)
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{
+		cores:  c.cores,
+		policy: c.policy,
+		fields: append(append([]zapcore.Field(nil), c.fields...), fields...),
+	}
+}
+
+func (c *dedupCore) enabledFor(ent zapcore.Entry) bool {
+	return anyEntryEnabled(c.cores, ent)
+}
+
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabledFor(ent) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return writeEnabled(c.cores, ent, dedupFields(all, c.policy))
+}
+
+func (c *dedupCore) Sync() error {
+	return syncAll(c.cores)
+}
+
+// dedupFields applies policy to fields. A namespace opens a new scope for
+// the fields after it and is itself always kept; skipped fields carry no
+// key.
+func dedupFields(fields []zapcore.Field, policy DuplicateKeyPolicy) []zapcore.Field {
+	// scope numbers the namespace each field belongs to.
+	type scoped struct {
+		scope int
+		key   string
+	}
+	scope := 0
+	counts := make(map[scoped]int, len(fields))
+	last := make(map[scoped]int, len(fields))
+	for i, f := range fields {
+		switch f.Type {
+		case zapcore.SkipType:
+		case zapcore.NamespaceType:
+			scope++
+		default:
+			k := scoped{scope, f.Key}
+			counts[k]++
+			last[k] = i
+		}
+	}
+
+	out := make([]zapcore.Field, 0, len(fields))
+	seen := make(map[scoped]int, len(fields))
+	scope = 0
+	for i, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			scope++
+		}
+		k := scoped{scope, f.Key}
+		if f.Type == zapcore.SkipType || f.Type == zapcore.NamespaceType || counts[k] == 1 {
+			out = append(out, f)
+			continue
+		}
+		seen[k]++
+		switch policy {
+		case DuplicateKeysLastWins:
+			if last[k] != i {
+				continue
+			}
+		case DuplicateKeysFirstWins:
+			if seen[k] > 1 {
+				continue
+			}
+		case DuplicateKeysSuffix:
+			if n := seen[k]; n > 1 {
+				f.Key += "_" + strconv.Itoa(n)
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
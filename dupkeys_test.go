@@ -0,0 +1,33 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithDuplicateKeys(t *testing.T) {
+	cases := []struct {
+		policy DuplicateKeyPolicy
+		want   string
+	}{
+		{DuplicateKeysKeep, `"user":"logger","n":1,"user":"call","req":{"user":"nested"}`},
+		{DuplicateKeysLastWins, `"n":1,"user":"call","req":{"user":"nested"}`},
+		{DuplicateKeysFirstWins, `"user":"logger","n":1,"req":{"user":"nested"}`},
+		{DuplicateKeysSuffix, `"user":"logger","n":1,"user_2":"call","req":{"user":"nested"}`},
+	}
+	for _, tc := range cases {
+		var buf bytes.Buffer
+		logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithDuplicateKeys(tc.policy))
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		logger.With(String("user", "logger")).Info("dup", Int("n", 1), String("user", "call"),
+			Namespace("req"), String("user", "nested"))
+		logger.Close()
+
+		if !strings.Contains(buf.String(), `"msg":"dup",`+tc.want+"}") {
+			t.Errorf("policy %d: expected %s in %s", tc.policy, tc.want, buf.String())
+		}
+	}
+}
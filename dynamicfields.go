@@ -0,0 +1,154 @@
+package golog
+
+import (
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                        Write-Time Dynamic State Fields                      */
+/* -------------------------------------------------------------------------- */
+
+// DynamicField is a field whose value is computed when an entry is written.
+// A nil value omits the field.
+type DynamicField struct {
+	Key   string
+	Value func() interface{}
+}
+
+// WithDynamicFields evaluates fields when entries at or above minLevel are
+// written and attaches the results, capturing system state alongside
+// failures:
+//
+//	golog.WithDynamicFields(golog.ErrorLevel, 0, golog.GoroutineCount(), golog.HeapInUse(), golog.OpenFDCount())
+//
+// A positive interval attaches them to at most one entry per interval, e.g.
+// a periodic snapshot on Info traffic.
+func WithDynamicFields(minLevel Level, interval time.Duration, fields ...DynamicField) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.dynamicFields = append(cfg.dynamicFields, dynamicFieldSet{min: minLevel, interval: interval, fields: fields})
+	}
+}
+
+type dynamicFieldSet struct {
+	min      Level
+	interval time.Duration
+	fields   []DynamicField
+	// last is the UnixNano time fields were last attached; shared by clones.
+	last *atomic.Int64
+}
+
+// due reports whether the set applies to an entry at lvl written at now.
+func (s dynamicFieldSet) due(lvl zapcore.Level, now time.Time) bool {
+	if lvl < toZapLevel(s.min) {
+		return false
+	}
+	if s.interval <= 0 {
+		return true
+	}
+	last := s.last.Load()
+	return now.UnixNano()-last >= int64(s.interval) && s.last.CompareAndSwap(last, now.UnixNano())
+}
+
+// dynamicCore attaches the due dynamic fields to each entry.
+type dynamicCore struct {
+	cores []zapcore.Core
+	sets  []dynamicFieldSet
+}
+
+func newDynamicCore(cores []zapcore.Core, sets []dynamicFieldSet) *dynamicCore {
+	for i := range sets {
+		sets[i].last = new(atomic.Int64)
+	}
+	return &dynamicCore{cores: cores, sets: sets}
+}
+
+func (c *dynamicCore) Enabled(lvl zapcore.Level) bool {
+	return anyEnabled(c.cores, lvl)
+}
+
+func (c *dynamicCore) enabledFor(ent zapcore.Entry) bool {
+	return anyEntryEnabled(c.cores, ent)
+}
+
+func (c *dynamicCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dynamicCore{cores: withAll(c.cores, fields), sets: c.sets}
+}
+
+func (c *dynamicCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabledFor(ent) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *dynamicCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var extra []Field
+	for _, s := range c.sets {
+		if !s.due(ent.Level, ent.Time) {
+			continue
+		}
+		for _, f := range s.fields {
+			if v := f.Value(); v != nil {
+				extra = append(extra, Field{Key: f.Key, Value: v})
+			}
+		}
+	}
+	if len(extra) > 0 {
+		fields = append(append([]zapcore.Field(nil), fields...), toZapFields(extra)...)
+	}
+	return writeEnabled(c.cores, ent, fields)
+}
+
+func (c *dynamicCore) Sync() error {
+	return syncAll(c.cores)
+}
+
+/* -------------------------------------------------------------------------- */
+/*                          Built-in Dynamic Fields                            */
+/* -------------------------------------------------------------------------- */
+
+// GoroutineCount reports the number of goroutines as runtime.goroutines.
+func GoroutineCount() DynamicField {
+	return DynamicField{Key: "runtime.goroutines", Value: func() interface{} {
+		return runtime.NumGoroutine()
+	}}
+}
+
+// HeapInUse reports the bytes of in-use heap spans as
+// runtime.heap_inuse_bytes. It reads runtime/metrics and does not stop the
+// world.
+func HeapInUse() DynamicField {
+	return DynamicField{Key: "runtime.heap_inuse_bytes", Value: func() interface{} {
+		sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}, {Name: "/memory/classes/heap/unused:bytes"}}
+		metrics.Read(sample)
+		var total uint64
+		for _, s := range sample {
+			if s.Value.Kind() != metrics.KindUint64 {
+				return nil
+			}
+			total += s.Value.Uint64()
+		}
+		return total
+	}}
+}
+
+// OpenFDCount reports the number of open file descriptors as
+// process.open_fds where the platform exposes them (/proc/self/fd on Linux,
+// /dev/fd on macOS and the BSDs).
+func OpenFDCount() DynamicField {
+	return DynamicField{Key: "process.open_fds", Value: func() interface{} {
+		for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+			if entries, err := os.ReadDir(dir); err == nil {
+				// The directory handle used for reading is itself listed.
+				return len(entries) - 1
+			}
+		}
+		return nil
+	}}
+}
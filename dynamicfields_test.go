@@ -0,0 +1,63 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithDynamicFields(t *testing.T) {
+	var buf bytes.Buffer
+	calls := 0
+	counter := DynamicField{Key: "calls", Value: func() interface{} {
+		calls++
+		return calls
+	}}
+	logger, err := NewLogger(
+		WithWriterProvider(&buf, JSONEncoder),
+		WithDynamicFields(ErrorLevel, 0, counter, GoroutineCount(), HeapInUse(), OpenFDCount()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("fine")
+	logger.Error("broken")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", buf.String())
+	}
+	if strings.Contains(lines[0], "calls") {
+		t.Errorf("dynamic fields attached below min level: %s", lines[0])
+	}
+	for _, key := range []string{`"calls":1`, `"runtime.goroutines":`, `"runtime.heap_inuse_bytes":`} {
+		if !strings.Contains(lines[1], key) {
+			t.Errorf("missing %s in %s", key, lines[1])
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected one evaluation, got %d", calls)
+	}
+}
+
+func TestDynamicFieldsInterval(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(
+		WithWriterProvider(&buf, JSONEncoder),
+		WithDynamicFields(InfoLevel, time.Hour, GoroutineCount()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	for i := 0; i < 3; i++ {
+		logger.Info("tick")
+	}
+	if n := strings.Count(buf.String(), "runtime.goroutines"); n != 1 {
+		t.Errorf("expected one snapshot per interval, got %d: %s", n, buf.String())
+	}
+}
@@ -0,0 +1,422 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                  Elasticsearch / OpenSearch Bulk Provider                   */
+/* -------------------------------------------------------------------------- */
+
+// ElasticsearchConfig configures WithElasticsearchProvider. The embedded
+// HTTPConfig covers TLS, proxy, headers, timeout, batching and compression;
+// its TokenProvider sends a bearer token. Zero values fall back to the
+// defaults noted on each field.
+type ElasticsearchConfig struct {
+	HTTPConfig
+	// Index names the index of each entry. Text in braces is a Go time
+	// layout applied to the entry's time in UTC, so "logs-{2006.01.02}"
+	// (the default) writes to daily indices.
+	Index string
+	// DataStream indexes with the "create" action that data streams
+	// require; Index then names the data stream.
+	DataStream bool
+	// Pipeline is the ingest pipeline run on every document.
+	Pipeline string
+	// Username and Password enable basic authentication.
+	Username, Password string
+	// APIKey is the base64 "id:api_key" credential, sent as
+	// "Authorization: ApiKey <APIKey>".
+	APIKey string
+	// AWSRegion signs requests with AWS Signature Version 4 for Amazon
+	// OpenSearch Service, using AWSCredentials (default from the
+	// environment, see CloudWatchConfig.Credentials).
+	AWSRegion      string
+	AWSCredentials AWSCredentialsProvider
+	// MaxRetries is how often a batch, or the documents of it that were
+	// rejected with 429 Too Many Requests, is resent (default 3).
+	MaxRetries int
+	// RetryBackoff is the first delay between retries, doubled after each
+	// one (default 500ms). A Retry-After header takes precedence.
+	RetryBackoff time.Duration
+}
+
+func (c ElasticsearchConfig) withDefaults() ElasticsearchConfig {
+	if c.Index == "" {
+		c.Index = "logs-{2006.01.02}"
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = 3
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = 500 * time.Millisecond
+	}
+	if c.AWSRegion != "" && c.AWSCredentials == nil {
+		c.AWSCredentials = envAWSCredentials
+	}
+	return c
+}
+
+// WithElasticsearchProvider indexes entries through the _bulk API of the
+// Elasticsearch or OpenSearch cluster at endpoint, e.g.
+// "https://es.example.com:9200":
+//
+//	golog.WithElasticsearchProvider("https://es.example.com:9200", golog.ElasticsearchConfig{
+//		Index:  "app-{2006.01}",
+//		APIKey: os.Getenv("ES_API_KEY"),
+//	})
+//
+// Documents are JSON with the time under "@timestamp" in RFC 3339 and the
+// message under "message"; WithProviderEncoderConfig adjusts the keys.
+// Requests and documents rejected with 429 Too Many Requests are retried
+// with exponential backoff. Documents rejected for other reasons, such as
+// mapping conflicts, are dropped and counted as DropProviderError like
+// failed batches, and the first reason is reported.
+func WithElasticsearchProvider(endpoint string, cfg ElasticsearchConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&elasticsearchProvider{
+			providerBase: providerBase{name: "elasticsearch:" + endpointName(endpoint), encoders: jsonEncoderOnly},
+			endpoint:     endpoint,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type elasticsearchProvider struct {
+	providerBase
+	endpoint string
+	cfg      ElasticsearchConfig
+	batch    *batchWriter
+}
+
+// elasticsearchEncoding are the document keys applied before those of
+// WithProviderEncoderConfig.
+func elasticsearchEncoding(c *zapcore.EncoderConfig) {
+	c.TimeKey = "@timestamp"
+	c.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	c.MessageKey = "message"
+}
+
+func (p *elasticsearchProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	cfg := p.cfg.withDefaults()
+	u, err := url.Parse(p.endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("elasticsearch provider: invalid endpoint %q", p.endpoint)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/_bulk"
+	if cfg.Pipeline != "" {
+		q := u.Query()
+		q.Set("pipeline", cfg.Pipeline)
+		u.RawQuery = q.Encode()
+	}
+	index, err := parseIndexTemplate(cfg.Index)
+	if err != nil {
+		return nil, fmt.Errorf("elasticsearch provider: %w", err)
+	}
+	sender, err := newHTTPSender(cfg.HTTPConfig)
+	if err != nil {
+		return nil, fmt.Errorf("elasticsearch provider: %w", err)
+	}
+	own := mergeEncoderConfig(&encoderConfigFuncs{fns: []func(*zapcore.EncoderConfig){elasticsearchEncoding}}, p.encoderConfig.funcs())
+	enc, err := p.tel.buildEncoder(JSONEncoder, own)
+	if err != nil {
+		return nil, err
+	}
+	client := &bulkClient{endpoint: u.String(), cfg: cfg, sender: sender, drops: p.dropped, report: p.report}
+	p.batch = newBatchWriter(sender.cfg.BatchSize, sender.cfg.FlushInterval, client.bulk, p.dropped, p.report)
+	action := "index"
+	if cfg.DataStream {
+		action = "create"
+	}
+	return &elasticsearchCore{LevelEnabler: level, enc: enc, index: index, action: action, out: p.batch}, nil
+}
+
+func (p *elasticsearchProvider) close() error {
+	if p.batch == nil {
+		return nil
+	}
+	return p.batch.close()
+}
+
+func (p *elasticsearchProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: JSONEncoder}
+}
+
+// elasticsearchCore writes the action line and document of each entry to
+// the batch as one write, so the batch counts entries.
+type elasticsearchCore struct {
+	zapcore.LevelEnabler
+	enc    zapcore.Encoder
+	index  indexTemplate
+	action string
+	out    *batchWriter
+}
+
+func (c *elasticsearchCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	clone := *c
+	clone.enc = enc
+	return &clone
+}
+
+func (c *elasticsearchCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *elasticsearchCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	doc, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer doc.Free()
+	buf := encoderPool.Get()
+	defer buf.Free()
+	buf.AppendString(`{"` + c.action + `":{"_index":`)
+	appendJSONString(buf, c.index.format(ent.Time))
+	buf.AppendString("}}\n")
+	buf.Write(bytes.TrimSuffix(doc.Bytes(), []byte("\n")))
+	buf.AppendByte('\n')
+	_, err = c.out.Write(buf.Bytes())
+	return err
+}
+
+func (c *elasticsearchCore) Sync() error { return c.out.Sync() }
+
+// indexTemplate alternates literal text and time layouts, starting with
+// literal text.
+type indexTemplate []string
+
+func parseIndexTemplate(s string) (indexTemplate, error) {
+	var t indexTemplate
+	for {
+		open := strings.IndexByte(s, '{')
+		if open < 0 {
+			if strings.IndexByte(s, '}') >= 0 {
+				return nil, fmt.Errorf("index %q: unbalanced braces", s)
+			}
+			return append(t, s), nil
+		}
+		end := strings.IndexByte(s[open:], '}')
+		if end < 0 || strings.IndexByte(s[:open], '}') >= 0 {
+			return nil, fmt.Errorf("index %q: unbalanced braces", s)
+		}
+		t = append(t, s[:open], s[open+1:open+end])
+		s = s[open+end+1:]
+	}
+}
+
+func (t indexTemplate) format(at time.Time) string {
+	if len(t) == 1 {
+		return t[0]
+	}
+	at = at.UTC()
+	var b strings.Builder
+	for i, part := range t {
+		if i%2 == 0 {
+			b.WriteString(part)
+		} else {
+			b.WriteString(at.Format(part))
+		}
+	}
+	return b.String()
+}
+
+/* -------------------------------------------------------------------------- */
+/*                                 Bulk Requests                               */
+/* -------------------------------------------------------------------------- */
+
+type bulkClient struct {
+	endpoint string
+	cfg      ElasticsearchConfig
+	sender   *httpSender
+	drops    func(DropReason, int)
+	report   func(error)
+}
+
+// bulkResponse is the part of a _bulk response needed to find rejected
+// documents.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// bulk sends body, retrying on 429. Documents rejected for other reasons
+// are counted and reported here, so only failures of the whole batch are
+// returned.
+func (c *bulkClient) bulk(body []byte) error {
+	docs := splitBulkBody(body)
+	total := len(docs)
+	delay := c.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		status, retryAfter, resp, err := c.post(bytes.Join(docs, nil))
+		if err == nil && (status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable) {
+			if attempt < c.cfg.MaxRetries {
+				delay = c.wait(delay, retryAfter)
+				continue
+			}
+			err = fmt.Errorf("elasticsearch: %d %s after %d retries", status, http.StatusText(status), attempt)
+		}
+		if err != nil {
+			if len(docs) == total {
+				return err
+			}
+			// Part of the batch was indexed; only the rest is lost.
+			c.drops(DropProviderError, len(docs))
+			c.report(err)
+			return nil
+		}
+
+		var retry [][]byte
+		rejected, reason := 0, ""
+		for i, item := range resp.Items {
+			for _, result := range item {
+				switch {
+				case result.Status == http.StatusTooManyRequests && i < len(docs):
+					retry = append(retry, docs[i])
+				case result.Status >= 300:
+					rejected++
+					if reason == "" {
+						reason = result.Error.Type + ": " + result.Error.Reason
+					}
+				}
+			}
+		}
+		if rejected > 0 {
+			c.drops(DropProviderError, rejected)
+			c.report(fmt.Errorf("elasticsearch: %d of %d documents rejected: %s", rejected, len(docs), reason))
+		}
+		if len(retry) == 0 {
+			return nil
+		}
+		if attempt >= c.cfg.MaxRetries {
+			c.drops(DropProviderError, len(retry))
+			c.report(fmt.Errorf("elasticsearch: %d documents still rejected with 429 after %d retries", len(retry), attempt))
+			return nil
+		}
+		docs = retry
+		delay = c.wait(delay, retryAfter)
+	}
+}
+
+// wait sleeps for retryAfter if set, or delay, and returns the next delay.
+func (c *bulkClient) wait(delay, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		time.Sleep(retryAfter)
+	} else {
+		time.Sleep(delay)
+	}
+	return delay * 2
+}
+
+// post sends one _bulk request. Statuses other than 2xx, 429 and 503 are
+// returned as errors, permanent for client errors.
+func (c *bulkClient) post(body []byte) (int, time.Duration, *bulkResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.sender.cfg.Timeout)
+	defer cancel()
+
+	cfg := c.sender.cfg
+	payload, err := cfg.Compression.compress(body)
+	if err != nil {
+		return 0, 0, nil, PermanentError(fmt.Errorf("elasticsearch: %w", err))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return 0, 0, nil, PermanentError(fmt.Errorf("elasticsearch: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if cfg.Compression != NoCompression {
+		req.Header.Set("Content-Encoding", string(cfg.Compression))
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	switch {
+	case c.cfg.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+c.cfg.APIKey)
+	case c.cfg.Username != "" || c.cfg.Password != "":
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	case cfg.TokenProvider != nil:
+		token, err := cfg.TokenProvider(ctx)
+		if err != nil {
+			return 0, 0, nil, fmt.Errorf("elasticsearch: token provider: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.cfg.AWSRegion != "" {
+		creds, err := c.cfg.AWSCredentials(ctx)
+		if err != nil {
+			return 0, 0, nil, fmt.Errorf("elasticsearch: credentials: %w", err)
+		}
+		signAWSRequest(req, payload, creds, c.cfg.AWSRegion, "es", time.Now())
+	}
+
+	resp, err := c.sender.client.Do(req)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("elasticsearch: %w", err)
+	}
+	defer resp.Body.Close()
+	var retryAfter time.Duration
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		retryAfter = time.Duration(secs) * time.Second
+	}
+	switch status := resp.StatusCode; {
+	case status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable:
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+		return status, retryAfter, nil, nil
+	case status/100 != 2:
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("elasticsearch: %s: %s", resp.Status, bytes.TrimSpace(snippet))
+		if status/100 == 4 && status != http.StatusRequestTimeout {
+			err = PermanentError(err)
+		}
+		return status, 0, nil, err
+	}
+	var result bulkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, 0, nil, fmt.Errorf("elasticsearch: invalid response: %w", err)
+	}
+	return resp.StatusCode, retryAfter, &result, nil
+}
+
+// splitBulkBody splits a batch into its documents, each an action line and
+// a source line.
+func splitBulkBody(body []byte) [][]byte {
+	var docs [][]byte
+	for len(body) > 0 {
+		end := bytes.IndexByte(body, '\n')
+		if end < 0 {
+			return append(docs, body)
+		}
+		if next := bytes.IndexByte(body[end+1:], '\n'); next >= 0 {
+			end += next + 1
+		} else {
+			end = len(body) - 1
+		}
+		docs = append(docs, body[:end+1])
+		body = body[end+1:]
+	}
+	return docs
+}
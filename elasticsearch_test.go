@@ -0,0 +1,197 @@
+package golog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeBulk answers _bulk requests with the statuses returned by respond for
+// each document, or with status for the whole request when set.
+type fakeBulk struct {
+	mu       sync.Mutex
+	requests []http.Header
+	paths    []string
+	docs     [][]map[string]interface{}
+	actions  [][]string
+	status   []int
+	respond  func(req, doc int, source map[string]interface{}) int
+}
+
+func (f *fakeBulk) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := len(f.requests)
+	f.requests = append(f.requests, r.Header.Clone())
+	f.paths = append(f.paths, r.URL.RequestURI())
+	if n < len(f.status) && f.status[n] != 0 {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(f.status[n])
+		return
+	}
+	var docs []map[string]interface{}
+	var actions []string
+	var items []string
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		action := scanner.Text()
+		scanner.Scan()
+		var source map[string]interface{}
+		json.Unmarshal(scanner.Bytes(), &source)
+		actions = append(actions, action)
+		docs = append(docs, source)
+		status := 201
+		if f.respond != nil {
+			status = f.respond(n, len(docs)-1, source)
+		}
+		item := fmt.Sprintf(`{"index":{"status":%d}}`, status)
+		if status >= 300 {
+			item = fmt.Sprintf(`{"index":{"status":%d,"error":{"type":"mapper_parsing_exception","reason":"bad field"}}}`, status)
+		}
+		items = append(items, item)
+	}
+	f.docs = append(f.docs, docs)
+	f.actions = append(f.actions, actions)
+	fmt.Fprintf(w, `{"errors":true,"items":[%s]}`, strings.Join(items, ","))
+}
+
+func TestElasticsearchProvider_BulkAndIndexTemplate(t *testing.T) {
+	fake := &fakeBulk{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	at := time.Date(2026, 10, 16, 23, 30, 0, 0, time.FixedZone("CET", 2*3600))
+	logger, err := NewLogger(
+		WithElasticsearchProvider(srv.URL+"/", ElasticsearchConfig{
+			HTTPConfig: HTTPConfig{BatchSize: 2, FlushInterval: time.Hour},
+			Index:      "app-{2006.01.02}",
+			Pipeline:   "logs",
+			APIKey:     "a2V5",
+		}),
+		WithZapOptions(zap.WithClock(fixedClock{at})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("first", String("user", "ann"))
+	logger.Warn("second")
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(fake.requests))
+	}
+	if got := fake.paths[0]; got != "/_bulk?pipeline=logs" {
+		t.Errorf("path = %s", got)
+	}
+	if got := fake.requests[0].Get("Authorization"); got != "ApiKey a2V5" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := fake.requests[0].Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := fake.actions[0][0]; got != `{"index":{"_index":"app-2026.10.16"}}` {
+		t.Errorf("action = %s", got)
+	}
+	doc := fake.docs[0][0]
+	if doc["message"] != "first" || doc["user"] != "ann" || doc["@timestamp"] != "2026-10-16T23:30:00+02:00" {
+		t.Errorf("unexpected document %v", doc)
+	}
+}
+
+func TestElasticsearchProvider_RetriesAndRejections(t *testing.T) {
+	fake := &fakeBulk{
+		status: []int{http.StatusTooManyRequests},
+		respond: func(req, doc int, source map[string]interface{}) int {
+			switch source["message"] {
+			case "busy":
+				if req == 1 {
+					return http.StatusTooManyRequests
+				}
+			case "bad":
+				return http.StatusBadRequest
+			}
+			return 201
+		},
+	}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithElasticsearchProvider(srv.URL, ElasticsearchConfig{
+		HTTPConfig:   HTTPConfig{BatchSize: 3, FlushInterval: time.Hour},
+		DataStream:   true,
+		Username:     "elastic",
+		Password:     "secret",
+		RetryBackoff: time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Info("ok")
+	logger.Info("busy")
+	logger.Info("bad")
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	// 429 for the request, then 429 for one document, which is resent alone.
+	if len(fake.requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(fake.requests))
+	}
+	if user, pass, ok := (&http.Request{Header: fake.requests[0]}).BasicAuth(); !ok || user != "elastic" || pass != "secret" {
+		t.Errorf("basic auth = %q %q %v", user, pass, ok)
+	}
+	if len(fake.docs[1]) != 1 || fake.docs[1][0]["message"] != "busy" {
+		t.Errorf("retried documents = %v", fake.docs[1])
+	}
+	if !strings.HasPrefix(fake.actions[0][0], `{"create":`) {
+		t.Errorf("action = %s", fake.actions[0][0])
+	}
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+}
+
+func TestElasticsearchProvider_Errors(t *testing.T) {
+	if _, err := NewLogger(WithElasticsearchProvider("es:9200", ElasticsearchConfig{})); err == nil || !strings.Contains(err.Error(), "invalid endpoint") {
+		t.Errorf("expected endpoint error, got %v", err)
+	}
+	if _, err := NewLogger(WithElasticsearchProvider("http://es:9200", ElasticsearchConfig{Index: "logs-{2006"})); err == nil || !strings.Contains(err.Error(), "unbalanced") {
+		t.Errorf("expected index error, got %v", err)
+	}
+	if _, err := NewLogger(WithElasticsearchProvider("http://es:9200", ElasticsearchConfig{}, WithProviderEncoder(ConsoleEncoder))); err == nil || !strings.Contains(err.Error(), "sent as JSON") {
+		t.Errorf("expected encoder error, got %v", err)
+	}
+}
+
+func TestIndexTemplate(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	for in, want := range map[string]string{
+		"logs":                 "logs",
+		"logs-{2006.01.02}":    "logs-2026.01.02",
+		"{2006}-logs-{01}":     "2026-logs-01",
+		"app-{2006.01}-stream": "app-2026.01-stream",
+	} {
+		tmpl, err := parseIndexTemplate(in)
+		if err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+		if got := tmpl.format(at); got != want {
+			t.Errorf("%s: got %s, want %s", in, got, want)
+		}
+	}
+	for _, in := range []string{"logs}", "{2006", "a}{b"} {
+		if _, err := parseIndexTemplate(in); err == nil {
+			t.Errorf("%s: expected error", in)
+		}
+	}
+}
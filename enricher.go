@@ -0,0 +1,222 @@
+package golog
+
+import (
+	"context"
+	"net"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                              Enricher Interface                             */
+/* -------------------------------------------------------------------------- */
+
+// Enricher adds cross-cutting metadata to entries. Enrich is called once per
+// written entry with the context passed to the *Ctx logging methods
+// (context.Background() for the others), so it must be cheap and safe for
+// concurrent use.
+type Enricher interface {
+	Enrich(ctx context.Context) []Field
+}
+
+// EnricherFunc adapts a function to the Enricher interface.
+type EnricherFunc func(ctx context.Context) []Field
+
+// Enrich calls f(ctx).
+func (f EnricherFunc) Enrich(ctx context.Context) []Field { return f(ctx) }
+
+// WithEnrichers adds the fields returned by each enricher to every entry, so
+// metadata such as the host name is attached in one place rather than at
+// every call site. Fields passed at the call site take precedence over
+// enriched fields with the same key. Filters and transforms see the
+// enriched entry.
+func WithEnrichers(enrichers ...Enricher) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.enrichers = append(cfg.enrichers, enrichers...)
+	}
+}
+
+// TraceCtx logs at Trace level, passing ctx to the configured enrichers.
+func (l *Logger) TraceCtx(ctx context.Context, msg string, fields ...Field) {
+	if ce := l.zapLogger.Check(traceZapLevel, msg); ce != nil {
+		ce.Write(l.contextFields(ctx, fields)...)
+	}
+}
+
+// DebugCtx logs at Debug level, passing ctx to the configured enrichers.
+func (l *Logger) DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	l.zapLogger.Debug(msg, l.contextFields(ctx, fields)...)
+}
+
+// InfoCtx logs at Info level, passing ctx to the configured enrichers.
+func (l *Logger) InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	l.zapLogger.Info(msg, l.contextFields(ctx, fields)...)
+}
+
+// WarnCtx logs at Warn level, passing ctx to the configured enrichers.
+func (l *Logger) WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	l.zapLogger.Warn(msg, l.contextFields(ctx, fields)...)
+}
+
+// ErrorCtx logs at Error level, passing ctx to the configured enrichers.
+func (l *Logger) ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	l.zapLogger.Error(msg, l.contextFields(ctx, fields)...)
+}
+
+// FatalCtx logs at Fatal level, passing ctx to the configured enrichers, and
+// then exits the process.
+func (l *Logger) FatalCtx(ctx context.Context, msg string, fields ...Field) {
+	l.zapLogger.Fatal(msg, l.contextFields(ctx, fields)...)
+}
+
+// contextFieldKey names the field that carries a call's context to
+// enrichCore. Its type is SkipType, so encoders ignore it should it ever
+// reach one.
+const contextFieldKey = "golog.context"
+
+func (l *Logger) contextFields(ctx context.Context, fields []Field) []zapcore.Field {
+	zf := toZapFields(fields)
+	if l.enriched && ctx != nil {
+		zf = append(zf, zapcore.Field{Key: contextFieldKey, Type: zapcore.SkipType, Interface: ctx})
+	}
+	return zf
+}
+
+// enrichCore appends the enrichers' fields to each entry.
+type enrichCore struct {
+	cores     []zapcore.Core
+	enrichers []Enricher
+}
+
+func (c *enrichCore) Enabled(lvl zapcore.Level) bool {
+	return anyEnabled(c.cores, lvl)
+}
+
+func (c *enrichCore) enabledFor(ent zapcore.Entry) bool {
+	return anyEntryEnabled(c.cores, ent)
+}
+
+func (c *enrichCore) With(fields []zapcore.Field) zapcore.Core {
+	return &enrichCore{cores: withAll(c.cores, fields), enrichers: c.enrichers}
+}
+
+func (c *enrichCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabledFor(ent) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *enrichCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ctx := context.Background()
+	out := make([]zapcore.Field, 0, len(fields)+4)
+	for _, f := range fields {
+		if f.Type == zapcore.SkipType && f.Key == contextFieldKey {
+			if fc, ok := f.Interface.(context.Context); ok {
+				ctx = fc
+			}
+			continue
+		}
+		out = append(out, f)
+	}
+	explicit := len(out)
+	for _, e := range c.enrichers {
+	next:
+		for _, f := range toZapFields(e.Enrich(ctx)) {
+			for _, g := range out[:explicit] {
+				if g.Key == f.Key {
+					continue next
+				}
+			}
+			out = append(out, f)
+		}
+	}
+	return writeEnabled(c.cores, ent, out)
+}
+
+func (c *enrichCore) Sync() error {
+	return syncAll(c.cores)
+}
+
+/* -------------------------------------------------------------------------- */
+/*                             Built-in Enrichers                              */
+/* -------------------------------------------------------------------------- */
+
+// staticEnricher returns an Enricher that computes its fields once, on first
+// use.
+func staticEnricher(compute func() []Field) Enricher {
+	fields := sync.OnceValue(compute)
+	return EnricherFunc(func(context.Context) []Field { return fields() })
+}
+
+// HostnameEnricher adds the host name as "host.name".
+func HostnameEnricher() Enricher {
+	return staticEnricher(func() []Field {
+		name, err := os.Hostname()
+		if err != nil {
+			return nil
+		}
+		return []Field{String("host.name", name)}
+	})
+}
+
+// LocalIPEnricher adds the first non-loopback address of the host as
+// "host.ip", preferring IPv4.
+func LocalIPEnricher() Enricher {
+	return staticEnricher(func() []Field {
+		addrs, err := net.InterfaceAddrs()
+		if err != nil {
+			return nil
+		}
+		var v6 net.IP
+		for _, a := range addrs {
+			ipNet, ok := a.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			if ip4 := ipNet.IP.To4(); ip4 != nil {
+				return []Field{String("host.ip", ip4.String())}
+			}
+			if v6 == nil {
+				v6 = ipNet.IP
+			}
+		}
+		if v6 == nil {
+			return nil
+		}
+		return []Field{String("host.ip", v6.String())}
+	})
+}
+
+// KubernetesEnricher adds the pod metadata NewKubernetes uses
+// (k8s.pod.name, k8s.namespace.name, …) from the downward-API environment
+// variables that are set.
+func KubernetesEnricher() Enricher {
+	return staticEnricher(kubernetesMetadata)
+}
+
+// ProcessEnricher adds the process ID, the current number of goroutines and
+// the bytes of live heap objects as process.pid, process.goroutines and
+// process.heap_bytes.
+func ProcessEnricher() Enricher {
+	pid := os.Getpid()
+	return EnricherFunc(func(context.Context) []Field {
+		sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+		metrics.Read(sample)
+		fields := []Field{Int("process.pid", pid), Int("process.goroutines", runtime.NumGoroutine())}
+		if sample[0].Value.Kind() == metrics.KindUint64 {
+			fields = append(fields, Any("process.heap_bytes", sample[0].Value.Uint64()))
+		}
+		return fields
+	})
+}
+
+// ContextEnricher adds the values FieldsFromContext knows about (request,
+// trace, user, worker IDs, …) from the context passed to the *Ctx methods.
+func ContextEnricher() Enricher {
+	return EnricherFunc(FieldsFromContext)
+}
@@ -0,0 +1,134 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWithEnrichers(t *testing.T) {
+	var buf bytes.Buffer
+	region := EnricherFunc(func(context.Context) []Field {
+		return []Field{String("region", "eu-west-1"), String("zone", "a")}
+	})
+	logger, err := NewLogger(
+		WithWriterProvider(&buf, JSONEncoder),
+		WithEnrichers(region, ContextEnricher(), ProcessEnricher()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	logger.InfoCtx(ctx, "with context", String("zone", "b"))
+	logger.Info("without context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", buf.String())
+	}
+	var first, second map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if first["region"] != "eu-west-1" || first["request_id"] != "req-1" {
+		t.Errorf("missing enriched fields: %s", lines[0])
+	}
+	if first["zone"] != "b" || strings.Count(lines[0], `"zone"`) != 1 {
+		t.Errorf("call-site field should win: %s", lines[0])
+	}
+	if first["process.pid"] != float64(os.Getpid()) || first["process.goroutines"] == nil {
+		t.Errorf("missing process fields: %s", lines[0])
+	}
+	if _, ok := second["request_id"]; ok || second["region"] != "eu-west-1" {
+		t.Errorf("unexpected fields without context: %s", lines[1])
+	}
+	if strings.Contains(buf.String(), contextFieldKey) {
+		t.Errorf("context field leaked into output: %s", buf.String())
+	}
+}
+
+func TestEnrichersKeepProviderAndNamedLevels(t *testing.T) {
+	var all, errs concurrentBuffer
+	region := EnricherFunc(func(context.Context) []Field { return []Field{String("region", "eu-west-1")} })
+	logger, err := NewLogger(
+		WithWriterProvider(&all, JSONEncoder),
+		WithWriterProvider(&errs, JSONEncoder, WithProviderLevel(ErrorLevel)),
+		WithEnrichers(region),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.SetNamedLevel("db", DebugLevel)
+
+	logger.Debug("root debug")
+	logger.Named("db").Debug("db debug")
+	logger.Info("info")
+	logger.Error("failed")
+
+	if got := all.String(); strings.Contains(got, "root debug") || !strings.Contains(got, "db debug") || !strings.Contains(got, `"region":"eu-west-1"`) {
+		t.Errorf("named level not applied with enrichers: %s", got)
+	}
+	if got := errs.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, `"msg":"failed"`) {
+		t.Errorf("provider level not applied with enrichers: %s", got)
+	}
+}
+
+func TestEnrichedFieldsVisibleToFilters(t *testing.T) {
+	var buf bytes.Buffer
+	tenant := EnricherFunc(func(ctx context.Context) []Field {
+		if id, _ := ctx.Value(UserIDKey).(string); id != "" {
+			return []Field{String("tenant", id)}
+		}
+		return nil
+	})
+	logger, err := NewLogger(
+		WithWriterProvider(&buf, JSONEncoder),
+		WithEnrichers(tenant),
+		WithFilter(func(_ Entry, fields []Field) bool {
+			for _, f := range fields {
+				if f.Key == "tenant" && f.Value == "noisy" {
+					return false
+				}
+			}
+			return true
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.InfoCtx(WithUserID(context.Background(), "noisy"), "dropped")
+	logger.InfoCtx(WithUserID(context.Background(), "quiet"), "kept")
+	if out := buf.String(); strings.Contains(out, "dropped") || !strings.Contains(out, `"tenant":"quiet"`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
+
+func TestStaticEnrichers(t *testing.T) {
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skip("no host name")
+	}
+	fields := HostnameEnricher().Enrich(context.Background())
+	if len(fields) != 1 || fields[0].Value != host {
+		t.Errorf("unexpected host fields: %+v", fields)
+	}
+	for _, m := range kubernetesEnvFields {
+		t.Setenv(m.env, "")
+	}
+	t.Setenv("POD_NAME", "api-0")
+	fields = KubernetesEnricher().Enrich(context.Background())
+	if len(fields) != 1 || fields[0].Key != "k8s.pod.name" || fields[0].Value != "api-0" {
+		t.Errorf("unexpected k8s fields: %+v", fields)
+	}
+}
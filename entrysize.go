@@ -0,0 +1,181 @@
+package golog
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                            Maximum Entry Size                               */
+/* -------------------------------------------------------------------------- */
+
+// OversizePolicy decides what happens to an entry over the WithMaxEntrySize
+// limit.
+type OversizePolicy int
+
+const (
+	// OversizeTruncate cuts the longest strings – message, stack trace and
+	// string fields – until the entry fits, and adds "truncated_from" with
+	// the original size in bytes.
+	OversizeTruncate OversizePolicy = iota
+	// OversizeReplace writes a summary entry at the same level instead,
+	// carrying the start of the message and the original size, and counts
+	// the original as DropOversized.
+	OversizeReplace
+)
+
+// DropOversized counts entries replaced under OversizeReplace.
+const DropOversized DropReason = "oversized"
+
+// oversizeAttempts bounds the truncation passes before an entry is replaced.
+const oversizeAttempts = 8
+
+// oversizeSummaryPrefix is how much of the message a summary entry keeps.
+const oversizeSummaryPrefix = 200
+
+// WithMaxEntrySize limits entries to maxBytes as encoded to JSON, for sinks
+// such as Cloud Logging (256 KiB) or Loki that reject larger entries
+// outright. policy chooses between truncating and replacing oversized
+// entries. The size is measured after transforms and redaction, on an
+// additional encoding of each entry.
+func WithMaxEntrySize(maxBytes int, policy OversizePolicy) LoggerOption {
+	return func(cfg *loggerConfig) {
+		if maxBytes <= 0 {
+			cfg.optionErrs = append(cfg.optionErrs, fmt.Errorf("WithMaxEntrySize: non-positive size %d", maxBytes))
+			return
+		}
+		cfg.maxEntrySize = maxBytes
+		cfg.oversizePolicy = policy
+	}
+}
+
+// sizeCore measures each entry and truncates or replaces it when it exceeds
+// max. Like transformCore it keeps context fields itself, since truncation
+// may rewrite them.
+type sizeCore struct {
+	cores  []zapcore.Core
+	max    int
+	policy OversizePolicy
+	drops  *dropCounter
+	enc    zapcore.Encoder
+	fields []zapcore.Field
+}
+
+func newSizeCore(cores []zapcore.Core, max int, policy OversizePolicy, drops *dropCounter) *sizeCore {
+	return &sizeCore{
+		cores:  cores,
+		max:    max,
+		policy: policy,
+		drops:  drops,
+		enc:    zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+	}
+}
+
+func (c *sizeCore) Enabled(lvl zapcore.Level) bool {
+	return anyEnabled(c.cores, lvl)
+}
+
+func (c *sizeCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+func (c *sizeCore) enabledFor(ent zapcore.Entry) bool {
+	return anyEntryEnabled(c.cores, ent)
+}
+
+func (c *sizeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabledFor(ent) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sizeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := append(append([]zapcore.Field(nil), c.fields...), fields...)
+	size := c.size(ent, all)
+	if size <= c.max {
+		return writeEnabled(c.cores, ent, all)
+	}
+	if c.policy == OversizeTruncate {
+		if tent, tfields, ok := c.truncate(ent, all, size); ok {
+			return writeEnabled(c.cores, tent, tfields)
+		}
+	}
+	c.drops.record(DropOversized, 1)
+	msg := ent.Message
+	if len(msg) > oversizeSummaryPrefix {
+		msg = msg[:oversizeSummaryPrefix] + "…"
+	}
+	summary := ent
+	summary.Message = "oversized log entry replaced"
+	summary.Stack = ""
+	return writeEnabled(c.cores, summary, []zapcore.Field{
+		zap.String("original_msg", msg),
+		zap.Int("original_size", size),
+		zap.Int("max_size", c.max),
+	})
+}
+
+// size returns the length of ent encoded as JSON, or 0 if it cannot be
+// encoded; the providers will then report the error themselves.
+func (c *sizeCore) size(ent zapcore.Entry, fields []zapcore.Field) int {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return 0
+	}
+	defer buf.Free()
+	return buf.Len()
+}
+
+// truncate repeatedly shortens the longest string until the entry fits,
+// reporting false if it cannot be made to.
+func (c *sizeCore) truncate(ent zapcore.Entry, fields []zapcore.Field, size int) (zapcore.Entry, []zapcore.Field, bool) {
+	e := decodeEntry(ent, nil, fields)
+	if e.Fields == nil {
+		e.Fields = map[string]interface{}{}
+	}
+	e.Fields["truncated_from"] = size
+	for i := 0; i < oversizeAttempts; i++ {
+		longest := &e.Message
+		if len(e.Stack) > len(e.Message) {
+			longest = &e.Stack
+		}
+		longestKey := ""
+		for k, v := range e.Fields {
+			if s, ok := v.(string); ok && len(s) > len(*longest) {
+				longest, longestKey = &s, k
+			}
+		}
+		// Cut the excess plus room for the marker; escaping may need
+		// another pass.
+		keep := len(*longest) - (size - c.max) - len("…")
+		if keep < 0 {
+			keep = 0
+		}
+		for keep > 0 && !utf8.RuneStart((*longest)[keep]) {
+			keep--
+		}
+		cut := (*longest)[:keep] + "…"
+		if longestKey != "" {
+			e.Fields[longestKey] = cut
+		} else {
+			*longest = cut
+		}
+
+		ent.Message, ent.Stack = e.Message, e.Stack
+		fields = mapToZapFields(e.Fields)
+		if size = c.size(ent, fields); size <= c.max {
+			return ent, fields, true
+		}
+	}
+	return ent, nil, false
+}
+
+func (c *sizeCore) Sync() error {
+	return syncAll(c.cores)
+}
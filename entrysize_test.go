@@ -0,0 +1,65 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithMaxEntrySizeTruncate(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithMaxEntrySize(300, OversizeTruncate))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("small", String("k", "v"))
+	logger.Info("big", String("body", strings.Repeat("é", 500)), String("id", "42"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", lines)
+	}
+	if strings.Contains(lines[0], "truncated_from") {
+		t.Errorf("small entry truncated: %s", lines[0])
+	}
+	if len(lines[1]) > 300 {
+		t.Errorf("entry still %d bytes: %s", len(lines[1]), lines[1])
+	}
+	var entry struct {
+		Body          string
+		ID            string
+		TruncatedFrom int `json:"truncated_from"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if entry.ID != "42" || !strings.HasSuffix(entry.Body, "é…") || entry.TruncatedFrom <= 300 {
+		t.Errorf("unexpected truncated entry: %s", lines[1])
+	}
+}
+
+func TestWithMaxEntrySizeReplace(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithMaxEntrySize(300, OversizeReplace))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Warn("huge payload", String("body", strings.Repeat("x", 1000)))
+
+	out := buf.String()
+	if strings.Contains(out, "xxxx") || !strings.Contains(out, `"level":"warn","ts"`) ||
+		!strings.Contains(out, `"msg":"oversized log entry replaced","original_msg":"huge payload"`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+	if n := logger.DroppedEntries()[DropOversized]; n != 1 {
+		t.Errorf("DropOversized = %d", n)
+	}
+	if _, err := NewLogger(WithMaxEntrySize(0, OversizeReplace)); err == nil {
+		t.Error("expected an error for a zero size")
+	}
+}
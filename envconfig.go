@@ -0,0 +1,214 @@
+package golog
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evdnx/golog/internal/term"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                     Construction from Environment Variables                 */
+/* -------------------------------------------------------------------------- */
+
+// NewFromEnv builds a logger entirely from GOLOG_* environment variables, so
+// each deployment can configure logging without code changes:
+//
+//	GOLOG_LEVEL              trace, debug, info (default), warn, error or fatal
+//	GOLOG_PROVIDERS          comma-separated list of stdout (default), file,
+//	                         gcp, http, syslog or other registered provider
+//	                         names
+//	GOLOG_FORMAT             stdout encoder: json (default), console, pretty
+//	                         (coloured on terminals), xml, ltsv or csv
+//	GOLOG_FILE_PATH          file provider: path, required
+//	GOLOG_FILE_MAX_SIZE      file provider: megabytes before rotating (100)
+//	GOLOG_FILE_MAX_BACKUPS   file provider: rotated files to keep (all)
+//	GOLOG_FILE_MAX_AGE       file provider: days to keep rotated files (all)
+//	GOLOG_FILE_COMPRESS      file provider: gzip rotated files (false)
+//	GOLOG_GCP_PROJECT        gcp provider: project ID, required
+//	GOLOG_GCP_LOG_NAME       gcp provider: log name, required
+//	GOLOG_HTTP_ENDPOINT      http provider: URL, required
+//	GOLOG_HTTP_COMPRESSION   http provider: gzip or zstd (default none)
+//	GOLOG_SYSLOG_NETWORK     syslog provider: udp, tcp, unix or unixgram
+//	                         (default the local daemon)
+//	GOLOG_SYSLOG_ADDRESS     syslog provider: address of the daemon
+//	GOLOG_SYSLOG_FORMAT      syslog provider: rfc3164 (default) or rfc5424
+//	GOLOG_SYSLOG_FACILITY    syslog provider: facility name, e.g. local0
+//	GOLOG_SYSLOG_TLS         syslog provider: true for TLS over tcp
+//	GOLOG_STACKTRACE         level from which entries carry a stack trace
+//	GOLOG_CALLER             false omits the caller (default true)
+//	GOLOG_TIME_FORMAT        timestamp format, see WithTimeFormat
+//	GOLOG_TIME_ZONE          IANA time zone of timestamps, e.g. UTC
+//
+// Unset variables keep their defaults; invalid values are reported as
+// errors naming the variable. Further options are applied after the
+// environment.
+func NewFromEnv(options ...LoggerOption) (*Logger, error) {
+	opts, err := envOptions(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	return NewLogger(append(opts, options...)...)
+}
+
+func envOptions(getenv func(string) string) ([]LoggerOption, error) {
+	var opts []LoggerOption
+	if v := getenv("GOLOG_LEVEL"); v != "" {
+		lvl, err := ParseLevel(v)
+		if err != nil {
+			return nil, fmt.Errorf("GOLOG_LEVEL: %w", err)
+		}
+		opts = append(opts, WithLevel(lvl))
+	}
+	if v := getenv("GOLOG_STACKTRACE"); v != "" {
+		lvl, err := ParseLevel(v)
+		if err != nil {
+			return nil, fmt.Errorf("GOLOG_STACKTRACE: %w", err)
+		}
+		opts = append(opts, WithStacktrace(lvl))
+	}
+	if v := getenv("GOLOG_CALLER"); v != "" {
+		caller, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("GOLOG_CALLER: %w", err)
+		}
+		if !caller {
+			opts = append(opts, WithoutCaller())
+		}
+	}
+
+	if v := getenv("GOLOG_TIME_FORMAT"); v != "" {
+		opts = append(opts, WithTimeFormat(v))
+	}
+	if v := getenv("GOLOG_TIME_ZONE"); v != "" {
+		loc, err := time.LoadLocation(v)
+		if err != nil {
+			return nil, fmt.Errorf("GOLOG_TIME_ZONE: %w", err)
+		}
+		opts = append(opts, WithTimeZone(loc))
+	}
+
+	names := getenv("GOLOG_PROVIDERS")
+	if names == "" {
+		names = "stdout"
+	}
+	for _, name := range strings.Split(names, ",") {
+		opt, err := envProvider(strings.ToLower(strings.TrimSpace(name)), getenv)
+		if err != nil {
+			return nil, err
+		}
+		if opt != nil {
+			opts = append(opts, opt)
+		}
+	}
+	return opts, nil
+}
+
+// envProvider returns the option registering the provider name configured
+// from its GOLOG_* variables; empty names yield nil.
+func envProvider(name string, getenv func(string) string) (LoggerOption, error) {
+	required := func(key string) (string, error) {
+		v := getenv(key)
+		if v == "" {
+			return "", fmt.Errorf("%s is required for the %s provider", key, name)
+		}
+		return v, nil
+	}
+	intVar := func(key string, def int) (int, error) {
+		v := getenv(key)
+		if v == "" {
+			return def, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%s: expected a non-negative integer, got %q", key, v)
+		}
+		return n, nil
+	}
+
+	switch name {
+	case "":
+		return nil, nil
+	case "stdout":
+		switch v := strings.ToLower(getenv("GOLOG_FORMAT")); v {
+		case "", "json":
+			return WithStdOutProvider(JSONEncoder), nil
+		case "console", "text":
+			return WithStdOutProvider(ConsoleEncoder), nil
+		case "pretty":
+			if term.ColorEnabled(os.Stdout, getenv) {
+				return WithStdOutProvider(PrettyColorEncoder), nil
+			}
+			return WithStdOutProvider(PrettyEncoder), nil
+		case "xml", "ltsv", "csv":
+			return WithStdOutProvider(EncoderType(v)), nil
+		default:
+			return nil, fmt.Errorf("GOLOG_FORMAT: unsupported value %q", v)
+		}
+	case "file":
+		path, err := required("GOLOG_FILE_PATH")
+		if err != nil {
+			return nil, err
+		}
+		maxSize, err := intVar("GOLOG_FILE_MAX_SIZE", 100)
+		if err != nil {
+			return nil, err
+		}
+		maxBackups, err := intVar("GOLOG_FILE_MAX_BACKUPS", 0)
+		if err != nil {
+			return nil, err
+		}
+		maxAge, err := intVar("GOLOG_FILE_MAX_AGE", 0)
+		if err != nil {
+			return nil, err
+		}
+		compress := false
+		if v := getenv("GOLOG_FILE_COMPRESS"); v != "" {
+			if compress, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("GOLOG_FILE_COMPRESS: %w", err)
+			}
+		}
+		return WithFileProvider(path, maxSize, maxBackups, maxAge, compress), nil
+	case "gcp":
+		project, err := required("GOLOG_GCP_PROJECT")
+		if err != nil {
+			return nil, err
+		}
+		logName, err := required("GOLOG_GCP_LOG_NAME")
+		if err != nil {
+			return nil, err
+		}
+		return WithGCPProvider(project, logName), nil
+	case "http":
+		endpoint, err := required("GOLOG_HTTP_ENDPOINT")
+		if err != nil {
+			return nil, err
+		}
+		return WithHTTPProvider(endpoint, HTTPConfig{Compression: Compression(getenv("GOLOG_HTTP_COMPRESSION"))}), nil
+	case "syslog":
+		cfg := SyslogConfig{Format: SyslogFormat(strings.ToLower(getenv("GOLOG_SYSLOG_FORMAT")))}
+		if v := getenv("GOLOG_SYSLOG_FACILITY"); v != "" {
+			facility, err := parseSyslogFacility(v)
+			if err != nil {
+				return nil, fmt.Errorf("GOLOG_SYSLOG_FACILITY: %w", err)
+			}
+			cfg.Facility = facility
+		}
+		if v := getenv("GOLOG_SYSLOG_TLS"); v != "" {
+			useTLS, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("GOLOG_SYSLOG_TLS: %w", err)
+			}
+			if useTLS {
+				cfg.TLS = &TLSConfig{}
+			}
+		}
+		return WithSyslogProvider(getenv("GOLOG_SYSLOG_NETWORK"), getenv("GOLOG_SYSLOG_ADDRESS"), cfg), nil
+	default:
+		// Providers registered by other packages take no parameters here.
+		return WithNamedProvider(name, nil), nil
+	}
+}
@@ -0,0 +1,87 @@
+package golog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvOptions(t *testing.T) {
+	env := func(m map[string]string) func(string) string {
+		return func(k string) string { return m[k] }
+	}
+
+	cfg := &loggerConfig{}
+	opts, err := envOptions(env(map[string]string{
+		"GOLOG_LEVEL":            "debug",
+		"GOLOG_PROVIDERS":        "stdout, file",
+		"GOLOG_FORMAT":           "console",
+		"GOLOG_FILE_PATH":        "/var/log/app.log",
+		"GOLOG_FILE_MAX_BACKUPS": "3",
+		"GOLOG_FILE_COMPRESS":    "true",
+		"GOLOG_STACKTRACE":       "error",
+		"GOLOG_CALLER":           "false",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.level != DebugLevel || !cfg.withoutCaller || cfg.stacktrace == nil || *cfg.stacktrace != ErrorLevel {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if len(cfg.providers) != 2 {
+		t.Fatalf("unexpected providers: %#v", cfg.providers)
+	}
+	if sp, ok := cfg.providers[0].(*stdOutProvider); !ok || *sp != (stdOutProvider{encoderType: ConsoleEncoder}) {
+		t.Fatalf("unexpected providers: %#v", cfg.providers)
+	}
+	fp, ok := cfg.providers[1].(*fileProvider)
+	if !ok || fp.filename != "/var/log/app.log" || fp.maxSize != 100 || fp.maxBackups != 3 || !fp.compress {
+		t.Errorf("unexpected file provider: %#v", cfg.providers[1])
+	}
+
+	for _, tc := range []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"GOLOG_LEVEL": "loud"}, "GOLOG_LEVEL"},
+		{map[string]string{"GOLOG_FORMAT": "yaml"}, "GOLOG_FORMAT"},
+		{map[string]string{"GOLOG_CALLER": "maybe"}, "GOLOG_CALLER"},
+		{map[string]string{"GOLOG_PROVIDERS": "file"}, "GOLOG_FILE_PATH is required"},
+		{map[string]string{"GOLOG_PROVIDERS": "file", "GOLOG_FILE_PATH": "a.log", "GOLOG_FILE_MAX_SIZE": "big"}, "GOLOG_FILE_MAX_SIZE"},
+		{map[string]string{"GOLOG_PROVIDERS": "gcp", "GOLOG_GCP_PROJECT": "p"}, "GOLOG_GCP_LOG_NAME is required"},
+		{map[string]string{"GOLOG_PROVIDERS": "http"}, "GOLOG_HTTP_ENDPOINT is required"},
+		{map[string]string{"GOLOG_PROVIDERS": "syslog", "GOLOG_SYSLOG_FACILITY": "local9"}, "GOLOG_SYSLOG_FACILITY"},
+		{map[string]string{"GOLOG_PROVIDERS": "syslog", "GOLOG_SYSLOG_TLS": "maybe"}, "GOLOG_SYSLOG_TLS"},
+	} {
+		if _, err := envOptions(env(tc.env)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v: expected error containing %q, got %v", tc.env, tc.want, err)
+		}
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv("GOLOG_PROVIDERS", "file")
+	t.Setenv("GOLOG_FILE_PATH", path)
+	t.Setenv("GOLOG_LEVEL", "warn")
+
+	logger, err := NewFromEnv()
+	if err != nil {
+		t.Fatalf("NewFromEnv: %v", err)
+	}
+	logger.Info("hidden")
+	logger.Warn("shown")
+	logger.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := string(data); strings.Contains(out, "hidden") || !strings.Contains(out, `"msg":"shown"`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+}
@@ -0,0 +1,132 @@
+package golog
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          Internal Error Output                              */
+/* -------------------------------------------------------------------------- */
+
+// errorSink collects the logger's *internal* failures – encoder errors, sink
+// write errors, asynchronous provider errors – and forwards them to the
+// configured writer and/or callback. It doubles as the zapcore.WriteSyncer
+// handed to zap.ErrorOutput so zap's own reports take the same route.
+type errorSink struct {
+	mu      sync.Mutex
+	out     zapcore.WriteSyncer
+	handler func(error)
+
+	lastErr error
+	lastAt  time.Time
+	// recent holds the last maxRecentErrors errors, oldest first.
+	recent []errorRecord
+}
+
+// maxRecentErrors bounds the history kept for the debug handler.
+const maxRecentErrors = 32
+
+func newErrorSink(out io.Writer, handler func(error)) *errorSink {
+	s := &errorSink{handler: handler}
+	if out != nil {
+		s.out = zapcore.AddSync(out)
+	}
+	// Without any explicit destination keep zap's default behaviour.
+	if s.out == nil && s.handler == nil {
+		s.out = zapcore.Lock(os.Stderr)
+	}
+	return s
+}
+
+// Write receives zap's pre-formatted error lines.
+func (s *errorSink) Write(p []byte) (int, error) {
+	msg := strings.TrimSpace(string(p))
+	s.mu.Lock()
+	if s.out != nil {
+		_, _ = s.out.Write(p)
+	}
+	if msg != "" {
+		s.remember(errors.New(msg))
+	}
+	err := s.lastErr
+	s.mu.Unlock()
+
+	if s.handler != nil && msg != "" {
+		s.handler(err)
+	}
+	return len(p), nil
+}
+
+func (s *errorSink) Sync() error {
+	if s.out == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return ignoreSyncError(s.out.Sync())
+}
+
+// report surfaces an error raised outside zap's write path (e.g. the GCP
+// client's background flush).
+func (s *errorSink) report(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.out != nil {
+		_, _ = io.WriteString(s.out, err.Error()+"\n")
+	}
+	s.remember(err)
+	s.mu.Unlock()
+
+	if s.handler != nil {
+		s.handler(err)
+	}
+}
+
+// remember records err as the latest error. Callers must hold s.mu.
+func (s *errorSink) remember(err error) {
+	s.lastErr, s.lastAt = err, time.Now()
+	if len(s.recent) == maxRecentErrors {
+		s.recent = append(s.recent[:0], s.recent[1:]...)
+	}
+	s.recent = append(s.recent, errorRecord{Time: s.lastAt, Error: err.Error()})
+}
+
+// recentErrors returns a copy of the recent error history.
+func (s *errorSink) recentErrors() []errorRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]errorRecord(nil), s.recent...)
+}
+
+// last returns the most recent internal error and when it was reported.
+func (s *errorSink) last() (error, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr, s.lastAt
+}
+
+// WithErrorOutput directs golog's internal errors (failed writes, encoder
+// errors, asynchronous provider failures) to w. Defaults to stderr.
+func WithErrorOutput(w io.Writer) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.errorOutput = w
+	}
+}
+
+// WithErrorHandler registers a callback invoked for every internal error. It
+// can be combined with WithErrorOutput; when used alone nothing is written to
+// stderr.
+func WithErrorHandler(fn func(error)) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.errorHandler = fn
+	}
+}
@@ -0,0 +1,101 @@
+package golog
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"strconv"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                        Stack Traces of Wrapped Errors                       */
+/* -------------------------------------------------------------------------- */
+
+// ErrVerbose logs err under "error" as an object with its message and, if an
+// error in its chain captured one, the stack trace of where it was created:
+//
+//	{"error": {"message": "load config: open app.yaml: no such file",
+//	           "stack": ["main.loadConfig (/src/app/config.go:42)", "main.main (/src/app/main.go:12)"]}}
+//
+// Stacks are recognised on errors with a StackTrace method returning a slice
+// of program counters, as github.com/pkg/errors and compatible packages
+// provide, or with a Callers() []uintptr method. The chain is followed
+// through Unwrap, and the innermost stack, closest to the failure, is used.
+// Err keeps logging the message alone.
+func ErrVerbose(err error) Field {
+	if err == nil {
+		return Field{Key: "error", Value: nil}
+	}
+	return Field{Key: "error", Value: errorDetails{err: err, fields: errorFielderFields(err)}}
+}
+
+// errorDetails encodes an error with its stack trace.
+type errorDetails struct {
+	err error
+	// fields are those of the LogFielder in the chain.
+	fields []Field
+}
+
+func (e errorDetails) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("message", e.err.Error())
+	if pcs := errorStack(e.err); len(pcs) > 0 {
+		if err := enc.AddArray("stack", stackFrames(pcs)); err != nil {
+			return err
+		}
+	}
+	for _, f := range toZapFields(e.fields) {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// stackFrames encodes program counters as "function (file:line)" strings.
+type stackFrames []uintptr
+
+func (s stackFrames) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	frames := runtime.CallersFrames(s)
+	for {
+		f, more := frames.Next()
+		if f.Function != "" || f.File != "" {
+			enc.AppendString(f.Function + " (" + f.File + ":" + strconv.Itoa(f.Line) + ")")
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// errorStack returns the innermost stack trace in err's chain.
+func errorStack(err error) []uintptr {
+	var pcs []uintptr
+	for ; err != nil; err = errors.Unwrap(err) {
+		if s := stackOf(err); len(s) > 0 {
+			pcs = s
+		}
+	}
+	return pcs
+}
+
+// stackOf returns the program counters err recorded, if any.
+func stackOf(err error) []uintptr {
+	if c, ok := err.(interface{ Callers() []uintptr }); ok {
+		return c.Callers()
+	}
+	// pkg/errors.StackTrace is []Frame with Frame a uintptr; match it by
+	// shape so the package need not be imported.
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() {
+		return nil
+	}
+	if t := m.Type(); t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Slice || t.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	v := m.Call(nil)[0]
+	pcs := make([]uintptr, v.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(v.Index(i).Uint())
+	}
+	return pcs
+}
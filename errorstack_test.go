@@ -0,0 +1,90 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// frame and stackError mimic github.com/pkg/errors.
+type frame uintptr
+
+type stackError struct {
+	msg   string
+	stack []uintptr
+}
+
+func newStackError(msg string) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &stackError{msg: msg, stack: pcs[:n]}
+}
+
+func (e *stackError) Error() string { return e.msg }
+
+func (e *stackError) StackTrace() []frame {
+	frames := make([]frame, len(e.stack))
+	for i, pc := range e.stack {
+		frames[i] = frame(pc)
+	}
+	return frames
+}
+
+// callersError mimics github.com/go-errors/errors.
+type callersError struct{ pcs []uintptr }
+
+func (e callersError) Error() string      { return "callers" }
+func (e callersError) Callers() []uintptr { return e.pcs }
+
+func failDeep() error { return newStackError("disk full") }
+
+func TestErrVerbose(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Error("save failed", ErrVerbose(fmt.Errorf("save: %w", failDeep())))
+	logger.Error("plain", ErrVerbose(errors.New("no stack")))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", lines)
+	}
+	var entry struct {
+		Error struct {
+			Message string   `json:"message"`
+			Stack   []string `json:"stack"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if entry.Error.Message != "save: disk full" {
+		t.Errorf("message = %q", entry.Error.Message)
+	}
+	if len(entry.Error.Stack) < 2 || !strings.HasPrefix(entry.Error.Stack[0], "github.com/evdnx/golog.failDeep (") ||
+		!strings.HasPrefix(entry.Error.Stack[1], "github.com/evdnx/golog.TestErrVerbose (") {
+		t.Errorf("unexpected stack: %q", entry.Error.Stack)
+	}
+	if !strings.Contains(lines[1], `"error":{"message":"no stack"}`) {
+		t.Errorf("error without a stack: %s", lines[1])
+	}
+}
+
+func TestErrorStackCallers(t *testing.T) {
+	pcs := make([]uintptr, 8)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+	if got := errorStack(fmt.Errorf("wrapped: %w", callersError{pcs})); len(got) != len(pcs) {
+		t.Errorf("Callers stack not found: %v", got)
+	}
+	if got := ErrVerbose(nil); got.Key != "error" || got.Value != nil {
+		t.Errorf("ErrVerbose(nil) = %+v", got)
+	}
+}
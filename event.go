@@ -0,0 +1,187 @@
+package golog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                           Builder-Style Event API                           */
+/* -------------------------------------------------------------------------- */
+
+// Event is a single entry under construction, for callers who prefer a
+// fluent style to variadic Fields:
+//
+//	logger.Event(golog.InfoLevel).Str("user", u).Int("count", n).Msg("signup complete")
+//
+// Fields are stored as zap fields directly, without the boxing Field
+// involves, and events are pooled. When the level is disabled Event returns
+// nil, on which every method is a no-op, so building a discarded entry costs
+// nothing. An Event must not be used after Msg, Msgf or Send.
+type Event struct {
+	logger *Logger
+	level  zapcore.Level
+	fields []zapcore.Field
+}
+
+var eventPool = sync.Pool{New: func() interface{} {
+	return &Event{fields: make([]zapcore.Field, 0, 8)}
+}}
+
+// Event starts an entry at level, or returns nil if level is disabled.
+func (l *Logger) Event(level Level) *Event {
+	lvl := toZapLevel(level)
+	if !l.zapLogger.Core().Enabled(lvl) && lvl < zapcore.DPanicLevel {
+		return nil
+	}
+	e := eventPool.Get().(*Event)
+	e.logger, e.level = l, lvl
+	return e
+}
+
+// Str adds a string field.
+func (e *Event) Str(key, value string) *Event {
+	if e != nil {
+		e.fields = append(e.fields, zap.String(key, value))
+	}
+	return e
+}
+
+// Int adds an int field.
+func (e *Event) Int(key string, value int) *Event {
+	if e != nil {
+		e.fields = append(e.fields, zap.Int(key, value))
+	}
+	return e
+}
+
+// Int64 adds an int64 field.
+func (e *Event) Int64(key string, value int64) *Event {
+	if e != nil {
+		e.fields = append(e.fields, zap.Int64(key, value))
+	}
+	return e
+}
+
+// Uint64 adds a uint64 field.
+func (e *Event) Uint64(key string, value uint64) *Event {
+	if e != nil {
+		e.fields = append(e.fields, zap.Uint64(key, value))
+	}
+	return e
+}
+
+// Float64 adds a float64 field.
+func (e *Event) Float64(key string, value float64) *Event {
+	if e != nil {
+		e.fields = append(e.fields, zap.Float64(key, value))
+	}
+	return e
+}
+
+// Bool adds a bool field.
+func (e *Event) Bool(key string, value bool) *Event {
+	if e != nil {
+		e.fields = append(e.fields, zap.Bool(key, value))
+	}
+	return e
+}
+
+// Dur adds a time.Duration field.
+func (e *Event) Dur(key string, value time.Duration) *Event {
+	if e != nil {
+		e.fields = append(e.fields, zap.Duration(key, value))
+	}
+	return e
+}
+
+// Time adds a time.Time field.
+func (e *Event) Time(key string, value time.Time) *Event {
+	if e != nil {
+		e.fields = append(e.fields, zap.Time(key, value))
+	}
+	return e
+}
+
+// Err adds err under "error" and, like the Err field, the fields of a
+// LogFielder in its chain; a nil err adds nothing.
+func (e *Event) Err(err error) *Event {
+	if e != nil && err != nil {
+		e.fields = append(e.fields, errorField(err))
+	}
+	return e
+}
+
+// Any adds a field of arbitrary type.
+func (e *Event) Any(key string, value interface{}) *Event {
+	if e != nil {
+		e.fields = append(e.fields, zap.Any(key, value))
+	}
+	return e
+}
+
+// Fields adds Fields built with the regular helpers.
+func (e *Event) Fields(fields ...Field) *Event {
+	if e != nil {
+		e.fields = append(e.fields, toZapFields(fields)...)
+	}
+	return e
+}
+
+// Ctx passes ctx to the configured enrichers, as the *Ctx methods do.
+func (e *Event) Ctx(ctx context.Context) *Event {
+	if e != nil {
+		e.fields = append(e.fields, e.logger.contextFields(ctx, nil)...)
+	}
+	return e
+}
+
+// Msg writes the entry with msg as its message.
+func (e *Event) Msg(msg string) {
+	if e == nil {
+		return
+	}
+	// Msg, Msgf and Send each call Check themselves so the caller is one
+	// frame up, as from the Logger methods.
+	if ce := e.logger.zapLogger.Check(e.level, msg); ce != nil {
+		ce.Write(e.fields...)
+	}
+	e.release()
+}
+
+// Msgf writes the entry with a formatted message.
+func (e *Event) Msgf(format string, args ...interface{}) {
+	if e == nil {
+		return
+	}
+	if ce := e.logger.zapLogger.Check(e.level, fmt.Sprintf(format, args...)); ce != nil {
+		ce.Write(e.fields...)
+	}
+	e.release()
+}
+
+// Send writes the entry with an empty message.
+func (e *Event) Send() {
+	if e == nil {
+		return
+	}
+	if ce := e.logger.zapLogger.Check(e.level, ""); ce != nil {
+		ce.Write(e.fields...)
+	}
+	e.release()
+}
+
+func (e *Event) release() {
+	// Don't let the pool pin large field slices.
+	if cap(e.fields) > 64 {
+		return
+	}
+	clear(e.fields)
+	e.logger, e.fields = nil, e.fields[:0]
+	eventPool.Put(e)
+}
@@ -0,0 +1,63 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvent(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Event(InfoLevel).
+		Str("user", "alice").
+		Int("count", 3).
+		Bool("new", true).
+		Dur("took", 1500*time.Millisecond).
+		Err(errors.New("partial")).
+		Fields(String("plan", "pro")).
+		Msg("signup complete")
+	logger.Event(WarnLevel).Err(nil).Msgf("retry %d", 2)
+
+	out := buf.String()
+	for _, want := range []string{
+		`"msg":"signup complete"`, `"user":"alice"`, `"count":3`, `"new":true`,
+		`"took":"1.5s"`, `"error":"partial"`, `"plan":"pro"`, `"msg":"retry 2"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in output: %s", want, out)
+		}
+	}
+	if strings.Count(out, `"error"`) != 1 {
+		t.Errorf("nil error should add no field: %s", out)
+	}
+}
+
+func TestEventDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithLevel(WarnLevel))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	if e := logger.Event(DebugLevel); e != nil {
+		t.Fatal("expected nil event for disabled level")
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Event(InfoLevel).Str("k", "v").Int("n", 1).Msg("dropped")
+	})
+	if allocs != 0 {
+		t.Errorf("disabled event allocated %v times", allocs)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("disabled event written: %s", buf.String())
+	}
+}
@@ -0,0 +1,205 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                         Windows Event Log Provider                          */
+/* -------------------------------------------------------------------------- */
+
+// eventLogMaxMessage is the longest string, in bytes here, that a single
+// event may carry.
+const eventLogMaxMessage = 31839
+
+// eventIDKey is the key of the field set by EventID.
+const eventIDKey = "event_id"
+
+// EventID sets the event ID of an entry written to the Windows Event Log,
+// overriding EventLogConfig. Other providers log it as an ordinary
+// "event_id" field.
+func EventID(id uint32) Field { return Field{Key: eventIDKey, Value: id} }
+
+// EventLogConfig configures WithEventLogProvider. Zero values fall back to
+// the defaults noted on each field.
+type EventLogConfig struct {
+	// EventID is the event ID of entries without an EventID field or an
+	// entry in EventIDs (default 1).
+	EventID uint32
+	// EventIDs gives levels their own event IDs, e.g. to filter warnings
+	// in Event Viewer.
+	EventIDs map[Level]uint32
+	// Install registers the source on start if it is not registered yet,
+	// which needs administrator rights; see InstallEventLogSource.
+	Install bool
+}
+
+// WithEventLogProvider writes entries to the Application log of the Windows
+// Event Log under source, typically the service name:
+//
+//	golog.WithEventLogProvider("MyService", golog.EventLogConfig{
+//		EventIDs: map[golog.Level]uint32{golog.ErrorLevel: 100},
+//	})
+//
+// Trace, Debug and Info entries become Information events, Warn entries
+// Warning events and Error and above Error events. The event text is the
+// message followed by the fields as key=value pairs and the stack trace, or
+// the encoded entry with WithProviderEncoder; Event Viewer records the time
+// and level itself. Text beyond the 31,839-byte event limit is cut.
+//
+// Register the source once, from an installer running as administrator,
+// with InstallEventLogSource; events of an unregistered source are still
+// written but Event Viewer shows them with a warning. On other systems
+// NewLogger fails with ErrEventLogUnsupported.
+func WithEventLogProvider(source string, cfg EventLogConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&eventLogProvider{
+			providerBase: providerBase{name: "eventlog:" + source, encoders: anyEncoder},
+			source:       source,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+// ErrEventLogUnsupported is returned outside Windows by the Event Log
+// functions.
+var ErrEventLogUnsupported = errors.New("the Windows Event Log is only available on Windows")
+
+// InstallEventLogSource registers source in the Application log, using the
+// message file of EventCreate.exe, which passes event text through for IDs
+// 1 to 1000. It needs administrator rights and succeeds if source is
+// already registered.
+func InstallEventLogSource(source string) error { return installEventLogSource(source) }
+
+// RemoveEventLogSource removes the registration of source, if any.
+func RemoveEventLogSource(source string) error { return removeEventLogSource(source) }
+
+// eventLog is the handle of a registered source, an *eventlog.Log on
+// Windows.
+type eventLog interface {
+	Info(eid uint32, msg string) error
+	Warning(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+	Close() error
+}
+
+type eventLogProvider struct {
+	providerBase
+	source string
+	cfg    EventLogConfig
+
+	log eventLog
+}
+
+func (p *eventLogProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	if p.source == "" {
+		return nil, errors.New("eventlog provider: source is required")
+	}
+	msg, err := newMessageEncoder(p.tel, p.encoderType, p.encoderConfig)
+	if err != nil {
+		return nil, err
+	}
+	if p.cfg.Install {
+		if err := installEventLogSource(p.source); err != nil {
+			return nil, fmt.Errorf("eventlog provider: %w", err)
+		}
+	}
+	log, err := openEventLog(p.source)
+	if err != nil {
+		return nil, fmt.Errorf("eventlog provider: %w", err)
+	}
+	p.log = log
+	return &eventLogCore{LevelEnabler: level, cfg: p.cfg, log: log, msg: msg}, nil
+}
+
+func (p *eventLogProvider) close() error {
+	if p.log == nil {
+		return nil
+	}
+	return p.log.Close()
+}
+
+func (p *eventLogProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: p.encoderType}
+}
+
+type eventLogCore struct {
+	zapcore.LevelEnabler
+	cfg EventLogConfig
+	log eventLog
+	msg messageEncoder
+	// eventID is set by an EventID field passed to With.
+	eventID uint32
+}
+
+func (c *eventLogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	fields, clone.eventID = takeEventID(fields, c.eventID)
+	clone.msg = c.msg.with(fields)
+	return &clone
+}
+
+func (c *eventLogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *eventLogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields, eid := takeEventID(fields, c.eventID)
+	if eid == 0 {
+		eid = c.cfg.EventIDs[fromZapLevel(ent.Level)]
+	}
+	if eid == 0 {
+		eid = c.cfg.EventID
+	}
+	if eid == 0 {
+		eid = 1
+	}
+
+	buf := encoderPool.Get()
+	defer buf.Free()
+	if err := c.msg.appendEntry(buf, ent, fields); err != nil {
+		return err
+	}
+	text := truncateUTF8(buf.String(), eventLogMaxMessage)
+	switch {
+	case ent.Level >= zapcore.ErrorLevel:
+		return c.log.Error(eid, text)
+	case ent.Level == zapcore.WarnLevel:
+		return c.log.Warning(eid, text)
+	}
+	return c.log.Info(eid, text)
+}
+
+func (c *eventLogCore) Sync() error { return nil }
+
+// takeEventID removes EventID fields from fields, returning the last ID
+// given, or def.
+func takeEventID(fields []zapcore.Field, def uint32) ([]zapcore.Field, uint32) {
+	out := fields[:0:0]
+	for _, f := range fields {
+		if f.Key == eventIDKey && f.Type == zapcore.Uint32Type {
+			def = uint32(f.Integer)
+			continue
+		}
+		out = append(out, f)
+	}
+	return out, def
+}
+
+// truncateUTF8 cuts s to at most max bytes without splitting a character.
+func truncateUTF8(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package golog
+
+func openEventLog(string) (eventLog, error) { return nil, ErrEventLogUnsupported }
+
+func installEventLogSource(string) error { return ErrEventLogUnsupported }
+
+func removeEventLogSource(string) error { return ErrEventLogUnsupported }
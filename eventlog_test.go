@@ -0,0 +1,96 @@
+package golog
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type eventRecord struct {
+	kind string
+	eid  uint32
+	msg  string
+}
+
+type fakeEventLog struct{ events []eventRecord }
+
+func (l *fakeEventLog) Info(eid uint32, msg string) error {
+	l.events = append(l.events, eventRecord{"info", eid, msg})
+	return nil
+}
+
+func (l *fakeEventLog) Warning(eid uint32, msg string) error {
+	l.events = append(l.events, eventRecord{"warning", eid, msg})
+	return nil
+}
+
+func (l *fakeEventLog) Error(eid uint32, msg string) error {
+	l.events = append(l.events, eventRecord{"error", eid, msg})
+	return nil
+}
+
+func (l *fakeEventLog) Close() error { return nil }
+
+func TestEventLogCore(t *testing.T) {
+	log := &fakeEventLog{}
+	core := &eventLogCore{
+		LevelEnabler: zapcore.DebugLevel,
+		cfg:          EventLogConfig{EventIDs: map[Level]uint32{WarnLevel: 20}},
+		log:          log,
+		msg:          messageEncoder{tree: newTreeEncoder()},
+	}
+	logger := zap.New(core)
+	logger.Debug("starting", toZapFields([]Field{String("mode", "fast")})...)
+	logger.Warn("slow", toZapFields([]Field{Int("ms", 1500)})...)
+	logger.Named("db").Error("failed", toZapFields([]Field{EventID(300), String("table", "users")})...)
+	logger.With(toZapFields([]Field{EventID(400)})...).Info("tagged")
+
+	want := []eventRecord{
+		{"info", 1, "starting mode=fast"},
+		{"warning", 20, "slow ms=1500"},
+		{"error", 300, "db: failed table=users"},
+		{"info", 400, "tagged"},
+	}
+	if len(log.events) != len(want) {
+		t.Fatalf("got %d events: %+v", len(log.events), log.events)
+	}
+	for i, w := range want {
+		if log.events[i] != w {
+			t.Errorf("event %d = %+v, want %+v", i, log.events[i], w)
+		}
+	}
+}
+
+func TestEventLogProvider_Unsupported(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the Event Log is available")
+	}
+	_, err := NewLogger(WithEventLogProvider("golog-test", EventLogConfig{}))
+	if !errors.Is(err, ErrEventLogUnsupported) {
+		t.Errorf("expected ErrEventLogUnsupported, got %v", err)
+	}
+	if _, err := NewLogger(WithEventLogProvider("", EventLogConfig{})); err == nil || !strings.Contains(err.Error(), "source is required") {
+		t.Errorf("expected missing source error, got %v", err)
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 3, "hel"},
+		{"héllo", 2, "h"},
+		{"héllo", 3, "hé"},
+	} {
+		if got := truncateUTF8(tc.in, tc.max); got != tc.want {
+			t.Errorf("truncateUTF8(%q, %d) = %q, want %q", tc.in, tc.max, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package golog
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+const eventLogSourceKey = `SYSTEM\CurrentControlSet\Services\EventLog\Application\`
+
+func openEventLog(source string) (eventLog, error) {
+	return eventlog.Open(source)
+}
+
+func installEventLogSource(source string) error {
+	if key, err := registry.OpenKey(registry.LOCAL_MACHINE, eventLogSourceKey+source, registry.QUERY_VALUE); err == nil {
+		key.Close()
+		return nil
+	}
+	return eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+}
+
+func removeEventLogSource(source string) error {
+	err := eventlog.Remove(source)
+	if errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                      Failover Provider (Primary/Secondary)                  */
+/* -------------------------------------------------------------------------- */
+
+// WithFailover sends each entry to the providers registered by primary and
+// falls through to those registered by secondary only when the primary write
+// fails or the primary's circuit breaker is open. Unlike registering both
+// (tee), entries are never duplicated while the primary is healthy.
+//
+// The primary is guarded by a circuit breaker with the default
+// CircuitBreakerConfig settings; transitions are reported as *BreakerEvent.
+func WithFailover(primary, secondary LoggerOption) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.providers = append(cfg.providers, &failoverProvider{
+			primary:   providersOf(primary),
+			secondary: providersOf(secondary),
+			breaker:   newBreaker(0, 0),
+		})
+	}
+}
+
+type failoverProvider struct {
+	primary   []provider
+	secondary []provider
+	breaker   *breaker
+	tel       *telemetry
+}
+
+func (p *failoverProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	if len(p.primary) == 0 || len(p.secondary) == 0 {
+		return nil, errors.New("failover: both primary and secondary must register a provider")
+	}
+	primary, err := coresOf(p.primary, level)
+	if err != nil {
+		return nil, fmt.Errorf("failover primary: %w", err)
+	}
+	secondary, err := coresOf(p.secondary, level)
+	if err != nil {
+		return nil, fmt.Errorf("failover secondary: %w", err)
+	}
+	return &failoverCore{primary: primary, secondary: secondary, p: p}, nil
+}
+
+// coresOf builds the cores of provs.
+func coresOf(provs []provider, level zapcore.Level) ([]zapcore.Core, error) {
+	cores := make([]zapcore.Core, 0, len(provs))
+	for _, q := range provs {
+		c, err := q.newCore(level)
+		if err != nil {
+			return nil, err
+		}
+		cores = append(cores, c)
+	}
+	return cores, nil
+}
+
+func (p *failoverProvider) close() error {
+	var errs []error
+	for _, q := range append(append([]provider(nil), p.primary...), p.secondary...) {
+		errs = append(errs, q.close())
+	}
+	return errors.Join(errs...)
+}
+
+func (p *failoverProvider) instrument(t *telemetry) {
+	p.tel = t
+	name := p.describe().Name
+	p.breaker.onChange = func(from, to BreakerState, err error) {
+		t.errs.report(&BreakerEvent{Provider: name, From: from, To: to, Err: err})
+	}
+	for _, q := range append(append([]provider(nil), p.primary...), p.secondary...) {
+		if ip, ok := q.(instrumentedProvider); ok {
+			ip.instrument(t)
+		}
+	}
+}
+
+func (p *failoverProvider) describe() ProviderInfo {
+	names := func(provs []provider) string {
+		parts := make([]string, len(provs))
+		for i, q := range provs {
+			parts[i] = describeProvider(q).Name
+		}
+		return strings.Join(parts, "+")
+	}
+	return ProviderInfo{Name: "failover(" + names(p.primary) + "|" + names(p.secondary) + ")"}
+}
+
+// failoverCore writes to each side's cores through writeEnabled, so the
+// providers keep their own levels. Only entries the primary takes are
+// candidates for the secondary.
+type failoverCore struct {
+	primary   []zapcore.Core
+	secondary []zapcore.Core
+	p         *failoverProvider
+}
+
+func (c *failoverCore) Enabled(lvl zapcore.Level) bool {
+	return anyEnabled(c.primary, lvl)
+}
+
+func (c *failoverCore) With(fields []zapcore.Field) zapcore.Core {
+	return &failoverCore{primary: withAll(c.primary, fields), secondary: withAll(c.secondary, fields), p: c.p}
+}
+
+func (c *failoverCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *failoverCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !anyEntryEnabled(c.primary, ent) {
+		return nil
+	}
+	var primaryErr error
+	if c.p.breaker.allow() {
+		primaryErr = writeEnabled(c.primary, ent, fields)
+		c.p.breaker.record(primaryErr)
+		if primaryErr == nil {
+			return nil
+		}
+	}
+	if err := writeEnabled(c.secondary, ent, fields); err != nil {
+		if c.p.tel != nil {
+			c.p.tel.drops.record(DropProviderError, 1)
+		}
+		return errors.Join(primaryErr, err)
+	}
+	return nil
+}
+
+func (c *failoverCore) Sync() error {
+	return errors.Join(syncAll(c.primary), syncAll(c.secondary))
+}
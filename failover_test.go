@@ -0,0 +1,98 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWithFailover(t *testing.T) {
+	var primary, secondary bytes.Buffer
+	logger, err := NewLogger(WithFailover(
+		WithWriterProvider(&primary, JSONEncoder),
+		WithWriterProvider(&secondary, JSONEncoder),
+	))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("healthy")
+	if !strings.Contains(primary.String(), "healthy") || secondary.Len() != 0 {
+		t.Fatalf("expected entry only on the primary; primary=%q secondary=%q", primary.String(), secondary.String())
+	}
+}
+
+func TestWithFailover_FallsThroughOnError(t *testing.T) {
+	var secondary bytes.Buffer
+	logger, err := NewLogger(
+		WithFailover(
+			WithWriterProvider(failingWriter{}, JSONEncoder),
+			WithWriterProvider(&secondary, JSONEncoder),
+		),
+		WithErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	for i := 0; i < 10; i++ { // enough failures to open the breaker as well
+		logger.Info("rescued")
+	}
+	if got := strings.Count(secondary.String(), "rescued"); got != 10 {
+		t.Fatalf("expected all 10 entries on the secondary, got %d", got)
+	}
+	if st := logger.Stats(); st.Providers[0].Name != "failover(writer|writer)" || st.Providers[0].Errors != 0 {
+		t.Fatalf("unexpected provider stats: %+v", st.Providers[0])
+	}
+}
+
+func TestWithFailover_ProviderLevels(t *testing.T) {
+	var errorsOnly, all bytes.Buffer
+	logger, err := NewLogger(WithFailover(
+		func(c *loggerConfig) {
+			WithWriterProvider(&errorsOnly, JSONEncoder, WithProviderLevel(ErrorLevel))(c)
+			WithWriterProvider(&all, JSONEncoder)(c)
+		},
+		WithWriterProvider(&bytes.Buffer{}, JSONEncoder),
+	))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("routine")
+	logger.Error("failed")
+	if got := errorsOnly.String(); strings.Contains(got, "routine") || !strings.Contains(got, "failed") {
+		t.Errorf("primary provider level ignored: %s", got)
+	}
+	if got := all.String(); !strings.Contains(got, "routine") || !strings.Contains(got, "failed") {
+		t.Errorf("expected both entries on the unrestricted primary: %s", got)
+	}
+
+	var secondary bytes.Buffer
+	logger, err = NewLogger(
+		WithFailover(
+			WithWriterProvider(failingWriter{}, JSONEncoder),
+			WithWriterProvider(&secondary, JSONEncoder, WithProviderLevel(WarnLevel)),
+		),
+		WithErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("routine")
+	logger.Warn("rescued")
+	if got := secondary.String(); strings.Contains(got, "routine") || !strings.Contains(got, "rescued") {
+		t.Errorf("secondary provider level ignored: %s", got)
+	}
+}
+
+func TestWithFailover_RequiresBothSides(t *testing.T) {
+	if _, err := NewLogger(WithFailover(WithLevel(InfoLevel), WithStdOutProvider(JSONEncoder))); err == nil {
+		t.Fatalf("expected an error when the primary registers no provider")
+	}
+}
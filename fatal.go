@@ -0,0 +1,90 @@
+package golog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                         Pre-Exit Flush Hooks for Fatal                      */
+/* -------------------------------------------------------------------------- */
+
+// defaultFatalFlushTimeout bounds the pre-exit sequence of Fatal.
+const defaultFatalFlushTimeout = 5 * time.Second
+
+// WithPreExitHook registers fn to run when a Fatal entry has been written,
+// before the process exits – e.g. to deliver a paging event or drain an
+// application queue. Hooks run in registration order; afterwards the logger
+// is closed, which flushes buffered providers (GCP, HTTP batches, spools).
+// Errors are reported through the error handler. The whole sequence is
+// bounded by WithFatalFlushTimeout, and ctx expires with it.
+func WithPreExitHook(fn func(ctx context.Context) error) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.preExitHooks = append(cfg.preExitHooks, fn)
+	}
+}
+
+// WithFatalFlushTimeout bounds how long Fatal waits for pre-exit hooks and
+// provider flushes before exiting anyway (default 5s).
+func WithFatalFlushTimeout(d time.Duration) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.fatalFlushTimeout = d
+	}
+}
+
+// WithExitFunc replaces os.Exit as the last step of Fatal, Fatalf and Fatalw,
+// e.g. to assert on fatal paths in tests or to release locks before exiting:
+//
+//	var code int
+//	logger, _ := golog.NewLogger(golog.WithExitFunc(func(c int) { code = c }))
+//
+// fn runs after the pre-exit hooks and receives exit code 1. If it returns,
+// so does the Fatal call; the logger has been closed by then.
+func WithExitFunc(fn func(code int)) LoggerOption {
+	return func(cfg *loggerConfig) {
+		if fn == nil {
+			cfg.optionErrs = append(cfg.optionErrs, errors.New("WithExitFunc: nil function"))
+			return
+		}
+		cfg.exit = fn
+	}
+}
+
+// fatalHook replaces zap's default WriteThenFatal so that buffered entries
+// are delivered before the process exits.
+type fatalHook struct {
+	hooks   []func(context.Context) error
+	timeout time.Duration
+	exit    func(int)
+	// logger is set once NewLogger has built it.
+	logger *Logger
+}
+
+func (h *fatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	report := h.logger.telemetry.errs.report
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, fn := range h.hooks {
+			if err := fn(ctx); err != nil {
+				report(fmt.Errorf("pre-exit hook: %w", err))
+			}
+		}
+		if err := h.logger.Close(); err != nil {
+			report(fmt.Errorf("pre-exit close: %w", err))
+		}
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		report(fmt.Errorf("pre-exit flush did not finish within %s", h.timeout))
+	}
+	h.exit(1)
+}
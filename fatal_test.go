@@ -0,0 +1,106 @@
+package golog
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFatalFlushesBeforeExit(t *testing.T) {
+	ingest := &ingestServer{}
+	srv := httptest.NewServer(ingest)
+	defer srv.Close()
+
+	var (
+		steps    []string
+		exitCode = -1
+		errs     []error
+	)
+	logger, err := NewLogger(
+		WithHTTPProvider(srv.URL, HTTPConfig{BatchSize: 100, FlushInterval: time.Hour}),
+		WithPreExitHook(func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("hook context has no deadline")
+			}
+			steps = append(steps, "hook")
+			return errors.New("pager unavailable")
+		}),
+		WithErrorHandler(func(err error) { errs = append(errs, err) }),
+		WithExitFunc(func(code int) {
+			steps = append(steps, "exit")
+			exitCode = code
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Fatal("cannot continue")
+
+	if strings.Join(steps, ",") != "hook,exit" || exitCode != 1 {
+		t.Errorf("unexpected sequence %v, exit code %d", steps, exitCode)
+	}
+	bodies, _, _ := ingest.snapshot()
+	if len(bodies) != 1 || !strings.Contains(bodies[0], "cannot continue") {
+		t.Errorf("fatal entry not delivered before exit: %q", bodies)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "pager unavailable") {
+		t.Errorf("unexpected reported errors: %v", errs)
+	}
+}
+
+func TestFatalFlushTimeout(t *testing.T) {
+	exited := make(chan int, 1)
+	var errs []error
+	logger, err := NewLogger(
+		WithWriterProvider(&strings.Builder{}, JSONEncoder),
+		WithPreExitHook(func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}),
+		WithFatalFlushTimeout(10*time.Millisecond),
+		WithErrorHandler(func(err error) { errs = append(errs, err) }),
+		WithExitFunc(func(code int) { exited <- code }),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Fatal("stuck")
+	if code := <-exited; code != 1 {
+		t.Errorf("unexpected exit code %d", code)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "did not finish") {
+		t.Errorf("expected timeout report, got %v", errs)
+	}
+}
+
+func TestWithExitFunc(t *testing.T) {
+	var codes []int
+	var buf strings.Builder
+	newLogger := func() *Logger {
+		logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithExitFunc(func(code int) { codes = append(codes, code) }))
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		return logger
+	}
+	newLogger().Fatalf("bad config %q", "x")
+	newLogger().Fatalw("bad config", "path", "x")
+
+	if len(codes) != 2 || codes[0] != 1 || codes[1] != 1 {
+		t.Errorf("unexpected exit codes %v", codes)
+	}
+	if n := strings.Count(buf.String(), `"level":"fatal"`); n != 2 {
+		t.Errorf("expected 2 fatal entries, got %d: %s", n, buf.String())
+	}
+
+	if _, err := NewLogger(WithExitFunc(nil)); err == nil {
+		t.Error("expected an error for a nil exit function")
+	}
+}
@@ -0,0 +1,371 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                      Field Tree for Non-JSON Encoders                       */
+/* -------------------------------------------------------------------------- */
+
+// fieldTree records the fields given to an encoder, in order and with their
+// nesting, for the encoders that render a format of their own from it.
+// Values are nil, bool, int64, uint64, float32, float64, complex128, string,
+// []byte, time.Time, time.Duration, json.Number, *fieldTree (objects and
+// namespaces), []interface{} (arrays) and map[string]interface{} (reflected
+// maps).
+type fieldTree struct {
+	keys []string
+	vals []interface{}
+	// namespace marks trees opened with OpenNamespace, which receive every
+	// field added after them.
+	namespace bool
+}
+
+func (t *fieldTree) add(key string, val interface{}) {
+	t.keys = append(t.keys, key)
+	t.vals = append(t.vals, val)
+}
+
+// clone copies t deeply enough that adding fields to either copy, at any
+// namespace depth, leaves the other alone.
+func (t *fieldTree) clone() *fieldTree {
+	c := &fieldTree{
+		keys:      append([]string(nil), t.keys...),
+		vals:      append([]interface{}(nil), t.vals...),
+		namespace: t.namespace,
+	}
+	if n := len(c.vals); n > 0 {
+		if sub, ok := c.vals[n-1].(*fieldTree); ok && sub.namespace {
+			c.vals[n-1] = sub.clone()
+		}
+	}
+	return c
+}
+
+// target returns the innermost open namespace.
+func (t *fieldTree) target() *fieldTree {
+	for {
+		n := len(t.vals)
+		if n == 0 {
+			return t
+		}
+		sub, ok := t.vals[n-1].(*fieldTree)
+		if !ok || !sub.namespace {
+			return t
+		}
+		t = sub
+	}
+}
+
+// flatten calls fn for every leaf of t in order, with the keys of enclosing
+// objects joined by dots, e.g. "http.status".
+func (t *fieldTree) flatten(prefix string, fn func(key string, val interface{})) {
+	for i, k := range t.keys {
+		if sub, ok := t.vals[i].(*fieldTree); ok {
+			sub.flatten(prefix+k+".", fn)
+			continue
+		}
+		fn(prefix+k, t.vals[i])
+	}
+}
+
+// treeEncoder is the zapcore.ObjectEncoder filling a fieldTree.
+type treeEncoder struct {
+	root *fieldTree
+}
+
+func newTreeEncoder() *treeEncoder { return &treeEncoder{root: &fieldTree{}} }
+
+func (e *treeEncoder) cloneTree() *treeEncoder { return &treeEncoder{root: e.root.clone()} }
+
+// fieldsWith returns the tree of e's context fields plus fields, leaving e
+// unchanged.
+func (e *treeEncoder) fieldsWith(fields []zapcore.Field) *fieldTree {
+	c := e.cloneTree()
+	for _, f := range fields {
+		f.AddTo(c)
+	}
+	return c.root
+}
+
+func (e *treeEncoder) add(key string, val interface{}) { e.root.target().add(key, val) }
+
+func (e *treeEncoder) AddArray(key string, m zapcore.ArrayMarshaler) error {
+	arr := &treeArray{}
+	err := m.MarshalLogArray(arr)
+	e.add(key, arr.elems)
+	return err
+}
+
+func (e *treeEncoder) AddObject(key string, m zapcore.ObjectMarshaler) error {
+	sub := newTreeEncoder()
+	err := m.MarshalLogObject(sub)
+	e.add(key, sub.root)
+	return err
+}
+
+func (e *treeEncoder) AddBinary(key string, v []byte) {
+	e.add(key, append([]byte(nil), v...))
+}
+func (e *treeEncoder) AddByteString(key string, v []byte)      { e.add(key, string(v)) }
+func (e *treeEncoder) AddBool(key string, v bool)              { e.add(key, v) }
+func (e *treeEncoder) AddComplex128(key string, v complex128)  { e.add(key, v) }
+func (e *treeEncoder) AddComplex64(key string, v complex64)    { e.add(key, complex128(v)) }
+func (e *treeEncoder) AddDuration(key string, v time.Duration) { e.add(key, v) }
+func (e *treeEncoder) AddFloat64(key string, v float64)        { e.add(key, v) }
+func (e *treeEncoder) AddFloat32(key string, v float32)        { e.add(key, v) }
+func (e *treeEncoder) AddInt(key string, v int)                { e.add(key, int64(v)) }
+func (e *treeEncoder) AddInt64(key string, v int64)            { e.add(key, v) }
+func (e *treeEncoder) AddInt32(key string, v int32)            { e.add(key, int64(v)) }
+func (e *treeEncoder) AddInt16(key string, v int16)            { e.add(key, int64(v)) }
+func (e *treeEncoder) AddInt8(key string, v int8)              { e.add(key, int64(v)) }
+func (e *treeEncoder) AddString(key, v string)                 { e.add(key, v) }
+func (e *treeEncoder) AddTime(key string, v time.Time)         { e.add(key, v) }
+func (e *treeEncoder) AddUint(key string, v uint)              { e.add(key, uint64(v)) }
+func (e *treeEncoder) AddUint64(key string, v uint64)          { e.add(key, v) }
+func (e *treeEncoder) AddUint32(key string, v uint32)          { e.add(key, uint64(v)) }
+func (e *treeEncoder) AddUint16(key string, v uint16)          { e.add(key, uint64(v)) }
+func (e *treeEncoder) AddUint8(key string, v uint8)            { e.add(key, uint64(v)) }
+func (e *treeEncoder) AddUintptr(key string, v uintptr)        { e.add(key, uint64(v)) }
+func (e *treeEncoder) OpenNamespace(key string)                { e.add(key, &fieldTree{namespace: true}) }
+func (e *treeEncoder) AddReflected(key string, v interface{}) error {
+	val, err := reflectedValue(v)
+	if err != nil {
+		return err
+	}
+	e.add(key, val)
+	return nil
+}
+
+// reflectedValue turns v into the generic form encoding/json gives it, with
+// numbers kept exact.
+func reflectedValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out interface{}
+	err = dec.Decode(&out)
+	return out, err
+}
+
+// treeArray is the zapcore.ArrayEncoder collecting an array's elements.
+type treeArray struct {
+	elems []interface{}
+}
+
+func (a *treeArray) append(v interface{}) { a.elems = append(a.elems, v) }
+
+func (a *treeArray) AppendArray(m zapcore.ArrayMarshaler) error {
+	sub := &treeArray{}
+	err := m.MarshalLogArray(sub)
+	a.append(sub.elems)
+	return err
+}
+
+func (a *treeArray) AppendObject(m zapcore.ObjectMarshaler) error {
+	sub := newTreeEncoder()
+	err := m.MarshalLogObject(sub)
+	a.append(sub.root)
+	return err
+}
+
+func (a *treeArray) AppendReflected(v interface{}) error {
+	val, err := reflectedValue(v)
+	if err != nil {
+		return err
+	}
+	a.append(val)
+	return nil
+}
+
+func (a *treeArray) AppendBool(v bool)              { a.append(v) }
+func (a *treeArray) AppendByteString(v []byte)      { a.append(string(v)) }
+func (a *treeArray) AppendComplex128(v complex128)  { a.append(v) }
+func (a *treeArray) AppendComplex64(v complex64)    { a.append(complex128(v)) }
+func (a *treeArray) AppendFloat64(v float64)        { a.append(v) }
+func (a *treeArray) AppendFloat32(v float32)        { a.append(v) }
+func (a *treeArray) AppendInt(v int)                { a.append(int64(v)) }
+func (a *treeArray) AppendInt64(v int64)            { a.append(v) }
+func (a *treeArray) AppendInt32(v int32)            { a.append(int64(v)) }
+func (a *treeArray) AppendInt16(v int16)            { a.append(int64(v)) }
+func (a *treeArray) AppendInt8(v int8)              { a.append(int64(v)) }
+func (a *treeArray) AppendString(v string)          { a.append(v) }
+func (a *treeArray) AppendUint(v uint)              { a.append(uint64(v)) }
+func (a *treeArray) AppendUint64(v uint64)          { a.append(v) }
+func (a *treeArray) AppendUint32(v uint32)          { a.append(uint64(v)) }
+func (a *treeArray) AppendUint16(v uint16)          { a.append(uint64(v)) }
+func (a *treeArray) AppendUint8(v uint8)            { a.append(uint64(v)) }
+func (a *treeArray) AppendUintptr(v uintptr)        { a.append(uint64(v)) }
+func (a *treeArray) AppendDuration(v time.Duration) { a.append(v) }
+func (a *treeArray) AppendTime(v time.Time)         { a.append(v) }
+
+// formatScalar renders a non-container tree value as text: times in
+// RFC 3339, durations as "1.5s" and binary as base64.
+func formatScalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case complex128:
+		return strconv.FormatComplex(v, 'g', -1, 128)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case json.Number:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// appendTreeJSON renders v as JSON, keeping the field order of trees, for
+// encoders that embed nested values in a single column.
+func appendTreeJSON(buf *buffer.Buffer, v interface{}) {
+	switch v := v.(type) {
+	case *fieldTree:
+		buf.AppendByte('{')
+		for i, k := range v.keys {
+			if i > 0 {
+				buf.AppendByte(',')
+			}
+			appendJSONString(buf, k)
+			buf.AppendByte(':')
+			appendTreeJSON(buf, v.vals[i])
+		}
+		buf.AppendByte('}')
+	case []interface{}:
+		buf.AppendByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				buf.AppendByte(',')
+			}
+			appendTreeJSON(buf, elem)
+		}
+		buf.AppendByte(']')
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		buf.AppendString(string(data))
+	case nil:
+		buf.AppendString("null")
+	case bool, int64, uint64, json.Number:
+		buf.AppendString(formatScalar(v))
+	case float32:
+		appendJSONFloat(buf, float64(v), formatScalar(v))
+	case float64:
+		appendJSONFloat(buf, v, formatScalar(v))
+	default:
+		appendJSONString(buf, formatScalar(v))
+	}
+}
+
+// appendJSONFloat writes text, the formatted f, quoting NaN and ±Inf, which
+// have no JSON number form.
+func appendJSONFloat(buf *buffer.Buffer, f float64, text string) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		appendJSONString(buf, text)
+		return
+	}
+	buf.AppendString(text)
+}
+
+func appendJSONString(buf *buffer.Buffer, s string) {
+	data, _ := json.Marshal(s)
+	buf.AppendString(string(data))
+}
+
+/* -------------------------------------------------------------------------- */
+/*                   Messages for Destinations with Metadata                   */
+/* -------------------------------------------------------------------------- */
+
+// messageEncoder renders entries for destinations that record the time and
+// level themselves, such as syslog and the Windows Event Log: the logger
+// name and message followed by the fields as key=value pairs, or the entry
+// encoded by enc when the provider was given WithProviderEncoder.
+type messageEncoder struct {
+	enc  zapcore.Encoder
+	tree *treeEncoder
+}
+
+func newMessageEncoder(tel *telemetry, t EncoderType, configure *encoderConfigFuncs) (messageEncoder, error) {
+	if t == "" {
+		return messageEncoder{tree: newTreeEncoder()}, nil
+	}
+	enc, err := tel.buildEncoder(t, configure)
+	return messageEncoder{enc: enc}, err
+}
+
+func (m messageEncoder) with(fields []zapcore.Field) messageEncoder {
+	if m.enc != nil {
+		enc := m.enc.Clone()
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+		return messageEncoder{enc: enc}
+	}
+	tree := m.tree.cloneTree()
+	for _, f := range fields {
+		f.AddTo(tree)
+	}
+	return messageEncoder{tree: tree}
+}
+
+func (m messageEncoder) appendEntry(buf *buffer.Buffer, ent zapcore.Entry, fields []zapcore.Field) error {
+	if m.enc != nil {
+		encoded, err := m.enc.EncodeEntry(ent, fields)
+		if err != nil {
+			return err
+		}
+		buf.AppendString(strings.TrimSuffix(encoded.String(), "\n"))
+		encoded.Free()
+		return nil
+	}
+	if ent.LoggerName != "" {
+		buf.AppendString(ent.LoggerName)
+		buf.AppendString(": ")
+	}
+	buf.AppendString(ent.Message)
+	m.tree.fieldsWith(fields).flatten("", func(key string, val interface{}) {
+		buf.AppendByte(' ')
+		buf.AppendString(key)
+		buf.AppendByte('=')
+		buf.AppendString(prettyValue(val))
+	})
+	appendStack(buf, ent)
+	return nil
+}
+
+// appendStack adds the entry's stack trace on the following lines.
+func appendStack(buf *buffer.Buffer, ent zapcore.Entry) {
+	if ent.Stack != "" {
+		buf.AppendByte('\n')
+		buf.AppendString(ent.Stack)
+	}
+}
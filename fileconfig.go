@@ -0,0 +1,288 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                       Construction from a Config File                       */
+/* -------------------------------------------------------------------------- */
+
+// NewFromConfig builds a logger from the YAML or JSON file at path, so the
+// logging topology can change without a rebuild:
+//
+//	level: info
+//	stacktrace: error
+//	named_levels:
+//	  db: debug
+//	sampling: {tick: 1s, first: 100, thereafter: 10}
+//	providers:
+//	  - type: stdout
+//	    encoder: console
+//	  - type: file
+//	    filename: /var/log/app.log
+//	    max_size: 100
+//	    max_backups: 3
+//	    compress: true
+//	    encoder: ltsv
+//	    level: warn
+//	routes:
+//	  - match: {field: audit, value: true}
+//	    exclusive: true
+//	    providers:
+//	      - type: file
+//	        filename: /var/log/audit.log
+//
+// The other top-level keys are caller (default true), caller_skip,
+// development, ring_buffer_size, sequence_key, log_id, goroutine_id,
+// time_format and time_zone (an IANA name), mirroring the options of the
+// same names. Each provider's type names a
+// factory registered with RegisterProviderFactory and its remaining keys are
+// the factory's params, except level, which sets the provider's own minimum
+// level. Each routes entry is WithRoute, or WithExclusiveRoute if exclusive
+// is set, for its providers; match accepts entries satisfying all of its
+// keys: field (with value, or present if value is omitted), level (at or
+// above) and message (a regular expression). Routes take effect on restart.
+// Unknown keys are errors, including params the built-in factories
+// do not take; factories registered elsewhere check their own. options are
+// applied after the file, e.g. for providers that need Go values. See
+// WatchConfig for applying later changes to the file.
+func NewFromConfig(path string, options ...LoggerOption) (*Logger, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	l, err := newFromConfig(data, path, options)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+// NewFromConfigBytes is NewFromConfig for configuration already in memory.
+func NewFromConfigBytes(data []byte, options ...LoggerOption) (*Logger, error) {
+	return newFromConfig(data, "", options)
+}
+
+func newFromConfig(data []byte, path string, options []LoggerOption) (*Logger, error) {
+	fc, err := parseFileConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	opts := fc.options()
+	routes, err := fc.routeOptions()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, routes...)
+	if len(fc.Providers) > 0 {
+		opts = append(opts, func(cfg *loggerConfig) { cfg.providersAttachedLater = true })
+	}
+	l, err := NewLogger(append(opts, options...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The providers and named levels are applied as a reload from an empty
+	// set, so later reloads can add and remove them.
+	initial := *fc
+	initial.NamedLevels = nil
+	initial.Providers = nil
+	l.source = &configSource{path: path, current: &initial}
+	if err := l.source.apply(l, fc); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// fileConfig is the schema NewFromConfig reads. JSON is decoded by the YAML
+// decoder, of which it is a subset.
+type fileConfig struct {
+	Level          *Level           `yaml:"level"`
+	NamedLevels    map[string]Level `yaml:"named_levels"`
+	Caller         *bool            `yaml:"caller"`
+	CallerSkip     int              `yaml:"caller_skip"`
+	Stacktrace     *Level           `yaml:"stacktrace"`
+	Development    bool             `yaml:"development"`
+	Sampling       *SamplingConfig  `yaml:"sampling"`
+	RingBufferSize int              `yaml:"ring_buffer_size"`
+	SequenceKey    string           `yaml:"sequence_key"`
+	LogID          bool             `yaml:"log_id"`
+	GoroutineID    bool             `yaml:"goroutine_id"`
+	TimeFormat     string           `yaml:"time_format"`
+	TimeZone       string           `yaml:"time_zone"`
+	Providers      []map[string]any `yaml:"providers"`
+	Routes         []routeConfig    `yaml:"routes"`
+}
+
+// routeConfig is one entry of routes.
+type routeConfig struct {
+	Match     routeMatch       `yaml:"match"`
+	Exclusive bool             `yaml:"exclusive"`
+	Providers []map[string]any `yaml:"providers"`
+}
+
+type routeMatch struct {
+	Field   string `yaml:"field"`
+	Value   any    `yaml:"value"`
+	Level   *Level `yaml:"level"`
+	Message string `yaml:"message"`
+}
+
+func parseFileConfig(data []byte) (*fileConfig, error) {
+	var fc fileConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&fc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if fc.TimeZone != "" {
+		if _, err := time.LoadLocation(fc.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid config: time_zone: %w", err)
+		}
+	}
+	return &fc, nil
+}
+
+// options translates fc's settings other than providers and named levels
+// into logger options. Development mode comes first so the explicit settings
+// override its defaults.
+func (fc *fileConfig) options() []LoggerOption {
+	var opts []LoggerOption
+	if fc.Development {
+		opts = append(opts, WithDevelopmentMode())
+	}
+	if fc.Level != nil {
+		opts = append(opts, WithLevel(*fc.Level))
+	}
+	if fc.Caller != nil && !*fc.Caller {
+		opts = append(opts, WithoutCaller())
+	}
+	if fc.CallerSkip != 0 {
+		opts = append(opts, WithCallerSkip(fc.CallerSkip))
+	}
+	if fc.Stacktrace != nil {
+		opts = append(opts, WithStacktrace(*fc.Stacktrace))
+	}
+	if s := fc.Sampling; s != nil {
+		opts = append(opts, WithSampling(s.Tick, s.First, s.Thereafter))
+	}
+	if fc.RingBufferSize > 0 {
+		opts = append(opts, WithRingBuffer(fc.RingBufferSize))
+	}
+	if fc.SequenceKey != "" {
+		opts = append(opts, WithSequence(fc.SequenceKey))
+	}
+	if fc.LogID {
+		opts = append(opts, WithLogID())
+	}
+	if fc.GoroutineID {
+		opts = append(opts, WithGoroutineID())
+	}
+	if fc.TimeFormat != "" {
+		opts = append(opts, WithTimeFormat(fc.TimeFormat))
+	}
+	if fc.TimeZone != "" {
+		// Validated by parseFileConfig.
+		loc, _ := time.LoadLocation(fc.TimeZone)
+		opts = append(opts, WithTimeZone(loc))
+	}
+	return opts
+}
+
+// routeOptions translates fc's routes into WithRoute and WithExclusiveRoute
+// options.
+func (fc *fileConfig) routeOptions() ([]LoggerOption, error) {
+	var opts []LoggerOption
+	for i, rc := range fc.Routes {
+		match, err := rc.Match.matcher()
+		if err != nil {
+			return nil, fmt.Errorf("routes[%d]: match: %w", i, err)
+		}
+		if len(rc.Providers) == 0 {
+			return nil, fmt.Errorf("routes[%d]: providers are required", i)
+		}
+		providers := make([]LoggerOption, len(rc.Providers))
+		for j, entry := range rc.Providers {
+			if providers[j], err = providerFromConfig(entry); err != nil {
+				return nil, fmt.Errorf("routes[%d].providers[%d]: %w", i, j, err)
+			}
+		}
+		opt := func(cfg *loggerConfig) {
+			for _, p := range providers {
+				p(cfg)
+			}
+		}
+		if rc.Exclusive {
+			opts = append(opts, WithExclusiveRoute(match, opt))
+		} else {
+			opts = append(opts, WithRoute(match, opt))
+		}
+	}
+	return opts, nil
+}
+
+func (m routeMatch) matcher() (Matcher, error) {
+	var ms []Matcher
+	switch {
+	case m.Field != "" && m.Value != nil:
+		ms = append(ms, MatchField(m.Field, m.Value))
+	case m.Field != "":
+		ms = append(ms, MatchFieldPresent(m.Field))
+	case m.Value != nil:
+		return nil, errors.New("value requires field")
+	}
+	if m.Level != nil {
+		ms = append(ms, MatchLevel(*m.Level))
+	}
+	if m.Message != "" {
+		re, err := regexp.Compile(m.Message)
+		if err != nil {
+			return nil, fmt.Errorf("message: %w", err)
+		}
+		ms = append(ms, MatchMessage(re))
+	}
+	if len(ms) == 0 {
+		return nil, errors.New("field, level or message is required")
+	}
+	return MatchAll(ms...), nil
+}
+
+// providerFromConfig turns one providers entry into the option registering
+// it.
+func providerFromConfig(entry map[string]any) (LoggerOption, error) {
+	// The helpers consume the keys they read, leaving the factory's params.
+	params := make(map[string]any, len(entry))
+	for k, v := range entry {
+		params[k] = v
+	}
+	typ, err := paramString(params, "type", "")
+	if err != nil {
+		return nil, err
+	}
+	if typ == "" {
+		return nil, errors.New("type is required")
+	}
+	level, err := paramString(params, "level", "")
+	if err != nil {
+		return nil, err
+	}
+	opt := WithNamedProvider(typ, params)
+	if level != "" {
+		lvl, err := ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("level: %w", err)
+		}
+		opt = WithProviderOptions(opt, WithProviderLevel(lvl))
+	}
+	return opt, nil
+}
@@ -0,0 +1,130 @@
+package golog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "app.log")
+	cfgFile := filepath.Join(dir, "golog.yaml")
+	yaml := `
+level: info
+stacktrace: error
+log_id: true
+named_levels:
+  db: debug
+sampling: {tick: 1s, first: 100, thereafter: 10}
+providers:
+  - type: file
+    filename: ` + logFile + `
+    max_size: 10
+    compress: true
+  - type: stdout
+    encoder: console
+    level: fatal
+`
+	if err := os.WriteFile(cfgFile, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	logger, err := NewFromConfig(cfgFile)
+	if err != nil {
+		t.Fatalf("NewFromConfig: %v", err)
+	}
+	logger.Debug("hidden")
+	logger.Named("db").Debug("query")
+	logger.Error("failed")
+	c := logger.Config()
+	logger.Close()
+
+	if c.Level != InfoLevel || !c.LogID || c.Stacktrace == nil || *c.Stacktrace != ErrorLevel ||
+		c.Sampling == nil || c.Sampling.Tick != time.Second || c.Sampling.First != 100 {
+		t.Errorf("unexpected config %+v", c)
+	}
+	if len(c.Providers) != 2 || c.Providers[1].Level != "fatal" {
+		t.Errorf("unexpected providers %+v", c.Providers)
+	}
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	if strings.Contains(out, "hidden") || !strings.Contains(out, `"msg":"query"`) || !strings.Contains(out, `"stacktrace"`) {
+		t.Errorf("unexpected file output: %s", out)
+	}
+}
+
+func TestNewFromConfigBytes_JSON(t *testing.T) {
+	logger, err := NewFromConfigBytes([]byte(`{"level": "warn", "caller": false, "providers": [{"type": "stdout"}]}`))
+	if err != nil {
+		t.Fatalf("NewFromConfigBytes: %v", err)
+	}
+	defer logger.Close()
+	if c := logger.Config(); c.Level != WarnLevel || c.Caller {
+		t.Errorf("unexpected config %+v", c)
+	}
+}
+
+func TestNewFromConfigBytes_Errors(t *testing.T) {
+	for name, tc := range map[string]struct{ config, want string }{
+		"unknown key":      {"levle: debug", "levle"},
+		"bad level":        {"level: loud", "loud"},
+		"missing type":     {"providers: [{encoder: json}]", "providers[0]: type is required"},
+		"bad provider lvl": {"providers: [{type: stdout, level: loud}]", "providers[0]: level"},
+		"unknown provider": {"providers: [{type: kafka}]", `unknown provider "kafka"`},
+		"unknown param":    {"providers: [{type: stdout, encodr: console}]", `provider "stdout": unknown params encodr`},
+		"empty match":      {"routes: [{providers: [{type: stdout}]}]", "routes[0]: match: field, level or message is required"},
+		"bad route regexp": {"routes: [{match: {message: '('}, providers: [{type: stdout}]}]", "routes[0]: match: message"},
+		"route provider":   {"routes: [{match: {level: warn}, providers: [{encoder: json}]}]", "routes[0].providers[0]: type is required"},
+	} {
+		if _, err := NewFromConfigBytes([]byte(tc.config)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", name, tc.want, err)
+		}
+	}
+	if _, err := NewFromConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestNewFromConfig_Routes(t *testing.T) {
+	dir := t.TempDir()
+	appLog := filepath.Join(dir, "app.log")
+	auditLog := filepath.Join(dir, "audit.log")
+	billingLog := filepath.Join(dir, "billing.log")
+	logger, err := NewFromConfigBytes([]byte(`
+providers:
+  - type: file
+    filename: ` + appLog + `
+routes:
+  - match: {field: audit, value: true}
+    exclusive: true
+    providers:
+      - type: file
+        filename: ` + auditLog + `
+  - match: {field: component, value: billing, level: warn}
+    providers:
+      - type: file
+        filename: ` + billingLog + `
+`))
+	if err != nil {
+		t.Fatalf("NewFromConfigBytes: %v", err)
+	}
+	logger.Info("login", Bool("audit", true))
+	logger.Info("charged", String("component", "billing"))
+	logger.Warn("declined", String("component", "billing"))
+	logger.Close()
+
+	if out := readLog(t, appLog); strings.Contains(out, "login") || !strings.Contains(out, "charged") || !strings.Contains(out, "declined") {
+		t.Errorf("unexpected app output: %s", out)
+	}
+	if out := readLog(t, auditLog); !strings.Contains(out, "login") || strings.Contains(out, "charged") {
+		t.Errorf("unexpected audit output: %s", out)
+	}
+	if out := readLog(t, billingLog); strings.Contains(out, "charged") || !strings.Contains(out, "declined") {
+		t.Errorf("unexpected billing output: %s", out)
+	}
+}
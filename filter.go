@@ -0,0 +1,72 @@
+package golog
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                        Filter Wrapper with Predicates                       */
+/* -------------------------------------------------------------------------- */
+
+// FilterFunc decides whether an entry is kept. It receives the entry header
+// (Entry.Fields is nil) and every field attached to it – logger-scoped fields
+// first, then call-site fields – and returns false to drop the entry.
+type FilterFunc func(Entry, []Field) bool
+
+// WithFilter drops entries rejected by keep before they reach any provider,
+// e.g. health-check access logs or messages matching a regex. Filtered
+// entries are counted as DropFiltered. Multiple filters may be registered;
+// an entry must pass all of them.
+func WithFilter(keep FilterFunc) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.filters = append(cfg.filters, keep)
+	}
+}
+
+// filterCore applies the configured filters in front of the provider cores.
+type filterCore struct {
+	cores   []zapcore.Core
+	filters []FilterFunc
+	drops   *dropCounter
+	fields  []zapcore.Field
+}
+
+func (c *filterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &filterCore{
+		cores:   withAll(c.cores, fields),
+		filters: c.filters,
+		drops:   c.drops,
+		fields:  append(append([]zapcore.Field(nil), c.fields...), fields...),
+	}
+}
+
+func (c *filterCore) Enabled(lvl zapcore.Level) bool {
+	return anyEnabled(c.cores, lvl)
+}
+
+func (c *filterCore) enabledFor(ent zapcore.Entry) bool {
+	return anyEntryEnabled(c.cores, ent)
+}
+
+func (c *filterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabledFor(ent) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *filterCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	header := decodeEntry(ent, nil, nil)
+	all := fromZapFields(append(append([]zapcore.Field(nil), c.fields...), fields...))
+	for _, keep := range c.filters {
+		if !keep(header, all) {
+			c.drops.record(DropFiltered, 1)
+			return nil
+		}
+	}
+	return writeEnabled(c.cores, ent, fields)
+}
+
+func (c *filterCore) Sync() error {
+	return syncAll(c.cores)
+}
@@ -0,0 +1,41 @@
+package golog
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	var buf bytes.Buffer
+	health := regexp.MustCompile(`^GET /healthz`)
+	logger, err := NewLogger(
+		WithWriterProvider(&buf, JSONEncoder),
+		WithFilter(func(e Entry, _ []Field) bool { return !health.MatchString(e.Message) }),
+		WithFilter(func(_ Entry, fields []Field) bool {
+			for _, f := range fields {
+				if f.Key == "status" && f.Value == int64(204) {
+					return false
+				}
+			}
+			return true
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("GET /healthz")
+	logger.Info("GET /api", Int("status", 204))
+	logger.Info("GET /api", Int("status", 200))
+
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 || !strings.Contains(out, `"status":200`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+	if got := logger.DroppedEntries()[DropFiltered]; got != 2 {
+		t.Errorf("expected 2 filtered entries, got %d", got)
+	}
+}
@@ -0,0 +1,426 @@
+package golog
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                       Fluentd / Fluent Bit Forward Provider                 */
+/* -------------------------------------------------------------------------- */
+
+// FluentConfig configures WithFluentProvider. Zero values fall back to the
+// defaults noted on each field.
+type FluentConfig struct {
+	// Tag routes the entries in Fluentd or Fluent Bit (default "golog").
+	Tag string
+	// TagLoggerName appends the logger name to Tag, so that entries of
+	// Named("db") go out as "golog.db".
+	TagLoggerName bool
+	// Ack requests an acknowledgment of every batch (require_ack_response),
+	// resending it once on a new connection when none arrives within
+	// AckTimeout.
+	Ack bool
+	// AckTimeout defaults to 10s.
+	AckTimeout time.Duration
+	// TLS enables TLS on tcp connections, for in_forward with tls enabled.
+	TLS *TLSConfig
+	// Timeout bounds dialling and each write (default 5s).
+	Timeout time.Duration
+	// BatchSize is the number of entries per message (default 100);
+	// FlushInterval bounds how long an entry waits for its batch (default
+	// 1s).
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+func (c FluentConfig) withDefaults() FluentConfig {
+	if c.Tag == "" {
+		c.Tag = "golog"
+	}
+	if c.AckTimeout <= 0 {
+		c.AckTimeout = 10 * time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 5 * time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = time.Second
+	}
+	return c
+}
+
+// WithFluentProvider sends entries to a Fluentd or Fluent Bit forward input
+// over "tcp" (address defaults to port 24224) or "unix":
+//
+//	golog.WithFluentProvider("tcp", "localhost", golog.FluentConfig{
+//		Tag:           "app",
+//		TagLoggerName: true,
+//		Ack:           true,
+//	})
+//
+// Entries are batched into PackedForward messages, one per tag, carrying
+// the entry time as EventTime. The record holds level, logger, caller, msg
+// and stacktrace followed by the fields; with WithProviderEncoder it holds
+// the encoded entry under "log", as Docker's fluentd driver sends it. The
+// connection is redialled after errors, and batches that cannot be sent,
+// or with Ack are not acknowledged, are dropped and counted as
+// DropProviderError.
+func WithFluentProvider(network, address string, cfg FluentConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&fluentProvider{
+			providerBase: providerBase{name: "fluent:" + network + ":" + address, encoders: anyEncoder},
+			network:      network,
+			address:      address,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type fluentProvider struct {
+	providerBase
+	network, address string
+	cfg              FluentConfig
+
+	conn  *fluentConn
+	batch *batchWriter
+}
+
+func (p *fluentProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	cfg := p.cfg.withDefaults()
+	tlsCfg, err := cfg.TLS.build()
+	if err != nil {
+		return nil, fmt.Errorf("fluent provider: %w", err)
+	}
+	conn := &fluentConn{network: p.network, address: p.address, tls: tlsCfg, timeout: cfg.Timeout}
+	if cfg.Ack {
+		conn.ackTimeout = cfg.AckTimeout
+	}
+	switch p.network {
+	case "tcp", "tcp4", "tcp6":
+		if _, _, err := net.SplitHostPort(p.address); err != nil {
+			conn.address = net.JoinHostPort(p.address, "24224")
+		}
+	case "unix":
+		if tlsCfg != nil {
+			return nil, errors.New("fluent provider: TLS needs a tcp connection")
+		}
+	default:
+		return nil, fmt.Errorf("fluent provider: unsupported network %q", p.network)
+	}
+	msg, err := newMessageEncoder(p.tel, p.encoderType, p.encoderConfig)
+	if err != nil {
+		return nil, err
+	}
+	p.conn = conn
+	p.batch = newBatchWriter(cfg.BatchSize, cfg.FlushInterval, conn.send, p.dropped, p.report)
+	return &fluentCore{LevelEnabler: level, tag: cfg.Tag, loggerTag: cfg.TagLoggerName, msg: msg, out: p.batch}, nil
+}
+
+func (p *fluentProvider) close() error {
+	if p.batch == nil {
+		return nil
+	}
+	err := p.batch.close()
+	if cerr := p.conn.close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (p *fluentProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: p.encoderType}
+}
+
+// fluentCore writes each entry to the batch as a tag and a [time, record]
+// event, both length-prefixed so that send can group the events by tag.
+type fluentCore struct {
+	zapcore.LevelEnabler
+	tag       string
+	loggerTag bool
+	msg       messageEncoder
+	out       *batchWriter
+}
+
+func (c *fluentCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.msg = c.msg.with(fields)
+	return &clone
+}
+
+func (c *fluentCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *fluentCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	event := appendFluentEventTime(append(make([]byte, 0, 256), 0x92), ent.Time)
+	if c.msg.enc != nil {
+		encoded, err := c.msg.enc.EncodeEntry(ent, fields)
+		if err != nil {
+			return err
+		}
+		event = appendMsgpackHeader(event, 1, 0x80, 0xde)
+		event = appendMsgpackString(event, "log")
+		event = appendMsgpackString(event, strings.TrimSuffix(encoded.String(), "\n"))
+		encoded.Free()
+	} else {
+		event = appendMsgpackEntry(event, ent, c.msg.tree.fieldsWith(fields), false)
+	}
+
+	tag := c.tag
+	if c.loggerTag && ent.LoggerName != "" {
+		tag += "." + ent.LoggerName
+	}
+	buf := encoderPool.Get()
+	defer buf.Free()
+	buf.Write(binary.AppendUvarint(nil, uint64(len(tag))))
+	buf.AppendString(tag)
+	buf.Write(binary.AppendUvarint(nil, uint64(len(event))))
+	buf.Write(event)
+	_, err := c.out.Write(buf.Bytes())
+	return err
+}
+
+func (c *fluentCore) Sync() error { return c.out.Sync() }
+
+// appendFluentEventTime writes t as the EventTime extension (type 0).
+func appendFluentEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
+
+/* -------------------------------------------------------------------------- */
+/*                             Forward Connection                             */
+/* -------------------------------------------------------------------------- */
+
+// fluentConn is a connection to the forward input, re-established after
+// errors.
+type fluentConn struct {
+	network, address string
+	tls              *tls.Config
+	timeout          time.Duration
+	// ackTimeout is zero without acknowledgments.
+	ackTimeout time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// fluentChunk holds the events of one tag in a batch.
+type fluentChunk struct {
+	tag    string
+	events []byte
+	n      int
+}
+
+// send writes a batch framed by fluentCore as one PackedForward message per
+// tag, in order of first appearance.
+func (c *fluentConn) send(body []byte) error {
+	var chunks []*fluentChunk
+	byTag := map[string]*fluentChunk{}
+	for len(body) > 0 {
+		var tag, event []byte
+		tag, body = splitUvarintPrefixed(body)
+		event, body = splitUvarintPrefixed(body)
+		chunk := byTag[string(tag)]
+		if chunk == nil {
+			chunk = &fluentChunk{tag: string(tag)}
+			byTag[chunk.tag] = chunk
+			chunks = append(chunks, chunk)
+		}
+		chunk.events = append(chunk.events, event...)
+		chunk.n++
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, chunk := range chunks {
+		if err := c.forward(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitUvarintPrefixed splits a length-prefixed value off b.
+func splitUvarintPrefixed(b []byte) (value, rest []byte) {
+	n, size := binary.Uvarint(b)
+	b = b[size:]
+	return b[:n], b[n:]
+}
+
+// forward sends chunk, redialling and resending once after a failed write
+// or a missing acknowledgment.
+func (c *fluentConn) forward(chunk *fluentChunk) error {
+	msg := appendMsgpackHeader(nil, 3, 0x90, 0xdc)
+	msg = appendMsgpackString(msg, chunk.tag)
+	msg = appendMsgpackValue(msg, chunk.events)
+	var id string
+	if c.ackTimeout > 0 {
+		var raw [16]byte
+		rand.Read(raw[:])
+		id = base64.StdEncoding.EncodeToString(raw[:])
+		msg = appendMsgpackHeader(msg, 2, 0x80, 0xde)
+		msg = appendMsgpackString(msg, "size")
+		msg = appendMsgpackInt(msg, int64(chunk.n))
+		msg = appendMsgpackString(msg, "chunk")
+		msg = appendMsgpackString(msg, id)
+	} else {
+		msg = appendMsgpackHeader(msg, 1, 0x80, 0xde)
+		msg = appendMsgpackString(msg, "size")
+		msg = appendMsgpackInt(msg, int64(chunk.n))
+	}
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if err = c.dial(); err != nil {
+			continue
+		}
+		if err = c.write(msg, id); err == nil {
+			return nil
+		}
+		c.conn.Close()
+		c.conn = nil
+	}
+	return fmt.Errorf("fluent: %w", err)
+}
+
+// write sends msg on the open connection and, when id is set, waits for
+// its acknowledgment.
+func (c *fluentConn) write(msg []byte, id string) error {
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	if _, err := c.conn.Write(msg); err != nil {
+		return err
+	}
+	if id == "" {
+		return nil
+	}
+	c.conn.SetReadDeadline(time.Now().Add(c.ackTimeout))
+	resp, err := readMsgpackStringMap(c.reader)
+	if err != nil {
+		return fmt.Errorf("reading acknowledgment: %w", err)
+	}
+	if resp["ack"] != id {
+		return fmt.Errorf("acknowledgment %q does not match chunk %q", resp["ack"], id)
+	}
+	return nil
+}
+
+// dial connects if not connected. The caller holds mu.
+func (c *fluentConn) dial() error {
+	if c.conn != nil {
+		return nil
+	}
+	dialer := &net.Dialer{Timeout: c.timeout}
+	var err error
+	if c.tls != nil {
+		c.conn, err = tls.DialWithDialer(dialer, c.network, c.address, c.tls)
+	} else {
+		c.conn, err = dialer.Dial(c.network, c.address)
+	}
+	if err == nil {
+		c.reader = bufio.NewReader(c.conn)
+	}
+	return err
+}
+
+func (c *fluentConn) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// readMsgpackStringMap reads a MessagePack map of strings, the shape of
+// forward acknowledgments.
+func readMsgpackStringMap(r *bufio.Reader) (map[string]string, error) {
+	code, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	var n int
+	switch {
+	case code&0xf0 == 0x80:
+		n = int(code & 0x0f)
+	case code == 0xde:
+		var size uint16
+		err = binary.Read(r, binary.BigEndian, &size)
+		n = int(size)
+	default:
+		return nil, fmt.Errorf("expected a map, got type 0x%02x", code)
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		key, err := readMsgpackString(r)
+		if err != nil {
+			return nil, err
+		}
+		if m[key], err = readMsgpackString(r); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func readMsgpackString(r *bufio.Reader) (string, error) {
+	code, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	var n uint64
+	switch {
+	case code&0xe0 == 0xa0:
+		n = uint64(code & 0x1f)
+	case code == 0xd9 || code == 0xc4:
+		var size uint8
+		err = binary.Read(r, binary.BigEndian, &size)
+		n = uint64(size)
+	case code == 0xda || code == 0xc5:
+		var size uint16
+		err = binary.Read(r, binary.BigEndian, &size)
+		n = uint64(size)
+	case code == 0xdb || code == 0xc6:
+		var size uint32
+		err = binary.Read(r, binary.BigEndian, &size)
+		n = uint64(size)
+	default:
+		return "", fmt.Errorf("expected a string, got type 0x%02x", code)
+	}
+	if err != nil {
+		return "", err
+	}
+	if n > math.MaxUint16 {
+		return "", fmt.Errorf("string of %d bytes is too long", n)
+	}
+	b := make([]byte, n)
+	_, err = io.ReadFull(r, b)
+	return string(b), err
+}
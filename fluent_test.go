@@ -0,0 +1,314 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// decodeMsgpack reads one value in the shapes the forward provider writes;
+// EventTime becomes a time.Time.
+func decodeMsgpack(r *bufio.Reader) (interface{}, error) {
+	code, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	readN := func(size int) uint64 {
+		b := make([]byte, size)
+		io.ReadFull(r, b)
+		var v uint64
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+		return v
+	}
+	readMap := func(n int) (interface{}, error) {
+		m := map[string]interface{}{}
+		for i := 0; i < n; i++ {
+			k, err := decodeMsgpack(r)
+			if err != nil {
+				return nil, err
+			}
+			if m[fmt.Sprint(k)], err = decodeMsgpack(r); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	readArray := func(n int) (interface{}, error) {
+		a := make([]interface{}, n)
+		for i := range a {
+			if a[i], err = decodeMsgpack(r); err != nil {
+				return nil, err
+			}
+		}
+		return a, nil
+	}
+	readBytes := func(n uint64) []byte {
+		b := make([]byte, n)
+		io.ReadFull(r, b)
+		return b
+	}
+	switch {
+	case code < 0x80:
+		return int64(code), nil
+	case code >= 0xe0:
+		return int64(int8(code)), nil
+	case code&0xf0 == 0x80:
+		return readMap(int(code & 0x0f))
+	case code&0xf0 == 0x90:
+		return readArray(int(code & 0x0f))
+	case code&0xe0 == 0xa0:
+		return string(readBytes(uint64(code & 0x1f))), nil
+	}
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2, 0xc3:
+		return code == 0xc3, nil
+	case 0xc4:
+		return readBytes(readN(1)), nil
+	case 0xc5:
+		return readBytes(readN(2)), nil
+	case 0xc6:
+		return readBytes(readN(4)), nil
+	case 0xcb:
+		return math.Float64frombits(readN(8)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return int64(readN(1 << (code - 0xcc))), nil
+	case 0xd0:
+		return int64(int8(readN(1))), nil
+	case 0xd9:
+		return string(readBytes(readN(1))), nil
+	case 0xda:
+		return string(readBytes(readN(2))), nil
+	case 0xde:
+		return readMap(int(readN(2)))
+	case 0xdc:
+		return readArray(int(readN(2)))
+	case 0xd7:
+		if typ, _ := r.ReadByte(); typ != 0 {
+			return nil, fmt.Errorf("unexpected extension type %d", typ)
+		}
+		sec, nsec := readN(4), readN(4)
+		return time.Unix(int64(sec), int64(nsec)).UTC(), nil
+	}
+	return nil, fmt.Errorf("unsupported type 0x%02x", code)
+}
+
+// fakeForward accepts forward connections and records the messages; with
+// ack set it answers chunk options, except for the first skipAcks.
+type fakeForward struct {
+	ln       net.Listener
+	ack      bool
+	skipAcks int
+
+	mu       sync.Mutex
+	messages [][]interface{}
+	conns    int
+	wg       sync.WaitGroup
+}
+
+func newFakeForward(t *testing.T, network, address string) *fakeForward {
+	t.Helper()
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeForward{ln: ln}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			f.mu.Lock()
+			f.conns++
+			f.mu.Unlock()
+			f.wg.Add(1)
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeForward) serve(conn net.Conn) {
+	defer f.wg.Done()
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		v, err := decodeMsgpack(r)
+		if err != nil {
+			return
+		}
+		msg, _ := v.([]interface{})
+		f.mu.Lock()
+		f.messages = append(f.messages, msg)
+		skip := f.skipAcks > 0
+		if skip {
+			f.skipAcks--
+		}
+		f.mu.Unlock()
+		if len(msg) < 3 || !f.ack {
+			continue
+		}
+		option, _ := msg[2].(map[string]interface{})
+		if chunk, ok := option["chunk"].(string); ok && !skip {
+			resp := appendMsgpackHeader(nil, 1, 0x80, 0xde)
+			resp = appendMsgpackString(resp, "ack")
+			conn.Write(appendMsgpackString(resp, chunk))
+		}
+	}
+}
+
+// close stops the listener and waits until the connections are drained.
+func (f *fakeForward) close() {
+	f.ln.Close()
+	f.wg.Wait()
+}
+
+// events decodes the entries of a PackedForward message.
+func (f *fakeForward) events(t *testing.T, msg []interface{}) [][]interface{} {
+	t.Helper()
+	packed, ok := msg[1].([]byte)
+	if !ok {
+		t.Fatalf("entries are %T, want bin", msg[1])
+	}
+	var events [][]interface{}
+	r := bufio.NewReader(bytes.NewReader(packed))
+	for {
+		v, err := decodeMsgpack(r)
+		if err != nil {
+			return events
+		}
+		events = append(events, v.([]interface{}))
+	}
+}
+
+func TestFluentProvider_PackedForward(t *testing.T) {
+	fake := newFakeForward(t, "tcp", "127.0.0.1:0")
+	at := time.Date(2026, 10, 16, 9, 30, 0, 123456789, time.UTC)
+	logger, err := NewLogger(
+		WithFluentProvider("tcp", fake.ln.Addr().String(), FluentConfig{
+			Tag:           "app",
+			TagLoggerName: true,
+			BatchSize:     3,
+			FlushInterval: time.Hour,
+		}),
+		WithZapOptions(zap.WithClock(fixedClock{at})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("first", String("user", "ann"), Int("n", -3))
+	logger.Named("db").Warn("slow", Dict("q", Float64("ms", 1.5)))
+	logger.Info("second")
+	logger.Close()
+	fake.close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.messages) != 2 {
+		t.Fatalf("got %d messages, want one per tag: %v", len(fake.messages), fake.messages)
+	}
+	if fake.messages[0][0] != "app" || fake.messages[1][0] != "app.db" {
+		t.Errorf("tags = %v, %v", fake.messages[0][0], fake.messages[1][0])
+	}
+	if option := fake.messages[0][2].(map[string]interface{}); option["size"] != int64(2) {
+		t.Errorf("option = %v", option)
+	}
+	events := fake.events(t, fake.messages[0])
+	if len(events) != 2 || events[0][0] != at {
+		t.Fatalf("unexpected events %v", events)
+	}
+	record := events[0][1].(map[string]interface{})
+	if record["msg"] != "first" || record["level"] != "info" || record["user"] != "ann" || record["n"] != int64(-3) {
+		t.Errorf("unexpected record %v", record)
+	}
+	if _, ok := record["ts"]; ok {
+		t.Errorf("record repeats the event time: %v", record)
+	}
+	db := fake.events(t, fake.messages[1])[0][1].(map[string]interface{})
+	if q, _ := db["q"].(map[string]interface{}); db["logger"] != "db" || q["ms"] != 1.5 {
+		t.Errorf("unexpected record %v", db)
+	}
+}
+
+func TestFluentProvider_AckAndReconnect(t *testing.T) {
+	fake := newFakeForward(t, "unix", filepath.Join(t.TempDir(), "fluent.sock"))
+	fake.ack = true
+	fake.skipAcks = 1
+	logger, err := NewLogger(WithFluentProvider("unix", fake.ln.Addr().String(), FluentConfig{
+		Ack:           true,
+		AckTimeout:    100 * time.Millisecond,
+		BatchSize:     1,
+		FlushInterval: time.Hour,
+	}, WithProviderEncoder(JSONEncoder)))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("hello", Int("attempt", 1))
+	logger.Close()
+	fake.close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	// The unacknowledged chunk is resent on a new connection.
+	if len(fake.messages) != 2 || fake.conns != 2 {
+		t.Fatalf("got %d messages on %d connections", len(fake.messages), fake.conns)
+	}
+	first, second := fake.messages[0][2].(map[string]interface{}), fake.messages[1][2].(map[string]interface{})
+	if first["chunk"] == nil || first["chunk"] != second["chunk"] {
+		t.Errorf("chunk ids = %v, %v", first["chunk"], second["chunk"])
+	}
+	record := fake.events(t, fake.messages[1])[0][1].(map[string]interface{})
+	if log, _ := record["log"].(string); !strings.HasPrefix(log, "{") || !strings.Contains(log, `"attempt":1`) || strings.HasSuffix(log, "\n") {
+		t.Errorf("unexpected record %v", record)
+	}
+	if n := logger.DroppedEntries()[DropProviderError]; n != 0 {
+		t.Errorf("provider error drops = %d, want 0", n)
+	}
+}
+
+func TestFluentProvider_Unreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := ln.Addr().String()
+	ln.Close()
+
+	logger, err := NewLogger(WithFluentProvider("tcp", address, FluentConfig{BatchSize: 1, Timeout: time.Second}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Info("lost")
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+}
+
+func TestFluentProvider_Errors(t *testing.T) {
+	if _, err := NewLogger(WithFluentProvider("udp", "localhost", FluentConfig{})); err == nil || !strings.Contains(err.Error(), "unsupported network") {
+		t.Errorf("expected network error, got %v", err)
+	}
+	if _, err := NewLogger(WithFluentProvider("unix", "/tmp/fluent.sock", FluentConfig{TLS: &TLSConfig{}})); err == nil || !strings.Contains(err.Error(), "TLS needs a tcp") {
+		t.Errorf("expected TLS error, got %v", err)
+	}
+	r := bufio.NewReader(bytes.NewReader(binary.BigEndian.AppendUint16([]byte{0xde}, 1)))
+	if _, err := readMsgpackStringMap(r); err == nil {
+		t.Error("expected error for a truncated map")
+	}
+}
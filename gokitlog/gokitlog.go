@@ -0,0 +1,98 @@
+// Package gokitlog adapts a golog Logger to go-kit's log.Logger interface,
+//
+//	type Logger interface {
+//		Log(keyvals ...interface{}) error
+//	}
+//
+// so go-kit based services and middlewares can write through golog without
+// rewriting their logging calls. Being an interface, it is satisfied
+// without importing go-kit:
+//
+//	var kitLogger log.Logger = gokitlog.New(logger)
+//	level.Info(kitLogger).Log("msg", "listening", "addr", addr)
+//
+// The "level" keyval (as written by go-kit's level package) selects the
+// golog level, Info by default; "msg" becomes the message. All other pairs
+// become fields.
+package gokitlog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evdnx/golog"
+)
+
+// Keys with special meaning, matching go-kit's conventions.
+const (
+	LevelKey   = "level"
+	MessageKey = "msg"
+)
+
+// missingValue fills in the value of an odd trailing key, as go-kit does.
+const missingValue = "(MISSING)"
+
+// Logger implements go-kit's log.Logger on top of a golog Logger.
+type Logger struct {
+	logger *golog.Logger
+}
+
+// New returns an adapter writing to logger.
+func New(logger *golog.Logger) *Logger {
+	return &Logger{logger: logger}
+}
+
+// Log writes keyvals as one entry. It never fails; golog reports write
+// errors through its own error handler.
+func (l *Logger) Log(keyvals ...interface{}) error {
+	if len(keyvals)%2 == 1 {
+		keyvals = append(keyvals, missingValue)
+	}
+	lvl := golog.InfoLevel
+	var msg string
+	fields := make([]golog.Field, 0, len(keyvals)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		switch value := keyvals[i+1]; key {
+		case LevelKey:
+			// Unknown values keep the default rather than clash with the
+			// encoder's own level key.
+			if parsed, ok := parseLevel(value); ok {
+				lvl = parsed
+			}
+		case MessageKey:
+			msg = fmt.Sprint(value)
+		default:
+			fields = append(fields, golog.Any(key, value))
+		}
+	}
+
+	switch lvl {
+	case golog.DebugLevel:
+		l.logger.Debug(msg, fields...)
+	case golog.InfoLevel:
+		l.logger.Info(msg, fields...)
+	case golog.WarnLevel:
+		l.logger.Warn(msg, fields...)
+	default:
+		// go-kit has no fatal level; never exit on its behalf.
+		l.logger.Error(msg, fields...)
+	}
+	return nil
+}
+
+// parseLevel understands go-kit's level values, which print as debug, info,
+// warn and error.
+func parseLevel(v interface{}) (golog.Level, bool) {
+	switch strings.ToLower(fmt.Sprint(v)) {
+	case "debug":
+		return golog.DebugLevel, true
+	case "info":
+		return golog.InfoLevel, true
+	case "warn", "warning":
+		return golog.WarnLevel, true
+	case "error":
+		return golog.ErrorLevel, true
+	}
+	return 0, false
+}
@@ -0,0 +1,49 @@
+package gokitlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/evdnx/golog"
+)
+
+// levelValue mimics go-kit's level.Value, which is a fmt.Stringer.
+type levelValue string
+
+func (v levelValue) String() string { return string(v) }
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := golog.NewLogger(golog.WithWriterProvider(&buf, golog.JSONEncoder), golog.WithLevel(golog.DebugLevel))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	kit := New(logger)
+	kit.Log("level", levelValue("warn"), "msg", "slow request", "path", "/orders", "ms", 812)
+	kit.Log("transport", "http", "addr", ":8080")
+	kit.Log("level", "verbose", "dangling")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 entries, got %q", buf.String())
+	}
+	var entries [3]map[string]interface{}
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &entries[i]); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+	}
+	if e := entries[0]; e["level"] != "warn" || e["msg"] != "slow request" || e["path"] != "/orders" || e["ms"] != float64(812) {
+		t.Errorf("unexpected first entry: %v", e)
+	}
+	if e := entries[1]; e["level"] != "info" || e["addr"] != ":8080" {
+		t.Errorf("unexpected second entry: %v", e)
+	}
+	if e := entries[2]; e["level"] != "info" || e["dangling"] != missingValue || strings.Count(lines[2], `"level"`) != 1 {
+		t.Errorf("unexpected third entry: %v", e)
+	}
+}
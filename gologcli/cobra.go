@@ -0,0 +1,16 @@
+package gologcli
+
+import "github.com/spf13/cobra"
+
+// AddCobraFlags registers the logging flags as persistent flags of cmd, so
+// they are accepted by all of its subcommands, and returns the Flags they
+// are parsed into.
+func AddCobraFlags(cmd *cobra.Command) *Flags {
+	f := &Flags{}
+	fs := cmd.PersistentFlags()
+	fs.StringVar(&f.Level, LevelFlag, "info", levelUsage)
+	fs.StringVar(&f.Format, FormatFlag, "json", formatUsage)
+	fs.StringVar(&f.File, FileFlag, "", fileUsage)
+	fs.CountVarP(&f.Verbosity, VerboseFlag, "v", verboseUsage)
+	return f
+}
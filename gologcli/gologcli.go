@@ -0,0 +1,102 @@
+// Package gologcli registers the standard logging flags on cobra and
+// urfave/cli applications and builds a golog.Logger from them:
+//
+//	--log-level   trace, debug, info (default), warn, error or fatal
+//	--log-format  json (default) or console
+//	--log-file    write logs to a rotating file instead of stderr
+//	-v, --verbose lower the level one step per occurrence (-vv, -vvv)
+//
+// With cobra:
+//
+//	flags := gologcli.AddCobraFlags(rootCmd)
+//	rootCmd.PersistentPreRunE = func(*cobra.Command, []string) error {
+//		logger, err := flags.NewLogger()
+//		…
+//	}
+//
+// With urfave/cli:
+//
+//	var flags gologcli.Flags
+//	app := &cli.App{
+//		Flags:  flags.CLIFlags(),
+//		Before: func(*cli.Context) error { logger, err := flags.NewLogger(); … },
+//	}
+//
+// Logs go to stderr so they do not mix with the program's output on stdout.
+package gologcli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/evdnx/golog"
+)
+
+// Flag names shared by the cobra and urfave/cli registrations.
+const (
+	LevelFlag   = "log-level"
+	FormatFlag  = "log-format"
+	FileFlag    = "log-file"
+	VerboseFlag = "verbose"
+)
+
+// Flags holds the parsed values of the logging flags.
+type Flags struct {
+	Level     string
+	Format    string
+	File      string
+	Verbosity int
+}
+
+// Options returns the logger options the flags describe. Each -v lowers the
+// level one step below --log-level, stopping at Trace.
+func (f *Flags) Options() ([]golog.LoggerOption, error) {
+	level := golog.InfoLevel
+	if f.Level != "" {
+		var err error
+		if level, err = golog.ParseLevel(f.Level); err != nil {
+			return nil, fmt.Errorf("--%s: %w", LevelFlag, err)
+		}
+	}
+	level -= golog.Level(f.Verbosity)
+	if level < golog.TraceLevel {
+		level = golog.TraceLevel
+	}
+
+	var enc golog.EncoderType
+	switch v := strings.ToLower(f.Format); v {
+	case "", "json":
+		enc = golog.JSONEncoder
+	case "console", "text":
+		enc = golog.ConsoleEncoder
+	default:
+		return nil, fmt.Errorf("--%s: unsupported value %q", FormatFlag, v)
+	}
+
+	opts := []golog.LoggerOption{golog.WithLevel(level)}
+	if f.File != "" {
+		// Zero rotation parameters select lumberjack's defaults.
+		opts = append(opts, golog.WithFileProvider(f.File, 0, 0, 0, false, golog.WithProviderEncoder(enc)))
+	} else {
+		opts = append(opts, golog.WithWriterProvider(os.Stderr, enc))
+	}
+	return opts, nil
+}
+
+// NewLogger builds a logger from the flags. Further options are applied
+// after the ones derived from the flags.
+func (f *Flags) NewLogger(options ...golog.LoggerOption) (*golog.Logger, error) {
+	opts, err := f.Options()
+	if err != nil {
+		return nil, err
+	}
+	return golog.NewLogger(append(opts, options...)...)
+}
+
+const (
+	levelUsage   = "minimum log level: trace, debug, info, warn, error or fatal"
+	formatUsage  = "log format: json or console"
+	fileUsage    = "write logs to this rotating file instead of stderr"
+	verboseUsage = "lower the log level one step per occurrence (-vv for two)"
+)
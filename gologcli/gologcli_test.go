@@ -0,0 +1,85 @@
+package gologcli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/urfave/cli/v2"
+)
+
+// logAll builds a logger from f, logs one entry per level and returns the
+// contents of the log file.
+func logAll(t *testing.T, f *Flags) string {
+	t.Helper()
+	logger, err := f.NewLogger()
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	logger.Trace("trace entry")
+	logger.Debug("debug entry")
+	logger.Info("info entry")
+	logger.Warn("warn entry")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	data, err := os.ReadFile(f.File)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	return string(data)
+}
+
+func TestCobraFlags(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	cmd := &cobra.Command{Use: "app"}
+	sub := &cobra.Command{Use: "serve", Run: func(*cobra.Command, []string) {}}
+	cmd.AddCommand(sub)
+	flags := AddCobraFlags(cmd)
+
+	cmd.SetArgs([]string{"serve", "--log-level", "error", "-vv", "--log-file", file})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if flags.Verbosity != 2 || flags.Level != "error" {
+		t.Fatalf("unexpected flags: %+v", flags)
+	}
+	out := logAll(t, flags)
+	if strings.Contains(out, "debug entry") || !strings.Contains(out, "info entry") {
+		t.Errorf("expected level info after -vv from error: %s", out)
+	}
+}
+
+func TestURFaveFlags(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	var flags Flags
+	app := &cli.App{Flags: flags.CLIFlags(), Action: func(*cli.Context) error { return nil }}
+	if err := app.Run([]string{"app", "-v", "-v", "-v", "--log-file", file}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if flags.Verbosity != 3 || flags.Level != "info" {
+		t.Fatalf("unexpected flags: %+v", flags)
+	}
+	if out := logAll(t, &flags); !strings.Contains(out, "trace entry") {
+		t.Errorf("three -v from info should reach trace: %s", out)
+	}
+}
+
+func TestFileFormat(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.log")
+	flags := &Flags{Format: "console", File: file}
+	out := logAll(t, flags)
+	if !strings.Contains(out, "info entry") || strings.Contains(out, `"msg"`) {
+		t.Errorf("expected console output in the log file: %s", out)
+	}
+}
+
+func TestInvalidFlags(t *testing.T) {
+	for _, f := range []Flags{{Level: "loud"}, {Format: "xml"}} {
+		if _, err := f.Options(); err == nil {
+			t.Errorf("expected error for %+v", f)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package gologcli
+
+import "github.com/urfave/cli/v2"
+
+// CLIFlags returns urfave/cli flags that parse into f. Add them to an App's
+// or Command's Flags.
+func (f *Flags) CLIFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: LevelFlag, Value: "info", Usage: levelUsage, Destination: &f.Level},
+		&cli.StringFlag{Name: FormatFlag, Value: "json", Usage: formatUsage, Destination: &f.Format},
+		&cli.StringFlag{Name: FileFlag, Usage: fileUsage, Destination: &f.File},
+		&cli.BoolFlag{Name: VerboseFlag, Aliases: []string{"v"}, Usage: verboseUsage, Count: &f.Verbosity},
+	}
+}
@@ -0,0 +1,141 @@
+// Package gologotel implements the OpenTelemetry logs bridge API
+// (go.opentelemetry.io/otel/log) on top of a golog logger, so records emitted
+// by instrumentation libraries through the OTel logs API reach golog's
+// providers:
+//
+//	global.SetLoggerProvider(gologotel.NewLoggerProvider(logger))
+//
+// This is the inbound direction: it does not export golog entries over OTLP.
+//
+// Severities map to the golog level of their range; Fatal severities are
+// logged at Error, since an OTel record must not terminate the process. A
+// string body becomes the message, any other body the "body" field.
+// Attributes become fields, and the instrumentation scope name and event
+// name are added as "otel.scope.name" and "event.name". When the context
+// carries a valid span, its IDs are added as "trace_id" and "span_id".
+// Entries are timestamped when they are emitted.
+package gologotel
+
+import (
+	"context"
+
+	"github.com/evdnx/golog"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LoggerProvider is a log.LoggerProvider whose loggers write to a golog
+// logger.
+type LoggerProvider struct {
+	embedded.LoggerProvider
+	logger *golog.Logger
+}
+
+var _ log.LoggerProvider = (*LoggerProvider)(nil)
+
+// NewLoggerProvider returns a LoggerProvider writing to logger.
+func NewLoggerProvider(logger *golog.Logger) *LoggerProvider {
+	return &LoggerProvider{logger: logger}
+}
+
+// Logger returns a log.Logger for the instrumentation scope name.
+func (p *LoggerProvider) Logger(name string, _ ...log.LoggerOption) log.Logger {
+	return &otelLogger{logger: p.logger, scope: name}
+}
+
+type otelLogger struct {
+	embedded.Logger
+	logger *golog.Logger
+	scope  string
+}
+
+// Emit writes record to the golog logger.
+func (l *otelLogger) Emit(ctx context.Context, record log.Record) {
+	ev := l.logger.Event(level(record.Severity()))
+	if ev == nil {
+		return
+	}
+	if l.scope != "" {
+		ev.Str("otel.scope.name", l.scope)
+	}
+	if name := record.EventName(); name != "" {
+		ev.Str("event.name", name)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		ev.Str(string(golog.TraceIDKey), sc.TraceID().String())
+		ev.Str(string(golog.SpanIDKey), sc.SpanID().String())
+	}
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		ev.Any(kv.Key, value(kv.Value))
+		return true
+	})
+
+	var msg string
+	switch body := record.Body(); body.Kind() {
+	case log.KindString:
+		msg = body.AsString()
+	case log.KindEmpty:
+	default:
+		ev.Any("body", value(body))
+	}
+	ev.Ctx(ctx).Msg(msg)
+}
+
+// Enabled reports whether the golog logger would log records of the given
+// severity, letting bridges skip building them.
+func (l *otelLogger) Enabled(_ context.Context, param log.EnabledParameters) bool {
+	return l.logger.Enabled(level(param.Severity))
+}
+
+// level maps an OTel severity to the golog level of its range. Undefined
+// severities are logged at Info and Fatal ones at Error.
+func level(s log.Severity) golog.Level {
+	switch {
+	case s == log.SeverityUndefined:
+		return golog.InfoLevel
+	case s < log.SeverityDebug1:
+		return golog.TraceLevel
+	case s < log.SeverityInfo1:
+		return golog.DebugLevel
+	case s < log.SeverityWarn1:
+		return golog.InfoLevel
+	case s < log.SeverityError1:
+		return golog.WarnLevel
+	default:
+		return golog.ErrorLevel
+	}
+}
+
+// value converts v to the equivalent Go value, with maps as
+// map[string]interface{} and slices as []interface{}.
+func value(v log.Value) interface{} {
+	switch v.Kind() {
+	case log.KindBool:
+		return v.AsBool()
+	case log.KindInt64:
+		return v.AsInt64()
+	case log.KindFloat64:
+		return v.AsFloat64()
+	case log.KindString:
+		return v.AsString()
+	case log.KindBytes:
+		return v.AsBytes()
+	case log.KindSlice:
+		vs := v.AsSlice()
+		out := make([]interface{}, len(vs))
+		for i, e := range vs {
+			out[i] = value(e)
+		}
+		return out
+	case log.KindMap:
+		kvs := v.AsMap()
+		out := make(map[string]interface{}, len(kvs))
+		for _, kv := range kvs {
+			out[kv.Key] = value(kv.Value)
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package gologotel
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/evdnx/golog"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestEmit(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := golog.NewLogger(golog.WithLevel(golog.InfoLevel), golog.WithWriterProvider(&buf, golog.JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	otelLog := NewLoggerProvider(logger).Logger("github.com/example/instr")
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	var rec log.Record
+	rec.SetSeverity(log.SeverityWarn2)
+	rec.SetBody(log.StringValue("cache miss"))
+	rec.SetEventName("cache.lookup")
+	rec.AddAttributes(
+		log.String("key", "user:42"),
+		log.Int("attempt", 3),
+		log.Map("peer", log.String("host", "redis")),
+		log.Slice("tags", log.StringValue("a"), log.BoolValue(true)),
+	)
+	otelLog.Emit(ctx, rec)
+
+	var debug log.Record
+	debug.SetSeverity(log.SeverityTrace)
+	debug.SetBody(log.StringValue("hidden"))
+	otelLog.Emit(ctx, debug)
+	if otelLog.Enabled(ctx, log.EnabledParameters{Severity: log.SeverityTrace}) {
+		t.Error("trace severity reported enabled below the logger's level")
+	}
+	if !otelLog.Enabled(ctx, log.EnabledParameters{Severity: log.SeverityWarn}) {
+		t.Error("warn severity reported disabled")
+	}
+
+	var fatal log.Record
+	fatal.SetSeverity(log.SeverityFatal)
+	fatal.SetBody(log.MapValue(log.Int("code", 7)))
+	otelLog.Emit(context.Background(), fatal)
+
+	out := buf.String()
+	for _, want := range []string{
+		`"level":"warn"`, `"msg":"cache miss"`, `"otel.scope.name":"github.com/example/instr"`,
+		`"event.name":"cache.lookup"`, `"key":"user:42"`, `"attempt":3`, `"peer":{"host":"redis"}`,
+		`"tags":["a",true]`, `"trace_id":"01000000000000000000000000000000"`, `"span_id":"0200000000000000"`,
+		`"level":"error"`, `"body":{"code":7}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in output: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "hidden") {
+		t.Errorf("trace record logged below Info: %s", out)
+	}
+}
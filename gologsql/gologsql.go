@@ -0,0 +1,343 @@
+// Package gologsql wraps a database/sql driver so that every query and
+// statement execution is logged through golog with its duration, errors and,
+// optionally, its arguments – database observability without an ORM:
+//
+//	sql.Register("postgres-logged", gologsql.Wrap(&pq.Driver{}, gologsql.Options{
+//		Logger:        logger,
+//		SlowThreshold: 200 * time.Millisecond,
+//	}))
+//	db, err := sql.Open("postgres-logged", dsn)
+//
+// or, for drivers that expose a driver.Connector:
+//
+//	db := sql.OpenDB(gologsql.WrapConnector(connector, opts))
+//
+// Successful statements are logged at Debug, statements slower than
+// SlowThreshold at Warn and failed ones at Error, with the fields
+// db.operation, db.statement, duration and (if enabled) db.args. Context
+// passed to QueryContext/ExecContext reaches golog's enrichers.
+package gologsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/evdnx/golog"
+)
+
+// Options configures the wrapper.
+type Options struct {
+	// Logger receives the entries. Required.
+	Logger *golog.Logger
+	// SlowThreshold logs statements taking at least this long at Warn; zero
+	// disables slow-query detection.
+	SlowThreshold time.Duration
+	// LogArgs adds the statement arguments as db.args.
+	LogArgs bool
+	// Redact, if set, replaces each logged argument with its return value,
+	// e.g. RedactAll or a function masking columns known to hold secrets.
+	// ordinal starts at 1; name is empty for positional arguments.
+	Redact func(ordinal int, name string, value any) any
+}
+
+// Redacted is the value RedactAll substitutes.
+const Redacted = "[REDACTED]"
+
+// RedactAll logs the number of arguments but none of their values.
+func RedactAll(int, string, any) any { return Redacted }
+
+// Wrap returns a driver that logs through opts.Logger and otherwise behaves
+// like d.
+func Wrap(d driver.Driver, opts Options) driver.Driver {
+	wd := &wrappedDriver{Driver: d, opts: &opts}
+	if _, ok := d.(driver.DriverContext); ok {
+		return wrappedDriverContext{wd}
+	}
+	return wd
+}
+
+// WrapConnector is Wrap for a driver.Connector, for use with sql.OpenDB.
+func WrapConnector(c driver.Connector, opts Options) driver.Connector {
+	return &connector{inner: c, drv: &wrappedDriver{Driver: c.Driver(), opts: &opts}}
+}
+
+type wrappedDriver struct {
+	driver.Driver
+	opts *Options
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: c, opts: d.opts}, nil
+}
+
+// wrappedDriverContext is returned for drivers implementing
+// driver.DriverContext, so database/sql keeps using their connectors.
+type wrappedDriverContext struct {
+	*wrappedDriver
+}
+
+func (d wrappedDriverContext) OpenConnector(name string) (driver.Connector, error) {
+	c, err := d.Driver.(driver.DriverContext).OpenConnector(name)
+	if err != nil {
+		return nil, err
+	}
+	return &connector{inner: c, drv: d.wrappedDriver}, nil
+}
+
+type connector struct {
+	inner driver.Connector
+	drv   *wrappedDriver
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	inner, err := c.inner.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: inner, opts: c.drv.opts}, nil
+}
+
+func (c *connector) Driver() driver.Driver { return c.drv }
+
+/* -------------------------------------------------------------------------- */
+/*                                Connections                                  */
+/* -------------------------------------------------------------------------- */
+
+type conn struct {
+	driver.Conn
+	opts *Options
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		s   driver.Stmt
+		err error
+	)
+	if cp, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		s, err = cp.PrepareContext(ctx, query)
+	} else {
+		s, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		c.opts.log(ctx, "prepare", query, nil, time.Time{}, err)
+		return nil, err
+	}
+	ws := &stmt{Stmt: s, query: query, opts: c.opts}
+	if _, ok := s.(driver.ColumnConverter); ok {
+		return columnConverterStmt{ws}, nil
+	}
+	return ws, nil
+}
+
+// BeginTx falls back to Begin for drivers without driver.ConnBeginTx,
+// refusing options Begin cannot honour as database/sql itself would.
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if cb, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return cb.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("gologsql: driver does not support non-default isolation level")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("gologsql: driver does not support read-only transactions")
+	}
+	return c.Conn.Begin()
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var (
+		res driver.Result
+		err error
+	)
+	switch e := c.Conn.(type) {
+	case driver.ExecerContext:
+		res, err = e.ExecContext(ctx, query, args)
+	case driver.Execer:
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			res, err = e.Exec(query, values)
+		}
+	default:
+		return nil, driver.ErrSkip
+	}
+	c.opts.log(ctx, "exec", query, args, start, err)
+	return res, err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var (
+		rows driver.Rows
+		err  error
+	)
+	switch q := c.Conn.(type) {
+	case driver.QueryerContext:
+		rows, err = q.QueryContext(ctx, query, args)
+	case driver.Queryer:
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			rows, err = q.Query(query, values)
+		}
+	default:
+		return nil, driver.ErrSkip
+	}
+	c.opts.log(ctx, "query", query, args, start, err)
+	return rows, err
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+/* -------------------------------------------------------------------------- */
+/*                            Prepared Statements                              */
+/* -------------------------------------------------------------------------- */
+
+type stmt struct {
+	driver.Stmt
+	query string
+	opts  *Options
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var (
+		res driver.Result
+		err error
+	)
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+	s.opts.log(ctx, "exec", s.query, args, start, err)
+	return res, err
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	s.opts.log(ctx, "query", s.query, args, start, err)
+	return rows, err
+}
+
+func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// columnConverterStmt is returned for statements implementing the legacy
+// driver.ColumnConverter, so database/sql keeps converting their arguments.
+// It is a separate type because database/sql treats any statement with the
+// method as having per-column converters.
+type columnConverterStmt struct {
+	*stmt
+}
+
+func (s columnConverterStmt) ColumnConverter(idx int) driver.ValueConverter {
+	return s.Stmt.(driver.ColumnConverter).ColumnConverter(idx)
+}
+
+func namedToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, errors.New("gologsql: driver does not support named parameters")
+		}
+		values[i] = a.Value
+	}
+	return values, nil
+}
+
+/* -------------------------------------------------------------------------- */
+/*                                  Logging                                    */
+/* -------------------------------------------------------------------------- */
+
+// log writes one entry for a statement started at start (zero if it never
+// ran). driver.ErrSkip only asks database/sql to take another path.
+func (o *Options) log(ctx context.Context, op, query string, args []driver.NamedValue, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	fields := []golog.Field{golog.String("db.operation", op), golog.String("db.statement", query)}
+	var elapsed time.Duration
+	if !start.IsZero() {
+		elapsed = time.Since(start)
+		fields = append(fields, golog.Duration("duration", elapsed))
+	}
+	if o.LogArgs && len(args) > 0 {
+		fields = append(fields, golog.Any("db.args", o.args(args)))
+	}
+	switch {
+	case err != nil:
+		o.Logger.ErrorCtx(ctx, "sql statement failed", append(fields, golog.Err(err))...)
+	case o.SlowThreshold > 0 && elapsed >= o.SlowThreshold:
+		o.Logger.WarnCtx(ctx, "slow sql statement", fields...)
+	default:
+		o.Logger.DebugCtx(ctx, "sql statement", fields...)
+	}
+}
+
+func (o *Options) args(args []driver.NamedValue) []any {
+	out := make([]any, len(args))
+	for i, a := range args {
+		v := a.Value
+		if o.Redact != nil {
+			v = o.Redact(a.Ordinal, a.Name, v)
+		}
+		out[i] = v
+	}
+	return out
+}
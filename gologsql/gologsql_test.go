@@ -0,0 +1,226 @@
+package gologsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evdnx/golog"
+)
+
+// fakeConnector is a minimal driver: queries return a single row holding
+// the query text, "SLOW" statements sleep, and "FAIL" statements fail.
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (fakeConnector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query: query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("no transactions") }
+
+func (fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	return run(query, driver.RowsAffected(1))
+}
+
+func (fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	return run(query, &fakeRows{value: query})
+}
+
+type fakeStmt struct{ query string }
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return run(s.query, driver.RowsAffected(1))
+}
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return run(s.query, &fakeRows{value: s.query})
+}
+
+func run[T any](query string, result T) (T, error) {
+	if strings.Contains(query, "SLOW") {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if strings.Contains(query, "FAIL") {
+		var zero T
+		return zero, errors.New("syntax error")
+	}
+	return result, nil
+}
+
+type fakeRows struct {
+	value string
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"value"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func TestWrapConnector(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := golog.NewLogger(golog.WithWriterProvider(&buf, golog.JSONEncoder), golog.WithLevel(golog.DebugLevel))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	db := sql.OpenDB(WrapConnector(fakeConnector{}, Options{
+		Logger:        logger,
+		SlowThreshold: 10 * time.Millisecond,
+		LogArgs:       true,
+		Redact: func(ordinal int, _ string, v any) any {
+			if ordinal == 2 {
+				return Redacted
+			}
+			return v
+		},
+	}))
+	defer db.Close()
+
+	var got string
+	if err := db.QueryRow("SELECT name FROM users WHERE id = ? AND token = ?", 7, "s3cret").Scan(&got); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if _, err := db.Exec("UPDATE SLOW"); err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if _, err := db.Exec("FAIL"); err == nil {
+		t.Fatal("expected exec error")
+	}
+	stmt, err := db.Prepare("DELETE FROM sessions")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if _, err := stmt.Exec(); err != nil {
+		t.Fatalf("stmt exec: %v", err)
+	}
+	stmt.Close()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 entries, got %d: %s", len(lines), buf.String())
+	}
+	checks := []struct{ level, msg, extra string }{
+		{"debug", "sql statement", `"db.args":[7,"[REDACTED]"]`},
+		{"warn", "slow sql statement", `"db.statement":"UPDATE SLOW"`},
+		{"error", "sql statement failed", `"error":"syntax error"`},
+		{"debug", "sql statement", `"db.statement":"DELETE FROM sessions"`},
+	}
+	for i, c := range checks {
+		for _, want := range []string{`"level":"` + c.level + `"`, `"msg":"` + c.msg + `"`, c.extra, `"duration":`} {
+			if !strings.Contains(lines[i], want) {
+				t.Errorf("entry %d: missing %s in %s", i, want, lines[i])
+			}
+		}
+	}
+	if strings.Contains(buf.String(), "s3cret") {
+		t.Error("redacted argument leaked")
+	}
+}
+
+func TestWrapDriver(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := golog.NewLogger(golog.WithWriterProvider(&buf, golog.JSONEncoder), golog.WithLevel(golog.DebugLevel))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	c, err := Wrap(fakeDriver{}, Options{Logger: logger}).Open("")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer c.Close()
+	args := []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}
+	if _, err := c.(driver.ExecerContext).ExecContext(context.Background(), "INSERT x", args); err != nil {
+		t.Fatalf("exec: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"db.statement":"INSERT x"`) || strings.Contains(buf.String(), "db.args") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+}
+
+// convertingStmt upper-cases string arguments through driver.ColumnConverter.
+type convertingStmt struct{ fakeStmt }
+
+func (convertingStmt) ColumnConverter(int) driver.ValueConverter { return upperConverter{} }
+
+type upperConverter struct{}
+
+func (upperConverter) ConvertValue(v any) (driver.Value, error) {
+	if s, ok := v.(string); ok {
+		return strings.ToUpper(s), nil
+	}
+	return v, nil
+}
+
+func TestWrapBeginTxAndColumnConverter(t *testing.T) {
+	logger, err := golog.NewLogger(golog.WithWriterProvider(io.Discard, golog.JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	c, err := Wrap(fakeDriver{}, Options{Logger: logger}).Open("")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer c.Close()
+	cb := c.(driver.ConnBeginTx)
+	if _, err := cb.BeginTx(context.Background(), driver.TxOptions{ReadOnly: true}); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("read-only BeginTx: got %v", err)
+	}
+	if _, err := cb.BeginTx(context.Background(), driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelSerializable)}); err == nil || !strings.Contains(err.Error(), "isolation") {
+		t.Errorf("serializable BeginTx: got %v", err)
+	}
+	if _, err := cb.BeginTx(context.Background(), driver.TxOptions{}); err == nil || err.Error() != "no transactions" {
+		t.Errorf("default BeginTx should reach Begin: got %v", err)
+	}
+
+	s, err := c.Prepare("SELECT 1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if _, ok := s.(driver.ColumnConverter); ok {
+		t.Error("ColumnConverter exposed for a statement without one")
+	}
+	w := &conn{Conn: convertingConn{}, opts: &Options{Logger: logger}}
+	s, err = w.Prepare("SELECT 1")
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	cc, ok := s.(driver.ColumnConverter)
+	if !ok {
+		t.Fatal("ColumnConverter not forwarded")
+	}
+	if v, _ := cc.ColumnConverter(0).ConvertValue("abc"); v != "ABC" {
+		t.Errorf("ConvertValue = %v", v)
+	}
+}
+
+type convertingConn struct{ fakeConn }
+
+func (convertingConn) Prepare(query string) (driver.Stmt, error) {
+	return convertingStmt{fakeStmt{query: query}}, nil
+}
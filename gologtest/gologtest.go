@@ -0,0 +1,291 @@
+// Package gologtest provides implementations of golog.Log for unit tests:
+// Nop discards everything, Recorder keeps every call for assertions.
+//
+//	rec := gologtest.NewRecorder()
+//	svc := payments.New(rec)
+//	svc.Charge(ctx, order)
+//	if got := rec.FilterLevel(golog.ErrorLevel); len(got) != 0 {
+//		t.Errorf("unexpected errors: %v", got)
+//	}
+//
+// Neither implementation exits on Fatal or panics on DPanic; both panic on
+// Panic, which Recorder records at Error level first.
+package gologtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/evdnx/golog"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                                     Nop                                     */
+/* -------------------------------------------------------------------------- */
+
+// Nop is a golog.Log that discards every call.
+type Nop struct{}
+
+var _ golog.Log = Nop{}
+
+func (Nop) Log(golog.Level, string, ...golog.Field) {}
+
+// Enabled reports false so callers skip building fields.
+func (Nop) Enabled(golog.Level) bool { return false }
+
+func (Nop) Trace(string, ...golog.Field)       {}
+func (Nop) Debug(string, ...golog.Field)       {}
+func (Nop) Info(string, ...golog.Field)        {}
+func (Nop) Warn(string, ...golog.Field)        {}
+func (Nop) Error(string, ...golog.Field)       {}
+func (Nop) DPanic(string, ...golog.Field)      {}
+func (Nop) Panic(msg string, _ ...golog.Field) { panic(msg) }
+func (Nop) Fatal(string, ...golog.Field)       {}
+
+func (Nop) TraceCtx(context.Context, string, ...golog.Field) {}
+func (Nop) DebugCtx(context.Context, string, ...golog.Field) {}
+func (Nop) InfoCtx(context.Context, string, ...golog.Field)  {}
+func (Nop) WarnCtx(context.Context, string, ...golog.Field)  {}
+func (Nop) ErrorCtx(context.Context, string, ...golog.Field) {}
+func (Nop) FatalCtx(context.Context, string, ...golog.Field) {}
+
+func (Nop) Tracef(string, ...interface{})             {}
+func (Nop) Debugf(string, ...interface{})             {}
+func (Nop) Infof(string, ...interface{})              {}
+func (Nop) Warnf(string, ...interface{})              {}
+func (Nop) Errorf(string, ...interface{})             {}
+func (Nop) DPanicf(string, ...interface{})            {}
+func (Nop) Panicf(format string, args ...interface{}) { panic(fmt.Sprintf(format, args...)) }
+func (Nop) Fatalf(string, ...interface{})             {}
+
+func (Nop) Tracew(string, ...interface{})       {}
+func (Nop) Debugw(string, ...interface{})       {}
+func (Nop) Infow(string, ...interface{})        {}
+func (Nop) Warnw(string, ...interface{})        {}
+func (Nop) Errorw(string, ...interface{})       {}
+func (Nop) DPanicw(string, ...interface{})      {}
+func (Nop) Panicw(msg string, _ ...interface{}) { panic(msg) }
+func (Nop) Fatalw(string, ...interface{})       {}
+
+/* -------------------------------------------------------------------------- */
+/*                                  Recorder                                   */
+/* -------------------------------------------------------------------------- */
+
+// Entry is one recorded call.
+type Entry struct {
+	Level   golog.Level
+	Message string
+	Fields  []golog.Field
+	// Context is the context passed to the *Ctx methods, nil otherwise.
+	Context context.Context
+}
+
+// Field returns the value of the first field named key.
+func (e Entry) Field(key string) (interface{}, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Recorder is a golog.Log that records every call. It is safe for
+// concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+var _ golog.Log = (*Recorder)(nil)
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder { return &Recorder{} }
+
+// Entries returns a copy of the recorded entries in call order.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// FilterLevel returns the recorded entries at level.
+func (r *Recorder) FilterLevel(level golog.Level) []Entry {
+	return r.filter(func(e Entry) bool { return e.Level == level })
+}
+
+// FilterMessage returns the recorded entries with message msg.
+func (r *Recorder) FilterMessage(msg string) []Entry {
+	return r.filter(func(e Entry) bool { return e.Message == msg })
+}
+
+// Reset discards the recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+func (r *Recorder) filter(keep func(Entry) bool) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Entry
+	for _, e := range r.entries {
+		if keep(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (r *Recorder) record(ctx context.Context, level golog.Level, msg string, fields []golog.Field) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, Entry{Level: level, Message: msg, Fields: fields, Context: ctx})
+}
+
+// keysAndValuesToFields pairs up the arguments of the *w methods. A trailing
+// key without a value is recorded with a nil value.
+func keysAndValuesToFields(kv []interface{}) []golog.Field {
+	fields := make([]golog.Field, 0, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i += 2 {
+		f := golog.Field{Key: fmt.Sprint(kv[i])}
+		if i+1 < len(kv) {
+			f.Value = kv[i+1]
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func (r *Recorder) Log(level golog.Level, msg string, fields ...golog.Field) {
+	r.record(nil, level, msg, fields)
+}
+
+// Enabled reports true for every level so nothing escapes the recording.
+func (r *Recorder) Enabled(golog.Level) bool { return true }
+
+func (r *Recorder) Trace(msg string, fields ...golog.Field) {
+	r.record(nil, golog.TraceLevel, msg, fields)
+}
+
+func (r *Recorder) Debug(msg string, fields ...golog.Field) {
+	r.record(nil, golog.DebugLevel, msg, fields)
+}
+
+func (r *Recorder) Info(msg string, fields ...golog.Field) {
+	r.record(nil, golog.InfoLevel, msg, fields)
+}
+
+func (r *Recorder) Warn(msg string, fields ...golog.Field) {
+	r.record(nil, golog.WarnLevel, msg, fields)
+}
+
+func (r *Recorder) Error(msg string, fields ...golog.Field) {
+	r.record(nil, golog.ErrorLevel, msg, fields)
+}
+
+func (r *Recorder) DPanic(msg string, fields ...golog.Field) {
+	r.record(nil, golog.ErrorLevel, msg, fields)
+}
+
+func (r *Recorder) Panic(msg string, fields ...golog.Field) {
+	r.record(nil, golog.ErrorLevel, msg, fields)
+	panic(msg)
+}
+
+func (r *Recorder) Fatal(msg string, fields ...golog.Field) {
+	r.record(nil, golog.FatalLevel, msg, fields)
+}
+
+func (r *Recorder) TraceCtx(ctx context.Context, msg string, fields ...golog.Field) {
+	r.record(ctx, golog.TraceLevel, msg, fields)
+}
+
+func (r *Recorder) DebugCtx(ctx context.Context, msg string, fields ...golog.Field) {
+	r.record(ctx, golog.DebugLevel, msg, fields)
+}
+
+func (r *Recorder) InfoCtx(ctx context.Context, msg string, fields ...golog.Field) {
+	r.record(ctx, golog.InfoLevel, msg, fields)
+}
+
+func (r *Recorder) WarnCtx(ctx context.Context, msg string, fields ...golog.Field) {
+	r.record(ctx, golog.WarnLevel, msg, fields)
+}
+
+func (r *Recorder) ErrorCtx(ctx context.Context, msg string, fields ...golog.Field) {
+	r.record(ctx, golog.ErrorLevel, msg, fields)
+}
+
+func (r *Recorder) FatalCtx(ctx context.Context, msg string, fields ...golog.Field) {
+	r.record(ctx, golog.FatalLevel, msg, fields)
+}
+
+func (r *Recorder) Tracef(format string, args ...interface{}) {
+	r.record(nil, golog.TraceLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (r *Recorder) Debugf(format string, args ...interface{}) {
+	r.record(nil, golog.DebugLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (r *Recorder) Infof(format string, args ...interface{}) {
+	r.record(nil, golog.InfoLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (r *Recorder) Warnf(format string, args ...interface{}) {
+	r.record(nil, golog.WarnLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (r *Recorder) Errorf(format string, args ...interface{}) {
+	r.record(nil, golog.ErrorLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (r *Recorder) DPanicf(format string, args ...interface{}) {
+	r.record(nil, golog.ErrorLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (r *Recorder) Panicf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	r.record(nil, golog.ErrorLevel, msg, nil)
+	panic(msg)
+}
+
+func (r *Recorder) Fatalf(format string, args ...interface{}) {
+	r.record(nil, golog.FatalLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (r *Recorder) Tracew(msg string, keysAndValues ...interface{}) {
+	r.record(nil, golog.TraceLevel, msg, keysAndValuesToFields(keysAndValues))
+}
+
+func (r *Recorder) Debugw(msg string, keysAndValues ...interface{}) {
+	r.record(nil, golog.DebugLevel, msg, keysAndValuesToFields(keysAndValues))
+}
+
+func (r *Recorder) Infow(msg string, keysAndValues ...interface{}) {
+	r.record(nil, golog.InfoLevel, msg, keysAndValuesToFields(keysAndValues))
+}
+
+func (r *Recorder) Warnw(msg string, keysAndValues ...interface{}) {
+	r.record(nil, golog.WarnLevel, msg, keysAndValuesToFields(keysAndValues))
+}
+
+func (r *Recorder) Errorw(msg string, keysAndValues ...interface{}) {
+	r.record(nil, golog.ErrorLevel, msg, keysAndValuesToFields(keysAndValues))
+}
+
+func (r *Recorder) DPanicw(msg string, keysAndValues ...interface{}) {
+	r.record(nil, golog.ErrorLevel, msg, keysAndValuesToFields(keysAndValues))
+}
+
+func (r *Recorder) Panicw(msg string, keysAndValues ...interface{}) {
+	r.record(nil, golog.ErrorLevel, msg, keysAndValuesToFields(keysAndValues))
+	panic(msg)
+}
+
+func (r *Recorder) Fatalw(msg string, keysAndValues ...interface{}) {
+	r.record(nil, golog.FatalLevel, msg, keysAndValuesToFields(keysAndValues))
+}
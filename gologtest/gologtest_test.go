@@ -0,0 +1,66 @@
+package gologtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evdnx/golog"
+)
+
+// charge stands in for application code that depends on golog.Log.
+func charge(log golog.Log, ctx context.Context, amount int) {
+	log.InfoCtx(ctx, "charging", golog.Int("amount", amount))
+	if amount > 100 {
+		log.Warnw("large charge", "amount", amount, "review")
+	}
+	log.Errorf("declined %d", amount)
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+	ctx := context.Background()
+	charge(rec, ctx, 250)
+
+	entries := rec.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", entries)
+	}
+	if e := entries[0]; e.Level != golog.InfoLevel || e.Message != "charging" || e.Context != ctx {
+		t.Errorf("unexpected first entry: %+v", e)
+	}
+	if v, ok := entries[0].Field("amount"); !ok || v != 250 {
+		t.Errorf("amount field = %v, %v", v, ok)
+	}
+	warn := rec.FilterLevel(golog.WarnLevel)
+	if len(warn) != 1 || len(warn[0].Fields) != 2 || warn[0].Fields[1].Key != "review" || warn[0].Fields[1].Value != nil {
+		t.Errorf("unexpected warn entries: %+v", warn)
+	}
+	if got := rec.FilterMessage("declined 250"); len(got) != 1 || got[0].Level != golog.ErrorLevel {
+		t.Errorf("unexpected formatted entries: %+v", got)
+	}
+
+	rec.Reset()
+	if len(rec.Entries()) != 0 {
+		t.Error("Reset kept entries")
+	}
+}
+
+func TestNop(t *testing.T) {
+	charge(Nop{}, context.Background(), 250)
+}
+
+func TestRecorderPanic(t *testing.T) {
+	rec := NewRecorder()
+	rec.DPanicw("unexpected state", "k", "v")
+	func() {
+		defer func() {
+			if r := recover(); r != "broken 1" {
+				t.Errorf("Panicf recovered %v", r)
+			}
+		}()
+		rec.Panicf("broken %d", 1)
+	}()
+	if got := rec.FilterLevel(golog.ErrorLevel); len(got) != 2 || got[1].Message != "broken 1" {
+		t.Errorf("unexpected entries: %+v", got)
+	}
+}
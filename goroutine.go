@@ -0,0 +1,47 @@
+package golog
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                      Goroutine Launcher with Panic Logging                  */
+/* -------------------------------------------------------------------------- */
+
+// Go runs fn in a new goroutine. A panic in fn is recovered and logged at
+// Error level with the panic value and stack trace instead of crashing the
+// process; see GoCtx.
+func (l *Logger) Go(fn func()) {
+	l.GoCtx(context.Background(), func(context.Context) { fn() })
+}
+
+// GoCtx runs fn(ctx) in a new goroutine. A panic in fn is recovered and
+// logged at Error level with the panic value under "panic", the error (if
+// the value is one), the stack trace and the fields FieldsFromContext finds
+// in ctx; ctx is also passed to the configured enrichers. Error-tracking
+// providers receive the entry like any other Error entry.
+func (l *Logger) GoCtx(ctx context.Context, fn func(context.Context)) {
+	go func() {
+		defer l.recoverPanic(ctx)
+		fn(ctx)
+	}()
+}
+
+// recoverPanic must be deferred directly so that recover takes effect.
+func (l *Logger) recoverPanic(ctx context.Context) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	fields := []Field{String("panic", fmt.Sprint(r))}
+	if err, ok := r.(error); ok {
+		fields = append(fields, Err(err))
+	}
+	fields = append(fields, FieldsFromContext(ctx)...)
+	l.zapLogger.WithOptions(zap.AddStacktrace(zapcore.ErrorLevel)).
+		Error("recovered panic in goroutine", l.contextFields(ctx, fields)...)
+}
@@ -0,0 +1,46 @@
+package golog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestGoRecoversPanics(t *testing.T) {
+	logger, err := NewLogger(WithWriterProvider(io.Discard, JSONEncoder), WithRingBuffer(10))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	ch, err := logger.Tail(context.Background(), TailOptions{Ring: true})
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+
+	logger.Go(func() { panic("boom") })
+	ctx := WithRequestID(context.Background(), "req-7")
+	logger.GoCtx(ctx, func(context.Context) { panic(errors.New("bad state")) })
+
+	got := map[string]Entry{}
+	timeout := time.After(5 * time.Second)
+	for len(got) < 2 {
+		select {
+		case e := <-ch:
+			got[e.Fields["panic"].(string)] = e
+		case <-timeout:
+			t.Fatalf("timed out, got %v", got)
+		}
+	}
+
+	boom := got["boom"]
+	if boom.Level != ErrorLevel || boom.Message != "recovered panic in goroutine" || boom.Stack == "" {
+		t.Errorf("unexpected entry: %+v", boom)
+	}
+	bad := got["bad state"]
+	if bad.Fields["error"] != "bad state" || bad.Fields["request_id"] != "req-7" {
+		t.Errorf("missing error or context fields: %+v", bad.Fields)
+	}
+}
@@ -0,0 +1,46 @@
+package golog
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          Goroutine ID per Entry                             */
+/* -------------------------------------------------------------------------- */
+
+// GoroutineIDKey is the field WithGoroutineID writes.
+const GoroutineIDKey = "goroutine"
+
+// WithGoroutineID tags every entry with the ID of the goroutine that logged
+// it, which makes interleaved output from concurrent code easy to untangle.
+// Goroutine IDs are reused and carry no meaning beyond the process; for
+// stable identifiers attach a worker ID to the context with WithWorkerID
+// instead. Reading the ID costs a short stack capture per entry.
+func WithGoroutineID() LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.goroutineID = true
+	}
+}
+
+func newGoroutineIDCore(cores []zapcore.Core) *stampCore {
+	return &stampCore{cores: cores, key: GoroutineIDKey, stamp: func() zapcore.Field {
+		return zap.Uint64(GoroutineIDKey, goroutineID())
+	}}
+}
+
+// goroutineID parses the current goroutine's ID from the header of its stack
+// trace ("goroutine 42 [running]:").
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
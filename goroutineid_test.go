@@ -0,0 +1,57 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWithGoroutineID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithGoroutineID())
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("main")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("worker")
+	}()
+	<-done
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", buf.String())
+	}
+	ids := make([]float64, len(lines))
+	for i, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		id, ok := entry[GoroutineIDKey].(float64)
+		if !ok || id <= 0 {
+			t.Fatalf("missing goroutine ID in %s", line)
+		}
+		ids[i] = id
+	}
+	if ids[0] == ids[1] {
+		t.Errorf("expected distinct goroutine IDs, got %v", ids)
+	}
+	if got := goroutineID(); uint64(ids[0]) != got {
+		t.Errorf("main entry has goroutine %v, want %d", ids[0], got)
+	}
+}
+
+func TestWorkerIDFromContext(t *testing.T) {
+	ctx := WithWorkerID(context.Background(), "worker-3")
+	fields := FieldsFromContext(ctx)
+	if len(fields) != 1 || fields[0].Key != string(WorkerIDKey) || fields[0].Value != "worker-3" {
+		t.Errorf("unexpected fields: %+v", fields)
+	}
+}
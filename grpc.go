@@ -0,0 +1,437 @@
+package golog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                           gRPC Streaming Provider                           */
+/* -------------------------------------------------------------------------- */
+
+// grpcMaxBackoff caps the wait between attempts to resend a batch.
+const grpcMaxBackoff = 5 * time.Second
+
+// GRPCConfig configures WithGRPCProvider. Zero values fall back to the
+// defaults noted on each field.
+type GRPCConfig struct {
+	// Method is the full name of a client-streaming method taking
+	// golog.v1.EntryBatch messages (default "/golog.v1.LogSink/Push"; see
+	// entry.proto).
+	Method string
+	// TLS secures the connection; without it the connection is plaintext.
+	TLS *TLSConfig
+	// Metadata is sent with the stream, e.g. an authorization token.
+	Metadata map[string]string
+	// QueueSize is the number of entries waiting to be sent (default 1000).
+	// When it is full, entries are dropped and counted as DropQueueFull, or
+	// with Block the logging call waits.
+	QueueSize int
+	Block     bool
+	// BatchSize is the number of entries per message (default 100);
+	// FlushInterval bounds how long an entry waits for its batch (default
+	// 1s).
+	BatchSize     int
+	FlushInterval time.Duration
+	// Timeout bounds Sync and Close (default 10s).
+	Timeout time.Duration
+	// DialOptions are passed to grpc.NewClient after golog's own.
+	DialOptions []grpc.DialOption
+}
+
+func (c GRPCConfig) withDefaults() GRPCConfig {
+	if c.Method == "" {
+		c.Method = "/golog.v1.LogSink/Push"
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = 1000
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	return c
+}
+
+// WithGRPCProvider streams entries to a gRPC service at target, e.g.
+// "collector.internal:4317" or "dns:///collector:4317":
+//
+//	golog.WithGRPCProvider("collector.internal:4317", golog.GRPCConfig{
+//		TLS:      &golog.TLSConfig{},
+//		Metadata: map[string]string{"authorization": "Bearer " + token},
+//	})
+//
+// Entries are encoded as in entry.proto, the schema of ProtobufEncoder, and
+// sent in EntryBatch messages on one long-lived stream, reopened after
+// errors. gRPC flow control holds batches back while the service is slow,
+// and the bounded queue in front of it drops entries, or blocks with
+// Block, when it fills. Batches failing with Unavailable, ResourceExhausted,
+// Aborted or DeadlineExceeded are resent with backoff; others are dropped
+// and counted as DropProviderError, as are batches in flight when a stream
+// breaks. Close ends the stream and waits for the service's response.
+func WithGRPCProvider(target string, cfg GRPCConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&grpcProvider{
+			providerBase: providerBase{name: "grpc:" + target},
+			target:       target,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type grpcProvider struct {
+	providerBase
+	target string
+	cfg    GRPCConfig
+
+	conn   *grpc.ClientConn
+	sender *grpcSender
+}
+
+func (p *grpcProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	cfg := p.cfg.withDefaults()
+	creds := insecure.NewCredentials()
+	tlsCfg, err := cfg.TLS.build()
+	if err != nil {
+		return nil, fmt.Errorf("grpc provider: %w", err)
+	}
+	if tlsCfg != nil {
+		creds = credentials.NewTLS(tlsCfg)
+	}
+	dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, cfg.DialOptions...)
+	conn, err := grpc.NewClient(p.target, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc provider: %w", err)
+	}
+	p.conn = conn
+	p.sender = newGRPCSender(conn, cfg, p.dropped, p.report)
+	return &grpcCore{LevelEnabler: level, tree: newTreeEncoder(), sender: p.sender}, nil
+}
+
+func (p *grpcProvider) close() error {
+	if p.sender == nil {
+		return nil
+	}
+	err := p.sender.close()
+	if cerr := p.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (p *grpcProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: ProtobufEncoder}
+}
+
+// grpcCore encodes entries as golog.v1.Entry messages for the sender.
+type grpcCore struct {
+	zapcore.LevelEnabler
+	tree   *treeEncoder
+	sender *grpcSender
+}
+
+func (c *grpcCore) With(fields []zapcore.Field) zapcore.Core {
+	tree := c.tree.cloneTree()
+	for _, f := range fields {
+		f.AddTo(tree)
+	}
+	return &grpcCore{LevelEnabler: c.LevelEnabler, tree: tree, sender: c.sender}
+}
+
+func (c *grpcCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *grpcCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.sender.enqueue(appendProtoEntry(nil, ent, c.tree.fieldsWith(fields)))
+	return nil
+}
+
+func (c *grpcCore) Sync() error { return c.sender.sync() }
+
+/* -------------------------------------------------------------------------- */
+/*                                 Stream Sender                              */
+/* -------------------------------------------------------------------------- */
+
+// rawCodec passes messages encoded by golog through gRPC unchanged. Its
+// name makes the content type application/grpc+proto, so services decode
+// them with generated code.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("rawCodec: cannot marshal %T", v)
+	}
+	return b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("rawCodec: cannot unmarshal into %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string { return "proto" }
+
+// grpcSender batches queued entries and sends them on the stream from a
+// single goroutine.
+type grpcSender struct {
+	conn   *grpc.ClientConn
+	cfg    GRPCConfig
+	drops  func(reason DropReason, n int)
+	report func(err error)
+
+	queue chan []byte
+	syncs chan chan struct{}
+
+	// ctx ends the stream; it is cancelled when Close times out.
+	ctx    context.Context
+	cancel context.CancelFunc
+	stream grpc.ClientStream
+	// inflight counts the entries sent on stream, lost if it fails.
+	inflight int
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newGRPCSender(conn *grpc.ClientConn, cfg GRPCConfig, drops func(DropReason, int), report func(error)) *grpcSender {
+	ctx, cancel := context.WithCancel(context.Background())
+	if len(cfg.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(cfg.Metadata))
+	}
+	s := &grpcSender{
+		conn: conn, cfg: cfg, drops: drops, report: report,
+		queue: make(chan []byte, cfg.QueueSize), syncs: make(chan chan struct{}),
+		ctx: ctx, cancel: cancel, done: make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+func (s *grpcSender) enqueue(entry []byte) {
+	if s.cfg.Block {
+		select {
+		case s.queue <- entry:
+		case <-s.done:
+			s.drops(DropProviderError, 1)
+		}
+		return
+	}
+	select {
+	case s.queue <- entry:
+	default:
+		s.drops(DropQueueFull, 1)
+	}
+}
+
+// sync waits until the entries queued so far have been sent, or the
+// timeout passes.
+func (s *grpcSender) sync() error {
+	ack := make(chan struct{})
+	timer := time.NewTimer(s.cfg.Timeout)
+	defer timer.Stop()
+	select {
+	case s.syncs <- ack:
+	case <-s.done:
+		return nil
+	case <-timer.C:
+		return errors.New("grpc: sync timed out")
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-timer.C:
+		return errors.New("grpc: sync timed out")
+	}
+}
+
+func (s *grpcSender) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+	var batch [][]byte
+	add := func(entry []byte) {
+		batch = append(batch, entry)
+		if len(batch) >= s.cfg.BatchSize {
+			s.flush(batch)
+			batch = nil
+		}
+	}
+	// take adds the entries queued so far.
+	take := func() {
+		for {
+			select {
+			case entry := <-s.queue:
+				add(entry)
+			default:
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case entry := <-s.queue:
+			add(entry)
+		case <-ticker.C:
+			s.flush(batch)
+			batch = nil
+		case ack := <-s.syncs:
+			take()
+			s.flush(batch)
+			batch = nil
+			close(ack)
+		case <-s.done:
+			take()
+			s.flush(batch)
+			s.closeStream()
+			return
+		}
+	}
+}
+
+// flush sends batch as one EntryBatch, resending it with backoff while the
+// error is transient and the logger is open.
+func (s *grpcSender) flush(batch [][]byte) {
+	if len(batch) == 0 {
+		return
+	}
+	var msg []byte
+	for _, entry := range batch {
+		msg = appendProtoMessage(msg, 1, entry)
+	}
+	backoff := 100 * time.Millisecond
+	for {
+		err := s.send(msg, len(batch))
+		if err == nil {
+			return
+		}
+		if !retryableGRPCStatus(err) || s.closing() {
+			s.drops(DropProviderError, len(batch))
+			s.report(err)
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-s.done:
+		}
+		backoff = min(backoff*2, grpcMaxBackoff)
+	}
+}
+
+func (s *grpcSender) closing() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// send writes msg, holding n entries, on the stream, opening one if needed.
+// A broken stream is discarded so that the next send opens a new one.
+func (s *grpcSender) send(msg []byte, n int) error {
+	if s.stream == nil {
+		stream, err := s.conn.NewStream(s.ctx, &grpc.StreamDesc{ClientStreams: true}, s.cfg.Method, grpc.ForceCodec(rawCodec{}))
+		if err != nil {
+			return fmt.Errorf("grpc: %w", err)
+		}
+		s.stream = stream
+	}
+	err := s.stream.SendMsg(msg)
+	if err == nil {
+		s.inflight += n
+		return nil
+	}
+	if err == io.EOF {
+		// The stream ended; its status comes with the response.
+		var resp []byte
+		if err = s.stream.RecvMsg(&resp); err == nil {
+			err = errors.New("stream closed by the service")
+		}
+	}
+	s.streamFailed()
+	return fmt.Errorf("grpc: %w", err)
+}
+
+// streamFailed discards the stream, counting the entries sent on it as
+// dropped since the service may not have received them.
+func (s *grpcSender) streamFailed() {
+	s.drops(DropProviderError, s.inflight)
+	s.inflight = 0
+	s.stream = nil
+}
+
+// closeStream ends the stream and waits for the service's response.
+func (s *grpcSender) closeStream() {
+	if s.stream == nil {
+		return
+	}
+	var resp []byte
+	err := s.stream.CloseSend()
+	if err == nil {
+		err = s.stream.RecvMsg(&resp)
+	}
+	if err != nil {
+		s.report(fmt.Errorf("grpc: closing stream: %w", err))
+		s.streamFailed()
+		return
+	}
+	s.inflight = 0
+	s.stream = nil
+}
+
+// close sends the queued entries and ends the stream, giving up after the
+// timeout.
+func (s *grpcSender) close() error {
+	close(s.done)
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		s.cancel()
+		return nil
+	case <-time.After(s.cfg.Timeout):
+		s.cancel()
+		<-finished
+		return errors.New("grpc: close timed out")
+	}
+}
+
+// retryableGRPCStatus reports whether a send failing with err may succeed
+// later.
+func retryableGRPCStatus(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
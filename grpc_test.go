@@ -0,0 +1,155 @@
+package golog
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// fakeLogSink implements golog.v1.LogSink/Push without generated code.
+type fakeLogSink struct {
+	mu       sync.Mutex
+	batches  [][][]byte
+	metadata metadata.MD
+	closed   chan struct{}
+}
+
+func (f *fakeLogSink) push(_ interface{}, stream grpc.ServerStream) error {
+	f.mu.Lock()
+	f.metadata, _ = metadata.FromIncomingContext(stream.Context())
+	f.mu.Unlock()
+	for {
+		var msg []byte
+		if err := stream.RecvMsg(&msg); err != nil {
+			close(f.closed)
+			return stream.SendMsg([]byte{})
+		}
+		var entries [][]byte
+		for len(msg) > 0 {
+			_, _, n := protowire.ConsumeTag(msg)
+			entry, m := protowire.ConsumeBytes(msg[n:])
+			entries = append(entries, entry)
+			msg = msg[n+m:]
+		}
+		f.mu.Lock()
+		f.batches = append(f.batches, entries)
+		f.mu.Unlock()
+	}
+}
+
+func startFakeLogSink(t *testing.T) (*fakeLogSink, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &fakeLogSink{closed: make(chan struct{})}
+	srv := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}))
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "golog.v1.LogSink",
+		HandlerType: (*interface{})(nil),
+		Streams:     []grpc.StreamDesc{{StreamName: "Push", Handler: sink.push, ClientStreams: true}},
+	}, struct{}{})
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+	return sink, ln.Addr().String()
+}
+
+// entryMessageOf returns the message field of an encoded Entry.
+func entryMessageOf(entry []byte) string {
+	for len(entry) > 0 {
+		num, typ, n := protowire.ConsumeTag(entry)
+		entry = entry[n:]
+		if num == protoEntryMessage {
+			s, _ := protowire.ConsumeString(entry)
+			return s
+		}
+		entry = entry[protowire.ConsumeFieldValue(num, typ, entry):]
+	}
+	return ""
+}
+
+func TestGRPCProvider_Stream(t *testing.T) {
+	sink, addr := startFakeLogSink(t)
+	logger, err := NewLogger(WithGRPCProvider(addr, GRPCConfig{
+		Metadata:      map[string]string{"authorization": "Bearer t"},
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("one", String("user", "ann"))
+	logger.Info("two")
+	logger.Warn("three")
+	if err := logger.Sync(); err != nil {
+		t.Errorf("sync: %v", err)
+	}
+	logger.Close()
+
+	select {
+	case <-sink.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not closed")
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.batches) != 2 || len(sink.batches[0]) != 2 || len(sink.batches[1]) != 1 {
+		t.Fatalf("unexpected batches %v", sink.batches)
+	}
+	if got := entryMessageOf(sink.batches[1][0]); got != "three" {
+		t.Errorf("message = %q", got)
+	}
+	if got := sink.metadata.Get("authorization"); len(got) != 1 || got[0] != "Bearer t" {
+		t.Errorf("metadata = %v", sink.metadata)
+	}
+}
+
+func TestGRPCProvider_PermanentErrorDrops(t *testing.T) {
+	_, addr := startFakeLogSink(t)
+	logger, err := NewLogger(WithGRPCProvider(addr, GRPCConfig{Method: "/golog.v1.LogSink/Missing", BatchSize: 1}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("lost")
+	logger.Close()
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+}
+
+func TestGRPCProvider_QueueFull(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	logger, err := NewLogger(WithGRPCProvider(addr, GRPCConfig{QueueSize: 1, BatchSize: 1, Timeout: time.Second}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		logger.Info("queued")
+	}
+	if n := logger.DroppedEntries()[DropQueueFull]; n == 0 {
+		t.Error("expected queue full drops while the service is unreachable")
+	}
+	logger.Close()
+}
+
+func TestGRPCProvider_Errors(t *testing.T) {
+	if _, err := NewLogger(WithGRPCProvider("localhost:1", GRPCConfig{}, WithProviderEncoder(JSONEncoder))); err == nil {
+		t.Error("expected an error for WithProviderEncoder")
+	}
+	if _, err := NewLogger(WithGRPCProvider("localhost:1", GRPCConfig{TLS: &TLSConfig{CAPEM: []byte("x")}})); err == nil || !strings.Contains(err.Error(), "grpc provider") {
+		t.Errorf("expected TLS error, got %v", err)
+	}
+}
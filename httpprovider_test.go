@@ -0,0 +1,131 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ingestServer records the bodies and headers of every request it receives.
+type ingestServer struct {
+	mu      sync.Mutex
+	bodies  []string
+	headers []http.Header
+	hosts   []string
+	status  int
+}
+
+func (s *ingestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	buf.ReadFrom(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bodies = append(s.bodies, buf.String())
+	s.headers = append(s.headers, r.Header.Clone())
+	s.hosts = append(s.hosts, r.Host)
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+	}
+}
+
+func (s *ingestServer) snapshot() ([]string, []http.Header, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...), append([]http.Header(nil), s.headers...), append([]string(nil), s.hosts...)
+}
+
+func TestHTTPProvider_BatchesAndTokens(t *testing.T) {
+	ingest := &ingestServer{}
+	srv := httptest.NewServer(ingest)
+	defer srv.Close()
+
+	var tokens int
+	logger, err := NewLogger(WithHTTPProvider(srv.URL+"/ingest", HTTPConfig{
+		Headers:       map[string]string{"X-Source": "golog"},
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		TokenProvider: func(context.Context) (string, error) {
+			tokens++
+			return "token-" + strconv.Itoa(tokens), nil
+		},
+	}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	bodies, headers, _ := ingest.snapshot()
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 batches, got %d: %q", len(bodies), bodies)
+	}
+	lines := 0
+	for _, b := range bodies {
+		sc := bufio.NewScanner(strings.NewReader(b))
+		for sc.Scan() {
+			lines++
+		}
+	}
+	if lines != 3 {
+		t.Errorf("expected 3 entries, got %d", lines)
+	}
+	if got := headers[0].Get("Authorization"); got != "Bearer token-1" {
+		t.Errorf("unexpected first token: %q", got)
+	}
+	if got := headers[1].Get("Authorization"); got != "Bearer token-2" {
+		t.Errorf("token not refreshed per batch: %q", got)
+	}
+	if got := headers[0].Get("X-Source"); got != "golog" {
+		t.Errorf("missing static header: %q", got)
+	}
+	if got := headers[0].Get("Content-Type"); got != "application/x-ndjson" {
+		t.Errorf("unexpected content type: %q", got)
+	}
+}
+
+func TestHTTPProvider_Proxy(t *testing.T) {
+	proxy := &ingestServer{}
+	srv := httptest.NewServer(proxy)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithHTTPProvider("http://logs.invalid/ingest", HTTPConfig{ProxyURL: srv.URL}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("via proxy")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	bodies, _, hosts := proxy.snapshot()
+	if len(bodies) != 1 || !strings.Contains(bodies[0], "via proxy") || hosts[0] != "logs.invalid" {
+		t.Errorf("request did not go through proxy: %q %q", bodies, hosts)
+	}
+}
+
+func TestHTTPProvider_ClientErrorIsPermanent(t *testing.T) {
+	srv := httptest.NewServer(&ingestServer{status: http.StatusBadRequest})
+	defer srv.Close()
+
+	sender, err := newHTTPSender(HTTPConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sender.post(srv.URL, "application/x-ndjson", []byte("{}\n"))
+	var pe *permanentError
+	if !errors.As(err, &pe) {
+		t.Errorf("expected permanent error, got %v", err)
+	}
+}
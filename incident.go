@@ -0,0 +1,378 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                     Incident Provider (PagerDuty / Opsgenie)                */
+/* -------------------------------------------------------------------------- */
+
+// incidentAttempts bounds the posts of one incident, waiting incidentBackoff
+// and then twice as long in between; Fatal entries are sent before the
+// process exits, so retries stay short.
+const incidentAttempts = 3
+
+var incidentBackoff = 500 * time.Millisecond
+
+// IncidentConfig configures WithPagerDutyProvider and WithOpsgenieProvider.
+// The embedded HTTPConfig covers TLS, proxy, headers and timeout; its
+// batching settings are not used.
+type IncidentConfig struct {
+	HTTPConfig
+	// Endpoint overrides the API URL, e.g. https://api.eu.opsgenie.com/v2/alerts
+	// for Opsgenie's EU instance.
+	Endpoint string
+	// Errors triggers incidents for Error entries as well as Fatal (and
+	// Panic) ones.
+	Errors bool
+	// DedupField names the field whose value is the dedup key (PagerDuty) or
+	// alias (Opsgenie), default "fingerprint". Entries without it use a hash
+	// of the logger name, message and caller, so repeats of the same failure
+	// update one incident.
+	DedupField string
+	// Severities maps ErrorLevel and FatalLevel to the PagerDuty severity
+	// (default "error" and "critical") or Opsgenie priority (default "P3"
+	// and "P1").
+	Severities map[Level]string
+	// Source is PagerDuty's source and Opsgenie's source (default hostname).
+	Source string
+	// Group and Class fill PagerDuty's payload fields of the same names.
+	Group, Class string
+	// Tags and Responders are added to Opsgenie alerts; a responder is
+	// {"type": "team", "name": "ops"} or similar.
+	Tags       []string
+	Responders []map[string]string
+}
+
+type incidentService struct {
+	name       string
+	endpoint   string
+	severities map[Level]string
+	// payload builds the request body of an incident.
+	payload func(p *incidentProvider, inc incident) interface{}
+	// authorize sets the credentials of a request.
+	authorize func(req *http.Request, key string)
+}
+
+var (
+	pagerDuty = incidentService{
+		name:       "pagerduty",
+		endpoint:   "https://events.pagerduty.com/v2/enqueue",
+		severities: map[Level]string{ErrorLevel: "error", FatalLevel: "critical"},
+		payload:    pagerDutyPayload,
+		authorize:  func(*http.Request, string) {},
+	}
+	opsgenie = incidentService{
+		name:       "opsgenie",
+		endpoint:   "https://api.opsgenie.com/v2/alerts",
+		severities: map[Level]string{ErrorLevel: "P3", FatalLevel: "P1"},
+		payload:    opsgeniePayload,
+		authorize:  func(req *http.Request, key string) { req.Header.Set("Authorization", "GenieKey "+key) },
+	}
+)
+
+// WithPagerDutyProvider triggers PagerDuty incidents through the Events API
+// v2 with the integration's routing key. Fatal entries always trigger; set
+// IncidentConfig.Errors to include Error entries:
+//
+//	golog.WithPagerDutyProvider(os.Getenv("PD_ROUTING_KEY"), golog.IncidentConfig{Errors: true})
+//
+// The summary is the message, the component the logger name and the custom
+// details the fields, caller and stack. Incidents are posted before the
+// logging call returns; failures are counted as DropProviderError.
+func WithPagerDutyProvider(routingKey string, cfg IncidentConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&incidentProvider{
+			providerBase: providerBase{name: pagerDuty.name},
+			service:      pagerDuty,
+			key:          routingKey,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+// WithOpsgenieProvider creates Opsgenie alerts with an API integration key,
+// on the same terms as WithPagerDutyProvider. The alias deduplicates alerts,
+// the description carries the fields and stack, and the fields are also
+// sent as details.
+func WithOpsgenieProvider(apiKey string, cfg IncidentConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&incidentProvider{
+			providerBase: providerBase{name: opsgenie.name},
+			service:      opsgenie,
+			key:          apiKey,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type incidentProvider struct {
+	providerBase
+	service incidentService
+	key     string
+	cfg     IncidentConfig
+
+	sender *httpSender
+}
+
+func (p *incidentProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	name := p.name
+	if p.key == "" {
+		return nil, fmt.Errorf("%s provider: missing key", name)
+	}
+	if p.cfg.Endpoint == "" {
+		p.cfg.Endpoint = p.service.endpoint
+	}
+	if p.cfg.DedupField == "" {
+		p.cfg.DedupField = "fingerprint"
+	}
+	if p.cfg.Source == "" {
+		p.cfg.Source, _ = os.Hostname()
+	}
+	severities := map[Level]string{}
+	for lvl, s := range p.service.severities {
+		severities[lvl] = s
+	}
+	for lvl, s := range p.cfg.Severities {
+		severities[lvl] = s
+	}
+	p.cfg.Severities = severities
+	sender, err := newHTTPSender(p.cfg.HTTPConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%s provider: %w", name, err)
+	}
+	p.sender = sender
+
+	min := zapcore.PanicLevel
+	if p.cfg.Errors {
+		min = zapcore.ErrorLevel
+	}
+	if level > min {
+		min = level
+	}
+	return &incidentCore{LevelEnabler: min, tree: newTreeEncoder(), p: p}, nil
+}
+
+func (p *incidentProvider) close() error { return nil }
+
+func (p *incidentProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name}
+}
+
+// incident is an entry about to be posted.
+type incident struct {
+	ent      zapcore.Entry
+	level    Level
+	tree     *fieldTree
+	dedupKey string
+}
+
+type incidentCore struct {
+	zapcore.LevelEnabler
+	tree *treeEncoder
+	p    *incidentProvider
+}
+
+func (c *incidentCore) With(fields []zapcore.Field) zapcore.Core {
+	tree := c.tree.cloneTree()
+	for _, f := range fields {
+		f.AddTo(tree)
+	}
+	return &incidentCore{LevelEnabler: c.LevelEnabler, tree: tree, p: c.p}
+}
+
+func (c *incidentCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *incidentCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	inc := incident{ent: ent, level: ErrorLevel, tree: c.tree.fieldsWith(fields)}
+	if ent.Level >= zapcore.PanicLevel {
+		// Panic ends the process as Fatal does.
+		inc.level = FatalLevel
+	}
+	for i, key := range inc.tree.keys {
+		if key == c.p.cfg.DedupField {
+			inc.dedupKey = columnText(inc.tree.vals[i])
+		}
+	}
+	if inc.dedupKey == "" {
+		sum := sha256.Sum256([]byte(ent.LoggerName + "\x00" + ent.Message + "\x00" + ent.Caller.Function))
+		inc.dedupKey = hex.EncodeToString(sum[:16])
+	}
+	err := c.p.post(c.p.service.payload(c.p, inc))
+	if err != nil {
+		c.p.dropped(DropProviderError, 1)
+		return fmt.Errorf("%s: %w", c.p.name, err)
+	}
+	return nil
+}
+
+func (c *incidentCore) Sync() error { return nil }
+
+// post sends payload, retrying throttling, server errors and network
+// failures.
+func (p *incidentProvider) post(payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	backoff := incidentBackoff
+	for attempt := 1; ; attempt++ {
+		err = p.postOnce(body)
+		if err == nil || !isRetryable(err) || attempt == incidentAttempts {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (p *incidentProvider) postOnce(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.sender.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return PermanentError(err)
+	}
+	for k, v := range p.sender.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	p.service.authorize(req, p.key)
+	resp, err := p.sender.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	err = fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(snippet))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return err
+	}
+	return PermanentError(err)
+}
+
+// incidentDetails returns the fields plus caller and stack, nil if empty.
+func incidentDetails(inc incident) map[string]interface{} {
+	details := map[string]interface{}{}
+	if fields := treeJSON(inc.tree); fields != nil {
+		// Fields keep their JSON types, nested objects included.
+		var m map[string]interface{}
+		if err := json.Unmarshal(fields, &m); err == nil {
+			details = m
+		}
+	}
+	if inc.ent.Caller.Defined {
+		details["caller"] = inc.ent.Caller.TrimmedPath()
+	}
+	if inc.ent.Stack != "" {
+		details["stack"] = inc.ent.Stack
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	return details
+}
+
+/* -------------------------------------------------------------------------- */
+/*                                   Payloads                                 */
+/* -------------------------------------------------------------------------- */
+
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Client      string           `json:"client"`
+	Payload     pagerDutyDetails `json:"payload"`
+}
+
+type pagerDutyDetails struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp"`
+	Component     string                 `json:"component,omitempty"`
+	Group         string                 `json:"group,omitempty"`
+	Class         string                 `json:"class,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+func pagerDutyPayload(p *incidentProvider, inc incident) interface{} {
+	return pagerDutyEvent{
+		RoutingKey:  p.key,
+		EventAction: "trigger",
+		DedupKey:    truncateUTF8(inc.dedupKey, 255),
+		Client:      "golog",
+		Payload: pagerDutyDetails{
+			Summary:       truncateUTF8(inc.ent.Message, 1024),
+			Source:        p.cfg.Source,
+			Severity:      p.cfg.Severities[inc.level],
+			Timestamp:     inc.ent.Time.UTC().Format(time.RFC3339Nano),
+			Component:     inc.ent.LoggerName,
+			Group:         p.cfg.Group,
+			Class:         p.cfg.Class,
+			CustomDetails: incidentDetails(inc),
+		},
+	}
+}
+
+type opsgenieAlert struct {
+	Message     string              `json:"message"`
+	Alias       string              `json:"alias"`
+	Description string              `json:"description,omitempty"`
+	Responders  []map[string]string `json:"responders,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Details     map[string]string   `json:"details,omitempty"`
+	Entity      string              `json:"entity,omitempty"`
+	Source      string              `json:"source,omitempty"`
+	Priority    string              `json:"priority"`
+}
+
+func opsgeniePayload(p *incidentProvider, inc incident) interface{} {
+	alert := opsgenieAlert{
+		Message:    truncateUTF8(inc.ent.Message, 130),
+		Alias:      truncateUTF8(inc.dedupKey, 512),
+		Responders: p.cfg.Responders,
+		Tags:       p.cfg.Tags,
+		Entity:     inc.ent.LoggerName,
+		Source:     p.cfg.Source,
+		Priority:   p.cfg.Severities[inc.level],
+	}
+	// Opsgenie details are string-valued, so nested fields are flattened.
+	var desc strings.Builder
+	inc.tree.flatten("", func(key string, val interface{}) {
+		if alert.Details == nil {
+			alert.Details = map[string]string{}
+		}
+		alert.Details[key] = columnText(val)
+		fmt.Fprintf(&desc, "%s=%s\n", key, prettyValue(val))
+	})
+	if inc.ent.Caller.Defined {
+		fmt.Fprintf(&desc, "caller=%s\n", inc.ent.Caller.TrimmedPath())
+	}
+	if inc.ent.Stack != "" {
+		desc.WriteString("\n" + inc.ent.Stack)
+	}
+	alert.Description = truncateUTF8(strings.TrimSuffix(desc.String(), "\n"), 15000)
+	return alert
+}
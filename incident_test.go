@@ -0,0 +1,180 @@
+package golog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeIncidents records posted incidents, failing the first failures
+// requests with status.
+type fakeIncidents struct {
+	mu       sync.Mutex
+	bodies   []map[string]interface{}
+	auth     []string
+	failures int
+	status   int
+}
+
+func (f *fakeIncidents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	if f.failures > 0 {
+		f.failures--
+		w.WriteHeader(f.status)
+		return
+	}
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.bodies = append(f.bodies, body)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestPagerDutyProvider_Trigger(t *testing.T) {
+	fake := &fakeIncidents{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	at := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	logger, err := NewLogger(
+		WithPagerDutyProvider("routing-key", IncidentConfig{Endpoint: srv.URL, Errors: true, Source: "web-1", Group: "prod"}),
+		WithZapOptions(zap.WithClock(fixedClock{at})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Warn("ignored")
+	db := logger.Named("db")
+	db.Error("connection lost", String("fingerprint", "db-down"), Dict("pool", Int("open", 0)))
+	db.Error("replica lagging")
+	db.Error("replica lagging")
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.bodies) != 3 {
+		t.Fatalf("got %d incidents, want 3", len(fake.bodies))
+	}
+	event := fake.bodies[0]
+	if event["routing_key"] != "routing-key" || event["event_action"] != "trigger" || event["dedup_key"] != "db-down" {
+		t.Errorf("event = %v", event)
+	}
+	payload := event["payload"].(map[string]interface{})
+	for key, want := range map[string]interface{}{
+		"summary": "connection lost", "source": "web-1", "severity": "error",
+		"component": "db", "group": "prod", "timestamp": "2026-10-16T09:30:00Z",
+	} {
+		if payload[key] != want {
+			t.Errorf("%s = %v, want %v", key, payload[key], want)
+		}
+	}
+	details := payload["custom_details"].(map[string]interface{})
+	if details["pool"].(map[string]interface{})["open"] != 0.0 {
+		t.Errorf("custom_details = %v", details)
+	}
+	if key := fake.bodies[1]["dedup_key"].(string); len(key) != 32 || key != fake.bodies[2]["dedup_key"] {
+		t.Errorf("fingerprints %v and %v should match", key, fake.bodies[2]["dedup_key"])
+	}
+}
+
+func TestPagerDutyProvider_FatalOnlyByDefault(t *testing.T) {
+	fake := &fakeIncidents{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithPagerDutyProvider("key", IncidentConfig{Endpoint: srv.URL}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Error("not an incident")
+	logger.DPanic("not an incident either")
+	func() {
+		defer func() { recover() }()
+		logger.Panic("crashed")
+	}()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.bodies) != 1 {
+		t.Fatalf("got %d incidents, want 1", len(fake.bodies))
+	}
+	if got := fake.bodies[0]["payload"].(map[string]interface{})["severity"]; got != "critical" {
+		t.Errorf("severity = %v", got)
+	}
+}
+
+func TestOpsgenieProvider_Alert(t *testing.T) {
+	fake := &fakeIncidents{failures: 1, status: http.StatusServiceUnavailable}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	defer func(d time.Duration) { incidentBackoff = d }(incidentBackoff)
+	incidentBackoff = time.Millisecond
+
+	logger, err := NewLogger(WithOpsgenieProvider("api-key", IncidentConfig{
+		Endpoint:   srv.URL,
+		Errors:     true,
+		Severities: map[Level]string{ErrorLevel: "P2"},
+		Tags:       []string{"golog"},
+		Responders: []map[string]string{{"type": "team", "name": "ops"}},
+	}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Named("jobs").Error("export failed", Int("job", 7), Dict("user", String("id", "u1")))
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.bodies) != 1 || fake.auth[1] != "GenieKey api-key" {
+		t.Fatalf("bodies = %v, auth = %v", fake.bodies, fake.auth)
+	}
+	alert := fake.bodies[0]
+	if alert["message"] != "export failed" || alert["priority"] != "P2" || alert["entity"] != "jobs" {
+		t.Errorf("alert = %v", alert)
+	}
+	if details := alert["details"].(map[string]interface{}); details["job"] != "7" || details["user.id"] != "u1" {
+		t.Errorf("details = %v", details)
+	}
+	if desc := alert["description"].(string); !strings.HasPrefix(desc, "job=7\nuser.id=u1") {
+		t.Errorf("description = %q", desc)
+	}
+	if r := alert["responders"].([]interface{}); len(r) != 1 {
+		t.Errorf("responders = %v", r)
+	}
+}
+
+func TestIncidentProvider_PermanentFailure(t *testing.T) {
+	fake := &fakeIncidents{failures: 5, status: http.StatusBadRequest}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithPagerDutyProvider("key", IncidentConfig{Endpoint: srv.URL, Errors: true}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Error("rejected")
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.auth) != 1 {
+		t.Errorf("a 400 was retried: %d requests", len(fake.auth))
+	}
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+	if _, err := NewLogger(WithOpsgenieProvider("", IncidentConfig{})); err == nil || !strings.Contains(err.Error(), "opsgenie provider") {
+		t.Errorf("expected missing key error, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+// Package term detects whether ANSI colour output is appropriate for a file
+// and enables it where the platform needs that (Windows consoles).
+package term
+
+import (
+	"os"
+	"strings"
+)
+
+// IsTerminal reports whether f is a character device such as a TTY.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorEnabled decides whether to colour output written to f, following the
+// NO_COLOR (https://no-color.org) and FORCE_COLOR conventions:
+//
+//   - FORCE_COLOR set to anything but "0" or "false" enables colour;
+//   - otherwise a non-empty NO_COLOR, a non-terminal f or TERM=dumb
+//     disables it;
+//   - otherwise colour is enabled if the terminal supports ANSI sequences,
+//     switching on virtual terminal processing on Windows.
+func ColorEnabled(f *os.File, getenv func(string) string) bool {
+	if forced, ok := ForceColor(getenv); ok {
+		if forced {
+			EnableVirtualTerminal(f)
+		}
+		return forced
+	}
+	if getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" || !IsTerminal(f) {
+		return false
+	}
+	return EnableVirtualTerminal(f)
+}
+
+// ForceColor reports the FORCE_COLOR setting and whether it is set at all.
+func ForceColor(getenv func(string) string) (force, set bool) {
+	v := getenv("FORCE_COLOR")
+	if v == "" {
+		return false, false
+	}
+	switch strings.ToLower(v) {
+	case "0", "false":
+		return false, true
+	}
+	return true, true
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package term
+
+import "os"
+
+// EnableVirtualTerminal reports whether f can render ANSI escapes. Outside
+// Windows terminals always can, so it only returns true.
+func EnableVirtualTerminal(*os.File) bool { return true }
@@ -0,0 +1,34 @@
+package term
+
+import (
+	"os"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	cases := []struct {
+		name string
+		vars map[string]string
+		want bool
+	}{
+		{"file", nil, false},
+		{"force", map[string]string{"FORCE_COLOR": "1"}, true},
+		{"force wins over no_color", map[string]string{"FORCE_COLOR": "true", "NO_COLOR": "1"}, true},
+		{"force off", map[string]string{"FORCE_COLOR": "0"}, false},
+		{"no_color", map[string]string{"NO_COLOR": "1"}, false},
+	}
+	for _, c := range cases {
+		if got := ColorEnabled(f, env(c.vars)); got != c.want {
+			t.Errorf("%s: ColorEnabled = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
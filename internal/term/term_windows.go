@@ -0,0 +1,24 @@
+//go:build windows
+
+package term
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// EnableVirtualTerminal turns on ANSI escape processing for the console
+// behind f and reports whether it is active. It fails on consoles older
+// than Windows 10 and on handles that are not consoles.
+func EnableVirtualTerminal(f *os.File) bool {
+	h := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(h, &mode); err != nil {
+		return false
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(h, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
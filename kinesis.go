@@ -0,0 +1,608 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                      Amazon Kinesis & Firehose Provider                     */
+/* -------------------------------------------------------------------------- */
+
+// kinesisAggregationMagic starts a record in the KPL aggregation format.
+var kinesisAggregationMagic = []byte{0xf3, 0x89, 0x9a, 0xc2}
+
+// KinesisConfig configures WithKinesisProvider and WithFirehoseProvider.
+// Zero values fall back to the defaults noted on each field.
+type KinesisConfig struct {
+	// Region is the AWS region (default AWS_REGION, then
+	// AWS_DEFAULT_REGION).
+	Region string
+	// Credentials signs each request (default AWS_ACCESS_KEY_ID,
+	// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, read per request).
+	Credentials AWSCredentialsProvider
+	// Endpoint overrides https://kinesis.<region>.amazonaws.com (or
+	// firehose.<region>…), e.g. for a VPC endpoint or LocalStack.
+	Endpoint string
+	// PartitionKeyField names the field whose value is the partition key of
+	// a Kinesis record, so that entries with the same value stay in order on
+	// one shard. Entries without it get a random key. Firehose has no
+	// partition keys and ignores it.
+	PartitionKeyField string
+	// Aggregate packs many entries into each record. Kinesis records use
+	// the KPL aggregation format, which the KCL, Firehose and the
+	// kinesis-aggregation libraries take apart again; an aggregated record
+	// is routed by the partition key of its first entry. Firehose records
+	// are the entries, newline-delimited, up to the record size limit.
+	Aggregate bool
+	// BatchSize is the number of entries per call (default 500, at most 500
+	// without Aggregate). Batches are also sent before exceeding the
+	// request size limit.
+	BatchSize int
+	// FlushInterval sends a partial batch after this long (default 1s).
+	FlushInterval time.Duration
+	// MaxRetries is the number of times throttled or failed records are
+	// resent, with exponential backoff (default 3).
+	MaxRetries int
+	// Timeout bounds each batch, retries included (default 30s).
+	Timeout time.Duration
+	// TLS and ProxyURL configure the connection as for HTTPConfig.
+	TLS      *TLSConfig
+	ProxyURL string
+}
+
+// kinesisService describes the put API of Kinesis Data Streams or Firehose.
+type kinesisService struct {
+	name   string
+	target string
+	// recordBytes, batchBytes and batchRecords are the API's limits.
+	recordBytes, batchBytes, batchRecords int
+	// keyed services take a partition key per record.
+	keyed bool
+}
+
+var (
+	kinesisStreams = kinesisService{
+		name: "kinesis", target: "Kinesis_20131202.PutRecords",
+		recordBytes: 1 << 20, batchBytes: 5 << 20, batchRecords: 500, keyed: true,
+	}
+	kinesisFirehose = kinesisService{
+		name: "firehose", target: "Firehose_20150804.PutRecordBatch",
+		recordBytes: 1000 << 10, batchBytes: 4 << 20, batchRecords: 500,
+	}
+)
+
+func (c KinesisConfig) withDefaults(svc kinesisService) KinesisConfig {
+	if c.Region == "" {
+		c.Region = envAWSRegion()
+	}
+	if c.Credentials == nil {
+		c.Credentials = envAWSCredentials
+	}
+	if c.Endpoint == "" && c.Region != "" {
+		c.Endpoint = "https://" + svc.name + "." + c.Region + ".amazonaws.com"
+		if strings.HasPrefix(c.Region, "cn-") {
+			c.Endpoint += ".cn"
+		}
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = svc.batchRecords
+	}
+	if !c.Aggregate && c.BatchSize > svc.batchRecords {
+		c.BatchSize = svc.batchRecords
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = time.Second
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = 3
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 30 * time.Second
+	}
+	return c
+}
+
+// WithKinesisProvider puts entries, JSON-encoded unless WithProviderEncoder
+// says otherwise, to a Kinesis data stream with PutRecords:
+//
+//	golog.WithKinesisProvider("app-logs", golog.KinesisConfig{
+//		Region:            "eu-west-1",
+//		PartitionKeyField: "request_id",
+//	})
+//
+// Entries are batched by count, size and age. Records the stream rejects,
+// typically when a shard's throughput is exceeded, are resent with backoff
+// up to MaxRetries times, then dropped and counted as DropProviderError.
+// Entries larger than a record are counted as DropOversized.
+func WithKinesisProvider(stream string, cfg KinesisConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&kinesisProvider{
+			providerBase: providerBase{name: kinesisStreams.name + ":" + stream, encoders: anyEncoder},
+			svc:          kinesisStreams,
+			stream:       stream,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+// WithFirehoseProvider puts entries to a Firehose delivery stream with
+// PutRecordBatch, on the same terms as WithKinesisProvider. Each entry ends
+// with a newline, so the objects Firehose delivers are newline-delimited.
+func WithFirehoseProvider(deliveryStream string, cfg KinesisConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&kinesisProvider{
+			providerBase: providerBase{name: kinesisFirehose.name + ":" + deliveryStream, encoders: anyEncoder},
+			svc:          kinesisFirehose,
+			stream:       deliveryStream,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type kinesisProvider struct {
+	providerBase
+	svc    kinesisService
+	stream string
+	cfg    KinesisConfig
+
+	batch *batcher[kinesisEntry]
+}
+
+func (p *kinesisProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	if p.stream == "" {
+		return nil, fmt.Errorf("%s provider: stream name is required", p.svc.name)
+	}
+	cfg := p.cfg.withDefaults(p.svc)
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("%s provider: no region; set KinesisConfig.Region or AWS_REGION", p.svc.name)
+	}
+	if u, err := url.Parse(cfg.Endpoint); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%s provider: invalid endpoint %q", p.svc.name, cfg.Endpoint)
+	}
+	sender, err := newHTTPSender(HTTPConfig{TLS: cfg.TLS, ProxyURL: cfg.ProxyURL, Timeout: cfg.Timeout})
+	if err != nil {
+		return nil, fmt.Errorf("%s provider: %w", p.svc.name, err)
+	}
+	enc, err := p.tel.buildEncoder(p.encoder(), p.encoderConfig)
+	if err != nil {
+		return nil, err
+	}
+	client := &kinesisClient{svc: p.svc, stream: p.stream, cfg: cfg, http: sender.client}
+	p.batch = &batcher[kinesisEntry]{
+		limit:    cfg.BatchSize,
+		maxBytes: p.svc.batchBytes,
+		measure:  p.svc.measure,
+		send:     client.sendEntries,
+		drops:    p.dropped,
+		report:   p.report,
+	}
+	p.batch.start(cfg.FlushInterval)
+	return &kinesisCore{LevelEnabler: level, enc: enc, keyField: cfg.PartitionKeyField, batch: p.batch}, nil
+}
+
+func (p *kinesisProvider) close() error {
+	if p.batch == nil {
+		return nil
+	}
+	return p.batch.close()
+}
+
+func (p *kinesisProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: p.encoder()}
+}
+
+// kinesisCore encodes entries for the batch, tracking the partition key
+// field among the context fields.
+type kinesisCore struct {
+	zapcore.LevelEnabler
+	enc      zapcore.Encoder
+	keyField string
+	key      string
+	batch    *batcher[kinesisEntry]
+}
+
+func (c *kinesisCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.enc = c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	if key, ok := fieldValueText(c.keyField, fields); ok {
+		clone.key = key
+	}
+	return &clone
+}
+
+func (c *kinesisCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *kinesisCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	data := append([]byte(nil), buf.Bytes()...)
+	buf.Free()
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	key := c.key
+	if k, ok := fieldValueText(c.keyField, fields); ok {
+		key = k
+	}
+	if key == "" {
+		key = newEventID()
+	}
+	return c.batch.add(kinesisEntry{data: data, key: truncateUTF8(key, 256)})
+}
+
+func (c *kinesisCore) Sync() error { return c.batch.Sync() }
+
+// fieldValueText returns the value of the last field named name as text.
+func fieldValueText(name string, fields []zapcore.Field) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	key, found := "", false
+	for _, f := range fields {
+		if f.Key != name {
+			continue
+		}
+		tree := newTreeEncoder()
+		f.AddTo(tree)
+		if len(tree.root.vals) > 0 {
+			key, found = columnText(tree.root.vals[0]), true
+		}
+	}
+	return key, found && key != ""
+}
+
+/* -------------------------------------------------------------------------- */
+/*                               Record Batching                               */
+/* -------------------------------------------------------------------------- */
+
+type kinesisEntry struct {
+	data []byte
+	key  string
+}
+
+// kinesisRecord is one record of a put call, holding entries entries.
+type kinesisRecord struct {
+	data    []byte
+	key     string
+	entries int
+}
+
+// entrySize is an upper bound of the bytes e adds to a put call.
+func (svc kinesisService) entrySize(e kinesisEntry) int {
+	if svc.keyed {
+		// Aggregation adds a few bytes of framing per entry.
+		return len(e.data) + len(e.key) + 16
+	}
+	return len(e.data)
+}
+
+// measure sizes e for the batcher, rejecting entries larger than a record.
+func (svc kinesisService) measure(e kinesisEntry) (int, error) {
+	size := svc.entrySize(e)
+	if size > svc.recordBytes-64 {
+		return 0, fmt.Errorf("%s: entry of %d bytes exceeds the %d byte record limit", svc.name, len(e.data), svc.recordBytes)
+	}
+	return size, nil
+}
+
+// sendEntries puts a batch of entries, as one record each or aggregated,
+// in as many calls as the record limit requires. The number of entries lost
+// is returned in a batchDropError.
+func (c *kinesisClient) sendEntries(entries []kinesisEntry) error {
+	var records []kinesisRecord
+	if c.cfg.Aggregate {
+		records = c.svc.aggregateRecords(entries)
+	} else {
+		for _, e := range entries {
+			records = append(records, kinesisRecord{data: e.data, key: e.key, entries: 1})
+		}
+	}
+	var (
+		errs []error
+		lost int
+	)
+	for len(records) > 0 {
+		n := min(len(records), c.svc.batchRecords)
+		if err := c.send(records[:n]); err != nil {
+			var dropErr *batchDropError
+			if errors.As(err, &dropErr) {
+				lost += dropErr.n
+			} else {
+				lost += countKinesisEntries(records[:n])
+			}
+			errs = append(errs, err)
+		}
+		records = records[n:]
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &batchDropError{n: lost, err: errors.Join(errs...)}
+}
+
+// aggregateRecords packs entries into as few records as the record size
+// limit allows.
+func (svc kinesisService) aggregateRecords(entries []kinesisEntry) []kinesisRecord {
+	var records []kinesisRecord
+	var group []kinesisEntry
+	size := 0
+	emit := func() {
+		if len(group) == 0 {
+			return
+		}
+		rec := kinesisRecord{key: group[0].key, entries: len(group)}
+		if svc.keyed {
+			rec.data = kplAggregate(group)
+		} else {
+			for _, e := range group {
+				rec.data = append(rec.data, e.data...)
+			}
+		}
+		records = append(records, rec)
+		group, size = nil, 0
+	}
+	for _, e := range entries {
+		s := svc.entrySize(e)
+		if size+s > svc.recordBytes-64 {
+			emit()
+		}
+		group = append(group, e)
+		size += s
+	}
+	emit()
+	return records
+}
+
+// kplAggregate encodes entries as a KPL AggregatedRecord: the magic
+// number, the protobuf message and its MD5.
+func kplAggregate(entries []kinesisEntry) []byte {
+	var msg []byte
+	keys := map[string]uint64{}
+	for _, e := range entries {
+		if _, ok := keys[e.key]; !ok {
+			keys[e.key] = uint64(len(keys))
+			// partition_key_table
+			msg = appendProtoMessage(msg, 1, []byte(e.key))
+		}
+	}
+	for _, e := range entries {
+		var rec []byte
+		rec = protowire.AppendTag(rec, 1, protowire.VarintType) // partition_key_index
+		rec = protowire.AppendVarint(rec, keys[e.key])
+		rec = appendProtoMessage(rec, 3, e.data) // data
+		msg = appendProtoMessage(msg, 3, rec)    // records
+	}
+	sum := md5.Sum(msg)
+	out := append(append([]byte(nil), kinesisAggregationMagic...), msg...)
+	return append(out, sum[:]...)
+}
+
+/* -------------------------------------------------------------------------- */
+/*                                  API Client                                */
+/* -------------------------------------------------------------------------- */
+
+type kinesisClient struct {
+	svc    kinesisService
+	stream string
+	cfg    KinesisConfig
+	http   *http.Client
+}
+
+// kinesisError is an error response of the Kinesis or Firehose API.
+type kinesisError struct {
+	Service string
+	Status  int
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+func (e *kinesisError) Error() string {
+	return fmt.Sprintf("%s: %d %s: %s", e.Service, e.Status, e.Type, e.Message)
+}
+
+// retryableKinesisError reports whether a call that failed with the error
+// type typ and HTTP status, or a record that failed with the error code typ
+// (and no status), may succeed later.
+func retryableKinesisError(typ string, status int) bool {
+	switch typ {
+	case "ProvisionedThroughputExceededException", "ThrottlingException", "LimitExceededException",
+		"ServiceUnavailableException", "InternalFailure", "InternalFailureException", "KMSThrottlingException":
+		return true
+	}
+	return status >= 500
+}
+
+// send puts records, resending the ones that fail with a retryable error
+// until they succeed, MaxRetries is reached or the timeout passes. The
+// number of entries lost on the way is returned in a batchDropError.
+func (c *kinesisClient) send(records []kinesisRecord) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeout)
+	defer cancel()
+	backoff := 100 * time.Millisecond
+	pending, rejected := records, 0
+	var lastErr, rejectErr error
+	for attempt := 0; ; attempt++ {
+		failed, err := c.put(ctx, pending)
+		if err != nil {
+			var apiErr *kinesisError
+			if errors.As(err, &apiErr) && !retryableKinesisError(apiErr.Type, apiErr.Status) {
+				return &batchDropError{n: rejected + countKinesisEntries(pending), err: errors.Join(rejectErr, PermanentError(err))}
+			}
+			lastErr = err
+		} else {
+			pending = failed.retry
+			rejected += failed.rejected
+			if failed.retryReason != "" {
+				lastErr = fmt.Errorf("%s: records failed: %s", c.svc.name, failed.retryReason)
+			}
+			if failed.rejectReason != "" && rejectErr == nil {
+				rejectErr = PermanentError(fmt.Errorf("%s: records rejected: %s", c.svc.name, failed.rejectReason))
+			}
+			if len(pending) == 0 {
+				if rejected > 0 {
+					return &batchDropError{n: rejected, err: rejectErr}
+				}
+				return nil
+			}
+		}
+		if attempt >= c.cfg.MaxRetries {
+			return &batchDropError{n: rejected + countKinesisEntries(pending), err: errors.Join(rejectErr, lastErr)}
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return &batchDropError{n: rejected + countKinesisEntries(pending), err: errors.Join(rejectErr, lastErr)}
+		}
+		backoff = min(backoff*2, 5*time.Second)
+	}
+}
+
+func countKinesisEntries(records []kinesisRecord) int {
+	n := 0
+	for _, r := range records {
+		n += r.entries
+	}
+	return n
+}
+
+// kinesisFailures are the records of a call that were not accepted.
+type kinesisFailures struct {
+	// retry holds the records that failed with a retryable error code.
+	retry []kinesisRecord
+	// rejected counts the entries of the other failed records.
+	rejected int
+	// retryReason and rejectReason are the first error code and message of
+	// each kind.
+	retryReason, rejectReason string
+}
+
+// put makes one PutRecords or PutRecordBatch call and sorts out the records
+// that were not accepted.
+func (c *kinesisClient) put(ctx context.Context, records []kinesisRecord) (kinesisFailures, error) {
+	type record struct {
+		Data         []byte `json:"Data"`
+		PartitionKey string `json:"PartitionKey,omitempty"`
+	}
+	req := map[string]interface{}{}
+	list := make([]record, len(records))
+	for i, r := range records {
+		list[i] = record{Data: r.data}
+		if c.svc.keyed {
+			list[i].PartitionKey = r.key
+		}
+	}
+	if c.svc.keyed {
+		req["StreamName"] = c.stream
+	} else {
+		req["DeliveryStreamName"] = c.stream
+	}
+	req["Records"] = list
+
+	var resp struct {
+		// Kinesis answers with Records, Firehose with RequestResponses.
+		Records          []kinesisResult `json:"Records"`
+		RequestResponses []kinesisResult `json:"RequestResponses"`
+	}
+	if err := c.call(ctx, req, &resp); err != nil {
+		return kinesisFailures{}, err
+	}
+	results := resp.Records
+	if !c.svc.keyed {
+		results = resp.RequestResponses
+	}
+	var failed kinesisFailures
+	for i, res := range results {
+		if res.ErrorCode == "" || i >= len(records) {
+			continue
+		}
+		reason := res.ErrorCode + ": " + res.ErrorMessage
+		if retryableKinesisError(res.ErrorCode, 0) {
+			failed.retry = append(failed.retry, records[i])
+			if failed.retryReason == "" {
+				failed.retryReason = reason
+			}
+		} else {
+			failed.rejected += records[i].entries
+			if failed.rejectReason == "" {
+				failed.rejectReason = reason
+			}
+		}
+	}
+	return failed, nil
+}
+
+type kinesisResult struct {
+	ErrorCode    string `json:"ErrorCode"`
+	ErrorMessage string `json:"ErrorMessage"`
+}
+
+// call sends the JSON request req and decodes the response into resp.
+func (c *kinesisClient) call(ctx context.Context, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return PermanentError(fmt.Errorf("%s: %w", c.svc.name, err))
+	}
+	creds, err := c.cfg.Credentials(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: credentials: %w", c.svc.name, err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return PermanentError(fmt.Errorf("%s: %w", c.svc.name, err))
+	}
+	httpReq.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	httpReq.Header.Set("X-Amz-Target", c.svc.target)
+	signAWSRequest(httpReq, body, creds, c.cfg.Region, c.svc.name, time.Now())
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.svc.name, err)
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.svc.name, err)
+	}
+	if httpResp.StatusCode/100 != 2 {
+		apiErr := &kinesisError{Service: c.svc.name, Status: httpResp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Type == "" {
+			apiErr.Type = httpResp.Header.Get("X-Amzn-ErrorType")
+			apiErr.Message = string(bytes.TrimSpace(data))
+		}
+		// Types may carry a namespace, e.g. "com.amazonaws.kinesis#…".
+		if i := strings.LastIndexByte(apiErr.Type, '#'); i >= 0 {
+			apiErr.Type = apiErr.Type[i+1:]
+		}
+		if i := strings.IndexByte(apiErr.Type, ':'); i >= 0 {
+			apiErr.Type = apiErr.Type[:i]
+		}
+		return apiErr
+	}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", c.svc.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package golog
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// fakeKinesis implements PutRecords and PutRecordBatch, failing the records
+// whose data contains a key of failures with its error code, once each.
+type fakeKinesis struct {
+	mu       sync.Mutex
+	targets  []string
+	streams  []string
+	records  []kinesisRecord
+	failures map[string]string
+	status   int
+}
+
+func (f *fakeKinesis) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.targets = append(f.targets, r.Header.Get("X-Amz-Target"))
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		json.NewEncoder(w).Encode(map[string]string{"__type": "com.amazonaws.kinesis#ResourceNotFoundException", "message": "no stream"})
+		return
+	}
+	var req struct {
+		StreamName         string
+		DeliveryStreamName string
+		Records            []struct {
+			Data         []byte
+			PartitionKey string
+		}
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	f.streams = append(f.streams, req.StreamName+req.DeliveryStreamName)
+	var results []kinesisResult
+	failed := 0
+	for _, rec := range req.Records {
+		var res kinesisResult
+		for marker, code := range f.failures {
+			if bytes.Contains(rec.Data, []byte(marker)) {
+				res = kinesisResult{ErrorCode: code, ErrorMessage: "rejected"}
+				delete(f.failures, marker)
+				failed++
+			}
+		}
+		if res.ErrorCode == "" {
+			f.records = append(f.records, kinesisRecord{data: rec.Data, key: rec.PartitionKey})
+		}
+		results = append(results, res)
+	}
+	if req.StreamName != "" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"FailedRecordCount": failed, "Records": results})
+	} else {
+		json.NewEncoder(w).Encode(map[string]interface{}{"FailedPutCount": failed, "RequestResponses": results})
+	}
+}
+
+func testKinesisConfig(url string) KinesisConfig {
+	return KinesisConfig{
+		Region:        "us-east-1",
+		Endpoint:      url,
+		FlushInterval: time.Hour,
+		Credentials:   testAWSCredentials,
+	}
+}
+
+// kplRecords decodes a KPL aggregated record into its partition keys and
+// entries.
+func kplRecords(t *testing.T, data []byte) (keys, entries []string) {
+	t.Helper()
+	if !bytes.HasPrefix(data, kinesisAggregationMagic) || len(data) < 4+md5.Size {
+		t.Fatalf("not an aggregated record: %q", data)
+	}
+	msg := data[4 : len(data)-md5.Size]
+	if sum := md5.Sum(msg); !bytes.Equal(sum[:], data[len(data)-md5.Size:]) {
+		t.Fatal("aggregated record checksum mismatch")
+	}
+	var table []string
+	consumeProtoFields(msg, func(num protowire.Number, _ uint64, b []byte) {
+		switch num {
+		case 1:
+			table = append(table, string(b))
+		case 3:
+			var index uint64
+			var entry string
+			consumeProtoFields(b, func(num protowire.Number, v uint64, b []byte) {
+				switch num {
+				case 1:
+					index = v
+				case 3:
+					entry = string(b)
+				}
+			})
+			keys = append(keys, table[index])
+			entries = append(entries, entry)
+		}
+	})
+	return keys, entries
+}
+
+func TestKinesisProvider_PutRecords(t *testing.T) {
+	fake := &fakeKinesis{failures: map[string]string{"throttled": "ProvisionedThroughputExceededException", "denied": "AccessDeniedException"}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	cfg := testKinesisConfig(srv.URL)
+	cfg.PartitionKeyField = "tenant"
+	logger, err := NewLogger(WithKinesisProvider("app-logs", cfg))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.With(String("tenant", "acme")).Info("first")
+	logger.Info("throttled", String("tenant", "globex"))
+	logger.Info("denied")
+	if err := logger.Sync(); err == nil || !strings.Contains(err.Error(), "AccessDeniedException") {
+		t.Errorf("sync error = %v", err)
+	}
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.targets) != 2 || fake.targets[0] != "Kinesis_20131202.PutRecords" || fake.streams[0] != "app-logs" {
+		t.Errorf("targets = %v, streams = %v", fake.targets, fake.streams)
+	}
+	if len(fake.records) != 2 {
+		t.Fatalf("got %d records, want 2", len(fake.records))
+	}
+	if fake.records[0].key != "acme" || !strings.Contains(string(fake.records[0].data), `"msg":"first"`) {
+		t.Errorf("first record = %s (%s)", fake.records[0].data, fake.records[0].key)
+	}
+	if fake.records[1].key != "globex" || !strings.HasSuffix(string(fake.records[1].data), "\n") {
+		t.Errorf("retried record = %q (%s)", fake.records[1].data, fake.records[1].key)
+	}
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+}
+
+func TestKinesisProvider_Aggregate(t *testing.T) {
+	fake := &fakeKinesis{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	cfg := testKinesisConfig(srv.URL)
+	cfg.PartitionKeyField = "tenant"
+	cfg.Aggregate = true
+	logger, err := NewLogger(WithKinesisProvider("app-logs", cfg, WithProviderEncoder(ConsoleEncoder)))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("one", String("tenant", "a"))
+	logger.Info("two", String("tenant", "b"))
+	logger.Info("three", String("tenant", "a"))
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.records) != 1 || fake.records[0].key != "a" {
+		t.Fatalf("records = %+v", fake.records)
+	}
+	keys, entries := kplRecords(t, fake.records[0].data)
+	if strings.Join(keys, ",") != "a,b,a" || len(entries) != 3 || !strings.Contains(entries[1], "two") {
+		t.Errorf("keys = %v, entries = %q", keys, entries)
+	}
+}
+
+func TestFirehoseProvider(t *testing.T) {
+	fake := &fakeKinesis{failures: map[string]string{"again": "ServiceUnavailableException"}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	cfg := testKinesisConfig(srv.URL)
+	cfg.Aggregate = true
+	logger, err := NewLogger(WithFirehoseProvider("delivery", cfg))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("again")
+	logger.Info("packed")
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.targets) != 2 || fake.targets[0] != "Firehose_20150804.PutRecordBatch" || fake.streams[0] != "delivery" {
+		t.Errorf("targets = %v, streams = %v", fake.targets, fake.streams)
+	}
+	if len(fake.records) != 1 || fake.records[0].key != "" {
+		t.Fatalf("records = %+v", fake.records)
+	}
+	if lines := strings.Split(strings.TrimSuffix(string(fake.records[0].data), "\n"), "\n"); len(lines) != 2 {
+		t.Errorf("record = %q, want two lines", fake.records[0].data)
+	}
+}
+
+func TestKinesisProvider_Errors(t *testing.T) {
+	fake := &fakeKinesis{status: http.StatusBadRequest}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	testAWSProviderErrors(t, WithKinesisProvider("missing", testKinesisConfig(srv.URL)), 1<<20, "ResourceNotFoundException", func() int {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return len(fake.targets)
+	})
+
+	if _, err := NewLogger(WithKinesisProvider("", testKinesisConfig(srv.URL))); err == nil {
+		t.Error("expected an error without a stream name")
+	}
+	clearAWSRegion(t)
+	if _, err := NewLogger(WithFirehoseProvider("d", KinesisConfig{})); err == nil || !strings.Contains(err.Error(), "region") {
+		t.Errorf("expected a region error, got %v", err)
+	}
+}
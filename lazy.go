@@ -0,0 +1,48 @@
+package golog
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                              Lazy Field Values                              */
+/* -------------------------------------------------------------------------- */
+
+// Lazy logs the value returned by fn, which is only called once the entry
+// has passed the level, sampling and filters and is being encoded, so
+// expensive debug-only values cost nothing while Debug is off:
+//
+//	logger.Debug("state", golog.Lazy("snapshot", func() interface{} { return dump(s) }))
+//
+// fn runs at most once per entry, however many providers encode it. Given
+// to With, it runs when the child logger is created.
+func Lazy(key string, fn func() interface{}) Field { return Field{Key: key, Value: lazy(fn)} }
+
+type lazy func() interface{}
+
+// lazyField encodes its value inline under key, computing it on first use.
+type lazyField struct {
+	key  string
+	fn   lazy
+	once sync.Once
+	zf   zapcore.Field
+}
+
+func newLazyField(key string, fn lazy) zapcore.Field {
+	f := zap.Inline(&lazyField{key: key, fn: fn})
+	// Inline fields ignore Key when encoding; set it for the wrapper cores
+	// that match fields by key.
+	f.Key = key
+	return f
+}
+
+func (f *lazyField) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	f.once.Do(func() {
+		f.zf = toZapFields([]Field{{Key: f.key, Value: f.fn()}})[0]
+	})
+	f.zf.AddTo(enc)
+	return nil
+}
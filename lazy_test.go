@@ -0,0 +1,33 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	var a, b bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&a, JSONEncoder), WithWriterProvider(&b, ConsoleEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	calls := 0
+	snapshot := Lazy("snapshot", func() interface{} {
+		calls++
+		return map[string]int{"n": calls}
+	})
+	logger.Debug("filtered", snapshot)
+	if calls != 0 {
+		t.Fatalf("fn ran for a disabled entry")
+	}
+	logger.Info("written", snapshot)
+	if calls != 1 {
+		t.Errorf("fn ran %d times, want 1", calls)
+	}
+	if !strings.Contains(a.String(), `"snapshot":{"n":1}`) || !strings.Contains(b.String(), `"snapshot": {"n":1}`) {
+		t.Errorf("unexpected output:\n%s\n%s", a.String(), b.String())
+	}
+}
@@ -0,0 +1,204 @@
+package golog
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                       Level Names, Parsing & Environment                    */
+/* -------------------------------------------------------------------------- */
+
+// traceZapLevel is TraceLevel in zap's numbering.
+const traceZapLevel = zapcore.DebugLevel - 1
+
+// customLevels maps the levels registered with RegisterLevel to their names.
+// It is replaced, never modified, so encoders read it without locking.
+var (
+	customLevelsMu sync.Mutex
+	customLevels   atomic.Pointer[map[Level]string]
+)
+
+// RegisterLevel names an additional level finer than Trace, e.g. for a
+// verbosity tier below it:
+//
+//	const WireLevel golog.Level = golog.TraceLevel - 1
+//
+//	func init() { golog.RegisterLevel(WireLevel, "wire") }
+//
+// The name is used by String, ParseLevel, text encoding and the encoders;
+// log at the level with Event. Like RegisterProviderFactory, it panics if
+// level is not below TraceLevel, out of range, or if level or name is
+// already taken; call it from an init function.
+func RegisterLevel(level Level, name string) {
+	customLevelsMu.Lock()
+	defer customLevelsMu.Unlock()
+	name = strings.ToLower(name)
+	if level >= TraceLevel || level <= Level(math.MinInt8) {
+		panic(fmt.Sprintf("golog: RegisterLevel level %d must lie in (%d, %d)", int(level), math.MinInt8, int(TraceLevel)))
+	}
+	if _, err := ParseLevel(name); err == nil {
+		panic("golog: RegisterLevel name already in use: " + name)
+	}
+	next := map[Level]string{}
+	if cur := customLevels.Load(); cur != nil {
+		for l, n := range *cur {
+			next[l] = n
+		}
+	}
+	if _, dup := next[level]; dup {
+		panic(fmt.Sprintf("golog: RegisterLevel called twice for level %d", int(level)))
+	}
+	next[level] = name
+	customLevels.Store(&next)
+}
+
+// levelName returns the name of l, if it has one.
+func levelName(l Level) (string, bool) {
+	switch l {
+	case TraceLevel:
+		return "trace", true
+	case DebugLevel:
+		return "debug", true
+	case InfoLevel:
+		return "info", true
+	case WarnLevel:
+		return "warn", true
+	case ErrorLevel:
+		return "error", true
+	case FatalLevel:
+		return "fatal", true
+	}
+	if custom := customLevels.Load(); custom != nil {
+		name, ok := (*custom)[l]
+		return name, ok
+	}
+	return "", false
+}
+
+// String returns the lower-case name of l ("debug", "info", …), as used in
+// encoded entries.
+func (l Level) String() string {
+	if name, ok := levelName(l); ok {
+		return name
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// ParseLevel parses a level name case-insensitively; "warning" is accepted
+// for "warn". Names registered with RegisterLevel are recognised.
+func ParseLevel(s string) (Level, error) {
+	switch name := strings.ToLower(strings.TrimSpace(s)); name {
+	case "trace":
+		return TraceLevel, nil
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	default:
+		if custom := customLevels.Load(); custom != nil {
+			for l, n := range *custom {
+				if n == name {
+					return l, nil
+				}
+			}
+		}
+		return InfoLevel, fmt.Errorf("unknown level %q", s)
+	}
+}
+
+// MarshalText encodes l as its name, so levels appear as "warn" rather than
+// 2 in JSON, YAML and flag output.
+func (l Level) MarshalText() ([]byte, error) {
+	name, ok := levelName(l)
+	if !ok {
+		return nil, fmt.Errorf("unknown level %d", int(l))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a level name as ParseLevel does.
+func (l *Level) UnmarshalText(text []byte) error {
+	lvl, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = lvl
+	return nil
+}
+
+// Set implements flag.Value, so a Level can be bound directly to a flag.
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+
+// lowercaseLevelEncoder is zap's lower-case level encoder extended with
+// Trace and the custom levels.
+func lowercaseLevelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if lvl < zapcore.DebugLevel {
+		enc.AppendString(fromZapLevel(lvl).String())
+		return
+	}
+	zapcore.LowercaseLevelEncoder(lvl, enc)
+}
+
+// zapLevelName is the lowercase name of lvl, including Trace, the custom
+// levels and zap's dpanic and panic.
+func zapLevelName(lvl zapcore.Level) string {
+	if lvl < zapcore.DebugLevel {
+		return fromZapLevel(lvl).String()
+	}
+	return lvl.String()
+}
+
+// capitalLevelEncoder is zap's capital level encoder extended with Trace and
+// the custom levels.
+func capitalLevelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if lvl < zapcore.DebugLevel {
+		enc.AppendString(strings.ToUpper(fromZapLevel(lvl).String()))
+		return
+	}
+	zapcore.CapitalLevelEncoder(lvl, enc)
+}
+
+// capitalColorLevelEncoder is zap's coloured capital level encoder extended
+// with Trace and the custom levels, which print uncoloured.
+func capitalColorLevelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if lvl < zapcore.DebugLevel {
+		enc.AppendString(strings.ToUpper(fromZapLevel(lvl).String()))
+		return
+	}
+	zapcore.CapitalColorLevelEncoder(lvl, enc)
+}
+
+// WithLevelFromEnv sets the level from the environment variable name, e.g.
+// GOLOG_LEVEL=debug, so the same binary can run at different levels per
+// environment. It overrides WithLevel given before it; when the variable is
+// unset or empty the level is left alone. An invalid value makes NewLogger
+// fail.
+func WithLevelFromEnv(name string) LoggerOption {
+	return func(cfg *loggerConfig) {
+		v := os.Getenv(name)
+		if v == "" {
+			return
+		}
+		lvl, err := ParseLevel(v)
+		if err != nil {
+			cfg.optionErrs = append(cfg.optionErrs, fmt.Errorf("%s: %w", name, err))
+			return
+		}
+		cfg.level = lvl
+	}
+}
@@ -0,0 +1,143 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLevelText(t *testing.T) {
+	for _, lvl := range []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel, FatalLevel} {
+		text, err := lvl.MarshalText()
+		if err != nil {
+			t.Fatalf("MarshalText(%v): %v", lvl, err)
+		}
+		var got Level
+		if err := got.UnmarshalText(text); err != nil || got != lvl {
+			t.Errorf("round trip of %v gave %v, %v", lvl, got, err)
+		}
+	}
+	if lvl, err := ParseLevel(" WARNING "); err != nil || lvl != WarnLevel {
+		t.Errorf("ParseLevel(WARNING) = %v, %v", lvl, err)
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	if s := Level(42).String(); s != "Level(42)" {
+		t.Errorf("unexpected name for unknown level: %s", s)
+	}
+
+	var cfg struct{ Level Level }
+	if err := json.Unmarshal([]byte(`{"Level":"error"}`), &cfg); err != nil || cfg.Level != ErrorLevel {
+		t.Errorf("JSON decode gave %v, %v", cfg.Level, err)
+	}
+	if out, _ := json.Marshal(cfg); string(out) != `{"Level":"error"}` {
+		t.Errorf("JSON encode gave %s", out)
+	}
+}
+
+func TestWithLevelFromEnv(t *testing.T) {
+	var buf bytes.Buffer
+	t.Setenv("GOLOG_LEVEL", "debug")
+	logger, err := NewLogger(WithLevel(ErrorLevel), WithLevelFromEnv("GOLOG_LEVEL"), WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Debug("from env")
+	logger.Close()
+	if !strings.Contains(buf.String(), "from env") {
+		t.Errorf("environment level not applied: %s", buf.String())
+	}
+
+	t.Setenv("GOLOG_LEVEL", "")
+	logger, err = NewLogger(WithLevel(ErrorLevel), WithLevelFromEnv("GOLOG_LEVEL"), WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	if logger.Level() != ErrorLevel {
+		t.Errorf("unset variable changed the level to %v", logger.Level())
+	}
+	logger.Close()
+
+	t.Setenv("GOLOG_LEVEL", "verbose")
+	if _, err := NewLogger(WithLevelFromEnv("GOLOG_LEVEL")); err == nil || !strings.Contains(err.Error(), "GOLOG_LEVEL") {
+		t.Errorf("expected an error naming the variable, got %v", err)
+	}
+}
+
+func TestTraceLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithLevel(DebugLevel), WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Trace("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("trace entry logged at debug level: %s", buf.String())
+	}
+
+	logger.SetLevel(TraceLevel)
+	logger.Trace("plain", String("k", "v"))
+	logger.Tracef("formatted %d", 1)
+	logger.Tracew("sugared", "k", "w")
+	out := buf.String()
+	if n := strings.Count(out, `"level":"trace"`); n != 3 {
+		t.Errorf("expected 3 trace entries, got %d: %s", n, out)
+	}
+	for _, want := range []string{`"msg":"plain"`, `"msg":"formatted 1"`, `"k":"w"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in output: %s", want, out)
+		}
+	}
+	if got := logger.Stats().Entries[TraceLevel]; got != 3 {
+		t.Errorf("Stats counted %d trace entries, want 3", got)
+	}
+	if lvl, err := ParseLevel("TRACE"); err != nil || lvl != TraceLevel {
+		t.Errorf("ParseLevel(TRACE) = %v, %v", lvl, err)
+	}
+}
+
+const wireLevel = TraceLevel - 1
+
+var registerWire sync.Once
+
+func TestRegisterLevel(t *testing.T) {
+	registerWire.Do(func() { RegisterLevel(wireLevel, "Wire") })
+
+	if s := wireLevel.String(); s != "wire" {
+		t.Errorf("custom level named %q", s)
+	}
+	if lvl, err := ParseLevel("wire"); err != nil || lvl != wireLevel {
+		t.Errorf("ParseLevel(wire) = %v, %v", lvl, err)
+	}
+
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithLevel(wireLevel), WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Event(wireLevel).Str("frame", "0x01").Msg("sent")
+	logger.Close()
+	if !strings.Contains(buf.String(), `"level":"wire"`) {
+		t.Errorf("custom level not encoded by name: %s", buf.String())
+	}
+
+	for name, register := range map[string]func(){
+		"taken level": func() { RegisterLevel(wireLevel, "other") },
+		"taken name":  func() { RegisterLevel(wireLevel-1, "debug") },
+		"above trace": func() { RegisterLevel(DebugLevel, "fine") },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			register()
+		}()
+	}
+}
@@ -0,0 +1,76 @@
+package golog
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                        Min/Max Level Range per Provider                     */
+/* -------------------------------------------------------------------------- */
+
+// WithLevelRange restricts the providers registered by opt to entries whose
+// level lies within [min, max], independently of the logger's level – e.g. a
+// provider that receives only Warn and Error but not Fatal, or a Debug-only
+// tap:
+//
+//	golog.WithLevelRange(golog.WithFileProvider("debug.log", 10, 3, 7, true), golog.DebugLevel, golog.DebugLevel)
+//
+// DPanic and Panic entries count as ErrorLevel.
+func WithLevelRange(opt LoggerOption, min, max Level) LoggerOption {
+	return wrapProviders(opt, func(p provider) provider {
+		return &levelRangeProvider{inner: p, min: min, max: max}
+	})
+}
+
+type levelRangeProvider struct {
+	inner    provider
+	min, max Level
+}
+
+func (p *levelRangeProvider) newCore(zapcore.Level) (zapcore.Core, error) {
+	if p.min > p.max {
+		return nil, fmt.Errorf("level range: min %d is above max %d", p.min, p.max)
+	}
+	core, err := p.inner.newCore(toZapLevel(p.min))
+	if err != nil {
+		return nil, err
+	}
+	return &levelRangeCore{Core: core, min: p.min, max: p.max}, nil
+}
+
+func (p *levelRangeProvider) close() error { return p.inner.close() }
+
+func (p *levelRangeProvider) instrument(t *telemetry) {
+	if ip, ok := p.inner.(instrumentedProvider); ok {
+		ip.instrument(t)
+	}
+}
+
+func (p *levelRangeProvider) describe() ProviderInfo {
+	info := describeProvider(p.inner)
+	info.ownsLevel = true
+	return info
+}
+
+type levelRangeCore struct {
+	zapcore.Core
+	min, max Level
+}
+
+func (c *levelRangeCore) Enabled(lvl zapcore.Level) bool {
+	l := fromZapLevel(lvl)
+	return l >= c.min && l <= c.max && c.Core.Enabled(lvl)
+}
+
+func (c *levelRangeCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelRangeCore{Core: c.Core.With(fields), min: c.min, max: c.max}
+}
+
+func (c *levelRangeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
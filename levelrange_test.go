@@ -0,0 +1,42 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLevelRange(t *testing.T) {
+	var main, alerts, tap bytes.Buffer
+	logger, err := NewLogger(
+		WithLevel(InfoLevel),
+		WithWriterProvider(&main, JSONEncoder),
+		WithLevelRange(WithWriterProvider(&alerts, JSONEncoder), WarnLevel, ErrorLevel),
+		WithLevelRange(WithWriterProvider(&tap, JSONEncoder), DebugLevel, DebugLevel),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Debug("d")
+	logger.Info("i")
+	logger.Warn("w")
+	logger.Error("e")
+
+	if out := main.String(); strings.Count(out, "\n") != 3 || strings.Contains(out, `"msg":"d"`) {
+		t.Errorf("unexpected main output: %s", out)
+	}
+	if out := alerts.String(); strings.Count(out, "\n") != 2 || !strings.Contains(out, `"msg":"w"`) || !strings.Contains(out, `"msg":"e"`) {
+		t.Errorf("unexpected alerts output: %s", out)
+	}
+	if out := tap.String(); strings.Count(out, "\n") != 1 || !strings.Contains(out, `"msg":"d"`) {
+		t.Errorf("unexpected tap output: %s", out)
+	}
+}
+
+func TestLevelRangeInvalid(t *testing.T) {
+	if _, err := NewLogger(WithLevelRange(WithStdOutProvider(JSONEncoder), ErrorLevel, InfoLevel)); err == nil {
+		t.Error("expected error for inverted range")
+	}
+}
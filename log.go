@@ -0,0 +1,51 @@
+package golog
+
+import "context"
+
+/* -------------------------------------------------------------------------- */
+/*                              Logging Interface                              */
+/* -------------------------------------------------------------------------- */
+
+// Log is the logging surface of *Logger. Depend on it rather than on
+// *Logger to substitute the nop and recording implementations in package
+// gologtest in unit tests.
+type Log interface {
+	Log(level Level, msg string, fields ...Field)
+	Enabled(level Level) bool
+
+	Trace(msg string, fields ...Field)
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	DPanic(msg string, fields ...Field)
+	Panic(msg string, fields ...Field)
+	Fatal(msg string, fields ...Field)
+
+	TraceCtx(ctx context.Context, msg string, fields ...Field)
+	DebugCtx(ctx context.Context, msg string, fields ...Field)
+	InfoCtx(ctx context.Context, msg string, fields ...Field)
+	WarnCtx(ctx context.Context, msg string, fields ...Field)
+	ErrorCtx(ctx context.Context, msg string, fields ...Field)
+	FatalCtx(ctx context.Context, msg string, fields ...Field)
+
+	Tracef(format string, args ...interface{})
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	DPanicf(format string, args ...interface{})
+	Panicf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+
+	Tracew(msg string, keysAndValues ...interface{})
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+	DPanicw(msg string, keysAndValues ...interface{})
+	Panicw(msg string, keysAndValues ...interface{})
+	Fatalw(msg string, keysAndValues ...interface{})
+}
+
+var _ Log = (*Logger)(nil)
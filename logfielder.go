@@ -0,0 +1,71 @@
+package golog
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                      Structured Fields of Domain Values                     */
+/* -------------------------------------------------------------------------- */
+
+// LogFielder is implemented by values that describe themselves as fields,
+// typically domain errors carrying a status code, retryability or IDs:
+//
+//	func (e *APIError) LogFields() []golog.Field {
+//		return []golog.Field{golog.Int("status", e.Status), golog.Bool("retryable", e.Retryable)}
+//	}
+//
+//	logger.Error("call failed", golog.Err(err))
+//	// {"msg":"call failed","error":"upstream unavailable","status":503,"retryable":true}
+//
+// Err, and Any given an error, keep the message under "error" and add the
+// fields of the first LogFielder in the error's Unwrap chain next to it.
+// ErrVerbose adds them inside its error object. Any given another
+// LogFielder logs its fields as a nested object under the key. LogFields is
+// called when the field is created, so the entry records the state at that
+// time.
+type LogFielder interface {
+	LogFields() []Field
+}
+
+// errorField is the zap field for err, with the fields of the LogFielder in
+// its chain, if any, inline after it.
+func errorField(err error) zapcore.Field {
+	var fielder LogFielder
+	if !errors.As(err, &fielder) {
+		return zap.Error(err)
+	}
+	f := zap.Inline(errorWithFields{err: err, fields: toZapFields(fielder.LogFields())})
+	// Inline fields ignore Key when encoding; set it for the wrapper cores
+	// that match fields by key.
+	f.Key = "error"
+	return f
+}
+
+// errorWithFields encodes an error followed by the fields it describes
+// itself with.
+type errorWithFields struct {
+	err    error
+	fields []zapcore.Field
+}
+
+func (e errorWithFields) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	zap.Error(e.err).AddTo(enc)
+	for _, f := range e.fields {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// errorFielderFields returns the fields of the first LogFielder in err's
+// chain.
+func errorFielderFields(err error) []Field {
+	var fielder LogFielder
+	if errors.As(err, &fielder) {
+		return fielder.LogFields()
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type apiError struct {
+	status    int
+	retryable bool
+}
+
+func (e *apiError) Error() string { return "upstream unavailable" }
+
+func (e *apiError) LogFields() []Field {
+	return []Field{Int("status", e.status), Bool("retryable", e.retryable)}
+}
+
+type order struct{ id string }
+
+func (o order) LogFields() []Field { return []Field{String("id", o.id)} }
+
+func TestLogFielder(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	apiErr := fmt.Errorf("charge: %w", &apiError{status: 503, retryable: true})
+	logger.Error("call failed", Err(apiErr))
+	logger.Error("verbose", ErrVerbose(apiErr))
+	logger.Info("placed", Any("order", order{id: "o-1"}))
+	logger.Event(ErrorLevel).Err(apiErr).Msg("event")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 entries, got %q", lines)
+	}
+	var entries [4]map[string]interface{}
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &entries[i]); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+	}
+	if e := entries[0]; e["error"] != "charge: upstream unavailable" || e["status"] != float64(503) || e["retryable"] != true {
+		t.Errorf("Err did not add the error's fields: %s", lines[0])
+	}
+	if obj, _ := entries[1]["error"].(map[string]interface{}); obj["message"] != "charge: upstream unavailable" || obj["status"] != float64(503) {
+		t.Errorf("ErrVerbose did not add the error's fields: %s", lines[1])
+	}
+	if obj, _ := entries[2]["order"].(map[string]interface{}); obj["id"] != "o-1" {
+		t.Errorf("Any did not log the LogFielder's fields: %s", lines[2])
+	}
+	if e := entries[3]; e["error"] != "charge: upstream unavailable" || e["status"] != float64(503) {
+		t.Errorf("Event.Err did not add the error's fields: %s", lines[3])
+	}
+}
+
+func TestLogFielderRoundTrip(t *testing.T) {
+	fields := fromZapFields(toZapFields([]Field{Err(&apiError{status: 429})}))
+	got := map[string]interface{}{}
+	for _, f := range fields {
+		got[f.Key] = f.Value
+	}
+	if len(got) != 3 || got["error"] != "upstream unavailable" || got["status"] != int64(429) || got["retryable"] != false {
+		t.Errorf("fromZapFields lost the error's fields: %+v", fields)
+	}
+}
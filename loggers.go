@@ -0,0 +1,316 @@
+package golog
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                     Named Loggers & Per-Name Level Overrides                */
+/* -------------------------------------------------------------------------- */
+
+// lowestLevel is the level provider cores are built at. The logger's own
+// levels – the root level and per-name overrides – are applied on top by
+// gateCore, so they can change after construction.
+const lowestLevel = zapcore.Level(math.MinInt8)
+
+// levelTable holds a logger's root level and per-name overrides. It is
+// shared by the logger and all of its children.
+type levelTable struct {
+	root  zap.AtomicLevel
+	floor atomic.Int32 // lowest of root and all overrides
+
+	mu        sync.RWMutex
+	overrides map[string]zapcore.Level
+	// hasOverrides lets levelFor skip the lock in the common case.
+	hasOverrides atomic.Bool
+}
+
+func newLevelTable(root zapcore.Level) *levelTable {
+	t := &levelTable{root: zap.NewAtomicLevelAt(root), overrides: map[string]zapcore.Level{}}
+	t.floor.Store(int32(root))
+	return t
+}
+
+// levelFor returns the level of the logger named name: the override for the
+// longest dotted prefix of name ("payments" covers "payments.db"), or the
+// root level.
+func (t *levelTable) levelFor(name string) zapcore.Level {
+	if name != "" && t.hasOverrides.Load() {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+		for n := name; ; {
+			if lvl, ok := t.overrides[n]; ok {
+				return lvl
+			}
+			i := strings.LastIndexByte(n, '.')
+			if i < 0 {
+				break
+			}
+			n = n[:i]
+		}
+	}
+	return t.root.Level()
+}
+
+func (t *levelTable) enabled(name string, lvl zapcore.Level) bool {
+	return lvl >= t.levelFor(name)
+}
+
+func (t *levelTable) setOverride(name string, lvl zapcore.Level) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.overrides[name] = lvl
+	t.updateLocked()
+}
+
+func (t *levelTable) clearOverride(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.overrides, name)
+	t.updateLocked()
+}
+
+func (t *levelTable) setRoot(lvl zapcore.Level) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.root.SetLevel(lvl)
+	t.updateLocked()
+}
+
+func (t *levelTable) updateLocked() {
+	floor := t.root.Level()
+	for _, lvl := range t.overrides {
+		if lvl < floor {
+			floor = lvl
+		}
+	}
+	t.floor.Store(int32(floor))
+	t.hasOverrides.Store(len(t.overrides) > 0)
+}
+
+// gateCore applies a levelTable to a provider core. Enabled answers for the
+// most verbose configured level; Check and enabledFor refine that using the
+// entry's logger name.
+type gateCore struct {
+	zapcore.Core
+	levels *levelTable
+}
+
+func (c *gateCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= zapcore.Level(c.levels.floor.Load()) && c.Core.Enabled(lvl)
+}
+
+func (c *gateCore) enabledFor(ent zapcore.Entry) bool {
+	return c.levels.enabled(ent.LoggerName, ent.Level) && c.Core.Enabled(ent.Level)
+}
+
+func (c *gateCore) With(fields []zapcore.Field) zapcore.Core {
+	return &gateCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *gateCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.levels.enabled(ent.LoggerName, ent.Level) {
+		return c.Core.Check(ent, ce)
+	}
+	return ce
+}
+
+// Named returns a child of l whose entries carry name, joined to l's own
+// name with a dot, as the "logger" key:
+//
+//	db := logger.Named("db")       // "db"
+//	pool := db.Named("pool")       // "db.pool"
+//	logger.SetNamedLevel("db", golog.DebugLevel)
+//
+// The child is subject to the level overrides for its name (see
+// SetNamedLevel). It shares l's providers; closing it only flushes them.
+func (l *Logger) Named(name string) *Logger {
+	return l.child(l.zapLogger.Named(name))
+}
+
+// SetNamedLevel overrides the minimum level of l's children named name and
+// their descendants: "db" covers "db" and "db.pool". name is the full dotted
+// name, whichever logger the override is set through. The override takes
+// effect immediately, may be lower than the logger's own level and is shared
+// by the logger and all of its children. Providers with their own bounds
+// (WithLevelRange) are unaffected.
+func (l *Logger) SetNamedLevel(name string, level Level) {
+	l.levels.setOverride(name, toZapLevel(level))
+}
+
+// ResetNamedLevel removes the override set for name by SetNamedLevel.
+func (l *Logger) ResetNamedLevel(name string) {
+	l.levels.clearOverride(name)
+}
+
+// SetLevel changes the logger's minimum level at runtime, e.g. from an admin
+// endpoint or a signal handler. It applies immediately to every provider the
+// logger writes to, except those with their own bounds (WithLevelRange), and
+// is shared by the logger and all of its children. Overrides set with
+// SetNamedLevel still take precedence for their names.
+func (l *Logger) SetLevel(level Level) {
+	l.levels.setRoot(toZapLevel(level))
+}
+
+// Level returns the logger's current minimum level.
+func (l *Logger) Level() Level {
+	return fromZapLevel(l.levels.root.Level())
+}
+
+// NamedLevels returns the overrides set with SetNamedLevel, by name.
+func (l *Logger) NamedLevels() map[string]Level {
+	l.levels.mu.RLock()
+	defer l.levels.mu.RUnlock()
+	out := make(map[string]Level, len(l.levels.overrides))
+	for name, lvl := range l.levels.overrides {
+		out[name] = fromZapLevel(lvl)
+	}
+	return out
+}
+
+// With returns a child of l that adds fields to every entry, e.g. a
+// request-scoped logger:
+//
+//	reqLog := logger.With(golog.String("request_id", id), golog.String("route", r.URL.Path))
+//
+// The child shares l's providers and levels; closing it only flushes them.
+func (l *Logger) With(fields ...Field) *Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	return l.child(l.zapLogger.With(toZapFields(fields)...))
+}
+
+// child wraps z, derived from l's zap logger, as a Logger sharing l's state.
+func (l *Logger) child(z *zap.Logger) *Logger {
+	root := l
+	if l.root != nil {
+		root = l.root
+	}
+	return &Logger{
+		zapLogger: z,
+		sugared:   z.Sugar(),
+		telemetry: l.telemetry,
+		stop:      l.stop,
+		providers: l.providers,
+		ring:      l.ring,
+		levels:    l.levels,
+		added:     l.added,
+		root:      root,
+		enriched:  l.enriched,
+	}
+}
+
+/* -------------------------------------------------------------------------- */
+/*                          Process-Wide Logger Registry                       */
+/* -------------------------------------------------------------------------- */
+
+var (
+	registryMu    sync.Mutex
+	defaultLogger *Logger
+	namedLoggers  = map[string]*Logger{}
+	// packageLogger is the default logger skipping the package-level
+	// function's frame; it is rebuilt after SetDefault.
+	packageLogger *Logger
+)
+
+// Default returns the process-wide default logger: the one passed to
+// SetDefault or, until then, a logger that discards everything, so libraries
+// and init code can log before the application has configured logging.
+// Providers attached to the discarding logger with AddProvider do receive
+// entries.
+func Default() *Logger {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return defaultLocked()
+}
+
+func defaultLocked() *Logger {
+	if defaultLogger == nil {
+		defaultLogger = NewNop()
+	}
+	return defaultLogger
+}
+
+// SetDefault makes l the process-wide default logger used by the package-level
+// logging functions and GetLogger; nil restores the discarding logger.
+// Loggers already obtained from Default or GetLogger keep writing to the
+// logger they were derived from, so call SetDefault early in main. Overrides
+// set with SetLoggerLevel belong to the previous default and do not carry
+// over. SetDefault does not close the previous default.
+func SetDefault(l *Logger) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	defaultLogger = l
+	namedLoggers = map[string]*Logger{}
+	packageLogger = nil
+}
+
+// pkg returns the logger used by the package-level logging functions.
+func pkg() *Logger {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if packageLogger == nil {
+		d := defaultLocked()
+		packageLogger = d.child(d.zapLogger.WithOptions(zap.AddCallerSkip(1)))
+	}
+	return packageLogger
+}
+
+// GetLogger returns the child of the default logger named name, creating it
+// on first use; later calls with the same name return the same Logger until
+// SetDefault is called. Names are hierarchical with dots as separators, so
+// overrides set with SetLoggerLevel for "payments" also apply to
+// "payments.db":
+//
+//	golog.SetDefault(logger)
+//	log := golog.GetLogger("payments")
+//
+//	golog.SetLoggerLevel("payments", golog.DebugLevel)
+func GetLogger(name string) *Logger {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if l, ok := namedLoggers[name]; ok {
+		return l
+	}
+	l := defaultLocked().Named(name)
+	namedLoggers[name] = l
+	return l
+}
+
+// SetLoggerLevel sets a level override on the default logger; see
+// Logger.SetNamedLevel.
+func SetLoggerLevel(name string, level Level) {
+	Default().SetNamedLevel(name, level)
+}
+
+// ResetLoggerLevel removes the override set for name by SetLoggerLevel.
+func ResetLoggerLevel(name string) {
+	Default().ResetNamedLevel(name)
+}
+
+/* -------------------------------------------------------------------------- */
+/*                          Package-Level Logging                              */
+/* -------------------------------------------------------------------------- */
+
+// Debug logs at Debug level to the default logger.
+func Debug(msg string, fields ...Field) { pkg().Debug(msg, fields...) }
+
+// Info logs at Info level to the default logger.
+func Info(msg string, fields ...Field) { pkg().Info(msg, fields...) }
+
+// Warn logs at Warn level to the default logger.
+func Warn(msg string, fields ...Field) { pkg().Warn(msg, fields...) }
+
+// Error logs at Error level to the default logger.
+func Error(msg string, fields ...Field) { pkg().Error(msg, fields...) }
+
+// Fatal logs at Fatal level to the default logger and then exits the
+// process, even while the default discards entries.
+func Fatal(msg string, fields ...Field) { pkg().Fatal(msg, fields...) }
@@ -0,0 +1,253 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNamedLevelOverrides(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(
+		WithLevel(InfoLevel),
+		WithWriterProvider(&buf, JSONEncoder),
+		WithSequence("seq"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	db := logger.Named("db")
+	pool := db.Named("pool")
+	logger.SetNamedLevel("db", DebugLevel)
+
+	logger.Debug("root debug")
+	db.Debug("db debug")
+	pool.Debug("pool debug")
+	db.Info("db info")
+
+	out := buf.String()
+	if strings.Contains(out, "root debug") {
+		t.Errorf("root debug should stay disabled: %s", out)
+	}
+	for _, want := range [][2]string{{"db", "db debug"}, {"db.pool", "pool debug"}, {"db", "db info"}} {
+		if !hasNamedEntry(out, want[0], want[1]) {
+			t.Errorf("missing %q from %q in output: %s", want[1], want[0], out)
+		}
+	}
+	// Entries gated out must not consume sequence numbers.
+	if !strings.Contains(out, `"seq":3`) || strings.Contains(out, `"seq":4`) {
+		t.Errorf("unexpected sequence numbers: %s", out)
+	}
+
+	buf.Reset()
+	logger.SetNamedLevel("db.pool", ErrorLevel)
+	pool.Warn("pool warn")
+	db.Debug("db debug again")
+	if got := pool.NamedLevels(); len(got) != 2 || got["db"] != DebugLevel || got["db.pool"] != ErrorLevel {
+		t.Errorf("unexpected overrides: %v", got)
+	}
+	logger.ResetNamedLevel("db")
+	db.Debug("db debug after reset")
+	out = buf.String()
+	if strings.Contains(out, "pool warn") || strings.Contains(out, "after reset") {
+		t.Errorf("overrides not applied: %s", out)
+	}
+	if !strings.Contains(out, "db debug again") {
+		t.Errorf("parent override lost: %s", out)
+	}
+}
+
+func TestNamedLoggerCloseKeepsRootOpen(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	if err := logger.Named("child").Close(); err != nil {
+		t.Fatalf("child close: %v", err)
+	}
+	logger.Info("still open")
+	if !strings.Contains(buf.String(), "still open") {
+		t.Errorf("root stopped logging after child close: %s", buf.String())
+	}
+}
+
+func TestGetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	registryMu.Lock()
+	prevDefault, prevNamed := defaultLogger, namedLoggers
+	defaultLogger, namedLoggers = logger, map[string]*Logger{}
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defaultLogger, namedLoggers = prevDefault, prevNamed
+		registryMu.Unlock()
+	})
+
+	payments := GetLogger("payments")
+	if GetLogger("payments") != payments {
+		t.Fatal("GetLogger should return the cached logger")
+	}
+	payments.Debug("hidden")
+	SetLoggerLevel("payments", DebugLevel)
+	payments.Debug("shown")
+	GetLogger("payments.refunds").Debug("nested")
+	ResetLoggerLevel("payments")
+	payments.Debug("hidden again")
+
+	out := buf.String()
+	if strings.Contains(out, "hidden") {
+		t.Errorf("debug logged without override: %s", out)
+	}
+	if !hasNamedEntry(out, "payments", "shown") || !hasNamedEntry(out, "payments.refunds", "nested") {
+		t.Errorf("override not applied: %s", out)
+	}
+}
+
+// hasNamedEntry reports whether out holds an entry from logger name with msg.
+func hasNamedEntry(out, name, msg string) bool {
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, `"logger":"`+name+`"`) && strings.Contains(line, `"msg":"`+msg+`"`) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWith(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	req := logger.With(String("request_id", "r-1"))
+	req.With(Int("attempt", 2)).Info("retrying")
+	req.Infow("done", "status", 200)
+	logger.Info("unscoped")
+	if err := req.Close(); err != nil {
+		t.Fatalf("child close: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 entries, got %q", lines)
+	}
+	if !strings.Contains(lines[0], `"request_id":"r-1","attempt":2`) {
+		t.Errorf("nested fields missing: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"request_id":"r-1"`) || !strings.Contains(lines[1], `"status":200`) {
+		t.Errorf("sugared call lost bound fields: %s", lines[1])
+	}
+	if strings.Contains(lines[2], "request_id") {
+		t.Errorf("fields leaked to the parent: %s", lines[2])
+	}
+	logger.Info("after child close")
+	if !strings.Contains(buf.String(), "after child close") {
+		t.Error("closing a With child closed the root")
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	registryMu.Lock()
+	prevDefault, prevNamed, prevPackage := defaultLogger, namedLoggers, packageLogger
+	defaultLogger, namedLoggers, packageLogger = nil, map[string]*Logger{}, nil
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defaultLogger, namedLoggers, packageLogger = prevDefault, prevNamed, prevPackage
+		registryMu.Unlock()
+	})
+
+	// Before configuration everything is discarded without failing.
+	Info("unconfigured")
+	early := GetLogger("early")
+	early.Warn("dropped")
+
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	SetDefault(logger)
+
+	if Default() != logger {
+		t.Fatal("Default should return the logger passed to SetDefault")
+	}
+	Debug("hidden")
+	Info("configured", String("k", "v"))
+	Error("failed")
+	GetLogger("early").Warn("rederived")
+
+	out := buf.String()
+	if strings.Contains(out, "unconfigured") || strings.Contains(out, "dropped") || strings.Contains(out, "hidden") {
+		t.Errorf("unexpected entries: %s", out)
+	}
+	for _, want := range []string{`"msg":"configured"`, `"k":"v"`, `"msg":"failed"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in output: %s", want, out)
+		}
+	}
+	if !hasNamedEntry(out, "early", "rederived") {
+		t.Errorf("GetLogger should derive from the new default: %s", out)
+	}
+
+	SetDefault(nil)
+	Info("after reset")
+	if strings.Contains(buf.String(), "after reset") {
+		t.Error("SetDefault(nil) should restore the discarding default")
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	var main, errs bytes.Buffer
+	logger, err := NewLogger(
+		WithLevel(InfoLevel),
+		WithWriterProvider(&main, JSONEncoder),
+		WithLevelRange(WithWriterProvider(&errs, JSONEncoder), ErrorLevel, FatalLevel),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	child := logger.With(String("k", "v"))
+
+	child.Debug("hidden")
+	child.SetLevel(DebugLevel)
+	if logger.Level() != DebugLevel {
+		t.Fatalf("level not shared with the root: %v", logger.Level())
+	}
+	logger.Debug("shown")
+	if logger.Event(DebugLevel) == nil {
+		t.Error("Event should see the lowered level")
+	}
+	logger.SetLevel(ErrorLevel)
+	logger.Warn("suppressed")
+	logger.Error("failure")
+
+	out := main.String()
+	if strings.Contains(out, "hidden") || strings.Contains(out, "suppressed") {
+		t.Errorf("level not applied: %s", out)
+	}
+	if !strings.Contains(out, "shown") || !strings.Contains(out, "failure") {
+		t.Errorf("missing entries: %s", out)
+	}
+	if strings.Contains(errs.String(), "shown") || !strings.Contains(errs.String(), "failure") {
+		t.Errorf("level-range provider should keep its own bounds: %s", errs.String())
+	}
+	if cfg := logger.Config(); cfg.Level != ErrorLevel {
+		t.Errorf("Config reports %v, want Error", cfg.Level)
+	}
+}
@@ -0,0 +1,100 @@
+package golog
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                       Unique Entry IDs (ULID) per Record                    */
+/* -------------------------------------------------------------------------- */
+
+// LogIDKey is the field WithLogID writes.
+const LogIDKey = "log_id"
+
+// WithLogID attaches a ULID under "log_id" to every entry. The same ID
+// reaches every provider, so a record can be cross-referenced exactly
+// between sinks (the chat alert and the search-index document) and
+// deduplicated in at-least-once pipelines such as WithSpool. IDs sort by
+// creation time and are strictly increasing within a process.
+func WithLogID() LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.logID = true
+	}
+}
+
+func newLogIDCore(cores []zapcore.Core) *stampCore {
+	src := &ulidSource{}
+	return &stampCore{cores: cores, key: LogIDKey, stamp: func() zapcore.Field {
+		return zap.String(LogIDKey, src.next(time.Now()))
+	}}
+}
+
+// crockford is the ULID base32 alphabet.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidSource generates monotonic ULIDs: within one millisecond the 80-bit
+// random part is incremented rather than redrawn.
+type ulidSource struct {
+	mu     sync.Mutex
+	lastMS uint64
+	hi     uint16 // top 16 bits of the random part
+	lo     uint64 // low 64 bits of the random part
+}
+
+func (s *ulidSource) next(now time.Time) string {
+	ms := uint64(now.UnixMilli())
+
+	s.mu.Lock()
+	if ms <= s.lastMS {
+		// Same (or earlier, after a clock step) millisecond: keep order.
+		ms = s.lastMS
+		s.lo++
+		if s.lo == 0 {
+			s.hi++
+			if s.hi == 0 {
+				// Random space exhausted; borrow the next millisecond.
+				ms++
+			}
+		}
+	} else {
+		var b [10]byte
+		_, _ = rand.Read(b[:])
+		s.hi = binary.BigEndian.Uint16(b[:2])
+		s.lo = binary.BigEndian.Uint64(b[2:])
+	}
+	s.lastMS = ms
+	hi, lo := s.hi, s.lo
+	s.mu.Unlock()
+
+	var id [16]byte
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+	binary.BigEndian.PutUint16(id[6:8], hi)
+	binary.BigEndian.PutUint64(id[8:], lo)
+	return encodeULID(id)
+}
+
+// encodeULID renders 128 bits as 26 Crockford base32 characters.
+func encodeULID(id [16]byte) string {
+	var out [26]byte
+	// 130 bits of output for 128 bits of input: the first character only
+	// carries the top 3 bits.
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
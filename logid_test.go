@@ -0,0 +1,53 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestULID(t *testing.T) {
+	// Timestamp vector from the ULID specification.
+	src := &ulidSource{lastMS: 1469918176385 - 1}
+	id := src.next(time.UnixMilli(1469918176385))
+	if len(id) != 26 || id[:10] != "01ARYZ6S41" {
+		t.Errorf("unexpected ULID %q", id)
+	}
+
+	prev := ""
+	now := time.Now()
+	for i := 0; i < 1000; i++ {
+		id := src.next(now) // same millisecond: must still increase
+		if id <= prev {
+			t.Fatalf("ULIDs not monotonic: %q after %q", id, prev)
+		}
+		prev = id
+	}
+}
+
+func TestWithLogID(t *testing.T) {
+	var a, b bytes.Buffer
+	logger, err := NewLogger(
+		WithWriterProvider(&a, JSONEncoder),
+		WithWriterProvider(&b, JSONEncoder),
+		WithLogID(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Error("payment failed")
+
+	var ea, eb map[string]interface{}
+	if err := json.Unmarshal(a.Bytes(), &ea); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b.Bytes(), &eb); err != nil {
+		t.Fatal(err)
+	}
+	id, _ := ea[LogIDKey].(string)
+	if len(id) != 26 || eb[LogIDKey] != id {
+		t.Errorf("expected identical ULIDs across providers, got %v and %v", ea[LogIDKey], eb[LogIDKey])
+	}
+}
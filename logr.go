@@ -0,0 +1,121 @@
+package golog
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                              logr.LogSink Adapter                           */
+/* -------------------------------------------------------------------------- */
+
+// logrVerbosityKey carries the V-level of entries logged through Logr with
+// V > 0.
+const logrVerbosityKey = "v"
+
+// Logr returns a logr.Logger writing to l, for controller-runtime, client-go
+// and other libraries in the Kubernetes ecosystem:
+//
+//	ctrl.SetLogger(logger.Logr())
+//	klog.SetLogger(logger.Logr())
+//
+// V(0) logs at Info, V(1) at Debug and V(2) and above at Trace, with the
+// V-level added as "v". Error always logs at Error, whatever the V-level.
+// Key/value pairs become fields; values implementing logr.Marshaler are
+// logged as the result of MarshalLog, and a trailing key without a value is
+// logged with the value "(MISSING)". WithName joins names with a dot like
+// Named.
+func (l *Logger) Logr() logr.Logger {
+	return logr.New(&logrSink{z: l.zapLogger})
+}
+
+type logrSink struct {
+	z *zap.Logger
+}
+
+var (
+	_ logr.LogSink          = (*logrSink)(nil)
+	_ logr.CallDepthLogSink = (*logrSink)(nil)
+)
+
+// Init skips the frames logr adds when reporting the caller; logrSink's own
+// frame takes the place of the Logger method golog already skips.
+func (s *logrSink) Init(info logr.RuntimeInfo) {
+	s.z = s.z.WithOptions(zap.AddCallerSkip(info.CallDepth))
+}
+
+func (s *logrSink) Enabled(level int) bool {
+	return s.z.Core().Enabled(logrLevel(level))
+}
+
+func (s *logrSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	ce := s.z.Check(logrLevel(level), msg)
+	if ce == nil {
+		return
+	}
+	fields := logrFields(keysAndValues, 1)
+	if level > 0 {
+		fields = append(fields, zap.Int(logrVerbosityKey, level))
+	}
+	ce.Write(fields...)
+}
+
+func (s *logrSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	ce := s.z.Check(zapcore.ErrorLevel, msg)
+	if ce == nil {
+		return
+	}
+	fields := logrFields(keysAndValues, 1)
+	if err != nil {
+		fields = append(fields, errorField(err))
+	}
+	ce.Write(fields...)
+}
+
+func (s *logrSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &logrSink{z: s.z.With(logrFields(keysAndValues, 0)...)}
+}
+
+func (s *logrSink) WithName(name string) logr.LogSink {
+	return &logrSink{z: s.z.Named(name)}
+}
+
+func (s *logrSink) WithCallDepth(depth int) logr.LogSink {
+	return &logrSink{z: s.z.WithOptions(zap.AddCallerSkip(depth))}
+}
+
+// logrLevel maps a logr V-level to a zap level.
+func logrLevel(v int) zapcore.Level {
+	switch {
+	case v <= 0:
+		return zapcore.InfoLevel
+	case v == 1:
+		return zapcore.DebugLevel
+	default:
+		return traceZapLevel
+	}
+}
+
+// logrFields converts logr key/value pairs to zap fields, leaving room for
+// extra more.
+func logrFields(keysAndValues []interface{}, extra int) []zapcore.Field {
+	fields := make([]zapcore.Field, 0, (len(keysAndValues)+1)/2+extra)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		var v interface{} = "(MISSING)"
+		if i+1 < len(keysAndValues) {
+			v = keysAndValues[i+1]
+		}
+		if m, ok := v.(logr.Marshaler); ok {
+			v = m.MarshalLog()
+		}
+		fields = append(fields, zap.Any(key, v))
+	}
+	return fields
+}
@@ -0,0 +1,60 @@
+package golog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type marshalledUser struct{ name string }
+
+func (u marshalledUser) MarshalLog() interface{} { return map[string]string{"name": u.name} }
+
+func TestLogr(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithLevel(DebugLevel), WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	log := logger.Logr().WithName("controller").WithValues("reconciler", "pods")
+	log.Info("reconciling", "pod", "web-0", "user", marshalledUser{"ana"}, "dangling")
+	log.V(1).Info("details")
+	log.V(3).Error(fmt.Errorf("boom: %w", &apiError{status: 503}), "reconcile failed")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 entries, got %q", lines)
+	}
+	for _, want := range []string{`"level":"info"`, `"logger":"controller"`, `"reconciler":"pods"`, `"pod":"web-0"`, `"user":{"name":"ana"}`, `"dangling":"(MISSING)"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("missing %s in %s", want, lines[0])
+		}
+	}
+	if strings.Contains(lines[0], `"v":`) {
+		t.Errorf("V(0) entries should not carry v: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"level":"debug"`) || !strings.Contains(lines[1], `"v":1`) {
+		t.Errorf("unexpected V(1) entry: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], `"level":"error"`) || !strings.Contains(lines[2], `"error":"boom: upstream unavailable"`) ||
+		!strings.Contains(lines[2], `"status":503`) {
+		t.Errorf("unexpected error entry: %s", lines[2])
+	}
+	if !strings.Contains(lines[0], `logr_test.go:`) {
+		t.Errorf("caller should be the logr call site: %s", lines[0])
+	}
+
+	buf.Reset()
+	info := logger.Logr()
+	logger.SetNamedLevel("quiet", ErrorLevel)
+	info.WithName("quiet").Info("hidden")
+	if !info.V(1).Enabled() {
+		t.Error("V(1) should be enabled at Debug")
+	}
+	if strings.Contains(buf.String(), "hidden") {
+		t.Errorf("named overrides not applied: %s", buf.String())
+	}
+}
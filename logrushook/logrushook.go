@@ -0,0 +1,104 @@
+// Package logrushook forwards logrus entries into a golog Logger, so legacy
+// dependencies that still log through logrus reach the same providers during
+// a migration.
+//
+// Either add a Hook to an existing logrus logger, keeping its own output:
+//
+//	logrus.AddHook(logrushook.New(logger))
+//
+// or replace its output entirely with the Formatter:
+//
+//	logrus.SetFormatter(logrushook.NewFormatter(logger))
+//	logrus.SetOutput(io.Discard)
+//
+// Panic and Fatal entries are forwarded at Error, since logrus panics or
+// exits by itself once hooks have run; the logger is synced first so the
+// entry is not lost.
+package logrushook
+
+import (
+	"sort"
+
+	"github.com/evdnx/golog"
+	"github.com/sirupsen/logrus"
+)
+
+// Hook is a logrus.Hook that writes every entry to a golog Logger.
+type Hook struct {
+	logger *golog.Logger
+	levels []logrus.Level
+}
+
+// New returns a Hook forwarding entries at levels, or at all levels when
+// none are given.
+func New(logger *golog.Logger, levels ...logrus.Level) *Hook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+	return &Hook{logger: logger, levels: levels}
+}
+
+// Levels implements logrus.Hook.
+func (h *Hook) Levels() []logrus.Level { return h.levels }
+
+// Fire implements logrus.Hook.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	forward(h.logger, entry)
+	return nil
+}
+
+// Formatter is a logrus.Formatter that writes every entry to a golog Logger
+// and returns no output of its own.
+type Formatter struct {
+	logger *golog.Logger
+}
+
+// NewFormatter returns a Formatter forwarding to logger.
+func NewFormatter(logger *golog.Logger) *Formatter {
+	return &Formatter{logger: logger}
+}
+
+// Format implements logrus.Formatter.
+func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	forward(f.logger, entry)
+	return nil, nil
+}
+
+func forward(logger *golog.Logger, entry *logrus.Entry) {
+	fields := toFields(entry.Data)
+	switch entry.Level {
+	case logrus.TraceLevel:
+		logger.Trace(entry.Message, fields...)
+	case logrus.DebugLevel:
+		logger.Debug(entry.Message, fields...)
+	case logrus.InfoLevel:
+		logger.Info(entry.Message, fields...)
+	case logrus.WarnLevel:
+		logger.Warn(entry.Message, fields...)
+	case logrus.ErrorLevel:
+		logger.Error(entry.Message, fields...)
+	default: // Panic, Fatal
+		logger.Error(entry.Message, fields...)
+		_ = logger.Sync()
+	}
+}
+
+// toFields converts logrus data to Fields sorted by key. Errors stored under
+// keys other than logrus.ErrorKey are rendered as strings so they keep
+// their key.
+func toFields(data logrus.Fields) []golog.Field {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]golog.Field, len(keys))
+	for i, k := range keys {
+		v := data[k]
+		if err, ok := v.(error); ok && k != logrus.ErrorKey {
+			v = err.Error()
+		}
+		fields[i] = golog.Any(k, v)
+	}
+	return fields
+}
@@ -0,0 +1,67 @@
+package logrushook
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/evdnx/golog"
+	"github.com/sirupsen/logrus"
+)
+
+func newLogger(t *testing.T) (*golog.Logger, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, err := golog.NewLogger(golog.WithWriterProvider(&buf, golog.JSONEncoder), golog.WithLevel(golog.TraceLevel))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	t.Cleanup(func() { logger.Close() })
+	return logger, &buf
+}
+
+func TestHook(t *testing.T) {
+	logger, buf := newLogger(t)
+	lr := logrus.New()
+	lr.SetOutput(io.Discard)
+	lr.SetLevel(logrus.TraceLevel)
+	lr.AddHook(New(logger, logrus.TraceLevel, logrus.WarnLevel, logrus.ErrorLevel))
+
+	lr.Trace("tracing")
+	lr.Info("not forwarded")
+	lr.WithFields(logrus.Fields{"user": "alice", "cause": errors.New("timeout")}).Warn("slow login")
+	lr.WithError(errors.New("denied")).Error("login failed")
+
+	out := buf.String()
+	for _, want := range []string{
+		`"level":"trace"`, `"msg":"tracing"`,
+		`"level":"warn"`, `"user":"alice"`, `"cause":"timeout"`,
+		`"level":"error"`, `"error":"denied"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in output: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "not forwarded") {
+		t.Errorf("entry outside hook levels forwarded: %s", out)
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	logger, buf := newLogger(t)
+	var own bytes.Buffer
+	lr := logrus.New()
+	lr.SetOutput(&own)
+	lr.SetFormatter(NewFormatter(logger))
+
+	lr.WithField("order", 42).Info("order placed")
+
+	if !strings.Contains(buf.String(), `"msg":"order placed"`) || !strings.Contains(buf.String(), `"order":42`) {
+		t.Errorf("unexpected golog output: %s", buf.String())
+	}
+	if own.Len() != 0 {
+		t.Errorf("formatter produced output of its own: %q", own.String())
+	}
+}
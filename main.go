@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"sync"
 	"syscall"
 	"time"
 
 	"cloud.google.com/go/logging"
+	"github.com/evdnx/golog/internal/term"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -23,7 +25,10 @@ import (
 type Level int
 
 const (
-	DebugLevel Level = iota
+	// TraceLevel is finer than Debug, for high-volume diagnostics (wire
+	// dumps, protocol state) kept off even in normal debug runs.
+	TraceLevel Level = iota - 1
+	DebugLevel
 	InfoLevel
 	WarnLevel
 	ErrorLevel
@@ -52,42 +57,153 @@ type provider interface {
 	close() error
 }
 
+// Provider is an output target implemented outside golog, e.g. an in-house
+// log bus or agent, registered with WithProvider:
+//
+//	type busProvider struct{ conn *bus.Conn }
+//
+//	func (p *busProvider) NewCore(level zapcore.Level) (zapcore.Core, error) {
+//		return zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(p.conn), level), nil
+//	}
+//	func (p *busProvider) Close() error { return p.conn.Close() }
+//
+// NewCore is called with the most verbose level the core must accept;
+// golog applies the logger's level, which can change at run time, in front
+// of it. Close is called when the logger is closed, and also when NewLogger
+// fails, possibly before NewCore. A provider passed to a logger that is
+// cloned with CloneWith is used by both loggers.
+type Provider interface {
+	NewCore(level zapcore.Level) (zapcore.Core, error)
+	Close() error
+}
+
+// WithProvider adds p as a destination. Like the built-in providers it
+// accepts provider options such as WithProviderLevel, and is listed by
+// DebugHandler and Config under its Go type name.
+func WithProvider(p Provider, options ...ProviderOption) LoggerOption {
+	return func(cfg *loggerConfig) {
+		if p == nil {
+			cfg.optionErrs = append(cfg.optionErrs, errors.New("WithProvider: nil provider"))
+			return
+		}
+		cfg.providers = append(cfg.providers, applyProviderOptions(publicProvider{p}, options))
+	}
+}
+
+// publicProvider adapts a Provider to the internal interface.
+type publicProvider struct{ p Provider }
+
+func (p publicProvider) newCore(level zapcore.Level) (zapcore.Core, error) { return p.p.NewCore(level) }
+func (p publicProvider) close() error                                      { return p.p.Close() }
+func (p publicProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: fmt.Sprintf("%T", p.p)}
+}
+
+// telemetry bundles the logger-wide instrumentation shared with providers.
+type telemetry struct {
+	errs  *errorSink
+	drops *dropCounter
+	stats *statsCollector
+	// deadLetters is nil unless WithDeadLetterFile was used.
+	deadLetters *deadLetterWriter
+	// encoding holds the logger-wide encoder config functions, applied
+	// before each provider's own; see WithTimeFormat.
+	encoding []func(*zapcore.EncoderConfig)
+	// callerFormat is used by providers writing the caller themselves.
+	callerFormat CallerFormat
+	// consoleBlocks is set by WithConsoleBlocks.
+	consoleBlocks bool
+}
+
+// buildEncoder returns the encoder typ with the logger-wide encoder settings
+// and then own, a provider's. t may be nil for providers not yet
+// instrumented.
+func (t *telemetry) buildEncoder(typ EncoderType, own *encoderConfigFuncs) (zapcore.Encoder, error) {
+	if t == nil {
+		return buildEncoder(typ, own.funcs()...)
+	}
+	configure := append(t.encoding[:len(t.encoding):len(t.encoding)], own.funcs()...)
+	enc, err := buildEncoder(typ, configure...)
+	if err == nil && t.consoleBlocks && consoleEncoders[typ] {
+		enc = &blockEncoder{Encoder: enc}
+	}
+	return enc, err
+}
+
+// instrumentedProvider is implemented by providers that fail or drop entries
+// outside of zapcore.Core.Write and therefore need a handle on the logger's
+// telemetry.
+type instrumentedProvider interface {
+	instrument(t *telemetry)
+}
+
 /* -------------------------------------------------------------------------- */
 /*                           StdOut Provider                                   */
 /* -------------------------------------------------------------------------- */
 
 type stdOutProvider struct {
-	encoderType EncoderType
+	encoderType   EncoderType
+	encoderConfig *encoderConfigFuncs
+	// stderrFrom, when set, sends entries at or above it to stderr; see
+	// WithStdErrProvider and WithStdErrLevel.
+	stderrFrom *Level
+	tel        *telemetry
 }
 
-func (p stdOutProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
-	enc, err := buildEncoder(p.encoderType)
+func (p *stdOutProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	if p.stderrFrom != nil {
+		return p.newSplitCore(level)
+	}
+	enc, err := p.streamEncoder(os.Stdout)
 	if err != nil {
 		return nil, err
 	}
 	syncer := zapcore.AddSync(os.Stdout)
 	return zapcore.NewCore(enc, syncer, level), nil
 }
-func (p stdOutProvider) close() error { return nil }
+
+// streamEncoder builds the provider's encoder for writing to f.
+func (p *stdOutProvider) streamEncoder(f *os.File) (zapcore.Encoder, error) {
+	encoderType := p.encoderType
+	if colorless, ok := uncolored[encoderType]; ok && !term.EnableVirtualTerminal(f) {
+		// Legacy Windows consoles would print the escape codes verbatim.
+		if force, _ := term.ForceColor(os.Getenv); !force {
+			encoderType = colorless
+		}
+	}
+	return p.tel.buildEncoder(encoderType, p.encoderConfig)
+}
+func (p *stdOutProvider) close() error            { return nil }
+func (p *stdOutProvider) instrument(t *telemetry) { p.tel = t }
+
+// uncolored maps the coloured encoders to their plain variants.
+var uncolored = map[EncoderType]EncoderType{
+	colorConsoleEncoder:     ConsoleEncoder,
+	developmentColorEncoder: developmentEncoder,
+	PrettyColorEncoder:      PrettyEncoder,
+}
 
 /* -------------------------------------------------------------------------- */
 /*                           Writer Provider                                    */
 /* -------------------------------------------------------------------------- */
 
 type writerProvider struct {
-	writer      io.Writer
-	encoderType EncoderType
+	writer        io.Writer
+	encoderType   EncoderType
+	encoderConfig *encoderConfigFuncs
+	tel           *telemetry
 }
 
-func (p writerProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
-	enc, err := buildEncoder(p.encoderType)
+func (p *writerProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	enc, err := p.tel.buildEncoder(p.encoderType, p.encoderConfig)
 	if err != nil {
 		return nil, err
 	}
 	syncer := zapcore.AddSync(p.writer)
 	return zapcore.NewCore(enc, syncer, level), nil
 }
-func (p writerProvider) close() error { return nil }
+func (p *writerProvider) close() error            { return nil }
+func (p *writerProvider) instrument(t *telemetry) { p.tel = t }
 
 /* -------------------------------------------------------------------------- */
 /*                            GCP Provider                                      */
@@ -100,6 +216,7 @@ type gcpProvider struct {
 	// internal fields populated during newCore
 	client *logging.Client
 	logger *logging.Logger
+	tel    *telemetry
 }
 
 func (p *gcpProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
@@ -108,15 +225,31 @@ func (p *gcpProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
 	if err != nil {
 		return nil, fmt.Errorf("gcpProvider: failed to create client: %w", err)
 	}
+	// The client flushes in the background; route its failures to the
+	// logger's telemetry instead of the library's default stderr logging.
+	// A failed bundle means its entries are lost.
+	if p.tel != nil {
+		tel := p.tel
+		client.OnError = func(err error) {
+			tel.drops.record(DropProviderError, 1)
+			tel.errs.report(err)
+		}
+	}
 	p.client = client
 	p.logger = client.Logger(p.logName)
 
-	return &gcpZapCore{
+	core := &gcpZapCore{
 		logger: p.logger,
 		level:  level,
 		fields: make(map[string]interface{}),
-	}, nil
+	}
+	if p.tel != nil {
+		core.callerFormat = p.tel.callerFormat
+	}
+	return core, nil
 }
+func (p *gcpProvider) instrument(t *telemetry) { p.tel = t }
+
 func (p *gcpProvider) close() error {
 	var errs []error
 	if p.logger != nil {
@@ -150,16 +283,22 @@ type fileProvider struct {
 	maxAge     int // days
 	compress   bool
 
+	// encoderType defaults to JSON; see WithProviderEncoder.
+	encoderType   EncoderType
+	encoderConfig *encoderConfigFuncs
+	tel           *telemetry
+
 	// Holds the lumberjack logger for later shutdown.
 	lumberjackLogger *lumberjack.Logger
 }
 
 /*
 	--------------------------------------------------------------
-	  newCore creates a zapcore.Core that writes JSON‑encoded logs
-	  to a rotating file.  It also stores the underlying
-	  lumberjack.Logger on the same *fileProvider* instance so that
-	  Close() can flush and release the file.
+	  newCore creates a zapcore.Core that writes encoded logs (JSON
+	  unless WithProviderEncoder says otherwise) to a rotating file.
+	  It also stores the underlying lumberjack.Logger on the same
+	  *fileProvider* instance so that Close() can flush and release
+	  the file.
 
 --------------------------------------------------------------
 */
@@ -168,7 +307,7 @@ func (p *fileProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
 	if p.maxSize < 0 || p.maxBackups < 0 || p.maxAge < 0 {
 		return nil, errors.New("fileProvider: rotation parameters must be non‑negative")
 	}
-	enc, err := buildEncoder(JSONEncoder) // file logs are always JSON
+	enc, err := p.tel.buildEncoder(p.encoder(), p.encoderConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -186,6 +325,16 @@ func (p *fileProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
 	return zapcore.NewCore(enc, syncer, level), nil
 }
 
+// encoder returns the file's encoder type, JSON by default.
+func (p *fileProvider) encoder() EncoderType {
+	if p.encoderType == "" {
+		return JSONEncoder
+	}
+	return p.encoderType
+}
+
+func (p *fileProvider) instrument(t *telemetry) { p.tel = t }
+
 /*
 	--------------------------------------------------------------
 	  close shuts down the lumberjack logger (if it was created),
@@ -212,32 +361,112 @@ type loggerConfig struct {
 	level     Level
 	// closers collects any provider that needs explicit shutdown.
 	closers []provider
+
+	errorOutput  io.Writer
+	errorHandler func(error)
+
+	sampling            *samplingConfig
+	dropHandler         func(DropReason, int)
+	dropSummaryInterval time.Duration
+	// raiseSignal and restoreSignal toggle verbosity; see WithLevelSignals.
+	raiseSignal   os.Signal
+	restoreSignal os.Signal
+
+	ringBufferSize int
+
+	deadLetter *lumberjack.Logger
+
+	routes     []route
+	filters    []FilterFunc
+	transforms []TransformFunc
+	// redactions run after the transforms; see WithRedaction.
+	redactions []RedactionRule
+	// maxEntrySize and oversizePolicy bound entries; see WithMaxEntrySize.
+	maxEntrySize   int
+	oversizePolicy OversizePolicy
+	duplicateKeys  DuplicateKeyPolicy
+	// withoutCaller omits the caller annotation; callerSkip adds frames to
+	// skip above golog's own.
+	withoutCaller bool
+	callerSkip    int
+	// callerFormat and callerFunction shape the caller annotation; see
+	// WithCallerFormat and WithCallerFunction.
+	callerFormat   CallerFormat
+	callerFunction bool
+	consoleBlocks  bool
+	// stacktrace is the level from which entries carry a stack trace; nil
+	// disables them.
+	stacktrace *Level
+	// timeFormat and timeZone set how timestamps are written; see
+	// WithTimeFormat and WithTimeZone.
+	timeFormat string
+	timeZone   *time.Location
+	// zapOptions are applied after golog's own; see WithZapOptions.
+	zapOptions []zap.Option
+	// development makes DPanic panic; see WithDevelopmentMode.
+	development   bool
+	sequenceKey   string
+	logID         bool
+	goroutineID   bool
+	enrichers     []Enricher
+	dynamicFields []dynamicFieldSet
+	schema        []byte
+
+	// providersAttachedLater suppresses the default stdout provider when the
+	// providers are attached after construction (NewFromConfig).
+	providersAttachedLater bool
+
+	// optionErrs collects invalid option values; NewLogger reports them.
+	optionErrs []error
+
+	preExitHooks []func(context.Context) error
+	// fatalFlushTimeout bounds the pre-exit sequence; exit ends the process.
+	fatalFlushTimeout time.Duration
+	exit              func(int)
+}
+
+// allProviders returns every provider the config owns, including routed ones.
+func (cfg *loggerConfig) allProviders() []provider {
+	all := append([]provider(nil), cfg.providers...)
+	for _, r := range cfg.routes {
+		all = append(all, r.providers...)
+	}
+	return all
 }
 
 func defaultProvider() provider {
 	// Choose the encoder you prefer for the default stdout logger.
 	// Here we use the console encoder for readability.
-	return stdOutProvider{encoderType: ConsoleEncoder}
+	return &stdOutProvider{encoderType: ConsoleEncoder}
 }
 
 // WithStdOutProvider adds a stdout destination.
-func WithStdOutProvider(encoderType EncoderType) LoggerOption {
+func WithStdOutProvider(encoderType EncoderType, options ...ProviderOption) LoggerOption {
 	return func(cfg *loggerConfig) {
-		cfg.providers = append(cfg.providers, stdOutProvider{encoderType: encoderType})
+		cfg.providers = append(cfg.providers, applyProviderOptions(&stdOutProvider{encoderType: encoderType}, options))
+	}
+}
+
+// WithStdErrProvider adds a stderr destination. See WithStdErrLevel for
+// splitting entries between stdout and stderr by level instead.
+func WithStdErrProvider(encoderType EncoderType, options ...ProviderOption) LoggerOption {
+	all := stderrAll
+	return func(cfg *loggerConfig) {
+		cfg.providers = append(cfg.providers, applyProviderOptions(&stdOutProvider{encoderType: encoderType, stderrFrom: &all}, options))
 	}
 }
 
 // WithWriterProvider adds a custom io.Writer destination.
-func WithWriterProvider(writer io.Writer, encoderType EncoderType) LoggerOption {
+func WithWriterProvider(writer io.Writer, encoderType EncoderType, options ...ProviderOption) LoggerOption {
 	return func(cfg *loggerConfig) {
-		cfg.providers = append(cfg.providers, writerProvider{writer: writer, encoderType: encoderType})
+		cfg.providers = append(cfg.providers, applyProviderOptions(&writerProvider{writer: writer, encoderType: encoderType}, options))
 	}
 }
 
 // WithGCPProvider adds Google Cloud Logging as a destination.
-func WithGCPProvider(projectID, logName string) LoggerOption {
+func WithGCPProvider(projectID, logName string, options ...ProviderOption) LoggerOption {
 	return func(cfg *loggerConfig) {
-		cfg.providers = append(cfg.providers, &gcpProvider{projectID: projectID, logName: logName})
+		cfg.providers = append(cfg.providers, applyProviderOptions(&gcpProvider{projectID: projectID, logName: logName}, options))
 	}
 }
 
@@ -250,17 +479,17 @@ func WithGCPProvider(projectID, logName string) LoggerOption {
 
 --------------------------------------------------------------
 */
-func WithFileProvider(filename string, maxSize, maxBackups, maxAge int, compress bool) LoggerOption {
+func WithFileProvider(filename string, maxSize, maxBackups, maxAge int, compress bool, options ...ProviderOption) LoggerOption {
 	return func(cfg *loggerConfig) {
 		// Store a pointer so the provider’s internal fields (e.g. the
 		// lumberjack logger) survive beyond the newCore call.
-		cfg.providers = append(cfg.providers, &fileProvider{
+		cfg.providers = append(cfg.providers, applyProviderOptions(&fileProvider{
 			filename:   filename,
 			maxSize:    maxSize,
 			maxBackups: maxBackups,
 			maxAge:     maxAge,
 			compress:   compress,
-		})
+		}, options))
 	}
 }
 
@@ -280,67 +509,273 @@ type Logger struct {
 	sugared   *zap.SugaredLogger
 	// keep a reference to the config so we can close providers later.
 	closers []provider
+	// telemetry receives internal failures and drop accounting.
+	telemetry *telemetry
+
+	// stop terminates background goroutines (drop summary, …); bg tracks them.
+	stop chan struct{}
+	bg   sync.WaitGroup
+
+	// providers describes the effective configuration.
+	providers []ProviderInfo
+	// ring retains recent entries when WithRingBuffer is set.
+	ring *ringBuffer
+	// levels gates entries by logger name; shared with children.
+	levels *levelTable
+	// added holds the providers attached by AddProvider or loaded from a
+	// config file.
+	added *addedProviders
+	// source is the config file the logger was built from, if any.
+	source *configSource
+	// options and config record how the logger was built, for CloneWith and
+	// Config. options is nil for loggers returned by Tee.
+	options []LoggerOption
+	config  Config
+	// root is the logger a child (named or With) was derived from, nil for roots.
+	root *Logger
+	// enriched is set when WithEnrichers was used, so the *Ctx methods pass
+	// their context on.
+	enriched bool
 
 	closeOnce sync.Once
 	closeErr  error
 }
 
+// NewNop returns a logger that discards every entry, for tests and for
+// libraries whose callers did not supply one. Like zap's, it still exits on
+// Fatal and panics on Panic. Providers attached with AddProvider receive
+// entries as usual.
+func NewNop() *Logger {
+	return newComposedLogger(nil, InfoLevel, nil, false)
+}
+
+// Must returns l, panicking if err is non-nil, for loggers built in package
+// variables and init code where a configuration error is fatal anyway:
+//
+//	var logger = golog.Must(golog.NewLogger(golog.WithLevel(golog.DebugLevel)))
+func Must(l *Logger, err error) *Logger {
+	if err != nil {
+		panic(fmt.Sprintf("golog: %v", err))
+	}
+	return l
+}
+
 // NewLogger builds a logger from the supplied functional options.
 func NewLogger(options ...LoggerOption) (*Logger, error) {
 	cfg := &loggerConfig{
 		providers: []provider{},
 		level:     InfoLevel, // default
+
+		fatalFlushTimeout: defaultFatalFlushTimeout,
+		exit:              os.Exit,
 	}
 
 	// Apply all supplied options.
 	for _, opt := range options {
 		opt(cfg)
 	}
+	if err := errors.Join(cfg.optionErrs...); err != nil {
+		return nil, fmt.Errorf("invalid option: %w", err)
+	}
 
 	// If the caller didn’t add any providers, fall back to stdout.
-	if len(cfg.providers) == 0 {
+	if len(cfg.providers) == 0 && len(cfg.routes) == 0 && !cfg.providersAttachedLater {
 		cfg.providers = append(cfg.providers, defaultProvider())
 	}
 	// ---------------------
 
-	var cores []zapcore.Core
+	tel := &telemetry{
+		errs:  newErrorSink(cfg.errorOutput, cfg.errorHandler),
+		drops: newDropCounter(cfg.dropHandler),
+		stats: &statsCollector{},
+	}
+	if cfg.timeFormat != "" {
+		tel.encoding = append(tel.encoding, timeFormatConfig(cfg.timeFormat))
+	}
+	if fn := callerConfig(cfg.callerFormat, cfg.callerFunction); fn != nil {
+		tel.encoding = append(tel.encoding, fn)
+	}
+	tel.callerFormat = cfg.callerFormat
+	tel.consoleBlocks = cfg.consoleBlocks
+	if cfg.deadLetter != nil {
+		tel.deadLetters = &deadLetterWriter{lj: cfg.deadLetter}
+	}
+
+	var (
+		cores     []zapcore.Core
+		providers []ProviderInfo
+		ring      *ringBuffer
+		levels    = newLevelTable(toZapLevel(cfg.level))
+	)
+	build := func(p provider) (zapcore.Core, error) {
+		core, info, _, err := newProviderCore(p, tel, levels)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, info)
+		// Track providers that need explicit shutdown.
+		cfg.closers = append(cfg.closers, p)
+		return core, nil
+	}
 	for _, p := range cfg.providers {
-		core, err := p.newCore(toZapLevel(cfg.level))
+		core, err := build(p)
 		if err != nil {
 			// Clean up any providers that were already initialised.
-			_ = closeProviders(cfg.providers)
-			return nil, fmt.Errorf("failed to initialise provider: %w", err)
+			_ = closeProviders(cfg.allProviders())
+			return nil, err
 		}
 		cores = append(cores, core)
-		// Track providers that need explicit shutdown.
-		cfg.closers = append(cfg.closers, p)
+	}
+	// Providers attached later, including those from a config file, are
+	// regular providers, so exclusive routes withhold entries from them too.
+	added := &addedProviders{tel: tel, levels: levels}
+	cores = append(cores, &extensionCore{added: added})
+	if len(cfg.routes) > 0 {
+		router, err := newRouterCore(cores, cfg.routes, build)
+		if err != nil {
+			_ = closeProviders(cfg.allProviders())
+			return nil, err
+		}
+		cores = []zapcore.Core{router}
 	}
 
+	if cfg.ringBufferSize > 0 {
+		ring = newRingBuffer(cfg.ringBufferSize)
+		cores = append(cores, &gateCore{Core: &ringCore{LevelEnabler: lowestLevel, buf: ring}, levels: levels})
+	}
+
+	if cfg.schema != nil {
+		validator, err := newSchemaCore(cores, cfg.schema, tel.errs.report)
+		if err != nil {
+			_ = closeProviders(cfg.allProviders())
+			return nil, err
+		}
+		cores = []zapcore.Core{validator}
+	}
+	if cfg.sequenceKey != "" {
+		cores = []zapcore.Core{newSequenceCore(cores, cfg.sequenceKey)}
+	}
+	if cfg.logID {
+		cores = []zapcore.Core{newLogIDCore(cores)}
+	}
+	if cfg.goroutineID {
+		cores = []zapcore.Core{newGoroutineIDCore(cores)}
+	}
+	if len(cfg.dynamicFields) > 0 {
+		cores = []zapcore.Core{newDynamicCore(cores, cfg.dynamicFields)}
+	}
+	transforms := cfg.transforms
+	if len(cfg.redactions) > 0 {
+		transforms = append(transforms[:len(transforms):len(transforms)], Redact(cfg.redactions...))
+	}
+	if cfg.maxEntrySize > 0 {
+		cores = []zapcore.Core{newSizeCore(cores, cfg.maxEntrySize, cfg.oversizePolicy, tel.drops)}
+	}
+	if len(transforms) > 0 {
+		cores = []zapcore.Core{&transformCore{cores: cores, fns: transforms}}
+	}
+	if cfg.duplicateKeys != DuplicateKeysKeep {
+		cores = []zapcore.Core{&dedupCore{cores: cores, policy: cfg.duplicateKeys}}
+	}
+	if len(cfg.filters) > 0 {
+		cores = []zapcore.Core{&filterCore{cores: cores, filters: cfg.filters, drops: tel.drops}}
+	}
+	if len(cfg.enrichers) > 0 {
+		cores = []zapcore.Core{&enrichCore{cores: cores, enrichers: cfg.enrichers}}
+	}
 	teeCore := zapcore.NewTee(cores...)
-	zapLogger := zap.New(teeCore, zap.AddCaller())
+	if sc := cfg.sampling; sc != nil {
+		teeCore = zapcore.NewSamplerWithOptions(teeCore, sc.tick, sc.first, sc.thereafter, samplerHook(tel.drops))
+	}
+	onFatal := &fatalHook{hooks: cfg.preExitHooks, timeout: cfg.fatalFlushTimeout, exit: cfg.exit}
+	zapOpts := []zap.Option{zap.ErrorOutput(tel.errs), zap.Hooks(tel.stats.countEntry), zap.WithFatalHook(onFatal)}
+	if !cfg.withoutCaller {
+		zapOpts = append(zapOpts, zap.AddCaller(), zap.AddCallerSkip(wrapperCallerSkip+cfg.callerSkip))
+	}
+	if cfg.stacktrace != nil {
+		zapOpts = append(zapOpts, zap.AddStacktrace(toZapLevel(*cfg.stacktrace)))
+	}
+	if cfg.development {
+		zapOpts = append(zapOpts, zap.Development())
+	}
+	if cfg.timeZone != nil {
+		zapOpts = append(zapOpts, zap.WithClock(zoneClock{cfg.timeZone}))
+	}
+	zapOpts = append(zapOpts, cfg.zapOptions...)
+	zapLogger := zap.New(teeCore, zapOpts...)
 	s := zapLogger.Sugar()
 
-	return &Logger{
+	l := &Logger{
 		zapLogger: zapLogger,
 		sugared:   s,
 		closers:   cfg.closers,
-	}, nil
+		telemetry: tel,
+		stop:      make(chan struct{}),
+		providers: providers,
+		ring:      ring,
+		levels:    levels,
+		added:     added,
+		enriched:  len(cfg.enrichers) > 0,
+		options:   append([]LoggerOption{}, options...),
+		config:    cfg.snapshot(),
+	}
+	onFatal.logger = l
+	if cfg.dropSummaryInterval > 0 {
+		l.bg.Add(1)
+		go l.runDropSummary(cfg.dropSummaryInterval)
+	}
+	if cfg.raiseSignal != nil {
+		l.watchLevelSignals(cfg.raiseSignal, cfg.restoreSignal)
+	}
+	return l, nil
 }
 
-// Close flushes the zap logger and shuts down any provider resources.
+// newProviderCore builds the core for p: instrumented with tel, counted in
+// the stats and, unless p bounds its own levels, gated by levels. The
+// counters are returned for removal once p is detached.
+func newProviderCore(p provider, tel *telemetry, levels *levelTable) (zapcore.Core, ProviderInfo, *providerCounters, error) {
+	if ip, ok := p.(instrumentedProvider); ok {
+		ip.instrument(tel)
+	}
+	core, err := p.newCore(lowestLevel)
+	if err != nil {
+		return nil, ProviderInfo{}, nil, fmt.Errorf("failed to initialise provider: %w", err)
+	}
+	info := describeProvider(p)
+	counters := tel.stats.addProvider(info.Name)
+	core = &countingCore{Core: core, counters: counters}
+	if info.ownsLevel {
+		return core, info, counters, nil
+	}
+	return &gateCore{Core: core, levels: levels}, info, counters, nil
+}
+
+// Close flushes the zap logger and shuts down any provider resources. For
+// children (GetLogger, With) it only flushes; the root owns the
+// providers.
 func (l *Logger) Close() error {
+	if l.root != nil {
+		return l.Sync()
+	}
 	l.closeOnce.Do(func() {
 		if l.zapLogger == nil {
 			return
 		}
+		if l.stop != nil {
+			close(l.stop)
+			l.bg.Wait()
+		}
 
 		// zap.Logger.Sync() can return benign errors on stdout/stderr (e.g. ENOTTY).
 		if err := ignoreSyncError(l.zapLogger.Sync()); err != nil {
 			l.closeErr = fmt.Errorf("zap sync error: %w", err)
 		}
-		if err := closeProviders(l.closers); err != nil && l.closeErr == nil {
+		if err := closeProviders(append(l.closers, l.added.detach()...)); err != nil && l.closeErr == nil {
 			l.closeErr = err
 		}
+		if err := l.telemetry.deadLetters.close(); err != nil && l.closeErr == nil {
+			l.closeErr = fmt.Errorf("dead letter close error: %w", err)
+		}
 
 		// Release references so subsequent Close calls are cheap and don't attempt to close again.
 		l.closers = nil
@@ -356,6 +791,34 @@ func (l *Logger) Sync() error {
 	return ignoreSyncError(l.zapLogger.Sync())
 }
 
+// Log logs at level, for wrappers and adapters that choose the level at run
+// time. Levels above Error behave as their methods do: Fatal exits.
+func (l *Logger) Log(level Level, msg string, fields ...Field) {
+	if ce := l.zapLogger.Check(toZapLevel(level), msg); ce != nil {
+		ce.Write(toZapFields(fields)...)
+	}
+}
+
+// Enabled reports whether entries at level reach at least one provider, so
+// callers can skip building expensive fields:
+//
+//	if logger.Enabled(golog.DebugLevel) {
+//		logger.Debug("request", golog.Any("dump", dump(req)))
+//	}
+//
+// It errs towards true: a named level override, filter or sampler may still
+// drop the entry.
+func (l *Logger) Enabled(level Level) bool {
+	return l.zapLogger.Core().Enabled(toZapLevel(level))
+}
+
+// Trace logs at Trace level.
+func (l *Logger) Trace(msg string, fields ...Field) {
+	if ce := l.zapLogger.Check(traceZapLevel, msg); ce != nil {
+		ce.Write(toZapFields(fields)...)
+	}
+}
+
 // Debug logs at Debug level.
 func (l *Logger) Debug(msg string, fields ...Field) {
 	l.zapLogger.Debug(msg, toZapFields(fields)...)
@@ -376,11 +839,28 @@ func (l *Logger) Error(msg string, fields ...Field) {
 	l.zapLogger.Error(msg, toZapFields(fields)...)
 }
 
+// DPanic logs at zap's DPanic level, between Error and Fatal, and then
+// panics in development mode only – for "can't happen" conditions that
+// should crash local runs but not production.
+func (l *Logger) DPanic(msg string, fields ...Field) {
+	l.zapLogger.DPanic(msg, toZapFields(fields)...)
+}
+
+// Panic logs at zap's Panic level and then panics with msg. Entry.Level and
+// Stats count DPanic and Panic entries as Error.
+func (l *Logger) Panic(msg string, fields ...Field) {
+	l.zapLogger.Panic(msg, toZapFields(fields)...)
+}
+
 // Fatal logs at Fatal level and then exits the process.
 func (l *Logger) Fatal(msg string, fields ...Field) {
 	l.zapLogger.Fatal(msg, toZapFields(fields)...)
 }
 
+func (l *Logger) Tracef(format string, args ...interface{}) {
+	l.sugared.Logf(traceZapLevel, format, args...)
+}
+
 func (l *Logger) Debugf(format string, args ...interface{}) {
 	l.sugared.Debugf(format, args...)
 }
@@ -397,10 +877,22 @@ func (l *Logger) Errorf(format string, args ...interface{}) {
 	l.sugared.Errorf(format, args...)
 }
 
+func (l *Logger) DPanicf(format string, args ...interface{}) {
+	l.sugared.DPanicf(format, args...)
+}
+
+func (l *Logger) Panicf(format string, args ...interface{}) {
+	l.sugared.Panicf(format, args...)
+}
+
 func (l *Logger) Fatalf(format string, args ...interface{}) {
 	l.sugared.Fatalf(format, args...)
 }
 
+func (l *Logger) Tracew(msg string, keysAndValues ...interface{}) {
+	l.sugared.Logw(traceZapLevel, msg, keysAndValues...)
+}
+
 func (l *Logger) Debugw(msg string, keysAndValues ...interface{}) {
 	l.sugared.Debugw(msg, keysAndValues...)
 }
@@ -417,6 +909,14 @@ func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) {
 	l.sugared.Errorw(msg, keysAndValues...)
 }
 
+func (l *Logger) DPanicw(msg string, keysAndValues ...interface{}) {
+	l.sugared.DPanicw(msg, keysAndValues...)
+}
+
+func (l *Logger) Panicw(msg string, keysAndValues ...interface{}) {
+	l.sugared.Panicw(msg, keysAndValues...)
+}
+
 func (l *Logger) Fatalw(msg string, keysAndValues ...interface{}) {
 	l.sugared.Fatalw(msg, keysAndValues...)
 }
@@ -440,11 +940,46 @@ func Duration(key string, value time.Duration) Field {
 }
 func Any(key string, value interface{}) Field { return Field{Key: key, Value: value} }
 
+// Typed helpers encode without going through Any's reflection.
+func Bool(key string, value bool) Field             { return Field{Key: key, Value: value} }
+func Int64(key string, value int64) Field           { return Field{Key: key, Value: value} }
+func Uint64(key string, value uint64) Field         { return Field{Key: key, Value: value} }
+func Time(key string, value time.Time) Field        { return Field{Key: key, Value: value} }
+func Strings(key string, value []string) Field      { return Field{Key: key, Value: value} }
+func Ints(key string, value []int) Field            { return Field{Key: key, Value: value} }
+func Binary(key string, value []byte) Field         { return Field{Key: key, Value: value} }
+func ByteString(key string, value []byte) Field     { return Field{Key: key, Value: byteString(value)} }
+func Stringer(key string, value fmt.Stringer) Field { return Field{Key: key, Value: stringer{value}} }
+
+// Namespace nests the fields that follow it, including those of the entry
+// when given to With, under key:
+//
+//	logger.With(golog.Namespace("http")).Info("served", golog.Int("status", 200))
+//	// {"msg":"served","http":{"status":200}}
+func Namespace(key string) Field { return Field{Key: key, Value: namespace{}} }
+
+// Dict groups fields under key, e.g. "http":{"method":"GET","status":200}.
+func Dict(key string, fields ...Field) Field { return Field{Key: key, Value: dict(fields)} }
+
+// byteString marks UTF-8 bytes logged as text rather than base64, stringer a
+// value whose String method runs only if the entry is written, namespace the
+// start of a nested object and dict a nested object.
+type (
+	byteString []byte
+	stringer   struct{ fmt.Stringer }
+	namespace  struct{}
+	dict       []Field
+)
+
 // Convert our custom Field slice into zapcore.Fields.
 func toZapFields(fields []Field) []zapcore.Field {
 	zapFields := make([]zapcore.Field, len(fields))
 	for i, f := range fields {
 		switch v := f.Value.(type) {
+		case zapcore.ObjectMarshaler:
+			zapFields[i] = zap.Object(f.Key, v)
+		case zapcore.ArrayMarshaler:
+			zapFields[i] = zap.Array(f.Key, v)
 		case string:
 			zapFields[i] = zap.String(f.Key, v)
 		case int:
@@ -452,16 +987,68 @@ func toZapFields(fields []Field) []zapcore.Field {
 		case float64:
 			zapFields[i] = zap.Float64(f.Key, v)
 		case error:
-			zapFields[i] = zap.Error(v)
+			zapFields[i] = errorField(v)
+		case LogFielder:
+			zapFields[i] = zap.Dict(f.Key, toZapFields(v.LogFields())...)
 		case time.Duration:
 			zapFields[i] = zap.Duration(f.Key, v)
+		case bool:
+			zapFields[i] = zap.Bool(f.Key, v)
+		case int64:
+			zapFields[i] = zap.Int64(f.Key, v)
+		case uint64:
+			zapFields[i] = zap.Uint64(f.Key, v)
+		case time.Time:
+			zapFields[i] = zap.Time(f.Key, v)
+		case []string:
+			zapFields[i] = zap.Strings(f.Key, v)
+		case []int:
+			zapFields[i] = zap.Ints(f.Key, v)
+		case []byte:
+			zapFields[i] = zap.Binary(f.Key, v)
+		case byteString:
+			zapFields[i] = zap.ByteString(f.Key, v)
+		case stringer:
+			zapFields[i] = zap.Stringer(f.Key, v.Stringer)
+		case namespace:
+			zapFields[i] = zap.Namespace(f.Key)
+		case dict:
+			zapFields[i] = zap.Dict(f.Key, toZapFields(v)...)
+		case lazy:
+			zapFields[i] = newLazyField(f.Key, v)
+		case redacted:
+			zapFields[i] = zap.String(f.Key, string(v))
 		default:
-			zapFields[i] = zap.Any(f.Key, v)
+			if zf, ok := maskedField(f.Key, v); ok {
+				zapFields[i] = zf
+			} else {
+				zapFields[i] = zap.Any(f.Key, v)
+			}
 		}
 	}
 	return zapFields
 }
 
+// fromZapFields is the inverse of toZapFields. Values take the shape zap's
+// map encoder gives them (e.g. ints become int64, errors their message).
+func fromZapFields(fields []zapcore.Field) []Field {
+	out := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		if v, ok := enc.Fields[f.Key]; ok && len(enc.Fields) == 1 {
+			out = append(out, Field{Key: f.Key, Value: v})
+			continue
+		}
+		// Fields such as namespaces and errors with LogFields encode under
+		// other or several keys.
+		for k, v := range enc.Fields {
+			out = append(out, Field{Key: k, Value: v})
+		}
+	}
+	return out
+}
+
 /* -------------------------------------------------------------------------- */
 /*                         Level Conversion Helpers                            */
 /* -------------------------------------------------------------------------- */
@@ -479,26 +1066,75 @@ func toZapLevel(lvl Level) zapcore.Level {
 	case FatalLevel:
 		return zapcore.FatalLevel
 	default:
+		if lvl < DebugLevel {
+			// Trace and custom levels continue below zap's Debug.
+			if lvl <= Level(math.MinInt8)+1 {
+				return zapcore.Level(math.MinInt8)
+			}
+			return zapcore.Level(lvl - 1)
+		}
 		// Gracefully fall back – callers get a sensible default.
 		return zapcore.InfoLevel
 	}
 }
 
+func fromZapLevel(lvl zapcore.Level) Level {
+	switch {
+	case lvl < zapcore.DebugLevel:
+		return Level(lvl + 1)
+	case lvl == zapcore.DebugLevel:
+		return DebugLevel
+	case lvl == zapcore.InfoLevel:
+		return InfoLevel
+	case lvl == zapcore.WarnLevel:
+		return WarnLevel
+	case lvl < zapcore.FatalLevel:
+		// DPanic and Panic have no golog equivalent; report them as errors.
+		return ErrorLevel
+	default:
+		return FatalLevel
+	}
+}
+
 /* -------------------------------------------------------------------------- */
 /*                     Encoder Construction Utility                             */
 /* -------------------------------------------------------------------------- */
 
-func buildEncoder(t EncoderType) (zapcore.Encoder, error) {
+// buildEncoder returns the encoder for t. configure adjusts the encoder
+// config of the zap-based encoders (JSON, console and their presets); the
+// encoders with a layout of their own ignore it.
+func buildEncoder(t EncoderType, configure ...func(*zapcore.EncoderConfig)) (zapcore.Encoder, error) {
 	encCfg := zap.NewProductionEncoderConfig()
 	// Show durations as human‑readable strings (e.g. “5ms”) instead of a float.
 	encCfg.EncodeDuration = zapcore.StringDurationEncoder
+	encCfg.EncodeLevel = lowercaseLevelEncoder
+	switch t {
+	case colorConsoleEncoder:
+		encCfg.EncodeLevel = capitalColorLevelEncoder
+	case kubernetesEncoder:
+		encCfg = kubernetesEncoderConfig()
+	case developmentEncoder, developmentColorEncoder:
+		encCfg = developmentEncoderConfig(t == developmentColorEncoder)
+	}
+	for _, fn := range configure {
+		fn(&encCfg)
+	}
 
 	switch t {
-	case ConsoleEncoder:
-		return zapcore.NewConsoleEncoder(encCfg), nil
-	case JSONEncoder:
+	case JSONEncoder, kubernetesEncoder:
 		return zapcore.NewJSONEncoder(encCfg), nil
+	case ConsoleEncoder, colorConsoleEncoder, developmentEncoder, developmentColorEncoder:
+		return zapcore.NewConsoleEncoder(encCfg), nil
+	case XMLEncoder:
+		return newXMLEncoder(), nil
+	case PrettyEncoder, PrettyColorEncoder:
+		return newPrettyEncoder(t == PrettyColorEncoder), nil
+	case MsgpackEncoder, ProtobufEncoder:
+		return &binaryEncoder{treeEncoder: newTreeEncoder(), protobuf: t == ProtobufEncoder}, nil
 	default:
+		if enc, ok := newDelimitedEncoder(t); ok {
+			return enc, nil
+		}
 		// Unknown encoder – default to JSON and surface a clear error for the caller.
 		return zapcore.NewJSONEncoder(encCfg), fmt.Errorf("unsupported encoder type %q, falling back to JSON", t)
 	}
@@ -512,6 +1148,8 @@ type gcpZapCore struct {
 	logger *logging.Logger
 	level  zapcore.Level
 	fields map[string]interface{}
+	// callerFormat CallerModule reports module-relative source files.
+	callerFormat CallerFormat
 }
 
 func (c *gcpZapCore) Enabled(lvl zapcore.Level) bool { return lvl >= c.level }
@@ -552,8 +1190,14 @@ func (c *gcpZapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
 		payload[k] = v
 	}
 	payload["message"] = ent.Message
+	if ent.Stack != "" {
+		payload["stack_trace"] = ent.Stack
+	}
 	if ent.Caller.Defined {
 		payload["source_file"] = ent.Caller.File
+		if c.callerFormat == CallerModule {
+			payload["source_file"] = moduleRelativeFile(ent.Caller)
+		}
 		payload["source_line"] = ent.Caller.Line
 		payload["source_function"] = ent.Caller.Function
 	}
@@ -569,6 +1213,9 @@ func (c *gcpZapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
 func (c *gcpZapCore) Sync() error { return c.logger.Flush() }
 
 func levelToSeverity(lvl zapcore.Level) logging.Severity {
+	if lvl < zapcore.DebugLevel {
+		return logging.Debug
+	}
 	switch lvl {
 	case zapcore.DebugLevel:
 		return logging.Debug
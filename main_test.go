@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -230,6 +231,15 @@ func TestFieldHelpers_JSONOutput(t *testing.T) {
 		Float64("f", 3.14),
 		Duration("d", 5*time.Millisecond),
 		Any("any", map[string]string{"k": "v"}),
+		Bool("b", true),
+		Int64("i64", -1<<40),
+		Uint64("u64", 1<<63),
+		Time("t", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+		Strings("ss", []string{"a", "b"}),
+		Ints("is", []int{1, 2}),
+		Binary("bin", []byte("hi")),
+		ByteString("bs", []byte("text")),
+		Stringer("str", net.IPv4(10, 0, 0, 1)),
 	)
 
 	out := buf.String()
@@ -239,6 +249,15 @@ func TestFieldHelpers_JSONOutput(t *testing.T) {
 		`"f":3.14`,
 		`"d":"5ms"`,
 		`"any":{"k":"v"}`,
+		`"b":true`,
+		`"i64":-1099511627776`,
+		`"u64":9223372036854775808`,
+		`"t":1704164645`,
+		`"ss":["a","b"]`,
+		`"is":[1,2]`,
+		`"bin":"aGk="`,
+		`"bs":"text"`,
+		`"str":"10.0.0.1"`,
 	}
 	for _, exp := range expected {
 		if !strings.Contains(out, exp) {
@@ -247,6 +266,25 @@ func TestFieldHelpers_JSONOutput(t *testing.T) {
 	}
 }
 
+func TestNamespaceAndDict(t *testing.T) {
+	logger, buf := newBufferLogger(t, InfoLevel)
+	defer logger.Close()
+
+	logger.Info("dict", Dict("http", String("method", "GET"), Int("status", 200)), String("after", "top"))
+	logger.With(String("svc", "api"), Namespace("req")).Info("namespace", Int("status", 404))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", lines)
+	}
+	if !strings.Contains(lines[0], `"http":{"method":"GET","status":200},"after":"top"`) {
+		t.Errorf("unexpected dict output: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"svc":"api","req":{"status":404}`) {
+		t.Errorf("unexpected namespace output: %s", lines[1])
+	}
+}
+
 /*
 TestSugarMethods validates every *non‑fatal* sugar wrapper:
 
@@ -465,7 +503,7 @@ func TestFileProvider_InvalidRotationParams(t *testing.T) {
 }
 
 func TestBuildEncoder_UnsupportedFallback(t *testing.T) {
-	enc, err := buildEncoder(EncoderType("xml")) // deliberately unsupported
+	enc, err := buildEncoder(EncoderType("yaml")) // deliberately unsupported
 	if err == nil {
 		t.Fatalf("expected error for unknown encoder")
 	}
@@ -517,3 +555,137 @@ func TestIgnoreSyncError(t *testing.T) {
 		t.Fatalf("non-ignorable errors should be returned")
 	}
 }
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk on fire") }
+
+func TestLogger_ErrorHandlerReceivesWriteFailures(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen []error
+	)
+	var out bytes.Buffer
+	logger, err := NewLogger(
+		WithWriterProvider(failingWriter{}, JSONEncoder),
+		WithErrorOutput(&out),
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			seen = append(seen, err)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("will not be written")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != 1 || !strings.Contains(seen[0].Error(), "disk on fire") {
+		t.Fatalf("expected write failure to reach handler, got %v", seen)
+	}
+	if !strings.Contains(out.String(), "disk on fire") {
+		t.Fatalf("expected write failure in error output, got %q", out.String())
+	}
+}
+
+func TestPanicMethods(t *testing.T) {
+	mustPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s did not panic", name)
+			}
+		}()
+		fn()
+	}
+
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.DPanic("unexpected state", String("k", "v"))
+	logger.DPanicf("unexpected %s", "state")
+	logger.DPanicw("unexpected state", "k", "v")
+	mustPanic("Panic", func() { logger.Panic("broken invariant") })
+	mustPanic("Panicf", func() { logger.Panicf("broken %s", "invariant") })
+	mustPanic("Panicw", func() { logger.Panicw("broken invariant", "k", "v") })
+
+	out := buf.String()
+	if n := strings.Count(out, `"level":"dpanic"`); n != 3 {
+		t.Errorf("expected 3 dpanic entries, got %d: %s", n, out)
+	}
+	if n := strings.Count(out, `"level":"panic"`); n != 3 {
+		t.Errorf("expected 3 panic entries, got %d: %s", n, out)
+	}
+	if got := logger.Stats().Entries[ErrorLevel]; got != 6 {
+		t.Errorf("Stats counted %d errors, want 6", got)
+	}
+
+	dev, err := NewLogger(WithWriterProvider(io.Discard, JSONEncoder), func(cfg *loggerConfig) { cfg.development = true })
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer dev.Close()
+	mustPanic("DPanic in development mode", func() { dev.DPanic("unexpected state") })
+}
+
+func TestLogAndEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithLevel(InfoLevel))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	if logger.Enabled(DebugLevel) || !logger.Enabled(InfoLevel) || !logger.Enabled(ErrorLevel) {
+		t.Errorf("Enabled does not follow the logger level")
+	}
+	logger.Log(DebugLevel, "hidden")
+	logger.Log(WarnLevel, "dynamic", String("k", "v"))
+	out := buf.String()
+	if strings.Contains(out, "hidden") {
+		t.Errorf("disabled level logged: %s", out)
+	}
+	if !strings.Contains(out, `"level":"warn"`) || !strings.Contains(out, `"k":"v"`) {
+		t.Errorf("missing dynamic entry: %s", out)
+	}
+
+	logger.SetLevel(DebugLevel)
+	if !logger.Enabled(DebugLevel) {
+		t.Error("Enabled ignores SetLevel")
+	}
+}
+
+func TestNewNopAndMust(t *testing.T) {
+	nop := NewNop()
+	nop.Info("discarded", String("k", "v"))
+	nop.Named("child").Errorw("discarded")
+	if nop.Enabled(InfoLevel) {
+		t.Error("nop logger reports Info enabled")
+	}
+	if err := nop.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := Must(NewLogger(WithWriterProvider(&buf, JSONEncoder)))
+	logger.Info("kept")
+	logger.Close()
+	if !strings.Contains(buf.String(), "kept") {
+		t.Errorf("Must returned a different logger: %s", buf.String())
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "WithExitFunc") {
+			t.Errorf("Must should panic with the error, got %v", r)
+		}
+	}()
+	Must(NewLogger(WithExitFunc(nil)))
+}
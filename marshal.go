@@ -0,0 +1,35 @@
+package golog
+
+import "go.uber.org/zap/zapcore"
+
+/* -------------------------------------------------------------------------- */
+/*                         Self-Encoding Field Values                          */
+/* -------------------------------------------------------------------------- */
+
+// ObjectMarshaler and ArrayMarshaler let domain types encode themselves
+// without reflection. They are zap's interfaces, so types already written
+// for zap work unchanged:
+//
+//	func (u user) MarshalLogObject(enc golog.ObjectEncoder) error {
+//		enc.AddString("name", u.Name)
+//		enc.AddInt("age", u.Age)
+//		return nil
+//	}
+//
+//	logger.Info("signed up", golog.Object("user", u))
+//
+// Any detects them too.
+type (
+	ObjectMarshaler     = zapcore.ObjectMarshaler
+	ArrayMarshaler      = zapcore.ArrayMarshaler
+	ObjectEncoder       = zapcore.ObjectEncoder
+	ArrayEncoder        = zapcore.ArrayEncoder
+	ObjectMarshalerFunc = zapcore.ObjectMarshalerFunc
+	ArrayMarshalerFunc  = zapcore.ArrayMarshalerFunc
+)
+
+// Object logs v as a nested object encoded by its MarshalLogObject method.
+func Object(key string, v ObjectMarshaler) Field { return Field{Key: key, Value: v} }
+
+// Array logs v as an array encoded by its MarshalLogArray method.
+func Array(key string, v ArrayMarshaler) Field { return Field{Key: key, Value: v} }
@@ -0,0 +1,52 @@
+package golog
+
+import (
+	"strings"
+	"testing"
+)
+
+type testUser struct {
+	Name string
+	Age  int
+}
+
+func (u testUser) MarshalLogObject(enc ObjectEncoder) error {
+	enc.AddString("name", u.Name)
+	enc.AddInt("age", u.Age)
+	return nil
+}
+
+type testUsers []testUser
+
+func (us testUsers) MarshalLogArray(enc ArrayEncoder) error {
+	for _, u := range us {
+		if err := enc.AppendObject(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestObjectAndArray(t *testing.T) {
+	logger, buf := newBufferLogger(t, InfoLevel)
+	defer logger.Close()
+
+	alice := testUser{Name: "alice", Age: 30}
+	logger.Info("object", Object("user", alice), Any("any", alice))
+	logger.Info("array", Array("users", testUsers{alice}), Any("tags", ArrayMarshalerFunc(func(enc ArrayEncoder) error {
+		enc.AppendString("a")
+		return nil
+	})))
+
+	out := buf.String()
+	for _, want := range []string{
+		`"user":{"name":"alice","age":30}`,
+		`"any":{"name":"alice","age":30}`,
+		`"users":[{"name":"alice","age":30}]`,
+		`"tags":["a"]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in %s", want, out)
+		}
+	}
+}
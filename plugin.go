@@ -0,0 +1,137 @@
+package golog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                       Out-of-Process Provider Plugins                       */
+/* -------------------------------------------------------------------------- */
+
+// pluginShutdownTimeout bounds how long close waits for a plugin to drain
+// its stdin and exit before killing it.
+const pluginShutdownTimeout = 5 * time.Second
+
+// WithPluginProvider runs command with args as a separate process and
+// streams entries to its stdin as JSON lines, one entry per line in the same
+// format the file provider writes; WithProviderEncoder selects another
+// encoder. This keeps exotic or license-encumbered sink SDKs out of the main
+// binary:
+//
+//	golog.WithPluginProvider("/usr/libexec/golog-kafka", []string{"--topic", "logs"})
+//
+// The protocol is intentionally minimal: the plugin reads stdin until EOF,
+// which signals shutdown, then exits. Each line it writes to stderr is
+// reported as an internal error (see WithErrorHandler); stdout is ignored.
+// Writes fail once the plugin has exited.
+func WithPluginProvider(command string, args []string, options ...ProviderOption) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.providers = append(cfg.providers, applyProviderOptions(&pluginProvider{
+			providerBase: providerBase{name: "plugin:" + filepath.Base(command), encoders: anyEncoder},
+			command:      command,
+			args:         args,
+		}, options))
+	}
+}
+
+type pluginProvider struct {
+	providerBase
+	command string
+	args    []string
+
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	stderr    sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (p *pluginProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	enc, err := p.tel.buildEncoder(p.encoder(), p.encoderConfig)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(p.command, p.args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.command, err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.command, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", p.command, err)
+	}
+	p.cmd, p.stdin = cmd, stdin
+
+	p.stderr.Add(1)
+	go func() {
+		defer p.stderr.Done()
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			p.report(errors.New(scanner.Text()))
+		}
+	}()
+	return zapcore.NewCore(enc, zapcore.AddSync(stdin), level), nil
+}
+
+// close signals EOF, waits for the plugin to exit and kills it if it does
+// not do so in time.
+func (p *pluginProvider) close() error {
+	p.closeOnce.Do(func() {
+		if p.cmd == nil {
+			return
+		}
+		p.stdin.Close()
+		done := make(chan error, 1)
+		go func() {
+			// Drain stderr before Wait, which closes the pipe.
+			p.stderr.Wait()
+			done <- p.cmd.Wait()
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				p.closeErr = fmt.Errorf("plugin %s: %w", p.command, err)
+			}
+		case <-time.After(pluginShutdownTimeout):
+			p.closeErr = errors.Join(
+				fmt.Errorf("plugin %s: did not exit within %s", p.command, pluginShutdownTimeout),
+				p.cmd.Process.Kill(),
+			)
+			<-done
+		}
+	})
+	return p.closeErr
+}
+
+func (p *pluginProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: p.encoder()}
+}
+
+func init() {
+	RegisterProviderFactory("plugin", knownParams(func(params map[string]any) (LoggerOption, error) {
+		command, err := paramString(params, "command", "")
+		if err != nil {
+			return nil, err
+		}
+		if command == "" {
+			return nil, errors.New("command is required")
+		}
+		args, err := paramStringList(params, "args")
+		if err != nil {
+			return nil, err
+		}
+		return WithPluginProvider(command, args), nil
+	}))
+}
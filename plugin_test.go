@@ -0,0 +1,99 @@
+package golog
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestPluginHelperProcess is not a real test: it is the plugin executed by
+// TestPluginProvider. It copies stdin to $GOLOG_PLUGIN_OUT and echoes a
+// complaint to stderr.
+func TestPluginHelperProcess(t *testing.T) {
+	out := os.Getenv("GOLOG_PLUGIN_OUT")
+	if out == "" {
+		return
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		os.Exit(2)
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		fmt.Fprintln(f, scanner.Text())
+	}
+	fmt.Fprintln(os.Stderr, "sink unavailable")
+	f.Close()
+	os.Exit(0)
+}
+
+func TestPluginProvider(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "plugin.out")
+	t.Setenv("GOLOG_PLUGIN_OUT", out)
+
+	var errs concurrentBuffer
+	logger, err := NewLogger(
+		WithPluginProvider(os.Args[0], []string{"-test.run=^TestPluginHelperProcess$"}),
+		WithErrorOutput(&errs),
+		WithTimeFormat(TimeEpochMillis),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("to the plugin", String("k", "v"))
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), `"msg":"to the plugin"`) || !strings.Contains(string(got), `"k":"v"`) {
+		t.Errorf("unexpected plugin input: %s", got)
+	}
+	// The logger-wide encoder settings apply to the plugin's input too.
+	if !regexp.MustCompile(`"ts":\d+,`).Match(got) {
+		t.Errorf("plugin input ignores WithTimeFormat: %s", got)
+	}
+	if !strings.Contains(errs.String(), "sink unavailable") {
+		t.Errorf("plugin stderr not reported: %q", errs.String())
+	}
+}
+
+func TestPluginProvider_Options(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "plugin.out")
+	t.Setenv("GOLOG_PLUGIN_OUT", out)
+
+	logger, err := NewLogger(
+		WithPluginProvider(os.Args[0], []string{"-test.run=^TestPluginHelperProcess$"},
+			WithProviderLevel(WarnLevel), WithProviderEncoder(ConsoleEncoder)),
+		WithErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("too quiet")
+	logger.Warn("to the plugin")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(got), "too quiet") || !strings.Contains(string(got), "to the plugin") || strings.Contains(string(got), `"msg"`) {
+		t.Errorf("plugin input ignores the provider options: %s", got)
+	}
+}
+
+func TestPluginProvider_MissingCommand(t *testing.T) {
+	if _, err := NewLogger(WithPluginProvider(filepath.Join(t.TempDir(), "missing"), nil)); err == nil {
+		t.Error("expected error for missing plugin binary")
+	}
+}
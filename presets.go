@@ -0,0 +1,208 @@
+package golog
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evdnx/golog/internal/term"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                                  Presets                                    */
+/* -------------------------------------------------------------------------- */
+
+// kubernetesEncoder is JSON with the keys kubelet log agents (Fluent Bit,
+// the GKE/Stackdriver agent, …) recognise natively.
+const kubernetesEncoder EncoderType = "kubernetes"
+
+func kubernetesEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LevelKey:       "severity",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    severityLevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// severityLevelEncoder writes Cloud Logging severity names.
+func severityLevelEncoder(lvl zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if lvl < zapcore.DebugLevel {
+		lvl = zapcore.DebugLevel
+	}
+	switch lvl {
+	case zapcore.DebugLevel:
+		enc.AppendString("DEBUG")
+	case zapcore.InfoLevel:
+		enc.AppendString("INFO")
+	case zapcore.WarnLevel:
+		enc.AppendString("WARNING")
+	case zapcore.ErrorLevel:
+		enc.AppendString("ERROR")
+	case zapcore.DPanicLevel:
+		enc.AppendString("CRITICAL")
+	case zapcore.PanicLevel:
+		enc.AppendString("ALERT")
+	case zapcore.FatalLevel:
+		enc.AppendString("EMERGENCY")
+	default:
+		enc.AppendString("DEFAULT")
+	}
+}
+
+// kubernetesEnvFields maps downward-API environment variables to field
+// names (OpenTelemetry resource conventions).
+var kubernetesEnvFields = []struct{ env, key string }{
+	{"POD_NAME", "k8s.pod.name"},
+	{"POD_NAMESPACE", "k8s.namespace.name"},
+	{"POD_IP", "k8s.pod.ip"},
+	{"NODE_NAME", "k8s.node.name"},
+	{"CONTAINER_NAME", "k8s.container.name"},
+}
+
+// kubernetesMetadata returns the kubernetesEnvFields that are set.
+func kubernetesMetadata() []Field {
+	var meta []Field
+	for _, m := range kubernetesEnvFields {
+		if v := os.Getenv(m.env); v != "" {
+			meta = append(meta, String(m.key, v))
+		}
+	}
+	return meta
+}
+
+// NewKubernetes returns a logger suited to containers on Kubernetes: one JSON
+// line per entry on stdout using the severity/timestamp/message keys log
+// agents parse natively, no caller, and pod metadata taken from the
+// downward-API variables POD_NAME, POD_NAMESPACE, POD_IP, NODE_NAME and
+// CONTAINER_NAME when set. Further options are applied after the preset and
+// may add providers or change the level.
+func NewKubernetes(options ...LoggerOption) (*Logger, error) {
+	preset := []LoggerOption{
+		WithProviderFields(WithStdOutProvider(kubernetesEncoder), kubernetesMetadata()...),
+		WithoutCaller(),
+	}
+	return NewLogger(append(preset, options...)...)
+}
+
+// colorConsoleEncoder is the console encoder with ANSI-coloured levels.
+const colorConsoleEncoder EncoderType = "console-color"
+
+// NewTwelveFactor returns a stdout logger configured entirely from the
+// environment, for platforms that mandate stdout logging (Heroku, Fly.io,
+// Railway, …):
+//
+//	LOG_FORMAT    json (default) or console
+//	LOG_LEVEL     trace, debug, info (default), warn, error or fatal
+//	LOG_COLOR     auto (default), always or never; console format only.
+//	              auto colours when stdout is a terminal supporting ANSI
+//	              sequences, honouring NO_COLOR and FORCE_COLOR.
+//	LOG_SAMPLING  "first,thereafter" per second, e.g. "100,10"; unset or
+//	              "off" disables sampling
+//
+// Invalid values are reported as errors rather than silently ignored.
+// Further options are applied after the preset.
+func NewTwelveFactor(options ...LoggerOption) (*Logger, error) {
+	preset, err := twelveFactorOptions(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	return NewLogger(append(preset, options...)...)
+}
+
+func twelveFactorOptions(getenv func(string) string) ([]LoggerOption, error) {
+	var opts []LoggerOption
+
+	level := InfoLevel
+	if v := getenv("LOG_LEVEL"); v != "" {
+		var err error
+		if level, err = ParseLevel(v); err != nil {
+			return nil, fmt.Errorf("LOG_LEVEL: %w", err)
+		}
+	}
+	opts = append(opts, WithLevel(level))
+
+	var color bool
+	switch v := strings.ToLower(getenv("LOG_COLOR")); v {
+	case "", "auto":
+		color = term.ColorEnabled(os.Stdout, getenv)
+	case "always", "true", "1":
+		color = true
+	case "never", "false", "0":
+	default:
+		return nil, fmt.Errorf("LOG_COLOR: unsupported value %q", v)
+	}
+
+	switch v := strings.ToLower(getenv("LOG_FORMAT")); v {
+	case "", "json":
+		opts = append(opts, WithStdOutProvider(JSONEncoder))
+	case "console", "text":
+		enc := ConsoleEncoder
+		if color {
+			enc = colorConsoleEncoder
+		}
+		opts = append(opts, WithStdOutProvider(enc))
+	default:
+		return nil, fmt.Errorf("LOG_FORMAT: unsupported value %q", v)
+	}
+
+	if v := strings.ToLower(getenv("LOG_SAMPLING")); v != "" && v != "off" {
+		first, thereafter, ok := strings.Cut(v, ",")
+		f, err1 := strconv.Atoi(strings.TrimSpace(first))
+		t, err2 := strconv.Atoi(strings.TrimSpace(thereafter))
+		if !ok || err1 != nil || err2 != nil || f < 0 || t < 0 {
+			return nil, fmt.Errorf("LOG_SAMPLING: expected \"first,thereafter\", got %q", v)
+		}
+		opts = append(opts, WithSampling(time.Second, f, t))
+	}
+	return opts, nil
+}
+
+// developmentEncoder is zap's development console format: ISO-8601
+// timestamps, capitalised levels and short callers; developmentColorEncoder
+// colours the levels.
+const (
+	developmentEncoder      EncoderType = "development"
+	developmentColorEncoder EncoderType = "development-color"
+)
+
+func developmentEncoderConfig(color bool) zapcore.EncoderConfig {
+	encCfg := zap.NewDevelopmentEncoderConfig()
+	encCfg.EncodeDuration = zapcore.StringDurationEncoder
+	encCfg.EncodeLevel = capitalLevelEncoder
+	if color {
+		encCfg.EncodeLevel = capitalColorLevelEncoder
+	}
+	return encCfg
+}
+
+// WithDevelopmentMode applies zap's development settings for local
+// iteration: a stdout provider with human-friendly console output (ISO-8601
+// timestamps, levels coloured when stdout is a terminal), Debug level, stack
+// traces from Warn, and DPanic panicking. Options given after it may change
+// the level or stack trace threshold; providers added by other options are
+// kept alongside stdout.
+func WithDevelopmentMode() LoggerOption {
+	return func(cfg *loggerConfig) {
+		enc := developmentEncoder
+		if term.ColorEnabled(os.Stdout, os.Getenv) {
+			enc = developmentColorEncoder
+		}
+		cfg.providers = append(cfg.providers, &stdOutProvider{encoderType: enc})
+		cfg.level = DebugLevel
+		warn := WarnLevel
+		cfg.stacktrace = &warn
+		cfg.development = true
+	}
+}
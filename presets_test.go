@@ -0,0 +1,177 @@
+package golog
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout runs fn with os.Stdout redirected and returns what it wrote.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestNewKubernetes(t *testing.T) {
+	t.Setenv("POD_NAME", "api-7d9f")
+	t.Setenv("POD_NAMESPACE", "prod")
+	t.Setenv("NODE_NAME", "")
+
+	out := captureStdout(t, func() {
+		logger, err := NewKubernetes()
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		logger.Warn("cache cold", Int("misses", 3))
+		logger.Close()
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected a single line, got %q", out)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[0], err)
+	}
+	want := map[string]interface{}{
+		"severity":           "WARNING",
+		"message":            "cache cold",
+		"k8s.pod.name":       "api-7d9f",
+		"k8s.namespace.name": "prod",
+		"misses":             float64(3),
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s: got %v, want %v", k, entry[k], v)
+		}
+	}
+	if _, ok := entry["timestamp"].(string); !ok {
+		t.Errorf("missing RFC 3339 timestamp: %v", entry)
+	}
+	if _, ok := entry["caller"]; ok {
+		t.Errorf("caller should be off by default: %v", entry)
+	}
+	if _, ok := entry["k8s.node.name"]; ok {
+		t.Errorf("unset downward-API variables should be omitted: %v", entry)
+	}
+}
+
+func TestTwelveFactorOptions(t *testing.T) {
+	env := func(m map[string]string) func(string) string {
+		return func(k string) string { return m[k] }
+	}
+
+	cfg := &loggerConfig{}
+	opts, err := twelveFactorOptions(env(map[string]string{
+		"LOG_FORMAT":   "console",
+		"LOG_LEVEL":    "WARNING",
+		"LOG_COLOR":    "always",
+		"LOG_SAMPLING": "100,10",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.level != WarnLevel {
+		t.Errorf("level: got %v, want warn", cfg.level)
+	}
+	if len(cfg.providers) != 1 {
+		t.Fatalf("unexpected providers: %#v", cfg.providers)
+	}
+	if sp, ok := cfg.providers[0].(*stdOutProvider); !ok || *sp != (stdOutProvider{encoderType: colorConsoleEncoder}) {
+		t.Errorf("unexpected providers: %#v", cfg.providers)
+	}
+	if cfg.sampling == nil || cfg.sampling.first != 100 || cfg.sampling.thereafter != 10 {
+		t.Errorf("unexpected sampling: %+v", cfg.sampling)
+	}
+
+	for _, bad := range []map[string]string{
+		{"LOG_FORMAT": "xml"},
+		{"LOG_LEVEL": "loud"},
+		{"LOG_COLOR": "sometimes"},
+		{"LOG_SAMPLING": "100"},
+	} {
+		if _, err := twelveFactorOptions(env(bad)); err == nil {
+			t.Errorf("expected error for %v", bad)
+		}
+	}
+}
+
+func TestNewTwelveFactor_Defaults(t *testing.T) {
+	for _, k := range []string{"LOG_FORMAT", "LOG_LEVEL", "LOG_COLOR", "LOG_SAMPLING"} {
+		t.Setenv(k, "")
+	}
+	out := captureStdout(t, func() {
+		logger, err := NewTwelveFactor()
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		logger.Debug("hidden")
+		logger.Info("shown")
+		logger.Close()
+	})
+	if strings.Contains(out, "hidden") || !strings.Contains(out, `"msg":"shown"`) {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestWithDevelopmentMode(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var logger *Logger
+	out := captureStdout(t, func() {
+		var err error
+		logger, err = NewLogger(WithDevelopmentMode())
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		logger.Debug("starting", Int("port", 8080))
+		logger.Warn("slow")
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("DPanic should panic in development mode")
+				}
+			}()
+			logger.DPanic("impossible")
+		}()
+		logger.Close()
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected debug, warn and dpanic entries, got %q", out)
+	}
+	// 2006-01-02T15:04:05.000Z0700<TAB>DEBUG<TAB>caller<TAB>msg<TAB>{fields}
+	cols := strings.Split(lines[0], "\t")
+	if len(cols) != 5 || !strings.Contains(cols[0], "T") || cols[1] != "DEBUG" ||
+		!strings.Contains(cols[2], "presets_test.go:") ||
+		cols[3] != "starting" || cols[4] != `{"port": 8080}` {
+		t.Errorf("unexpected development line %q", lines[0])
+	}
+	if !strings.Contains(out, "\tWARN\t") || !strings.Contains(out, "TestWithDevelopmentMode") {
+		t.Errorf("warn entry should carry a stack trace: %q", out)
+	}
+	if c := logger.Config(); !c.Development || c.Level != DebugLevel {
+		t.Errorf("unexpected config %+v", c)
+	}
+}
@@ -0,0 +1,178 @@
+package golog
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                         Pretty Console Encoder                              */
+/* -------------------------------------------------------------------------- */
+
+const (
+	// PrettyEncoder is a console format for local development that is easy
+	// to scan: short timestamps, three-letter levels, aligned callers and
+	// messages, and fields inline as key=value with nested objects
+	// flattened into dotted keys:
+	//
+	//	15:04:05.000 INF api/server.go:42        served request            method=GET status=200
+	//
+	// Stack traces follow on their own lines.
+	PrettyEncoder EncoderType = "pretty"
+	// PrettyColorEncoder is PrettyEncoder with ANSI colours per level. Used
+	// with WithStdOutProvider it falls back to PrettyEncoder on consoles
+	// without ANSI support.
+	PrettyColorEncoder EncoderType = "pretty-color"
+)
+
+const (
+	prettyTimeLayout   = "15:04:05.000"
+	prettyCallerWidth  = 24
+	prettyMessageWidth = 32
+)
+
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+	ansiCyan    = "\x1b[36m"
+	ansiGray    = "\x1b[90m"
+)
+
+type prettyEncoder struct {
+	*treeEncoder
+	color bool
+}
+
+func newPrettyEncoder(color bool) zapcore.Encoder {
+	return &prettyEncoder{treeEncoder: newTreeEncoder(), color: color}
+}
+
+func (e *prettyEncoder) Clone() zapcore.Encoder {
+	return &prettyEncoder{treeEncoder: e.cloneTree(), color: e.color}
+}
+
+// paint wraps s in the ANSI code when colouring.
+func (e *prettyEncoder) paint(buf *buffer.Buffer, code, s string) {
+	if e.color && code != "" {
+		buf.AppendString(code)
+		buf.AppendString(s)
+		buf.AppendString(ansiReset)
+		return
+	}
+	buf.AppendString(s)
+}
+
+func (e *prettyEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	tree := e.fieldsWith(fields)
+	buf := encoderPool.Get()
+
+	e.paint(buf, ansiDim, ent.Time.Format(prettyTimeLayout))
+	buf.AppendByte(' ')
+	name, code := prettyLevel(ent.Level)
+	e.paint(buf, code, name)
+	buf.AppendByte(' ')
+	if ent.Caller.Defined {
+		e.paint(buf, ansiDim, pad(prettyCaller(ent.Caller), prettyCallerWidth))
+		buf.AppendByte(' ')
+	}
+	if ent.LoggerName != "" {
+		e.paint(buf, ansiBlue, ent.LoggerName+":")
+		buf.AppendByte(' ')
+	}
+	msg := ent.Message
+	if len(tree.keys) > 0 {
+		msg = pad(msg, prettyMessageWidth)
+	}
+	e.paint(buf, ansiBold, msg)
+
+	tree.flatten("", func(key string, val interface{}) {
+		buf.AppendByte(' ')
+		e.paint(buf, ansiCyan, key+"=")
+		code := ""
+		if key == "error" {
+			code = ansiRed
+		}
+		e.paint(buf, code, prettyValue(val))
+	})
+	buf.AppendByte('\n')
+	if ent.Stack != "" {
+		buf.AppendString(ent.Stack)
+		buf.AppendByte('\n')
+	}
+	return buf, nil
+}
+
+// prettyLevel returns the three-letter name of lvl and its colour.
+func prettyLevel(lvl zapcore.Level) (string, string) {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return "DBG", ansiMagenta
+	case zapcore.InfoLevel:
+		return "INF", ansiGreen
+	case zapcore.WarnLevel:
+		return "WRN", ansiYellow
+	case zapcore.ErrorLevel:
+		return "ERR", ansiRed
+	case zapcore.DPanicLevel:
+		return "DPN", ansiBold + ansiRed
+	case zapcore.PanicLevel:
+		return "PNC", ansiBold + ansiRed
+	case zapcore.FatalLevel:
+		return "FTL", ansiBold + ansiRed
+	}
+	// Trace and the custom levels.
+	name := strings.ToUpper(zapLevelName(lvl))
+	if name == "TRACE" {
+		name = "TRC"
+	}
+	return name, ansiGray
+}
+
+// prettyCaller shortens caller to fit its column, dropping the package
+// directory first and then the start of the file name.
+func prettyCaller(caller zapcore.EntryCaller) string {
+	s := caller.TrimmedPath()
+	if utf8.RuneCountInString(s) <= prettyCallerWidth {
+		return s
+	}
+	s = filepath.Base(caller.File) + ":" + strconv.Itoa(caller.Line)
+	if n := utf8.RuneCountInString(s); n > prettyCallerWidth {
+		s = "…" + string([]rune(s)[n-prettyCallerWidth+1:])
+	}
+	return s
+}
+
+// pad right-pads s with spaces to width runes.
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// prettyValue renders v for key=value output, quoting strings that would
+// otherwise be ambiguous.
+func prettyValue(v interface{}) string {
+	s := columnText(v)
+	if _, ok := v.(string); !ok {
+		return s
+	}
+	if s == "" || strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '=' || r == '"' || !unicode.IsPrint(r)
+	}) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
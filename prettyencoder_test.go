@@ -0,0 +1,79 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestPrettyEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, PrettyEncoder), WithoutCaller())
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Named("api").Info("served", Dict("http", String("method", "GET"), Int("status", 200)),
+		String("note", "two words"), Strings("tags", []string{"a", "b"}))
+	logger.Warn("bare")
+	logger.Error("failed", Err(errors.New("boom")))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", lines)
+	}
+	want := []string{
+		" INF api: served" + strings.Repeat(" ", prettyMessageWidth-len("served")) +
+			` http.method=GET http.status=200 note="two words" tags=["a","b"]`,
+		" WRN bare",
+		" ERR failed" + strings.Repeat(" ", prettyMessageWidth-len("failed")) + " error=boom",
+	}
+	for i, line := range lines {
+		// Skip the 12-character timestamp.
+		if len(line) < 12 || line[12:] != want[i] {
+			t.Errorf("line %d = %q, want suffix %q", i, line, want[i])
+		}
+	}
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("plain pretty output contains ANSI codes: %q", buf.String())
+	}
+}
+
+func TestPrettyColorEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, PrettyColorEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Error("failed", String("user", "alice"))
+
+	out := buf.String()
+	for _, want := range []string{
+		ansiRed + "ERR" + ansiReset,
+		ansiCyan + "user=" + ansiReset + "alice",
+		"prettyencoder_test.go:",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q does not contain %q", out, want)
+		}
+	}
+}
+
+func TestPrettyLevelNames(t *testing.T) {
+	for lvl, want := range map[zapcore.Level]string{
+		traceZapLevel:       "TRC",
+		zapcore.DebugLevel:  "DBG",
+		zapcore.DPanicLevel: "DPN",
+		zapcore.FatalLevel:  "FTL",
+	} {
+		if got, _ := prettyLevel(lvl); got != want {
+			t.Errorf("prettyLevel(%v) = %q, want %q", lvl, got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type bufferProvider struct {
+	buf    bytes.Buffer
+	closed int
+	err    error
+}
+
+func (p *bufferProvider) NewCore(level zapcore.Level) (zapcore.Core, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	return zapcore.NewCore(enc, zapcore.AddSync(&p.buf), level), nil
+}
+
+func (p *bufferProvider) Close() error {
+	p.closed++
+	return nil
+}
+
+func TestWithProvider(t *testing.T) {
+	p := &bufferProvider{}
+	logger, err := NewLogger(WithProvider(p, WithProviderLevel(WarnLevel)), WithLevel(DebugLevel))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("below provider level")
+	logger.Error("delivered", String("k", "v"))
+
+	infos := logger.Config().Providers
+	if len(infos) != 1 || infos[0].Name != "*golog.bufferProvider" || infos[0].Level != "warn" {
+		t.Errorf("unexpected provider info %+v", infos)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	out := p.buf.String()
+	if strings.Contains(out, "below provider level") || !strings.Contains(out, `"msg":"delivered"`) {
+		t.Errorf("unexpected output: %s", out)
+	}
+	if p.closed != 1 {
+		t.Errorf("Close called %d times, want 1", p.closed)
+	}
+
+	failing := &bufferProvider{err: errors.New("bus unreachable")}
+	if _, err := NewLogger(WithProvider(failing)); err == nil || !strings.Contains(err.Error(), "bus unreachable") {
+		t.Errorf("expected the NewCore error, got %v", err)
+	}
+	if _, err := NewLogger(WithProvider(nil)); err == nil {
+		t.Error("expected an error for a nil provider")
+	}
+}
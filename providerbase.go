@@ -0,0 +1,91 @@
+package golog
+
+import (
+	"fmt"
+	"net/url"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                             Remote Provider Base                            */
+/* -------------------------------------------------------------------------- */
+
+// providerBase is embedded by the providers sending entries to a remote
+// service. It holds their name, the logger's telemetry and the settings of
+// WithProviderEncoder and WithProviderEncoderConfig, and is set up by the
+// provider's constructor.
+type providerBase struct {
+	// name is the provider's ProviderInfo name, which prefixes the errors
+	// it reports.
+	name string
+	tel  *telemetry
+
+	// encoders says which encoder settings the provider takes.
+	encoders encoderSupport
+	// encoderType is empty unless set by WithProviderEncoder; see encoder.
+	encoderType   EncoderType
+	encoderConfig *encoderConfigFuncs
+}
+
+// encoderSupport says which encoder settings a provider takes.
+type encoderSupport uint8
+
+const (
+	// noEncoderSettings rejects both encoder options.
+	noEncoderSettings encoderSupport = iota
+	// anyEncoder takes any encoder type and config.
+	anyEncoder
+	// jsonEncoderOnly takes encoder config, but no encoder type other than
+	// JSON.
+	jsonEncoderOnly
+)
+
+func (b *providerBase) instrument(t *telemetry) { b.tel = t }
+
+// encoder returns the provider's encoder type, JSON by default.
+func (b *providerBase) encoder() EncoderType {
+	if b.encoderType == "" {
+		return JSONEncoder
+	}
+	return b.encoderType
+}
+
+// setEncoder implements encodedProvider.
+func (b *providerBase) setEncoder(t EncoderType, configure []func(*zapcore.EncoderConfig)) error {
+	switch {
+	case b.encoders == noEncoderSettings:
+		return fmt.Errorf("provider %s: encoder options are not supported", b.name)
+	case b.encoders == jsonEncoderOnly && t != "" && t != JSONEncoder:
+		return fmt.Errorf("provider %s: entries are sent as JSON, not %s", b.name, t)
+	}
+	if t != "" {
+		b.encoderType = t
+	}
+	b.encoderConfig = mergeEncoderConfig(b.encoderConfig, configure)
+	return nil
+}
+
+// dropped counts n entries lost for reason.
+func (b *providerBase) dropped(reason DropReason, n int) {
+	if b.tel != nil {
+		b.tel.drops.record(reason, n)
+	}
+}
+
+// report surfaces errors from background flushes, which have no caller.
+func (b *providerBase) report(err error) {
+	if b.tel != nil {
+		b.tel.errs.report(fmt.Errorf("%s: %w", b.name, err))
+	}
+}
+
+// endpointName returns the host and path of endpoint for provider names,
+// dropping credentials and query parameters, which often carry secrets.
+func endpointName(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	return u.Host + u.Path
+}
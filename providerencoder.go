@@ -0,0 +1,119 @@
+package golog
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          Per-Provider Encoder Settings                      */
+/* -------------------------------------------------------------------------- */
+
+// WithProviderEncoder sets the encoder of a stdout, writer, file or HTTP
+// provider, e.g. for human-readable files next to JSON on stdout:
+//
+//	golog.WithFileProvider("app.log", 100, 3, 28, true, golog.WithProviderEncoder(golog.ConsoleEncoder))
+//
+// For stdout and writer providers it replaces the encoder given to the
+// option. Other providers fail NewLogger when given it.
+func WithProviderEncoder(t EncoderType) ProviderOption {
+	return func(s *providerSettings) { s.encoderType = t }
+}
+
+// WithProviderEncoderConfig adjusts the zap encoder config of a stdout,
+// writer, file or HTTP provider, e.g. for a different time format per sink:
+//
+//	golog.WithStdOutProvider(golog.ConsoleEncoder, golog.WithProviderEncoderConfig(func(c *zapcore.EncoderConfig) {
+//		c.EncodeTime = zapcore.TimeEncoderOfLayout(time.Kitchen)
+//	}))
+//
+// fn runs after golog's defaults for the encoder are set, and applies to the
+// JSON and console encoders and their presets; the XML, pretty, CSV, LTSV,
+// MessagePack and Protobuf encoders have fixed layouts and ignore it. Several
+// calls apply in order. Other providers fail NewLogger when given it.
+func WithProviderEncoderConfig(fn func(*zapcore.EncoderConfig)) ProviderOption {
+	return func(s *providerSettings) {
+		if fn != nil {
+			s.encoderConfig = append(s.encoderConfig, fn)
+		}
+	}
+}
+
+// encoderConfigFuncs holds a provider's WithProviderEncoderConfig functions.
+// Providers refer to it by pointer so they stay comparable.
+type encoderConfigFuncs struct {
+	fns []func(*zapcore.EncoderConfig)
+}
+
+// funcs returns the functions, none for a nil receiver.
+func (c *encoderConfigFuncs) funcs() []func(*zapcore.EncoderConfig) {
+	if c == nil {
+		return nil
+	}
+	return c.fns
+}
+
+// encodedProvider is implemented by the providers whose encoder can be
+// chosen per provider.
+type encodedProvider interface {
+	// setEncoder makes the provider use t, unless empty, and the additional
+	// encoder config functions, or says why it cannot.
+	setEncoder(t EncoderType, configure []func(*zapcore.EncoderConfig)) error
+}
+
+// applyEncoderSettings applies the encoder settings in s to p.
+func applyEncoderSettings(p provider, s *providerSettings) provider {
+	if s.encoderType == "" && len(s.encoderConfig) == 0 {
+		return p
+	}
+	name := describeProvider(p).Name
+	ep, ok := p.(encodedProvider)
+	if !ok {
+		return errProvider{name: name, err: fmt.Errorf("provider %s: encoder options are not supported", name)}
+	}
+	if err := ep.setEncoder(s.encoderType, s.encoderConfig); err != nil {
+		return errProvider{name: name, err: err}
+	}
+	return p
+}
+
+// mergeEncoderConfig returns c extended by configure.
+func mergeEncoderConfig(c *encoderConfigFuncs, configure []func(*zapcore.EncoderConfig)) *encoderConfigFuncs {
+	if len(configure) == 0 {
+		return c
+	}
+	fns := make([]func(*zapcore.EncoderConfig), 0, len(c.funcs())+len(configure))
+	return &encoderConfigFuncs{fns: append(append(fns, c.funcs()...), configure...)}
+}
+
+func (p *stdOutProvider) setEncoder(t EncoderType, configure []func(*zapcore.EncoderConfig)) error {
+	if t != "" {
+		p.encoderType = t
+	}
+	p.encoderConfig = mergeEncoderConfig(p.encoderConfig, configure)
+	return nil
+}
+
+func (p *writerProvider) setEncoder(t EncoderType, configure []func(*zapcore.EncoderConfig)) error {
+	if t != "" {
+		p.encoderType = t
+	}
+	p.encoderConfig = mergeEncoderConfig(p.encoderConfig, configure)
+	return nil
+}
+
+func (p *fileProvider) setEncoder(t EncoderType, configure []func(*zapcore.EncoderConfig)) error {
+	if t != "" {
+		p.encoderType = t
+	}
+	p.encoderConfig = mergeEncoderConfig(p.encoderConfig, configure)
+	return nil
+}
+
+// minLevelProvider passes encoder settings through, so the provider options
+// given to WithProviderOptions may come in any order.
+func (p *minLevelProvider) setEncoder(t EncoderType, configure []func(*zapcore.EncoderConfig)) error {
+	p.inner = applyEncoderSettings(p.inner, &providerSettings{encoderType: t, encoderConfig: configure})
+	return nil
+}
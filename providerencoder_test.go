@@ -0,0 +1,88 @@
+package golog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestProviderEncoder(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	var out bytes.Buffer
+	logger, err := NewLogger(
+		WithFileProvider(path, 1, 0, 0, false, WithProviderEncoder(LTSVEncoder)),
+		WithWriterProvider(&out, JSONEncoder, WithProviderEncoder(ConsoleEncoder), WithProviderEncoderConfig(func(c *zapcore.EncoderConfig) {
+			c.EncodeTime = zapcore.TimeEncoderOfLayout("epoch-less")
+		})),
+		WithoutCaller(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("hello", String("user", "alice"))
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "\tmessage:hello\tuser:alice") {
+		t.Errorf("file provider did not use LTSV: %q", data)
+	}
+	if got := out.String(); !strings.HasPrefix(got, "epoch-less\tinfo\thello\t{\"user\": \"alice\"}") {
+		t.Errorf("writer provider did not use the console encoder config: %q", got)
+	}
+	if p := logger.Config().Providers; len(p) != 2 || p[0].Encoder != LTSVEncoder || p[1].Encoder != ConsoleEncoder {
+		t.Errorf("unexpected provider descriptions: %+v", p)
+	}
+}
+
+func TestProviderEncoderConfigOrder(t *testing.T) {
+	var out bytes.Buffer
+	layout := func(l string) ProviderOption {
+		return WithProviderEncoderConfig(func(c *zapcore.EncoderConfig) { c.EncodeTime = zapcore.TimeEncoderOfLayout(l) })
+	}
+	logger, err := NewLogger(
+		WithProviderOptions(WithWriterProvider(&out, JSONEncoder, WithProviderLevel(InfoLevel), layout("first")), layout("second")),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("hello")
+	if got := out.String(); !strings.Contains(got, `"ts":"second"`) {
+		t.Errorf("later encoder config should win: %q", got)
+	}
+}
+
+func TestProviderEncoderUnsupported(t *testing.T) {
+	_, err := NewLogger(WithGCPProvider("project", "log", WithProviderEncoder(ConsoleEncoder)))
+	if err == nil || !strings.Contains(err.Error(), "encoder options are not supported") {
+		t.Fatalf("expected unsupported encoder error, got %v", err)
+	}
+	// Providers embedding providerBase reject them unless they opt in.
+	_, err = NewLogger(WithSMTPProvider("mail:25", SMTPConfig{}, WithProviderEncoderConfig(func(*zapcore.EncoderConfig) {})))
+	if err == nil || !strings.Contains(err.Error(), "provider smtp:mail:25: encoder options are not supported") {
+		t.Fatalf("expected unsupported encoder error, got %v", err)
+	}
+}
+
+func TestEncoderContentType(t *testing.T) {
+	for enc, want := range map[EncoderType]string{
+		JSONEncoder:     "application/x-ndjson",
+		ProtobufEncoder: "application/octet-stream",
+		LTSVEncoder:     "text/plain; charset=utf-8",
+	} {
+		if got := encoderContentType(enc); got != want {
+			t.Errorf("encoderContentType(%q) = %q, want %q", enc, got, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package golog
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          Per-Provider Minimum Level                         */
+/* -------------------------------------------------------------------------- */
+
+// ProviderOption configures a single provider. The built-in provider options
+// accept them as trailing arguments; WithProviderOptions applies them to any
+// provider option.
+type ProviderOption func(*providerSettings)
+
+type providerSettings struct {
+	level *Level
+	// encoderType and encoderConfig are set by WithProviderEncoder and
+	// WithProviderEncoderConfig.
+	encoderType   EncoderType
+	encoderConfig []func(*zapcore.EncoderConfig)
+	// stderrLevel is set by WithStdErrLevel.
+	stderrLevel *Level
+}
+
+// WithProviderLevel sets the provider's minimum level, so expensive sinks
+// receive less than cheap ones:
+//
+//	golog.NewLogger(
+//		golog.WithLevel(golog.DebugLevel),
+//		golog.WithStdOutProvider(golog.ConsoleEncoder),
+//		golog.WithFileProvider("app.log", 100, 3, 28, true, golog.WithProviderLevel(golog.InfoLevel)),
+//		golog.WithGCPProvider(project, "app", golog.WithProviderLevel(golog.WarnLevel)),
+//	)
+//
+// Entries must pass both the provider's level and the logger's (WithLevel,
+// SetLevel, SetNamedLevel), so the logger's level must be at least as
+// verbose as the most verbose provider. Use WithLevelRange for a provider
+// that ignores the logger's level.
+func WithProviderLevel(level Level) ProviderOption {
+	return func(s *providerSettings) { s.level = &level }
+}
+
+// WithProviderOptions applies options to the providers registered by opt, for
+// provider options without a ProviderOption parameter:
+//
+//	golog.WithProviderOptions(golog.WithNamedProvider("kafka", params), golog.WithProviderLevel(golog.WarnLevel))
+func WithProviderOptions(opt LoggerOption, options ...ProviderOption) LoggerOption {
+	return wrapProviders(opt, func(p provider) provider {
+		return applyProviderOptions(p, options)
+	})
+}
+
+// applyProviderOptions wraps p according to options.
+func applyProviderOptions(p provider, options []ProviderOption) provider {
+	if len(options) == 0 {
+		return p
+	}
+	var s providerSettings
+	for _, o := range options {
+		o(&s)
+	}
+	p = applyEncoderSettings(p, &s)
+	if s.stderrLevel != nil {
+		p = applyStdErrLevel(p, *s.stderrLevel)
+	}
+	if s.level != nil {
+		p = &minLevelProvider{inner: p, min: *s.level}
+	}
+	return p
+}
+
+// minLevelProvider builds its inner provider's core at no less than min.
+type minLevelProvider struct {
+	inner provider
+	min   Level
+}
+
+func (p *minLevelProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	if min := toZapLevel(p.min); min > level {
+		level = min
+	}
+	return p.inner.newCore(level)
+}
+
+func (p *minLevelProvider) close() error { return p.inner.close() }
+
+func (p *minLevelProvider) instrument(t *telemetry) {
+	if ip, ok := p.inner.(instrumentedProvider); ok {
+		ip.instrument(t)
+	}
+}
+
+func (p *minLevelProvider) describe() ProviderInfo {
+	info := describeProvider(p.inner)
+	info.Level = p.min.String()
+	return info
+}
@@ -0,0 +1,53 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProviderLevel(t *testing.T) {
+	var verbose, quiet, wrapped bytes.Buffer
+	logger, err := NewLogger(
+		WithLevel(DebugLevel),
+		WithWriterProvider(&verbose, JSONEncoder),
+		WithWriterProvider(&quiet, JSONEncoder, WithProviderLevel(WarnLevel)),
+		WithProviderOptions(WithWriterProvider(&wrapped, JSONEncoder), WithProviderLevel(ErrorLevel)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Debug("debug entry")
+	logger.Warn("warn entry")
+	logger.Error("error entry")
+	logger.SetLevel(ErrorLevel)
+	logger.Warn("warn after raise")
+
+	if out := verbose.String(); !strings.Contains(out, "debug entry") || strings.Contains(out, "warn after raise") {
+		t.Errorf("unexpected verbose output: %s", out)
+	}
+	if out := quiet.String(); strings.Contains(out, "debug entry") || !strings.Contains(out, "warn entry") || strings.Contains(out, "warn after raise") {
+		t.Errorf("provider level or logger level not applied: %s", out)
+	}
+	if out := wrapped.String(); strings.Contains(out, "warn entry") || !strings.Contains(out, "error entry") {
+		t.Errorf("WithProviderOptions not applied: %s", out)
+	}
+	if p := logger.Config().Providers; len(p) != 3 || p[0].Level != "" || p[1].Level != "warn" || p[2].Level != "error" {
+		t.Errorf("unexpected provider descriptions: %+v", p)
+	}
+}
+
+func TestProviderLevelDescribeTrace(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder, WithProviderLevel(TraceLevel)))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	if p := logger.Config().Providers; len(p) != 1 || p[0].Level != "trace" {
+		t.Errorf("unexpected provider descriptions: %+v", p)
+	}
+}
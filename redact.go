@@ -0,0 +1,134 @@
+package golog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                               Redacted Values                               */
+/* -------------------------------------------------------------------------- */
+
+// redactedMask replaces secret values in the output.
+const redactedMask = "***"
+
+// Secret logs key with the value masked as "***" by every encoder, so the
+// entry still shows that a value was present:
+//
+//	logger.Info("login", golog.String("user", u), golog.Secret("token", tok))
+//	// {"msg":"login","user":"alice","token":"***"}
+//
+// value is discarded, never stored in the entry.
+func Secret(key, value string) Field { return Field{Key: key, Value: redacted(redactedMask)} }
+
+// Redacted logs a short SHA-256 digest of value, e.g. "sha256:9f86d081884c7d65",
+// so entries carrying the same secret can be correlated without revealing
+// it. Low-entropy values such as passwords can be recovered from a digest by
+// brute force; log those with Secret.
+func Redacted(key, value string) Field {
+	sum := sha256.Sum256([]byte(value))
+	return Field{Key: key, Value: redacted("sha256:" + hex.EncodeToString(sum[:8]))}
+}
+
+// redacted is the text logged in place of a secret.
+type redacted string
+
+/* -------------------------------------------------------------------------- */
+/*                              Redaction Rules                                */
+/* -------------------------------------------------------------------------- */
+
+// RedactionRule selects values to mask; see WithRedaction.
+type RedactionRule struct {
+	keys   []string
+	values *regexp.Regexp
+}
+
+// RedactKeys masks the whole value of every field whose key contains one of
+// names, ignoring case, at any nesting depth: RedactKeys("password") covers
+// "password", "db_password" and "Password" inside a Dict.
+func RedactKeys(names ...string) RedactionRule {
+	keys := make([]string, len(names))
+	for i, n := range names {
+		keys[i] = strings.ToLower(n)
+	}
+	return RedactionRule{keys: keys}
+}
+
+// RedactPattern masks the parts of the message and of string field values
+// matching re, e.g. bearer tokens or card numbers.
+func RedactPattern(re *regexp.Regexp) RedactionRule {
+	return RedactionRule{values: re}
+}
+
+// WithRedaction applies rules to every entry, for all providers and the ring
+// buffer, before it is encoded, so compliance does not rest on each call
+// site using Secret:
+//
+//	golog.WithRedaction(
+//		golog.RedactKeys("password", "authorization", "token"),
+//		golog.RedactPattern(regexp.MustCompile(`Bearer [A-Za-z0-9._~+/-]+=*`)),
+//	)
+//
+// Masked values read "***". Redaction runs after every WithTransform, so
+// transforms cannot reintroduce what it removed.
+func WithRedaction(rules ...RedactionRule) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.redactions = append(cfg.redactions, rules...)
+	}
+}
+
+// Redact returns a TransformFunc applying rules, for use with
+// WithProviderTransform when only some providers need redaction.
+func Redact(rules ...RedactionRule) TransformFunc {
+	var (
+		keys   []string
+		values []*regexp.Regexp
+	)
+	for _, r := range rules {
+		keys = append(keys, r.keys...)
+		if r.values != nil {
+			values = append(values, r.values)
+		}
+	}
+	secretKey := func(k string) bool {
+		k = strings.ToLower(k)
+		for _, name := range keys {
+			if strings.Contains(k, name) {
+				return true
+			}
+		}
+		return false
+	}
+	redactString := func(s string) string {
+		for _, re := range values {
+			s = re.ReplaceAllString(s, redactedMask)
+		}
+		return s
+	}
+	var redactValue func(v interface{}) interface{}
+	redactValue = func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case string:
+			return redactString(v)
+		case map[string]interface{}:
+			for k, inner := range v {
+				if secretKey(k) {
+					v[k] = redactedMask
+				} else {
+					v[k] = redactValue(inner)
+				}
+			}
+		case []interface{}:
+			for i, inner := range v {
+				v[i] = redactValue(inner)
+			}
+		}
+		return v
+	}
+	return func(e *Entry) {
+		e.Message = redactString(e.Message)
+		redactValue(e.Fields)
+	}
+}
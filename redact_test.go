@@ -0,0 +1,73 @@
+package golog
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSecretAndRedacted(t *testing.T) {
+	var js, console bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&js, JSONEncoder), WithWriterProvider(&console, ConsoleEncoder), WithRingBuffer(1))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("login", Secret("token", "hunter2"), Redacted("session", "test"))
+
+	for _, out := range []string{js.String(), console.String()} {
+		if strings.Contains(out, "hunter2") || strings.Contains(out, `"test"`) {
+			t.Errorf("secret leaked: %s", out)
+		}
+	}
+	if !strings.Contains(js.String(), `"token":"***","session":"sha256:9f86d081884c7d65"`) {
+		t.Errorf("unexpected JSON output: %s", js.String())
+	}
+	recent := logger.RecentEntries()
+	if len(recent) != 1 || recent[0].Fields["token"] != "***" {
+		t.Errorf("ring buffer entry should carry the mask: %+v", recent)
+	}
+}
+
+func TestWithRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(
+		WithWriterProvider(&buf, JSONEncoder),
+		WithTransform(RenameField("pw", "password")),
+		WithRedaction(
+			RedactKeys("password", "Authorization"),
+			RedactPattern(regexp.MustCompile(`Bearer \S+`)),
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("sent Bearer abc.def",
+		String("pw", "hunter2"),
+		String("note", "header was Bearer xyz"),
+		Dict("req", String("authorization", "Basic Zm9v"), Int("status", 200)),
+		Strings("tokens", []string{"Bearer t1", "plain"}),
+	)
+
+	out := buf.String()
+	for _, leak := range []string{"hunter2", "abc.def", "xyz", "Zm9v", "t1"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("%q leaked: %s", leak, out)
+		}
+	}
+	for _, want := range []string{
+		`"msg":"sent ***"`,
+		`"password":"***"`,
+		`"note":"header was ***"`,
+		`"req":{"authorization":"***","status":200}`,
+		`"tokens":["***","plain"]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,997 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                    Provider Registry for Config Construction                */
+/* -------------------------------------------------------------------------- */
+
+// ProviderFactory builds a provider from configuration parameters, typically
+// decoded from JSON or YAML. It returns the option that registers the
+// provider, so third-party packages can compose existing options such as
+// WithWriterProvider:
+//
+//	golog.RegisterProviderFactory("acme", func(params map[string]any) (golog.LoggerOption, error) {
+//		w, err := acme.Dial(params["addr"].(string))
+//		if err != nil {
+//			return nil, err
+//		}
+//		return golog.WithWriterProvider(w, golog.JSONEncoder), nil
+//	})
+type ProviderFactory func(params map[string]any) (LoggerOption, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]ProviderFactory{}
+)
+
+// RegisterProviderFactory makes a provider available under name to
+// WithNamedProvider. Like database/sql.Register, it panics if factory is nil
+// or name is already taken; call it from an init function.
+func RegisterProviderFactory(name string, factory ProviderFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if factory == nil {
+		panic("golog: RegisterProviderFactory factory is nil")
+	}
+	if _, dup := factories[name]; dup {
+		panic("golog: RegisterProviderFactory called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// ProviderFactories returns the sorted names of all registered factories.
+func ProviderFactories() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithNamedProvider adds the provider registered under name, configured by
+// params. An unknown name or invalid params make NewLogger fail. The
+// "fields" param, if present, is handled here for every provider: an object
+// of static fields applied as by WithProviderFields.
+func WithNamedProvider(name string, params map[string]any) LoggerOption {
+	return func(cfg *loggerConfig) {
+		factoriesMu.RLock()
+		factory, ok := factories[name]
+		factoriesMu.RUnlock()
+		if !ok {
+			cfg.providers = append(cfg.providers, errProvider{name: name, err: fmt.Errorf("unknown provider %q", name)})
+			return
+		}
+		opt, err := factory(params)
+		if err != nil {
+			cfg.providers = append(cfg.providers, errProvider{name: name, err: fmt.Errorf("provider %q: %w", name, err)})
+			return
+		}
+		if v, ok := params["fields"]; ok && v != nil {
+			m, ok := v.(map[string]any)
+			if !ok {
+				cfg.providers = append(cfg.providers, errProvider{name: name, err: fmt.Errorf("provider %q: fields: expected object, got %T", name, v)})
+				return
+			}
+			opt = WithProviderFields(opt, mapToFields(m)...)
+		}
+		opt(cfg)
+	}
+}
+
+// errProvider defers a configuration error to NewLogger, where provider
+// errors are reported.
+type errProvider struct {
+	name string
+	err  error
+}
+
+func (p errProvider) newCore(zapcore.Level) (zapcore.Core, error) { return nil, p.err }
+func (p errProvider) close() error                                { return nil }
+func (p errProvider) describe() ProviderInfo                      { return ProviderInfo{Name: p.name} }
+
+/* -------------------------------------------------------------------------- */
+/*                            Built-in Factories                              */
+/* -------------------------------------------------------------------------- */
+
+func init() {
+	register := func(name string, factory ProviderFactory) {
+		RegisterProviderFactory(name, knownParams(factory))
+	}
+	register("stdout", func(params map[string]any) (LoggerOption, error) {
+		enc, err := paramString(params, "encoder", string(JSONEncoder))
+		if err != nil {
+			return nil, err
+		}
+		levelName, err := paramString(params, "stderr_level", "")
+		if err != nil {
+			return nil, err
+		}
+		if levelName == "" {
+			return WithStdOutProvider(EncoderType(enc)), nil
+		}
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, fmt.Errorf("stderr_level: %w", err)
+		}
+		return WithStdOutProvider(EncoderType(enc), WithStdErrLevel(level)), nil
+	})
+	register("stderr", func(params map[string]any) (LoggerOption, error) {
+		enc, err := paramString(params, "encoder", string(JSONEncoder))
+		if err != nil {
+			return nil, err
+		}
+		return WithStdErrProvider(EncoderType(enc)), nil
+	})
+	register("file", func(params map[string]any) (LoggerOption, error) {
+		filename, err := paramString(params, "filename", "")
+		if err != nil {
+			return nil, err
+		}
+		if filename == "" {
+			return nil, errors.New("filename is required")
+		}
+		maxSize, err := paramInt(params, "max_size", 100)
+		if err != nil {
+			return nil, err
+		}
+		maxBackups, err := paramInt(params, "max_backups", 0)
+		if err != nil {
+			return nil, err
+		}
+		maxAge, err := paramInt(params, "max_age", 0)
+		if err != nil {
+			return nil, err
+		}
+		compress, err := paramBool(params, "compress", false)
+		if err != nil {
+			return nil, err
+		}
+		enc, err := paramString(params, "encoder", string(JSONEncoder))
+		if err != nil {
+			return nil, err
+		}
+		return WithFileProvider(filename, maxSize, maxBackups, maxAge, compress, WithProviderEncoder(EncoderType(enc))), nil
+	})
+	register("gcp", func(params map[string]any) (LoggerOption, error) {
+		projectID, err := paramString(params, "project_id", "")
+		if err != nil {
+			return nil, err
+		}
+		logName, err := paramString(params, "log_name", "")
+		if err != nil {
+			return nil, err
+		}
+		return WithGCPProvider(projectID, logName), nil
+	})
+	register("http", func(params map[string]any) (LoggerOption, error) {
+		endpoint, err := paramString(params, "endpoint", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg HTTPConfig
+		if cfg.ProxyURL, err = paramString(params, "proxy_url", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Headers, err = paramStringMap(params, "headers"); err != nil {
+			return nil, err
+		}
+		compression, err := paramString(params, "compression", "")
+		if err != nil {
+			return nil, err
+		}
+		cfg.Compression = Compression(compression)
+		if cfg.BatchSize, err = paramInt(params, "batch_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		if cfg.FlushInterval, err = paramDuration(params, "flush_interval", 0); err != nil {
+			return nil, err
+		}
+		enc, err := paramString(params, "encoder", string(JSONEncoder))
+		if err != nil {
+			return nil, err
+		}
+		return WithHTTPProvider(endpoint, cfg, WithProviderEncoder(EncoderType(enc))), nil
+	})
+	register("cloudwatch", func(params map[string]any) (LoggerOption, error) {
+		group, err := paramString(params, "group", "")
+		if err != nil {
+			return nil, err
+		}
+		stream, err := paramString(params, "stream", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg CloudWatchConfig
+		if cfg.Region, err = paramString(params, "region", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Endpoint, err = paramString(params, "endpoint", ""); err != nil {
+			return nil, err
+		}
+		if cfg.ProxyURL, err = paramString(params, "proxy_url", ""); err != nil {
+			return nil, err
+		}
+		if cfg.CreateStream, err = paramBool(params, "create_stream", false); err != nil {
+			return nil, err
+		}
+		if cfg.BatchSize, err = paramInt(params, "batch_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		if cfg.FlushInterval, err = paramDuration(params, "flush_interval", 0); err != nil {
+			return nil, err
+		}
+		enc, err := paramString(params, "encoder", string(JSONEncoder))
+		if err != nil {
+			return nil, err
+		}
+		return WithCloudWatchProvider(group, stream, cfg, WithProviderEncoder(EncoderType(enc))), nil
+	})
+	kinesisConfig := func(params map[string]any) (cfg KinesisConfig, err error) {
+		if cfg.Region, err = paramString(params, "region", ""); err != nil {
+			return cfg, err
+		}
+		if cfg.Endpoint, err = paramString(params, "endpoint", ""); err != nil {
+			return cfg, err
+		}
+		if cfg.ProxyURL, err = paramString(params, "proxy_url", ""); err != nil {
+			return cfg, err
+		}
+		if cfg.PartitionKeyField, err = paramString(params, "partition_key_field", ""); err != nil {
+			return cfg, err
+		}
+		if cfg.Aggregate, err = paramBool(params, "aggregate", false); err != nil {
+			return cfg, err
+		}
+		if cfg.BatchSize, err = paramInt(params, "batch_size", 0); err != nil {
+			return cfg, err
+		}
+		if cfg.MaxRetries, err = paramInt(params, "max_retries", 0); err != nil {
+			return cfg, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return cfg, err
+		}
+		if cfg.FlushInterval, err = paramDuration(params, "flush_interval", 0); err != nil {
+			return cfg, err
+		}
+		return cfg, nil
+	}
+	register("kinesis", func(params map[string]any) (LoggerOption, error) {
+		stream, err := paramString(params, "stream", "")
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := kinesisConfig(params)
+		if err != nil {
+			return nil, err
+		}
+		enc, err := paramString(params, "encoder", string(JSONEncoder))
+		if err != nil {
+			return nil, err
+		}
+		return WithKinesisProvider(stream, cfg, WithProviderEncoder(EncoderType(enc))), nil
+	})
+	register("firehose", func(params map[string]any) (LoggerOption, error) {
+		stream, err := paramString(params, "delivery_stream", "")
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := kinesisConfig(params)
+		if err != nil {
+			return nil, err
+		}
+		enc, err := paramString(params, "encoder", string(JSONEncoder))
+		if err != nil {
+			return nil, err
+		}
+		return WithFirehoseProvider(stream, cfg, WithProviderEncoder(EncoderType(enc))), nil
+	})
+	register("sqs", func(params map[string]any) (LoggerOption, error) {
+		queueURL, err := paramString(params, "queue_url", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg SQSConfig
+		if cfg.Region, err = paramString(params, "region", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Endpoint, err = paramString(params, "endpoint", ""); err != nil {
+			return nil, err
+		}
+		if cfg.ProxyURL, err = paramString(params, "proxy_url", ""); err != nil {
+			return nil, err
+		}
+		if cfg.MessageGroupField, err = paramString(params, "message_group_field", ""); err != nil {
+			return nil, err
+		}
+		if cfg.MessageGroupID, err = paramString(params, "message_group_id", ""); err != nil {
+			return nil, err
+		}
+		if cfg.DeduplicationField, err = paramString(params, "deduplication_field", ""); err != nil {
+			return nil, err
+		}
+		if cfg.BatchSize, err = paramInt(params, "batch_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.MaxRetries, err = paramInt(params, "max_retries", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		if cfg.FlushInterval, err = paramDuration(params, "flush_interval", 0); err != nil {
+			return nil, err
+		}
+		enc, err := paramString(params, "encoder", string(JSONEncoder))
+		if err != nil {
+			return nil, err
+		}
+		return WithSQSProvider(queueURL, cfg, WithProviderEncoder(EncoderType(enc))), nil
+	})
+	register("axiom", func(params map[string]any) (LoggerOption, error) {
+		dataset, err := paramString(params, "dataset", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg AxiomConfig
+		if cfg.Token, err = paramString(params, "token", ""); err != nil {
+			return nil, err
+		}
+		if cfg.OrgID, err = paramString(params, "org_id", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Endpoint, err = paramString(params, "endpoint", ""); err != nil {
+			return nil, err
+		}
+		if cfg.ProxyURL, err = paramString(params, "proxy_url", ""); err != nil {
+			return nil, err
+		}
+		compression, err := paramString(params, "compression", "")
+		if err != nil {
+			return nil, err
+		}
+		cfg.Compression = Compression(compression)
+		if cfg.BatchSize, err = paramInt(params, "batch_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		if cfg.FlushInterval, err = paramDuration(params, "flush_interval", 0); err != nil {
+			return nil, err
+		}
+		return WithAxiomProvider(dataset, cfg), nil
+	})
+	register("syslog", func(params map[string]any) (LoggerOption, error) {
+		network, err := paramString(params, "network", "")
+		if err != nil {
+			return nil, err
+		}
+		address, err := paramString(params, "address", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg SyslogConfig
+		format, err := paramString(params, "format", "")
+		if err != nil {
+			return nil, err
+		}
+		cfg.Format = SyslogFormat(format)
+		if facility, err := paramString(params, "facility", ""); err != nil {
+			return nil, err
+		} else if facility != "" {
+			if cfg.Facility, err = parseSyslogFacility(facility); err != nil {
+				return nil, fmt.Errorf("facility: %w", err)
+			}
+		}
+		if cfg.AppName, err = paramString(params, "app_name", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Hostname, err = paramString(params, "hostname", ""); err != nil {
+			return nil, err
+		}
+		if useTLS, err := paramBool(params, "tls", false); err != nil {
+			return nil, err
+		} else if useTLS {
+			cfg.TLS = &TLSConfig{}
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		var options []ProviderOption
+		if enc, err := paramString(params, "encoder", ""); err != nil {
+			return nil, err
+		} else if enc != "" {
+			options = append(options, WithProviderEncoder(EncoderType(enc)))
+		}
+		return WithSyslogProvider(network, address, cfg, options...), nil
+	})
+	register("eventlog", func(params map[string]any) (LoggerOption, error) {
+		source, err := paramString(params, "source", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg EventLogConfig
+		eventID, err := paramInt(params, "event_id", 0)
+		if err != nil {
+			return nil, err
+		}
+		if eventID < 0 {
+			return nil, fmt.Errorf("event_id: expected a non-negative integer, got %d", eventID)
+		}
+		cfg.EventID = uint32(eventID)
+		if cfg.Install, err = paramBool(params, "install", false); err != nil {
+			return nil, err
+		}
+		var options []ProviderOption
+		if enc, err := paramString(params, "encoder", ""); err != nil {
+			return nil, err
+		} else if enc != "" {
+			options = append(options, WithProviderEncoder(EncoderType(enc)))
+		}
+		return WithEventLogProvider(source, cfg, options...), nil
+	})
+	elasticsearch := func(params map[string]any) (LoggerOption, error) {
+		endpoint, err := paramString(params, "endpoint", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg ElasticsearchConfig
+		if cfg.Index, err = paramString(params, "index", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Pipeline, err = paramString(params, "pipeline", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Username, err = paramString(params, "username", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Password, err = paramString(params, "password", ""); err != nil {
+			return nil, err
+		}
+		if cfg.APIKey, err = paramString(params, "api_key", ""); err != nil {
+			return nil, err
+		}
+		if cfg.AWSRegion, err = paramString(params, "aws_region", ""); err != nil {
+			return nil, err
+		}
+		if cfg.ProxyURL, err = paramString(params, "proxy_url", ""); err != nil {
+			return nil, err
+		}
+		if cfg.DataStream, err = paramBool(params, "data_stream", false); err != nil {
+			return nil, err
+		}
+		compression, err := paramString(params, "compression", "")
+		if err != nil {
+			return nil, err
+		}
+		cfg.Compression = Compression(compression)
+		if cfg.BatchSize, err = paramInt(params, "batch_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		if cfg.FlushInterval, err = paramDuration(params, "flush_interval", 0); err != nil {
+			return nil, err
+		}
+		return WithElasticsearchProvider(endpoint, cfg), nil
+	}
+	register("elasticsearch", elasticsearch)
+	register("opensearch", elasticsearch)
+	register("bigquery", func(params map[string]any) (LoggerOption, error) {
+		project, err := paramString(params, "project", "")
+		if err != nil {
+			return nil, err
+		}
+		dataset, err := paramString(params, "dataset", "")
+		if err != nil {
+			return nil, err
+		}
+		table, err := paramString(params, "table", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg BigQueryConfig
+		if cfg.FieldsColumn, err = paramString(params, "fields_column", ""); err != nil {
+			return nil, err
+		}
+		if cfg.BatchSize, err = paramInt(params, "batch_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.FlushInterval, err = paramDuration(params, "flush_interval", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		return WithBigQueryProvider(project, dataset, table, cfg), nil
+	})
+	register("chat", func(params map[string]any) (LoggerOption, error) {
+		webhook, err := paramString(params, "webhook_url", "")
+		if err != nil {
+			return nil, err
+		}
+		levelName, err := paramString(params, "min_level", "error")
+		if err != nil {
+			return nil, err
+		}
+		minLevel, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, fmt.Errorf("min_level: %w", err)
+		}
+		var cfg ChatConfig
+		platform, err := paramString(params, "platform", "")
+		if err != nil {
+			return nil, err
+		}
+		cfg.Platform = ChatPlatform(platform)
+		if cfg.Template, err = paramString(params, "template", ""); err != nil {
+			return nil, err
+		}
+		if cfg.RateLimit, err = paramInt(params, "rate_limit", 0); err != nil {
+			return nil, err
+		}
+		if cfg.RatePeriod, err = paramDuration(params, "rate_period", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		return WithChatProvider(webhook, minLevel, cfg), nil
+	})
+	register("fluent", func(params map[string]any) (LoggerOption, error) {
+		network, err := paramString(params, "network", "tcp")
+		if err != nil {
+			return nil, err
+		}
+		address, err := paramString(params, "address", "localhost")
+		if err != nil {
+			return nil, err
+		}
+		var cfg FluentConfig
+		if cfg.Tag, err = paramString(params, "tag", ""); err != nil {
+			return nil, err
+		}
+		if cfg.TagLoggerName, err = paramBool(params, "tag_logger_name", false); err != nil {
+			return nil, err
+		}
+		if cfg.Ack, err = paramBool(params, "ack", false); err != nil {
+			return nil, err
+		}
+		if cfg.AckTimeout, err = paramDuration(params, "ack_timeout", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		if cfg.BatchSize, err = paramInt(params, "batch_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.FlushInterval, err = paramDuration(params, "flush_interval", 0); err != nil {
+			return nil, err
+		}
+		var options []ProviderOption
+		if enc, err := paramString(params, "encoder", ""); err != nil {
+			return nil, err
+		} else if enc != "" {
+			options = append(options, WithProviderEncoder(EncoderType(enc)))
+		}
+		return WithFluentProvider(network, address, cfg, options...), nil
+	})
+	register("grpc", func(params map[string]any) (LoggerOption, error) {
+		target, err := paramString(params, "target", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg GRPCConfig
+		if cfg.Method, err = paramString(params, "method", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Metadata, err = paramStringMap(params, "metadata"); err != nil {
+			return nil, err
+		}
+		if cfg.QueueSize, err = paramInt(params, "queue_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Block, err = paramBool(params, "block", false); err != nil {
+			return nil, err
+		}
+		if cfg.BatchSize, err = paramInt(params, "batch_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.FlushInterval, err = paramDuration(params, "flush_interval", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		return WithGRPCProvider(target, cfg), nil
+	})
+	incidentConfig := func(params map[string]any) (cfg IncidentConfig, err error) {
+		if cfg.Endpoint, err = paramString(params, "endpoint", ""); err != nil {
+			return cfg, err
+		}
+		if cfg.Errors, err = paramBool(params, "errors", false); err != nil {
+			return cfg, err
+		}
+		if cfg.DedupField, err = paramString(params, "dedup_field", ""); err != nil {
+			return cfg, err
+		}
+		if cfg.Source, err = paramString(params, "source", ""); err != nil {
+			return cfg, err
+		}
+		if cfg.Group, err = paramString(params, "group", ""); err != nil {
+			return cfg, err
+		}
+		if cfg.Class, err = paramString(params, "class", ""); err != nil {
+			return cfg, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return cfg, err
+		}
+		tags, err := paramString(params, "tags", "")
+		if err != nil {
+			return cfg, err
+		}
+		if tags != "" {
+			cfg.Tags = strings.Split(tags, ",")
+		}
+		return cfg, nil
+	}
+	register("pagerduty", func(params map[string]any) (LoggerOption, error) {
+		key, err := paramString(params, "routing_key", "")
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := incidentConfig(params)
+		if err != nil {
+			return nil, err
+		}
+		return WithPagerDutyProvider(key, cfg), nil
+	})
+	register("opsgenie", func(params map[string]any) (LoggerOption, error) {
+		key, err := paramString(params, "api_key", "")
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := incidentConfig(params)
+		if err != nil {
+			return nil, err
+		}
+		return WithOpsgenieProvider(key, cfg), nil
+	})
+	register("sentry", func(params map[string]any) (LoggerOption, error) {
+		dsn, err := paramString(params, "dsn", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg SentryConfig
+		if cfg.Environment, err = paramString(params, "environment", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Release, err = paramString(params, "release", ""); err != nil {
+			return nil, err
+		}
+		if cfg.ServerName, err = paramString(params, "server_name", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Tags, err = paramStringMap(params, "tags"); err != nil {
+			return nil, err
+		}
+		if cfg.MaxBreadcrumbs, err = paramInt(params, "max_breadcrumbs", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		return WithSentryProvider(dsn, cfg), nil
+	})
+	register("smtp", func(params map[string]any) (LoggerOption, error) {
+		addr, err := paramString(params, "address", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg SMTPConfig
+		if cfg.From, err = paramString(params, "from", ""); err != nil {
+			return nil, err
+		}
+		to, err := paramString(params, "to", "")
+		if err != nil {
+			return nil, err
+		}
+		if to != "" {
+			cfg.To = strings.Split(to, ",")
+		}
+		if cfg.Username, err = paramString(params, "username", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Password, err = paramString(params, "password", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Interval, err = paramDuration(params, "interval", 0); err != nil {
+			return nil, err
+		}
+		if cfg.MaxEntries, err = paramInt(params, "max_entries", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Subject, err = paramString(params, "subject", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Template, err = paramString(params, "template", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		return WithSMTPProvider(addr, cfg), nil
+	})
+	register("socket", func(params map[string]any) (LoggerOption, error) {
+		network, err := paramString(params, "network", "")
+		if err != nil {
+			return nil, err
+		}
+		address, err := paramString(params, "address", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg SocketConfig
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		if cfg.BufferSize, err = paramInt(params, "buffer_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.ReconnectInterval, err = paramDuration(params, "reconnect_interval", 0); err != nil {
+			return nil, err
+		}
+		var options []ProviderOption
+		if enc, err := paramString(params, "encoder", ""); err != nil {
+			return nil, err
+		} else if enc != "" {
+			options = append(options, WithProviderEncoder(EncoderType(enc)))
+		}
+		return WithSocketProvider(network, address, cfg, options...), nil
+	})
+	register("splunk", func(params map[string]any) (LoggerOption, error) {
+		endpoint, err := paramString(params, "endpoint", "")
+		if err != nil {
+			return nil, err
+		}
+		var cfg SplunkConfig
+		if cfg.Token, err = paramString(params, "token", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Index, err = paramString(params, "index", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Source, err = paramString(params, "source", ""); err != nil {
+			return nil, err
+		}
+		if cfg.SourceType, err = paramString(params, "sourcetype", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Host, err = paramString(params, "host", ""); err != nil {
+			return nil, err
+		}
+		if cfg.Ack, err = paramBool(params, "ack", false); err != nil {
+			return nil, err
+		}
+		if cfg.Channel, err = paramString(params, "channel", ""); err != nil {
+			return nil, err
+		}
+		if cfg.AckTimeout, err = paramDuration(params, "ack_timeout", 0); err != nil {
+			return nil, err
+		}
+		if cfg.ProxyURL, err = paramString(params, "proxy_url", ""); err != nil {
+			return nil, err
+		}
+		compression, err := paramString(params, "compression", "")
+		if err != nil {
+			return nil, err
+		}
+		cfg.Compression = Compression(compression)
+		if cfg.BatchSize, err = paramInt(params, "batch_size", 0); err != nil {
+			return nil, err
+		}
+		if cfg.Timeout, err = paramDuration(params, "timeout", 0); err != nil {
+			return nil, err
+		}
+		if cfg.FlushInterval, err = paramDuration(params, "flush_interval", 0); err != nil {
+			return nil, err
+		}
+		var options []ProviderOption
+		if enc, err := paramString(params, "encoder", ""); err != nil {
+			return nil, err
+		} else if enc != "" {
+			options = append(options, WithProviderEncoder(EncoderType(enc)))
+		}
+		return WithSplunkProvider(endpoint, cfg, options...), nil
+	})
+}
+
+/* -------------------------------------------------------------------------- */
+/*                              Param Helpers                                 */
+/* -------------------------------------------------------------------------- */
+
+// knownParams makes factory, a built-in reading all of its params through
+// the helpers below, reject keys it does not know. The helpers remove each
+// key they read from the copy factory is given, so what remains afterwards
+// was never read. "fields" is handled by WithNamedProvider.
+func knownParams(factory ProviderFactory) ProviderFactory {
+	return func(params map[string]any) (LoggerOption, error) {
+		rest := make(map[string]any, len(params))
+		for k, v := range params {
+			if k != "fields" {
+				rest[k] = v
+			}
+		}
+		opt, err := factory(rest)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) > 0 {
+			keys := make([]string, 0, len(rest))
+			for k := range rest {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			return nil, fmt.Errorf("unknown params %s", strings.Join(keys, ", "))
+		}
+		return opt, nil
+	}
+}
+
+// The helpers below accept the shapes JSON and YAML decoders produce
+// (float64 vs int, etc.), return def for missing keys and remove key from
+// params (see knownParams).
+
+func paramString(params map[string]any, key, def string) (string, error) {
+	v, ok := params[key]
+	delete(params, key)
+	if !ok || v == nil {
+		return def, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s: expected string, got %T", key, v)
+	}
+	return s, nil
+}
+
+func paramInt(params map[string]any, key string, def int) (int, error) {
+	v, ok := params[key]
+	delete(params, key)
+	if !ok || v == nil {
+		return def, nil
+	}
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case uint64:
+		return int(n), nil
+	case float64:
+		if n != float64(int(n)) {
+			return 0, fmt.Errorf("%s: expected integer, got %v", key, n)
+		}
+		return int(n), nil
+	default:
+		return 0, fmt.Errorf("%s: expected integer, got %T", key, v)
+	}
+}
+
+func paramBool(params map[string]any, key string, def bool) (bool, error) {
+	v, ok := params[key]
+	delete(params, key)
+	if !ok || v == nil {
+		return def, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s: expected bool, got %T", key, v)
+	}
+	return b, nil
+}
+
+// paramDuration accepts Go duration strings ("1.5s") or numbers of seconds.
+func paramDuration(params map[string]any, key string, def time.Duration) (time.Duration, error) {
+	v, ok := params[key]
+	delete(params, key)
+	if !ok || v == nil {
+		return def, nil
+	}
+	if s, ok := v.(string); ok {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", key, err)
+		}
+		return d, nil
+	}
+	switch n := v.(type) {
+	case int:
+		return time.Duration(n) * time.Second, nil
+	case int64:
+		return time.Duration(n) * time.Second, nil
+	case float64:
+		return time.Duration(n * float64(time.Second)), nil
+	default:
+		return 0, fmt.Errorf("%s: expected duration, got %T", key, v)
+	}
+}
+
+func paramStringMap(params map[string]any, key string) (map[string]string, error) {
+	v, ok := params[key]
+	delete(params, key)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected object, got %T", key, v)
+	}
+	out := make(map[string]string, len(m))
+	for k, e := range m {
+		s, ok := e.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s: expected string, got %T", key, k, e)
+		}
+		out[k] = s
+	}
+	return out, nil
+}
+
+func paramStringList(params map[string]any, key string) ([]string, error) {
+	v, ok := params[key]
+	delete(params, key)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	raw, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected list, got %T", key, v)
+	}
+	out := make([]string, len(raw))
+	for i, e := range raw {
+		s, ok := e.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d]: expected string, got %T", key, i, e)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+// mapToFields converts m into Fields sorted by key.
+func mapToFields(m map[string]any) []Field {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]Field, len(keys))
+	for i, k := range keys {
+		fields[i] = Field{Key: k, Value: m[k]}
+	}
+	return fields
+}
@@ -0,0 +1,58 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// registerTestFactory guards against double registration under -count>1.
+var registerTestFactory sync.Once
+
+func TestProviderRegistry(t *testing.T) {
+	registerTestFactory.Do(func() {
+		RegisterProviderFactory("test-buffer", func(params map[string]any) (LoggerOption, error) {
+			enc, err := paramString(params, "encoder", string(JSONEncoder))
+			if err != nil {
+				return nil, err
+			}
+			return WithWriterProvider(params["writer"].(io.Writer), EncoderType(enc)), nil
+		})
+	})
+
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithNamedProvider("test-buffer", map[string]any{"encoder": "json", "writer": &buf}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("from registry")
+	logger.Close()
+	if !strings.Contains(buf.String(), "from registry") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	names := strings.Join(ProviderFactories(), ",")
+	for _, want := range []string{"axiom", "bigquery", "chat", "cloudwatch", "elasticsearch", "eventlog", "file", "firehose", "fluent", "gcp", "grpc", "http", "kinesis", "opensearch", "opsgenie", "pagerduty", "sentry", "smtp", "socket", "splunk", "sqs", "stderr", "stdout", "syslog", "test-buffer"} {
+		if !strings.Contains(names, want) {
+			t.Errorf("factory %q not registered: %s", want, names)
+		}
+	}
+}
+
+func TestProviderRegistry_Errors(t *testing.T) {
+	if _, err := NewLogger(WithNamedProvider("no-such-provider", nil)); err == nil || !strings.Contains(err.Error(), "no-such-provider") {
+		t.Errorf("expected unknown provider error, got %v", err)
+	}
+	if _, err := NewLogger(WithNamedProvider("file", map[string]any{"filename": "x.log", "max_size": "big"})); err == nil || !strings.Contains(err.Error(), "max_size") {
+		t.Errorf("expected param error, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate registration")
+		}
+	}()
+	RegisterProviderFactory("stdout", func(map[string]any) (LoggerOption, error) { return nil, nil })
+}
@@ -0,0 +1,228 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          Config File Hot Reloading                          */
+/* -------------------------------------------------------------------------- */
+
+// ReloadConfig rereads the file the logger was built from with NewFromConfig
+// and applies its changes to the running logger: the level, named levels and
+// providers. Providers whose entries are unchanged keep running; removed ones
+// are closed after the new set is in place, so no entry is lost in between.
+// Other settings only take effect on restart and are reported to the error
+// handler when they change. An invalid file leaves the logger as it was.
+func (l *Logger) ReloadConfig() error {
+	if l.root != nil {
+		return l.root.ReloadConfig()
+	}
+	if l.source == nil || l.source.path == "" {
+		return errors.New("ReloadConfig: logger was not built with NewFromConfig")
+	}
+	data, err := os.ReadFile(l.source.path)
+	if err != nil {
+		return fmt.Errorf("ReloadConfig: %w", err)
+	}
+	fc, err := parseFileConfig(data)
+	if err != nil {
+		return fmt.Errorf("ReloadConfig: %s: %w", l.source.path, err)
+	}
+	if err := l.source.apply(l, fc); err != nil {
+		return fmt.Errorf("ReloadConfig: %s: %w", l.source.path, err)
+	}
+	return nil
+}
+
+// WatchConfig calls ReloadConfig whenever the config file changes, until the
+// logger is closed. Reload errors go to the error handler. The file's
+// directory is watched rather than the file, so editors that replace the
+// file and Kubernetes ConfigMap updates, which swap a symlink, are seen.
+func (l *Logger) WatchConfig() error {
+	if l.root != nil {
+		return l.root.WatchConfig()
+	}
+	if l.source == nil || l.source.path == "" {
+		return errors.New("WatchConfig: logger was not built with NewFromConfig")
+	}
+	l.source.mu.Lock()
+	defer l.source.mu.Unlock()
+	if l.source.watching {
+		return errors.New("WatchConfig: already watching")
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("WatchConfig: %w", err)
+	}
+	path := filepath.Clean(l.source.path)
+	if err := w.Add(filepath.Dir(path)); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("WatchConfig: %w", err)
+	}
+	l.source.watching = true
+
+	l.bg.Add(1)
+	go l.watchConfig(w, path)
+	return nil
+}
+
+func (l *Logger) watchConfig(w *fsnotify.Watcher, path string) {
+	defer l.bg.Done()
+	defer w.Close()
+
+	resolved, _ := filepath.EvalSymlinks(path)
+	for {
+		select {
+		case <-l.stop:
+			return
+		case ev, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			// A missing file is mid-replacement; the create that follows
+			// triggers the reload.
+			current, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				continue
+			}
+			if filepath.Clean(ev.Name) != path && current == resolved {
+				continue
+			}
+			resolved = current
+			if err := l.ReloadConfig(); err != nil {
+				l.telemetry.errs.report(err)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			l.telemetry.errs.report(fmt.Errorf("WatchConfig: %w", err))
+		}
+	}
+}
+
+// configSource is the config file a logger was built from and the settings
+// last applied from it.
+type configSource struct {
+	path string
+
+	mu       sync.Mutex
+	current  *fileConfig
+	watching bool
+}
+
+// apply moves l from the settings in s.current to those in fc.
+func (s *configSource) apply(l *Logger, fc *fileConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.current
+
+	// Providers are identified by their entry, so an edited entry is
+	// replaced and an unchanged one keeps running.
+	prevKeys := providerKeys(prev.Providers)
+	nextKeys := providerKeys(fc.Providers)
+	kept := make(map[string]bool, len(prevKeys))
+	for _, key := range prevKeys {
+		kept[key] = false
+	}
+	var added []addedEntry
+	closeAdded := func() {
+		for _, e := range added {
+			l.added.discard(e)
+		}
+	}
+	for i, entry := range fc.Providers {
+		key := nextKeys[i]
+		if _, ok := kept[key]; ok {
+			kept[key] = true
+			continue
+		}
+		opt, err := providerFromConfig(entry)
+		if err == nil {
+			var e addedEntry
+			if e, err = l.added.build(key, opt); err == nil {
+				added = append(added, e)
+				continue
+			}
+		}
+		closeAdded()
+		return fmt.Errorf("providers[%d]: %w", i, err)
+	}
+	remove := make(map[string]bool)
+	for key, ok := range kept {
+		if !ok {
+			remove[key] = true
+		}
+	}
+	removed, err := l.added.replace(remove, added)
+	if err != nil {
+		closeAdded()
+		return err
+	}
+	if err := closeProviders(removed); err != nil {
+		l.telemetry.errs.report(fmt.Errorf("config reload: %w", err))
+	}
+
+	if !reflect.DeepEqual(prev.Level, fc.Level) {
+		level := InfoLevel
+		if fc.Level != nil {
+			level = *fc.Level
+		}
+		l.SetLevel(level)
+	}
+	for name := range prev.NamedLevels {
+		if _, ok := fc.NamedLevels[name]; !ok {
+			l.ResetNamedLevel(name)
+		}
+	}
+	for name, level := range fc.NamedLevels {
+		if old, ok := prev.NamedLevels[name]; !ok || old != level {
+			l.SetNamedLevel(name, level)
+		}
+	}
+
+	if !sameRestartSettings(prev, fc) {
+		l.telemetry.errs.report(errors.New("config reload: settings other than level, named_levels and providers take effect on restart"))
+	}
+	s.current = fc
+	return nil
+}
+
+// providerKeys identifies each providers entry by its contents. fmt prints
+// maps with sorted keys; repeated entries are numbered to keep them
+// distinct.
+func providerKeys(entries []map[string]any) []string {
+	keys := make([]string, len(entries))
+	seen := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		key := fmt.Sprintf("%v", entry)
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%s#%d", key, n)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// sameRestartSettings reports whether a and b agree on everything reloading
+// cannot change.
+func sameRestartSettings(a, b *fileConfig) bool {
+	x, y := *a, *b
+	x.Level, y.Level = nil, nil
+	x.NamedLevels, y.NamedLevels = nil, nil
+	x.Providers, y.Providers = nil, nil
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,155 @@
+package golog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, path, yaml string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestReloadConfig(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.log")
+	second := filepath.Join(dir, "second.log")
+	cfgFile := filepath.Join(dir, "golog.yaml")
+	writeConfig(t, cfgFile, `
+level: warn
+named_levels:
+  db: debug
+providers:
+  - type: file
+    filename: `+first+`
+`)
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	logger, err := NewFromConfig(cfgFile, WithErrorHandler(func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}))
+	if err != nil {
+		t.Fatalf("NewFromConfig: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("before")
+	logger.Named("db").Debug("query before")
+
+	writeConfig(t, cfgFile, `
+level: info
+providers:
+  - type: file
+    filename: `+second+`
+`)
+	if err := logger.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+	logger.Info("after")
+	logger.Named("db").Debug("query after")
+
+	if out := readLog(t, first); strings.Contains(out, `"msg":"before"`) || !strings.Contains(out, "query before") ||
+		strings.Contains(out, "after") {
+		t.Errorf("unexpected output from the removed provider: %s", out)
+	}
+	if out := readLog(t, second); !strings.Contains(out, `"msg":"after"`) || strings.Contains(out, "query after") {
+		t.Errorf("unexpected output from the added provider: %s", out)
+	}
+	if p := logger.Config().Providers; len(p) != 1 {
+		t.Errorf("expected one provider, got %+v", p)
+	}
+	// The removed provider's counters go with it.
+	if p := logger.Stats().Providers; len(p) != 1 || !strings.Contains(p[0].Name, "second.log") {
+		t.Errorf("expected stats for the added provider only, got %+v", p)
+	}
+
+	// A broken file leaves the running configuration alone.
+	writeConfig(t, cfgFile, "level: loud\n")
+	if err := logger.ReloadConfig(); err == nil {
+		t.Error("expected an error for an invalid file")
+	}
+	if logger.Level() != InfoLevel {
+		t.Errorf("level changed to %v", logger.Level())
+	}
+
+	// Settings that need a restart are reported, not applied.
+	writeConfig(t, cfgFile, `
+level: info
+log_id: true
+providers:
+  - type: file
+    filename: `+second+`
+`)
+	if err := logger.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "restart") {
+		t.Errorf("expected a restart notice, got %v", errs)
+	}
+}
+
+func TestReloadConfigWithoutFile(t *testing.T) {
+	logger, err := NewFromConfigBytes([]byte("level: info\n"))
+	if err != nil {
+		t.Fatalf("NewFromConfigBytes: %v", err)
+	}
+	defer logger.Close()
+	if err := logger.ReloadConfig(); err == nil {
+		t.Error("expected an error without a config file")
+	}
+	if err := logger.WatchConfig(); err == nil {
+		t.Error("expected an error without a config file")
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := filepath.Join(dir, "golog.yaml")
+	writeConfig(t, cfgFile, "level: info\n")
+	logger, err := NewFromConfig(cfgFile, WithWriterProvider(&strings.Builder{}, JSONEncoder))
+	if err != nil {
+		t.Fatalf("NewFromConfig: %v", err)
+	}
+	defer logger.Close()
+	if err := logger.WatchConfig(); err != nil {
+		t.Fatalf("WatchConfig: %v", err)
+	}
+	if err := logger.WatchConfig(); err == nil {
+		t.Error("expected an error when already watching")
+	}
+
+	// Replace the file the way editors and ConfigMap updates do.
+	tmp := filepath.Join(dir, "golog.yaml.tmp")
+	writeConfig(t, tmp, "level: debug\n")
+	if err := os.Rename(tmp, cfgFile); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for logger.Level() != DebugLevel {
+		if time.Now().After(deadline) {
+			t.Fatalf("level not reloaded, still %v", logger.Level())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,202 @@
+package golog
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                        Retry-with-Backoff Wrapper                           */
+/* -------------------------------------------------------------------------- */
+
+// RetryPolicy controls how failed provider writes are retried. Zero values
+// fall back to the defaults noted on each field.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first (default 3).
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry (default 100ms).
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts (default 5s).
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after each attempt (default 2).
+	Multiplier float64
+	// Jitter randomises each delay by ±Jitter×delay, 0–1 (default 0.2).
+	Jitter float64
+	// Retryable classifies errors. When nil every error is retried except
+	// those wrapped with PermanentError.
+	Retryable func(error) bool
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Jitter <= 0 || p.Jitter > 1 {
+		p.Jitter = 0.2
+	}
+	if p.Retryable == nil {
+		p.Retryable = isRetryable
+	}
+	return p
+}
+
+// backoff returns the delay to wait after the given (1-based) failed attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= p.Multiplier
+		if d >= float64(p.MaxBackoff) {
+			d = float64(p.MaxBackoff)
+			break
+		}
+	}
+	d += d * p.Jitter * (2*rand.Float64() - 1)
+	if d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	return time.Duration(d)
+}
+
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// PermanentError marks err as non-retryable. Providers return it for failures
+// that cannot succeed on a later attempt (bad credentials, rejected payload).
+func PermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func isRetryable(err error) bool {
+	var pe *permanentError
+	return !errors.As(err, &pe)
+}
+
+// WithRetry wraps every provider registered by opt so that failed writes are
+// retried according to policy. Retries happen on the logging goroutine, so
+// pair this with remote providers rather than latency-sensitive ones.
+//
+//	golog.WithRetry(golog.WithWriterProvider(conn, golog.JSONEncoder), golog.RetryPolicy{MaxAttempts: 5})
+func WithRetry(opt LoggerOption, policy RetryPolicy) LoggerOption {
+	return wrapProviders(opt, func(p provider) provider {
+		return &retryProvider{inner: p, policy: policy.withDefaults(), done: make(chan struct{})}
+	})
+}
+
+// wrapProviders applies opt and passes each provider it registered through
+// wrap. Any other settings opt changes are left untouched.
+func wrapProviders(opt LoggerOption, wrap func(provider) provider) LoggerOption {
+	return func(cfg *loggerConfig) {
+		before := len(cfg.providers)
+		opt(cfg)
+		for i := before; i < len(cfg.providers); i++ {
+			cfg.providers[i] = wrap(cfg.providers[i])
+		}
+	}
+}
+
+type retryProvider struct {
+	inner  provider
+	policy RetryPolicy
+	tel    *telemetry
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (p *retryProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	core, err := p.inner.newCore(level)
+	if err != nil {
+		return nil, err
+	}
+	return &retryCore{Core: core, p: p}, nil
+}
+
+func (p *retryProvider) close() error {
+	// Abort any in-flight backoff so shutdown is not delayed.
+	p.closeOnce.Do(func() { close(p.done) })
+	return p.inner.close()
+}
+
+func (p *retryProvider) instrument(t *telemetry) {
+	p.tel = t
+	if ip, ok := p.inner.(instrumentedProvider); ok {
+		ip.instrument(t)
+	}
+}
+
+func (p *retryProvider) describe() ProviderInfo {
+	info := describeProvider(p.inner)
+	info.Name = "retry(" + info.Name + ")"
+	return info
+}
+
+type retryCore struct {
+	zapcore.Core
+	p *retryProvider
+	// fields mirrors the context added via With, for dead-lettering.
+	fields []zapcore.Field
+}
+
+func (c *retryCore) With(fields []zapcore.Field) zapcore.Core {
+	return &retryCore{
+		Core:   c.Core.With(fields),
+		p:      c.p,
+		fields: append(append([]zapcore.Field(nil), c.fields...), fields...),
+	}
+}
+
+func (c *retryCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *retryCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	policy := c.p.policy
+	var (
+		err     error
+		attempt int
+	)
+retry:
+	for attempt = 1; ; attempt++ {
+		if err = c.Core.Write(ent, fields); err == nil {
+			return nil
+		}
+		if attempt >= policy.MaxAttempts || !policy.Retryable(err) {
+			break
+		}
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-c.p.done:
+			timer.Stop()
+			break retry
+		}
+	}
+	if tel := c.p.tel; tel != nil {
+		tel.drops.record(DropProviderError, 1)
+		if dlErr := tel.deadLetters.write(describeProvider(c.p.inner).Name, attempt, err, ent, c.fields, fields); dlErr != nil {
+			tel.errs.report(dlErr)
+		}
+	}
+	return err
+}
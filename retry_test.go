@@ -0,0 +1,97 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flakyWriter fails the first `failures` writes with err, then succeeds.
+type flakyWriter struct {
+	failures int
+	err      error
+	calls    int
+	buf      bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.calls++
+	if w.calls <= w.failures {
+		return 0, w.err
+	}
+	return w.buf.Write(p)
+}
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+func TestWithRetry_RecoversFromTransientFailures(t *testing.T) {
+	w := &flakyWriter{failures: 2, err: errors.New("connection reset")}
+	logger, err := NewLogger(WithRetry(WithWriterProvider(w, JSONEncoder), fastRetry))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("eventually delivered")
+
+	if w.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", w.calls)
+	}
+	if !strings.Contains(w.buf.String(), "eventually delivered") {
+		t.Fatalf("entry was not delivered: %q", w.buf.String())
+	}
+	if st := logger.Stats(); st.Providers[0].Name != "retry(writer)" || st.Providers[0].Writes != 1 {
+		t.Fatalf("unexpected provider stats: %+v", st.Providers[0])
+	}
+}
+
+func TestWithRetry_GivesUp(t *testing.T) {
+	w := &flakyWriter{failures: 10, err: errors.New("connection reset")}
+	logger, err := NewLogger(
+		WithRetry(WithWriterProvider(w, JSONEncoder), fastRetry),
+		WithErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("lost")
+
+	if w.calls != fastRetry.MaxAttempts {
+		t.Fatalf("expected %d attempts, got %d", fastRetry.MaxAttempts, w.calls)
+	}
+	if got := logger.DroppedEntries()[DropProviderError]; got != 1 {
+		t.Fatalf("expected one provider_error drop, got %d", got)
+	}
+}
+
+func TestWithRetry_PermanentErrorsAreNotRetried(t *testing.T) {
+	w := &flakyWriter{failures: 10, err: PermanentError(errors.New("payload rejected"))}
+	logger, err := NewLogger(
+		WithRetry(WithWriterProvider(w, JSONEncoder), fastRetry),
+		WithErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("rejected")
+
+	if w.calls != 1 {
+		t.Fatalf("expected a single attempt for a permanent error, got %d", w.calls)
+	}
+}
+
+func TestRetryPolicy_BackoffIsBounded(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}.withDefaults()
+	for attempt := 1; attempt <= 10; attempt++ {
+		d := p.backoff(attempt)
+		if d <= 0 || d > p.MaxBackoff {
+			t.Fatalf("attempt %d: backoff %v outside (0, %v]", attempt, d, p.MaxBackoff)
+		}
+	}
+}
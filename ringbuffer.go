@@ -0,0 +1,158 @@
+package golog
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          In-Memory Ring Buffer                              */
+/* -------------------------------------------------------------------------- */
+
+// Entry is a decoded log record as retained by the in-memory ring buffer.
+type Entry struct {
+	Time    time.Time              `json:"time"`
+	Level   Level                  `json:"level"`
+	Logger  string                 `json:"logger,omitempty"`
+	Message string                 `json:"message"`
+	Caller  string                 `json:"caller,omitempty"`
+	Stack   string                 `json:"stack,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// ringBuffer keeps the most recent entries in a fixed-size circular slice
+// and forwards new ones to live subscribers (see Logger.Tail).
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+	subs    map[chan Entry]struct{}
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{entries: make([]Entry, size)}
+}
+
+func (r *ringBuffer) add(e Entry) {
+	r.mu.Lock()
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	for ch := range r.subs {
+		// Never block logging on a slow reader.
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+	r.mu.Unlock()
+}
+
+// subscribe returns the buffered entries and a channel receiving every entry
+// added afterwards, with no gap between the two. cancel must be called to
+// unsubscribe.
+func (r *ringBuffer) subscribe(size int) (history []Entry, ch chan Entry, cancel func()) {
+	ch = make(chan Entry, size)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	history = r.snapshotLocked()
+	if r.subs == nil {
+		r.subs = make(map[chan Entry]struct{})
+	}
+	r.subs[ch] = struct{}{}
+	return history, ch, func() {
+		r.mu.Lock()
+		delete(r.subs, ch)
+		r.mu.Unlock()
+	}
+}
+
+// snapshot returns the buffered entries, oldest first.
+func (r *ringBuffer) snapshot() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshotLocked()
+}
+
+func (r *ringBuffer) snapshotLocked() []Entry {
+	if !r.full {
+		return append([]Entry(nil), r.entries[:r.next]...)
+	}
+	out := make([]Entry, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+// ringCore is the zapcore.Core feeding a ringBuffer.
+type ringCore struct {
+	zapcore.LevelEnabler
+	buf    *ringBuffer
+	fields []zapcore.Field
+}
+
+func (c *ringCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+func (c *ringCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *ringCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.buf.add(decodeEntry(ent, c.fields, fields))
+	return nil
+}
+
+func (c *ringCore) Sync() error { return nil }
+
+// decodeEntry converts a zap entry and its fields into an Entry.
+func decodeEntry(ent zapcore.Entry, ctxFields, fields []zapcore.Field) Entry {
+	e := Entry{
+		Time:    ent.Time,
+		Level:   fromZapLevel(ent.Level),
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+		Stack:   ent.Stack,
+	}
+	if ent.Caller.Defined {
+		e.Caller = ent.Caller.TrimmedPath()
+	}
+	if len(ctxFields)+len(fields) > 0 {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, f := range ctxFields {
+			f.AddTo(enc)
+		}
+		for _, f := range fields {
+			f.AddTo(enc)
+		}
+		e.Fields = enc.Fields
+	}
+	return e
+}
+
+// WithRingBuffer keeps the last size entries in memory so they can be
+// inspected at runtime (see Logger.RecentEntries and Logger.DebugHandler).
+func WithRingBuffer(size int) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.ringBufferSize = size
+	}
+}
+
+// RecentEntries returns the entries currently held in the ring buffer, oldest
+// first. It returns nil when WithRingBuffer was not configured.
+func (l *Logger) RecentEntries() []Entry {
+	if l.ring == nil {
+		return nil
+	}
+	return l.ring.snapshot()
+}
@@ -0,0 +1,259 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                        Field-Based Conditional Routing                      */
+/* -------------------------------------------------------------------------- */
+
+// Matcher decides whether an entry is routed. The Entry passed in has its
+// Fields populated with both logger-scoped and call-site fields.
+type Matcher func(Entry) bool
+
+// MatchField matches entries carrying key with the given value. Values are
+// compared by their fmt.Sprint representation so Int(…, 1) matches 1, "1",
+// int64(1) and so on.
+func MatchField(key string, value interface{}) Matcher {
+	want := fmt.Sprint(value)
+	return func(e Entry) bool {
+		v, ok := e.Fields[key]
+		return ok && fmt.Sprint(v) == want
+	}
+}
+
+// MatchFieldPresent matches entries carrying key, whatever its value.
+func MatchFieldPresent(key string) Matcher {
+	return func(e Entry) bool {
+		_, ok := e.Fields[key]
+		return ok
+	}
+}
+
+// MatchLevel matches entries at or above min.
+func MatchLevel(min Level) Matcher {
+	return func(e Entry) bool { return e.Level >= min }
+}
+
+// MatchMessage matches entries whose message matches re.
+func MatchMessage(re *regexp.Regexp) Matcher {
+	return func(e Entry) bool { return re.MatchString(e.Message) }
+}
+
+// MatchAll matches when every matcher does.
+func MatchAll(ms ...Matcher) Matcher {
+	return func(e Entry) bool {
+		for _, m := range ms {
+			if !m(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchAny matches when at least one matcher does.
+func MatchAny(ms ...Matcher) Matcher {
+	return func(e Entry) bool {
+		for _, m := range ms {
+			if m(e) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type route struct {
+	match     Matcher
+	exclusive bool
+	providers []provider
+}
+
+// WithRoute sends entries accepted by match to the providers registered by
+// opt, in addition to the regular providers. Routed providers receive nothing
+// else.
+//
+//	golog.WithRoute(golog.MatchField("component", "billing"), golog.WithFileProvider("billing.log", 10, 3, 7, true))
+func WithRoute(match Matcher, opt LoggerOption) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.routes = append(cfg.routes, route{match: match, providers: providersOf(opt)})
+	}
+}
+
+// WithExclusiveRoute is like WithRoute, but matching entries go *only* to
+// the route's providers and are withheld from the regular ones – e.g. audit
+// records that must not reach the general log stream.
+func WithExclusiveRoute(match Matcher, opt LoggerOption) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.routes = append(cfg.routes, route{match: match, exclusive: true, providers: providersOf(opt)})
+	}
+}
+
+type builtRoute struct {
+	match     Matcher
+	exclusive bool
+	cores     []zapcore.Core
+}
+
+// routerCore evaluates routes once per entry and dispatches to the regular
+// providers and/or the matching routes.
+type routerCore struct {
+	def    []zapcore.Core
+	routes []builtRoute
+	// fields mirrors the context added via With so matchers see it.
+	fields []zapcore.Field
+}
+
+func newRouterCore(def []zapcore.Core, routes []route, build func(provider) (zapcore.Core, error)) (*routerCore, error) {
+	rc := &routerCore{def: def}
+	for _, r := range routes {
+		if r.match == nil {
+			return nil, errors.New("route: matcher must not be nil")
+		}
+		br := builtRoute{match: r.match, exclusive: r.exclusive}
+		for _, p := range r.providers {
+			c, err := build(p)
+			if err != nil {
+				return nil, err
+			}
+			br.cores = append(br.cores, c)
+		}
+		rc.routes = append(rc.routes, br)
+	}
+	return rc, nil
+}
+
+func (c *routerCore) Enabled(lvl zapcore.Level) bool {
+	if anyEnabled(c.def, lvl) {
+		return true
+	}
+	for _, r := range c.routes {
+		if anyEnabled(r.cores, lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *routerCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &routerCore{
+		def:    withAll(c.def, fields),
+		routes: make([]builtRoute, len(c.routes)),
+		fields: append(append([]zapcore.Field(nil), c.fields...), fields...),
+	}
+	for i, r := range c.routes {
+		r.cores = withAll(r.cores, fields)
+		clone.routes[i] = r
+	}
+	return clone
+}
+
+func (c *routerCore) enabledFor(ent zapcore.Entry) bool {
+	if anyEntryEnabled(c.def, ent) {
+		return true
+	}
+	for _, r := range c.routes {
+		if anyEntryEnabled(r.cores, ent) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *routerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabledFor(ent) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *routerCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	decoded := decodeEntry(ent, c.fields, fields)
+	var (
+		errs     []error
+		excluded bool
+	)
+	for _, r := range c.routes {
+		if !r.match(decoded) {
+			continue
+		}
+		excluded = excluded || r.exclusive
+		errs = append(errs, writeEnabled(r.cores, ent, fields))
+	}
+	if !excluded {
+		errs = append(errs, writeEnabled(c.def, ent, fields))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *routerCore) Sync() error {
+	errs := []error{syncAll(c.def)}
+	for _, r := range c.routes {
+		errs = append(errs, syncAll(r.cores))
+	}
+	return errors.Join(errs...)
+}
+
+func anyEnabled(cores []zapcore.Core, lvl zapcore.Level) bool {
+	for _, c := range cores {
+		if c.Enabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+// entryEnabler is implemented by cores whose decision depends on more of the
+// entry than its level, such as the logger name (see gateCore).
+type entryEnabler interface {
+	enabledFor(ent zapcore.Entry) bool
+}
+
+func entryEnabled(c zapcore.Core, ent zapcore.Entry) bool {
+	if e, ok := c.(entryEnabler); ok {
+		return e.enabledFor(ent)
+	}
+	return c.Enabled(ent.Level)
+}
+
+func anyEntryEnabled(cores []zapcore.Core, ent zapcore.Entry) bool {
+	for _, c := range cores {
+		if entryEnabled(c, ent) {
+			return true
+		}
+	}
+	return false
+}
+
+func withAll(cores []zapcore.Core, fields []zapcore.Field) []zapcore.Core {
+	out := make([]zapcore.Core, len(cores))
+	for i, c := range cores {
+		out[i] = c.With(fields)
+	}
+	return out
+}
+
+// writeEnabled writes ent to every core whose level admits it.
+func writeEnabled(cores []zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) error {
+	var errs []error
+	for _, c := range cores {
+		if entryEnabled(c, ent) {
+			errs = append(errs, c.Write(ent, fields))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func syncAll(cores []zapcore.Core) error {
+	var errs []error
+	for _, c := range cores {
+		errs = append(errs, c.Sync())
+	}
+	return errors.Join(errs...)
+}
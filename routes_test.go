@@ -0,0 +1,60 @@
+package golog
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRouting(t *testing.T) {
+	var general, billing, audit bytes.Buffer
+	logger, err := NewLogger(
+		WithWriterProvider(&general, JSONEncoder),
+		WithRoute(MatchField("component", "billing"), WithWriterProvider(&billing, JSONEncoder)),
+		WithExclusiveRoute(MatchField("audit", true), WithWriterProvider(&audit, JSONEncoder)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("plain")
+	logger.Info("invoice sent", String("component", "billing"))
+	logger.Info("role granted", Any("audit", true))
+
+	if out := general.String(); !strings.Contains(out, "plain") || !strings.Contains(out, "invoice sent") || strings.Contains(out, "role granted") {
+		t.Errorf("unexpected general output: %s", out)
+	}
+	if out := billing.String(); strings.Count(out, "\n") != 1 || !strings.Contains(out, "invoice sent") {
+		t.Errorf("unexpected billing output: %s", out)
+	}
+	if out := audit.String(); strings.Count(out, "\n") != 1 || !strings.Contains(out, "role granted") {
+		t.Errorf("unexpected audit output: %s", out)
+	}
+	if got := len(logger.Stats().Providers); got != 3 {
+		t.Errorf("expected routed providers in stats, got %d providers", got)
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	e := Entry{Level: WarnLevel, Message: "GET /healthz", Fields: map[string]interface{}{"status": int64(200)}}
+	cases := []struct {
+		name string
+		m    Matcher
+		want bool
+	}{
+		{"field", MatchField("status", 200), true},
+		{"field mismatch", MatchField("status", 500), false},
+		{"present", MatchFieldPresent("status"), true},
+		{"level", MatchLevel(ErrorLevel), false},
+		{"message", MatchMessage(regexp.MustCompile(`^GET /health`)), true},
+		{"all", MatchAll(MatchLevel(WarnLevel), MatchField("status", 200)), true},
+		{"any", MatchAny(MatchLevel(ErrorLevel), MatchFieldPresent("missing")), false},
+	}
+	for _, tc := range cases {
+		if got := tc.m(e); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package golog
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                         JSON Schema Validation Mode                         */
+/* -------------------------------------------------------------------------- */
+
+// WithSchemaValidation validates every entry, rendered as the JSON encoder
+// would write it, against schema and reports violations as *SchemaError
+// through the error handler (see WithErrorHandler). Entries are still
+// written. It is meant for development and CI, so log contracts with
+// downstream consumers don't drift silently; in tests, fail on violations
+// with
+//
+//	golog.WithErrorHandler(func(err error) { t.Error(err) })
+//
+// The supported keywords are type, required, properties,
+// additionalProperties, items, enum, const, pattern, minLength, maxLength,
+// minimum, maximum, allOf and anyOf. An invalid schema makes NewLogger fail.
+func WithSchemaValidation(schema []byte) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.schema = schema
+	}
+}
+
+// SchemaError reports an entry that does not satisfy the configured schema.
+type SchemaError struct {
+	Message    string
+	Violations []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("schema violation in %q: %s", e.Message, strings.Join(e.Violations, "; "))
+}
+
+// jsonSchema is a compiled schema node.
+type jsonSchema struct {
+	types                []string
+	required             []string
+	properties           map[string]*jsonSchema
+	additionalProperties *jsonSchema
+	noAdditional         bool
+	items                *jsonSchema
+	enum                 []interface{}
+	constant             interface{}
+	hasConst             bool
+	pattern              *regexp.Regexp
+	minLength, maxLength *int
+	minimum, maximum     *float64
+	allOf, anyOf         []*jsonSchema
+}
+
+func compileSchema(raw []byte) (*jsonSchema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+	return compileSchemaNode(doc, "#")
+}
+
+func compileSchemaNode(doc interface{}, path string) (*jsonSchema, error) {
+	if b, ok := doc.(bool); ok {
+		// true accepts everything; false accepts nothing.
+		if b {
+			return &jsonSchema{}, nil
+		}
+		return &jsonSchema{anyOf: []*jsonSchema{}}, nil
+	}
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema %s: expected object, got %T", path, doc)
+	}
+	s := &jsonSchema{}
+	var err error
+	for key, v := range m {
+		at := path + "/" + key
+		switch key {
+		case "type":
+			switch t := v.(type) {
+			case string:
+				s.types = []string{t}
+			case []interface{}:
+				for _, e := range t {
+					name, ok := e.(string)
+					if !ok {
+						return nil, fmt.Errorf("schema %s: expected string", at)
+					}
+					s.types = append(s.types, name)
+				}
+			default:
+				return nil, fmt.Errorf("schema %s: expected string or array", at)
+			}
+		case "required":
+			list, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("schema %s: expected array", at)
+			}
+			for _, e := range list {
+				name, ok := e.(string)
+				if !ok {
+					return nil, fmt.Errorf("schema %s: expected string", at)
+				}
+				s.required = append(s.required, name)
+			}
+		case "properties":
+			props, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("schema %s: expected object", at)
+			}
+			s.properties = make(map[string]*jsonSchema, len(props))
+			for name, sub := range props {
+				if s.properties[name], err = compileSchemaNode(sub, at+"/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "additionalProperties":
+			if b, ok := v.(bool); ok {
+				s.noAdditional = !b
+				continue
+			}
+			if s.additionalProperties, err = compileSchemaNode(v, at); err != nil {
+				return nil, err
+			}
+		case "items":
+			if s.items, err = compileSchemaNode(v, at); err != nil {
+				return nil, err
+			}
+		case "enum":
+			list, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("schema %s: expected array", at)
+			}
+			s.enum = list
+		case "const":
+			s.constant, s.hasConst = v, true
+		case "pattern":
+			p, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("schema %s: expected string", at)
+			}
+			if s.pattern, err = regexp.Compile(p); err != nil {
+				return nil, fmt.Errorf("schema %s: %w", at, err)
+			}
+		case "minLength", "maxLength":
+			f, ok := v.(float64)
+			if !ok || f < 0 || f != math.Trunc(f) {
+				return nil, fmt.Errorf("schema %s: expected non-negative integer", at)
+			}
+			n := int(f)
+			if key == "minLength" {
+				s.minLength = &n
+			} else {
+				s.maxLength = &n
+			}
+		case "minimum", "maximum":
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("schema %s: expected number", at)
+			}
+			if key == "minimum" {
+				s.minimum = &f
+			} else {
+				s.maximum = &f
+			}
+		case "allOf", "anyOf":
+			list, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("schema %s: expected array", at)
+			}
+			subs := make([]*jsonSchema, len(list))
+			for i, e := range list {
+				if subs[i], err = compileSchemaNode(e, fmt.Sprintf("%s/%d", at, i)); err != nil {
+					return nil, err
+				}
+			}
+			if key == "allOf" {
+				s.allOf = subs
+			} else {
+				s.anyOf = subs
+			}
+		}
+		// Other keywords ($schema, title, description, …) are ignored.
+	}
+	return s, nil
+}
+
+// validate appends a message for every violation of s by v at path.
+func (s *jsonSchema) validate(v interface{}, path string, out []string) []string {
+	if len(s.types) > 0 {
+		ok := false
+		for _, t := range s.types {
+			if jsonTypeMatches(t, v) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return append(out, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.types, " or "), jsonTypeOf(v)))
+		}
+	}
+	if s.enum != nil {
+		found := false
+		for _, e := range s.enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, fmt.Sprintf("%s: %v is not one of %v", path, v, s.enum))
+		}
+	}
+	if s.hasConst && !reflect.DeepEqual(s.constant, v) {
+		out = append(out, fmt.Sprintf("%s: expected %v", path, s.constant))
+	}
+
+	switch x := v.(type) {
+	case string:
+		n := len([]rune(x))
+		if s.minLength != nil && n < *s.minLength {
+			out = append(out, fmt.Sprintf("%s: shorter than %d", path, *s.minLength))
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			out = append(out, fmt.Sprintf("%s: longer than %d", path, *s.maxLength))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(x) {
+			out = append(out, fmt.Sprintf("%s: does not match %s", path, s.pattern))
+		}
+	case float64:
+		if s.minimum != nil && x < *s.minimum {
+			out = append(out, fmt.Sprintf("%s: below minimum %v", path, *s.minimum))
+		}
+		if s.maximum != nil && x > *s.maximum {
+			out = append(out, fmt.Sprintf("%s: above maximum %v", path, *s.maximum))
+		}
+	case []interface{}:
+		if s.items != nil {
+			for i, e := range x {
+				out = s.items.validate(e, fmt.Sprintf("%s/%d", path, i), out)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.required {
+			if _, ok := x[name]; !ok {
+				out = append(out, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if sub, ok := s.properties[k]; ok {
+				out = sub.validate(x[k], path+"/"+k, out)
+				continue
+			}
+			if s.noAdditional {
+				out = append(out, fmt.Sprintf("%s: unexpected property %q", path, k))
+			} else if s.additionalProperties != nil {
+				out = s.additionalProperties.validate(x[k], path+"/"+k, out)
+			}
+		}
+	}
+
+	for _, sub := range s.allOf {
+		out = sub.validate(v, path, out)
+	}
+	if s.anyOf != nil {
+		ok := false
+		for _, sub := range s.anyOf {
+			if len(sub.validate(v, path, nil)) == 0 {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			out = append(out, fmt.Sprintf("%s: matches none of anyOf", path))
+		}
+	}
+	return out
+}
+
+func jsonTypeMatches(t string, v interface{}) bool {
+	switch t {
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	default:
+		return jsonTypeOf(v) == t
+	}
+}
+
+func jsonTypeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// schemaCore renders each entry with the JSON encoder, validates it and
+// passes it through unchanged.
+type schemaCore struct {
+	cores  []zapcore.Core
+	enc    zapcore.Encoder
+	schema *jsonSchema
+	report func(error)
+}
+
+func newSchemaCore(cores []zapcore.Core, raw []byte, report func(error)) (*schemaCore, error) {
+	schema, err := compileSchema(raw)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := buildEncoder(JSONEncoder)
+	if err != nil {
+		return nil, err
+	}
+	return &schemaCore{cores: cores, enc: enc, schema: schema, report: report}, nil
+}
+
+func (c *schemaCore) Enabled(lvl zapcore.Level) bool {
+	return anyEnabled(c.cores, lvl)
+}
+
+func (c *schemaCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &schemaCore{cores: withAll(c.cores, fields), enc: enc, schema: c.schema, report: c.report}
+}
+
+func (c *schemaCore) enabledFor(ent zapcore.Entry) bool {
+	return anyEntryEnabled(c.cores, ent)
+}
+
+func (c *schemaCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabledFor(ent) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *schemaCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err == nil {
+		var doc interface{}
+		if err = json.Unmarshal(buf.Bytes(), &doc); err == nil {
+			if violations := c.schema.validate(doc, "#", nil); len(violations) > 0 {
+				c.report(&SchemaError{Message: ent.Message, Violations: violations})
+			}
+		}
+		buf.Free()
+	}
+	if err != nil {
+		c.report(fmt.Errorf("schema validation: %w", err))
+	}
+	return writeEnabled(c.cores, ent, fields)
+}
+
+func (c *schemaCore) Sync() error {
+	return syncAll(c.cores)
+}
@@ -0,0 +1,69 @@
+package golog
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["level", "msg", "service"],
+	"properties": {
+		"level":   {"enum": ["info", "warn", "error"]},
+		"service": {"type": "string", "pattern": "^[a-z-]+$"},
+		"status":  {"type": "integer", "minimum": 100, "maximum": 599},
+		"tags":    {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+func TestSchemaValidation(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	logger, err := NewLogger(
+		WithWriterProvider(&concurrentBuffer{}, JSONEncoder),
+		WithLevel(DebugLevel),
+		WithSchemaValidation([]byte(testSchema)),
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("ok", String("service", "billing-api"), Int("status", 200), Any("tags", []string{"a"}))
+	logger.Debug("bad level", String("service", "billing"))
+	logger.Info("bad fields", String("service", "Billing API"), Int("status", 700), Any("tags", []int{1}))
+	logger.Warn("missing service")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 violations, got %d: %v", len(errs), errs)
+	}
+	var se *SchemaError
+	if !errors.As(errs[1], &se) || se.Message != "bad fields" || len(se.Violations) != 3 {
+		t.Errorf("unexpected violation report: %v", errs[1])
+	}
+	for i, want := range []string{`#/level`, `#/service: does not match`, `missing required property "service"`} {
+		if !strings.Contains(errs[i].Error(), want) {
+			t.Errorf("violation %d: %q does not mention %q", i, errs[i], want)
+		}
+	}
+}
+
+func TestSchemaValidation_InvalidSchema(t *testing.T) {
+	if _, err := NewLogger(WithSchemaValidation([]byte(`{"type": 5}`))); err == nil {
+		t.Error("expected error for invalid schema")
+	}
+	if _, err := NewLogger(WithSchemaValidation([]byte(`{"pattern": "("}`))); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
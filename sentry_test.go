@@ -0,0 +1,173 @@
+package golog
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeSentry records the events of posted envelopes.
+type fakeSentry struct {
+	mu      sync.Mutex
+	paths   []string
+	auth    []string
+	headers []map[string]interface{}
+	events  []map[string]interface{}
+	status  int
+}
+
+func (f *fakeSentry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths = append(f.paths, r.URL.Path)
+	f.auth = append(f.auth, r.Header.Get("X-Sentry-Auth"))
+	if f.status != 0 {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(f.status)
+		return
+	}
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(nil, 1<<20)
+	var header, item, event map[string]interface{}
+	for _, v := range []*map[string]interface{}{&header, &item, &event} {
+		scanner.Scan()
+		json.Unmarshal(scanner.Bytes(), v)
+	}
+	if item["type"] != "event" || int(item["length"].(float64)) != len(scanner.Bytes()) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.headers = append(f.headers, header)
+	f.events = append(f.events, event)
+	w.Write([]byte(`{"id":"` + header["event_id"].(string) + `"}`))
+}
+
+func TestSentryProvider_EventsAndBreadcrumbs(t *testing.T) {
+	fake := &fakeSentry{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	dsn := strings.Replace(srv.URL, "http://", "http://pubkey@", 1) + "/sentry/42"
+
+	at := time.Date(2026, 10, 16, 9, 30, 0, 500000000, time.UTC)
+	logger, err := NewLogger(
+		WithSentryProvider(dsn, SentryConfig{
+			Environment: "production",
+			Release:     "app@1.2.3",
+			ServerName:  "web-1",
+			Tags:        map[string]string{"region": "eu"},
+		}, WithProviderLevel(InfoLevel)),
+		WithZapOptions(zap.WithClock(fixedClock{at})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Debug("not a breadcrumb")
+	logger.Named("http").Info("request", String("path", "/a"))
+	logger.Warn("slow")
+	logger.Error("failed to save", Err(failDeep()), Int("attempt", 2))
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.events) != 1 {
+		t.Fatalf("got %d events, want 1", len(fake.events))
+	}
+	if fake.paths[0] != "/sentry/api/42/envelope/" {
+		t.Errorf("path = %s", fake.paths[0])
+	}
+	if !strings.Contains(fake.auth[0], "sentry_key=pubkey") {
+		t.Errorf("auth = %s", fake.auth[0])
+	}
+	event := fake.events[0]
+	if event["event_id"] != fake.headers[0]["event_id"] || len(event["event_id"].(string)) != 32 {
+		t.Errorf("event_id = %v", event["event_id"])
+	}
+	for key, want := range map[string]interface{}{
+		"message": "failed to save", "level": "error", "environment": "production",
+		"release": "app@1.2.3", "server_name": "web-1", "timestamp": 1792143000.5,
+	} {
+		if event[key] != want {
+			t.Errorf("%s = %v, want %v", key, event[key], want)
+		}
+	}
+	if extra := event["extra"].(map[string]interface{}); extra["attempt"] != 2.0 || extra["error"] != "disk full" {
+		t.Errorf("extra = %v", extra)
+	}
+	crumbs := event["breadcrumbs"].(map[string]interface{})["values"].([]interface{})
+	if len(crumbs) != 2 {
+		t.Fatalf("breadcrumbs = %v", crumbs)
+	}
+	first := crumbs[0].(map[string]interface{})
+	if first["message"] != "request" || first["category"] != "http" || first["level"] != "info" || first["data"].(map[string]interface{})["path"] != "/a" {
+		t.Errorf("breadcrumb = %v", first)
+	}
+	exception := event["exception"].(map[string]interface{})["values"].([]interface{})[0].(map[string]interface{})
+	if exception["type"] != "*golog.stackError" || exception["value"] != "disk full" {
+		t.Errorf("exception = %v", exception)
+	}
+	frames := exception["stacktrace"].(map[string]interface{})["frames"].([]interface{})
+	last := frames[len(frames)-1].(map[string]interface{})
+	if last["function"] != "failDeep" || last["module"] != "github.com/evdnx/golog" {
+		t.Errorf("innermost frame = %v", last)
+	}
+}
+
+func TestSentryProvider_RateLimited(t *testing.T) {
+	fake := &fakeSentry{status: http.StatusTooManyRequests}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	dsn := strings.Replace(srv.URL, "http://", "http://key:secret@", 1) + "/1"
+
+	logger, err := NewLogger(WithSentryProvider(dsn, SentryConfig{}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Error("first")
+	logger.Error("second")
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.paths) != 1 || !strings.Contains(fake.auth[0], "sentry_secret=secret") {
+		t.Errorf("requests = %v, auth = %v", fake.paths, fake.auth)
+	}
+	drops := logger.DroppedEntries()
+	if drops[DropProviderError] != 1 || drops[DropRateLimited] != 1 {
+		t.Errorf("drops = %v", drops)
+	}
+}
+
+func TestSentryProvider_Errors(t *testing.T) {
+	for _, dsn := range []string{"", "https://o0.ingest.sentry.io/1", "https://key@o0.ingest.sentry.io/"} {
+		if _, err := NewLogger(WithSentryProvider(dsn, SentryConfig{})); err == nil || !strings.Contains(err.Error(), "invalid DSN") {
+			t.Errorf("%q: expected DSN error, got %v", dsn, err)
+		}
+	}
+}
+
+func TestSentryEntryFrames(t *testing.T) {
+	stack := "main.handler\n\t/src/app/main.go:42\nruntime.goexit\n\t/usr/lib/go/src/runtime/asm_amd64.s:1700"
+	frames := sentryEntryFrames(stack)
+	if len(frames) != 2 {
+		t.Fatalf("got %d frames", len(frames))
+	}
+	if f := frames[0]; f.Module != "main" || f.Function != "handler" || f.AbsPath != "/src/app/main.go" || f.Lineno != 42 || !f.InApp {
+		t.Errorf("frame = %+v", f)
+	}
+	if frames[1].InApp {
+		t.Errorf("runtime frame marked in app: %+v", frames[1])
+	}
+	if f := newSentryFrame("github.com/a/b.(*T).Method", "", 0); f.Module != "github.com/a/b" || f.Function != "(*T).Method" {
+		t.Errorf("frame = %+v", f)
+	}
+	if sentryLevel(zap.DPanicLevel) != "fatal" {
+		t.Error("DPanic should map to fatal")
+	}
+}
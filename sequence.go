@@ -0,0 +1,103 @@
+package golog
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                     Monotonic Per-Logger Sequence Numbers                   */
+/* -------------------------------------------------------------------------- */
+
+// WithSequence stamps every entry with key set to a number incremented
+// atomically per entry, starting at 1, so downstream systems can detect loss
+// and order entries whose timestamps collide. Entries removed by WithFilter
+// do not consume a number.
+func WithSequence(key string) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.sequenceKey = key
+	}
+}
+
+// WithProviderSequence is like WithSequence but keeps a separate counter for
+// each provider registered by opt, so gaps reveal loss on that sink alone.
+func WithProviderSequence(opt LoggerOption, key string) LoggerOption {
+	return wrapProviders(opt, func(p provider) provider {
+		return &sequenceProvider{inner: p, key: key}
+	})
+}
+
+type sequenceProvider struct {
+	inner provider
+	key   string
+}
+
+func (p *sequenceProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	core, err := p.inner.newCore(level)
+	if err != nil {
+		return nil, err
+	}
+	return newSequenceCore([]zapcore.Core{core}, p.key), nil
+}
+
+func (p *sequenceProvider) close() error { return p.inner.close() }
+
+func (p *sequenceProvider) instrument(t *telemetry) {
+	if ip, ok := p.inner.(instrumentedProvider); ok {
+		ip.instrument(t)
+	}
+}
+
+func (p *sequenceProvider) describe() ProviderInfo { return describeProvider(p.inner) }
+
+func newSequenceCore(cores []zapcore.Core, key string) *stampCore {
+	var seq atomic.Uint64
+	return &stampCore{cores: cores, key: key, stamp: func() zapcore.Field {
+		return zap.Uint64(key, seq.Add(1))
+	}}
+}
+
+// stampCore appends a per-entry generated field (sequence number, log ID,
+// …) to each entry. Clones made by With share the generator. Entries that
+// already carry key – e.g. resubmitted dead letters – keep their value.
+type stampCore struct {
+	cores []zapcore.Core
+	key   string
+	stamp func() zapcore.Field
+}
+
+func (c *stampCore) Enabled(lvl zapcore.Level) bool {
+	return anyEnabled(c.cores, lvl)
+}
+
+func (c *stampCore) With(fields []zapcore.Field) zapcore.Core {
+	return &stampCore{cores: withAll(c.cores, fields), key: c.key, stamp: c.stamp}
+}
+
+func (c *stampCore) enabledFor(ent zapcore.Entry) bool {
+	return anyEntryEnabled(c.cores, ent)
+}
+
+func (c *stampCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabledFor(ent) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *stampCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	for _, f := range fields {
+		if f.Key == c.key {
+			return writeEnabled(c.cores, ent, fields)
+		}
+	}
+	stamped := make([]zapcore.Field, len(fields), len(fields)+1)
+	copy(stamped, fields)
+	return writeEnabled(c.cores, ent, append(stamped, c.stamp()))
+}
+
+func (c *stampCore) Sync() error {
+	return syncAll(c.cores)
+}
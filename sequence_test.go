@@ -0,0 +1,67 @@
+package golog
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSequence(t *testing.T) {
+	var buf concurrentBuffer
+	logger, err := NewLogger(
+		WithWriterProvider(&buf, JSONEncoder),
+		WithSequence("seq"),
+		WithFilter(func(e Entry, _ []Field) bool { return e.Message != "skip" }),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("tick")
+		}()
+	}
+	wg.Wait()
+	logger.Info("skip")
+	logger.Info("last")
+
+	out := buf.String()
+	for i := 1; i <= 51; i++ {
+		if !strings.Contains(out, `"seq":`+strconv.Itoa(i)+`}`) {
+			t.Errorf("missing sequence number %d", i)
+		}
+	}
+	if !strings.Contains(out, `"msg":"last","seq":51}`) {
+		t.Errorf("filtered entry consumed a sequence number:\n%s", out)
+	}
+}
+
+func TestProviderSequence(t *testing.T) {
+	var a, b bytes.Buffer
+	logger, err := NewLogger(
+		WithProviderSequence(WithWriterProvider(&a, JSONEncoder), "sink_seq"),
+		WithRoute(MatchField("to_b", true), WithProviderSequence(WithWriterProvider(&b, JSONEncoder), "sink_seq")),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("one")
+	logger.Info("two", Any("to_b", true))
+	logger.Info("three")
+
+	if out := a.String(); !strings.Contains(out, `"sink_seq":3}`) {
+		t.Errorf("unexpected output for a: %s", out)
+	}
+	if out := b.String(); !strings.Contains(out, `"sink_seq":1}`) || strings.Contains(out, `"sink_seq":2`) {
+		t.Errorf("unexpected output for b: %s", out)
+	}
+}
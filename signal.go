@@ -0,0 +1,67 @@
+package golog
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                        Signal-Driven Verbosity Toggle                       */
+/* -------------------------------------------------------------------------- */
+
+// WithLevelSignals lets an operator raise the verbosity of a live process
+// without an admin endpoint or a restart: receiving raise lowers the level
+// to DebugLevel and receiving restore brings back the level in effect
+// before; passing the same signal for both makes it a toggle.
+// WithSignalVerbosity, available on Unix, uses SIGUSR1 and SIGUSR2:
+//
+//	kill -USR1 <pid>   # debug on
+//	kill -USR2 <pid>   # back to normal
+//
+// Each change is logged at InfoLevel. The signals are handled until the
+// logger is closed.
+func WithLevelSignals(raise, restore os.Signal) LoggerOption {
+	return func(cfg *loggerConfig) {
+		if raise == nil || restore == nil {
+			cfg.optionErrs = append(cfg.optionErrs, errors.New("WithLevelSignals: nil signal"))
+			return
+		}
+		cfg.raiseSignal, cfg.restoreSignal = raise, restore
+	}
+}
+
+// watchLevelSignals subscribes to raise and restore before returning, so
+// signals sent once the logger exists are not missed.
+func (l *Logger) watchLevelSignals(raise, restore os.Signal) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, raise, restore)
+	l.bg.Add(1)
+	go func() {
+		defer l.bg.Done()
+		defer signal.Stop(ch)
+
+		// saved is the level to restore, nil while not raised.
+		var saved *Level
+		for {
+			select {
+			case <-l.stop:
+				return
+			case sig := <-ch:
+				from := l.Level()
+				switch {
+				case sig == raise && saved == nil:
+					saved = &from
+					l.SetLevel(DebugLevel)
+					l.Info("log level raised by signal", String("signal", sig.String()),
+						String("from", from.String()), String("to", DebugLevel.String()))
+				case sig == restore && saved != nil:
+					l.Info("log level restored by signal", String("signal", sig.String()),
+						String("from", from.String()), String("to", saved.String()))
+					l.SetLevel(*saved)
+					saved = nil
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,81 @@
+//go:build unix
+
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func waitForLevel(t *testing.T, logger *Logger, want Level) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for logger.Level() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("level is %v, want %v", logger.Level(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWithSignalVerbosity(t *testing.T) {
+	var buf lockedBuffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithLevel(WarnLevel), WithSignalVerbosity())
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	waitForLevel(t, logger, DebugLevel)
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	waitForLevel(t, logger, WarnLevel)
+
+	out := buf.String()
+	if !strings.Contains(out, `"msg":"log level raised by signal"`) || !strings.Contains(out, `"msg":"log level restored by signal"`) ||
+		!strings.Contains(out, `"from":"warn"`) {
+		t.Errorf("level changes not logged: %s", out)
+	}
+}
+
+func TestWithLevelSignalsToggle(t *testing.T) {
+	logger, err := NewLogger(WithWriterProvider(&lockedBuffer{}, JSONEncoder), WithLevelSignals(syscall.SIGUSR1, syscall.SIGUSR1))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitForLevel(t, logger, DebugLevel)
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitForLevel(t, logger, InfoLevel)
+
+	if _, err := NewLogger(WithLevelSignals(nil, syscall.SIGUSR2)); err == nil {
+		t.Error("expected an error for a nil signal")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build unix
+
+package golog
+
+import "syscall"
+
+// WithSignalVerbosity is WithLevelSignals(syscall.SIGUSR1, syscall.SIGUSR2):
+// SIGUSR1 switches the logger to DebugLevel and SIGUSR2 restores its level.
+func WithSignalVerbosity() LoggerOption {
+	return WithLevelSignals(syscall.SIGUSR1, syscall.SIGUSR2)
+}
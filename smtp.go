@@ -0,0 +1,413 @@
+package golog
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                            SMTP Email Digest Provider                       */
+/* -------------------------------------------------------------------------- */
+
+const (
+	defaultSMTPSubject = "[{{.Level}}] {{.Count}} log entries from {{.Host}}"
+	defaultSMTPBody    = `{{.Count}} entries at Error or above from {{.Host}} between {{.Start.Format "2006-01-02 15:04:05 MST"}} and {{.End.Format "15:04:05 MST"}}.
+{{range .Entries}}
+{{.Time.Format "15:04:05.000"}} [{{.Level}}] {{with .Logger}}{{.}}: {{end}}{{.Message}}
+{{- with .FieldsText}}
+    {{.}}{{end}}
+{{- with .Caller}}
+    at {{.}}{{end}}
+{{- with .Stack}}
+{{.}}{{end}}
+{{end}}
+{{- if .Omitted}}
+{{.Omitted}} more entries were left out of this mail.
+{{end}}`
+)
+
+// SMTPConfig configures WithSMTPProvider. Zero values fall back to the
+// defaults noted on each field.
+type SMTPConfig struct {
+	// From and To are the envelope and header addresses; both are required.
+	From string
+	To   []string
+	// Username and Password enable PLAIN authentication, which net/smtp
+	// only performs over TLS or to localhost.
+	Username string
+	Password string
+	// TLS configures STARTTLS, used whenever the server offers it and
+	// required when TLS is set. Port 465 uses implicit TLS instead.
+	TLS *TLSConfig
+	// Interval is the time between digests (default 5m). Fatal and Panic
+	// entries are mailed at once, together with the pending digest.
+	Interval time.Duration
+	// MaxEntries caps the entries listed in one mail (default 100); the rest
+	// are counted in EmailDigest.Omitted and as DropQueueFull.
+	MaxEntries int
+	// Subject and Template are text/templates over an EmailDigest, for the
+	// subject line and the plain text body.
+	Subject  string
+	Template string
+	// Timeout bounds one SMTP session (default 30s).
+	Timeout time.Duration
+}
+
+func (c SMTPConfig) withDefaults() SMTPConfig {
+	if c.Interval <= 0 {
+		c.Interval = 5 * time.Minute
+	}
+	if c.MaxEntries <= 0 {
+		c.MaxEntries = 100
+	}
+	if c.Subject == "" {
+		c.Subject = defaultSMTPSubject
+	}
+	if c.Template == "" {
+		c.Template = defaultSMTPBody
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 30 * time.Second
+	}
+	return c
+}
+
+// EmailDigest is the data of SMTPConfig.Subject and SMTPConfig.Template.
+type EmailDigest struct {
+	Host string
+	// Level is the highest level in the digest, upper case.
+	Level string
+	// Count is the number of entries, Omitted those beyond MaxEntries.
+	Count      int
+	Omitted    int
+	Entries    []ChatEntry
+	Start, End time.Time
+}
+
+// WithSMTPProvider mails Error and higher entries as periodic digests,
+// for teams that watch a mailbox rather than a dashboard:
+//
+//	golog.WithSMTPProvider("smtp.example.com:587", golog.SMTPConfig{
+//		From: "app@example.com", To: []string{"ops@example.com"},
+//		Username: "app", Password: os.Getenv("SMTP_PASSWORD"),
+//		Interval: 15 * time.Minute,
+//	})
+//
+// Entries are collected for Interval and sent in one mail; nothing is sent
+// for a quiet interval. Sync and Close send the pending digest. A failed
+// mail counts its entries as DropProviderError.
+func WithSMTPProvider(addr string, cfg SMTPConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&smtpProvider{
+			providerBase: providerBase{name: "smtp:" + addr},
+			addr:         addr,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type smtpProvider struct {
+	providerBase
+	addr string
+	cfg  SMTPConfig
+
+	digest *smtpDigest
+}
+
+func (p *smtpProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	cfg := p.cfg.withDefaults()
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("smtp provider: From and To are required")
+	}
+	host, port, err := net.SplitHostPort(p.addr)
+	if err != nil {
+		return nil, fmt.Errorf("smtp provider: %w", err)
+	}
+	tlsConfig, err := cfg.TLS.build()
+	if err != nil {
+		return nil, fmt.Errorf("smtp provider: %w", err)
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.ServerName = host
+	}
+	subject, err := template.New("subject").Parse(cfg.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("smtp provider: %w", err)
+	}
+	body, err := template.New("body").Parse(cfg.Template)
+	if err != nil {
+		return nil, fmt.Errorf("smtp provider: %w", err)
+	}
+	if level < zapcore.ErrorLevel {
+		level = zapcore.ErrorLevel
+	}
+	hostname, _ := os.Hostname()
+	p.digest = &smtpDigest{
+		mailer: &smtpMailer{
+			addr: p.addr, host: host, implicitTLS: port == "465",
+			tls: tlsConfig, requireTLS: cfg.TLS != nil, cfg: cfg,
+		},
+		cfg:      cfg,
+		hostname: hostname,
+		subject:  subject,
+		body:     body,
+		drops:    p.dropped,
+		report:   p.report,
+		done:     make(chan struct{}),
+	}
+	p.digest.wg.Add(1)
+	go p.digest.run()
+	return &smtpCore{LevelEnabler: level, tree: newTreeEncoder(), digest: p.digest}, nil
+}
+
+func (p *smtpProvider) close() error {
+	if p.digest == nil {
+		return nil
+	}
+	return p.digest.close()
+}
+
+func (p *smtpProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name}
+}
+
+type smtpCore struct {
+	zapcore.LevelEnabler
+	tree   *treeEncoder
+	digest *smtpDigest
+}
+
+func (c *smtpCore) With(fields []zapcore.Field) zapcore.Core {
+	tree := c.tree.cloneTree()
+	for _, f := range fields {
+		f.AddTo(tree)
+	}
+	return &smtpCore{LevelEnabler: c.LevelEnabler, tree: tree, digest: c.digest}
+}
+
+func (c *smtpCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *smtpCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	c.digest.add(newChatEntry(ent, c.tree.fieldsWith(fields)))
+	if ent.Level >= zapcore.PanicLevel {
+		// The process is about to end; mail now rather than at the tick.
+		return c.digest.flush()
+	}
+	return nil
+}
+
+func (c *smtpCore) Sync() error { return c.digest.flush() }
+
+/* -------------------------------------------------------------------------- */
+/*                                    Digest                                  */
+/* -------------------------------------------------------------------------- */
+
+type smtpDigest struct {
+	mailer   *smtpMailer
+	cfg      SMTPConfig
+	hostname string
+	subject  *template.Template
+	body     *template.Template
+	drops    func(reason DropReason, n int)
+	report   func(error)
+
+	mu      sync.Mutex
+	pending EmailDigest
+	// sendMu keeps mails in order when a flush and a tick overlap.
+	sendMu sync.Mutex
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+func (d *smtpDigest) add(e ChatEntry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pending.Count == 0 {
+		d.pending.Start = e.Time
+	}
+	d.pending.Count++
+	d.pending.End = e.Time
+	if levelRank(e.Level) > levelRank(d.pending.Level) {
+		d.pending.Level = e.Level
+	}
+	if len(d.pending.Entries) < d.cfg.MaxEntries {
+		d.pending.Entries = append(d.pending.Entries, e)
+	} else {
+		d.pending.Omitted++
+	}
+}
+
+// levelRank orders the upper-case level names of a digest.
+func levelRank(level string) zapcore.Level {
+	var lvl zapcore.Level
+	if level == "" || lvl.UnmarshalText([]byte(level)) != nil {
+		return zapcore.DebugLevel - 1
+	}
+	return lvl
+}
+
+func (d *smtpDigest) run() {
+	defer d.wg.Done()
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.flush(); err != nil {
+				d.report(err)
+			}
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// flush mails the pending digest, if any.
+func (d *smtpDigest) flush() error {
+	d.sendMu.Lock()
+	defer d.sendMu.Unlock()
+	d.mu.Lock()
+	digest := d.pending
+	d.pending = EmailDigest{}
+	d.mu.Unlock()
+	if digest.Count == 0 {
+		return nil
+	}
+	digest.Host = d.hostname
+
+	var subject, body strings.Builder
+	err := d.subject.Execute(&subject, digest)
+	if err == nil {
+		err = d.body.Execute(&body, digest)
+	}
+	if err == nil {
+		err = d.mailer.send(strings.Join(strings.Fields(subject.String()), " "), body.String())
+	}
+	if err != nil {
+		d.drops(DropProviderError, digest.Count)
+		return fmt.Errorf("smtp: %w", err)
+	}
+	if digest.Omitted > 0 {
+		d.drops(DropQueueFull, digest.Omitted)
+	}
+	return nil
+}
+
+func (d *smtpDigest) close() error {
+	var err error
+	d.closeOnce.Do(func() {
+		close(d.done)
+		d.wg.Wait()
+		err = d.flush()
+	})
+	return err
+}
+
+/* -------------------------------------------------------------------------- */
+/*                                 SMTP Session                               */
+/* -------------------------------------------------------------------------- */
+
+type smtpMailer struct {
+	addr        string
+	host        string
+	implicitTLS bool
+	tls         *tls.Config
+	requireTLS  bool
+	cfg         SMTPConfig
+}
+
+// send delivers one plain text mail in its own session.
+func (m *smtpMailer) send(subject, body string) error {
+	dialer := &net.Dialer{Timeout: m.cfg.Timeout}
+	var conn net.Conn
+	var err error
+	if m.implicitTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", m.addr, m.tls)
+	} else {
+		conn, err = dialer.Dial("tcp", m.addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(m.cfg.Timeout))
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if !m.implicitTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(m.tls); err != nil {
+				return err
+			}
+		} else if m.requireTLS {
+			return errors.New("server does not offer STARTTLS")
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.cfg.From); err != nil {
+		return err
+	}
+	for _, rcpt := range m.cfg.To {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.message(subject, body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (m *smtpMailer) message(subject, body string) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(m.cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", newEventID(), m.host)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(&msg)
+	qp.Write([]byte(body))
+	qp.Close()
+	return msg.Bytes()
+}
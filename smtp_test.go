@@ -0,0 +1,222 @@
+package golog
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSMTP accepts mail without TLS, offering AUTH PLAIN.
+type fakeSMTP struct {
+	mu    sync.Mutex
+	mails []*mail.Message
+	rcpts [][]string
+	auth  []string
+	fail  bool
+}
+
+func startFakeSMTP(t *testing.T) (*fakeSMTP, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	f := &fakeSMTP{}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f, ln.Addr().String()
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+	reply("220 fake ESMTP")
+	var rcpts []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO":
+			reply("250-fake\r\n250 AUTH PLAIN")
+		case "AUTH":
+			f.mu.Lock()
+			f.auth = append(f.auth, line)
+			f.mu.Unlock()
+			reply("235 ok")
+		case "MAIL":
+			reply("250 ok")
+		case "RCPT":
+			rcpts = append(rcpts, line[len("RCPT TO:"):])
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			f.mu.Lock()
+			if f.fail {
+				f.mu.Unlock()
+				reply("554 rejected")
+				continue
+			}
+			msg, _ := mail.ReadMessage(strings.NewReader(data.String()))
+			f.mails = append(f.mails, msg)
+			f.rcpts = append(f.rcpts, rcpts)
+			f.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func mailText(t *testing.T, msg *mail.Message) (string, string) {
+	t.Helper()
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return subject, strings.TrimSuffix(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+}
+
+func TestSMTPProvider_Digest(t *testing.T) {
+	fake, addr := startFakeSMTP(t)
+	logger, err := NewLogger(WithSMTPProvider(addr, SMTPConfig{
+		From:       "app@example.com",
+		To:         []string{"ops@example.com", "dev@example.com"},
+		Username:   "app",
+		Password:   "secret",
+		Interval:   time.Hour,
+		MaxEntries: 2,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Warn("not mailed")
+	logger.Named("billing").Error("invoice failed", Int("invoice", 42))
+	logger.Error("second")
+	logger.Error("third")
+	if err := logger.Sync(); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.mails) != 1 {
+		t.Fatalf("got %d mails, want 1", len(fake.mails))
+	}
+	if len(fake.rcpts[0]) != 2 || len(fake.auth) != 1 {
+		t.Errorf("rcpts = %v, auth = %v", fake.rcpts, fake.auth)
+	}
+	subject, body := mailText(t, fake.mails[0])
+	if !strings.HasPrefix(subject, "[ERROR] 3 log entries from ") {
+		t.Errorf("subject = %q", subject)
+	}
+	for _, want := range []string{"[ERROR] billing: invoice failed\n    invoice=42", "[ERROR] second", "1 more entries"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body lacks %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "third") {
+		t.Errorf("body should stop at MaxEntries:\n%s", body)
+	}
+	if n := logger.DroppedEntries()[DropQueueFull]; n != 1 {
+		t.Errorf("queue full drops = %d, want 1", n)
+	}
+}
+
+func TestSMTPProvider_FatalMailsAtOnce(t *testing.T) {
+	fake, addr := startFakeSMTP(t)
+	logger, err := NewLogger(WithSMTPProvider(addr, SMTPConfig{
+		From:     "app@example.com",
+		To:       []string{"ops@example.com"},
+		Interval: time.Hour,
+		Subject:  "{{.Level}}: {{(index .Entries 0).Message}}",
+		Template: "{{range .Entries}}{{.Message}};{{end}}",
+	}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Error("pending")
+	func() {
+		defer func() { recover() }()
+		logger.Panic("crashed")
+	}()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.mails) != 1 {
+		t.Fatalf("got %d mails, want 1", len(fake.mails))
+	}
+	subject, body := mailText(t, fake.mails[0])
+	if subject != "PANIC: pending" || body != "pending;crashed;" {
+		t.Errorf("subject = %q, body = %q", subject, body)
+	}
+}
+
+func TestSMTPProvider_Failure(t *testing.T) {
+	fake, addr := startFakeSMTP(t)
+	fake.fail = true
+	logger, err := NewLogger(WithSMTPProvider(addr, SMTPConfig{From: "a@example.com", To: []string{"b@example.com"}}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Error("one")
+	logger.Error("two")
+	if err := logger.Sync(); err == nil || !strings.Contains(err.Error(), "554") {
+		t.Errorf("sync error = %v", err)
+	}
+	logger.Close()
+	if n := logger.DroppedEntries()[DropProviderError]; n != 2 {
+		t.Errorf("provider error drops = %d, want 2", n)
+	}
+
+	for _, cfg := range []SMTPConfig{{}, {From: "a@example.com", To: []string{"b@example.com"}, Template: "{{"}} {
+		if _, err := NewLogger(WithSMTPProvider(addr, cfg)); err == nil || !strings.Contains(err.Error(), "smtp provider") {
+			t.Errorf("%+v: expected error, got %v", cfg, err)
+		}
+	}
+	strict, err := NewLogger(WithSMTPProvider(addr, SMTPConfig{From: "a@example.com", To: []string{"b@example.com"}, TLS: &TLSConfig{}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer strict.Close()
+	strict.Error("plain text refused")
+	if err := strict.Sync(); err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("expected STARTTLS error, got %v", err)
+	}
+}
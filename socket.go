@@ -0,0 +1,259 @@
+package golog
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                               Socket Provider                              */
+/* -------------------------------------------------------------------------- */
+
+// socketMaxBackoff caps the wait between reconnection attempts.
+const socketMaxBackoff = 30 * time.Second
+
+// SocketConfig configures WithSocketProvider. Zero values fall back to the
+// defaults noted on each field.
+type SocketConfig struct {
+	// Timeout bounds dialling and each write (default 5s).
+	Timeout time.Duration
+	// BufferSize is the number of entries kept while disconnected (default
+	// 1000); beyond it the oldest are dropped and counted as DropQueueFull.
+	BufferSize int
+	// ReconnectInterval is the first wait before redialling (default
+	// 500ms); it doubles after every failed attempt, up to 30s.
+	ReconnectInterval time.Duration
+	// TLS enables TLS on tcp connections.
+	TLS *TLSConfig
+}
+
+func (c SocketConfig) withDefaults() SocketConfig {
+	if c.Timeout <= 0 {
+		c.Timeout = 5 * time.Second
+	}
+	if c.BufferSize <= 0 {
+		c.BufferSize = 1000
+	}
+	if c.ReconnectInterval <= 0 {
+		c.ReconnectInterval = 500 * time.Millisecond
+	}
+	return c
+}
+
+// WithSocketProvider writes entries, newline-delimited, to a "tcp", "udp",
+// "unix" or "unixgram" socket, such as a local relay daemon:
+//
+//	golog.WithSocketProvider("unix", "/run/log-relay.sock", golog.SocketConfig{})
+//
+// Entries are JSON unless WithProviderEncoder picks another encoder; on
+// datagram sockets each entry is one datagram. When the connection fails,
+// entries are kept in a bounded buffer while the provider redials in the
+// background with exponential backoff, and are sent in order once it
+// reconnects. An unreachable address does not fail NewLogger. Entries
+// still buffered on Close are counted as DropProviderError.
+func WithSocketProvider(network, address string, cfg SocketConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&socketProvider{
+			providerBase: providerBase{name: "socket:" + network + ":" + address, encoders: anyEncoder},
+			network:      network,
+			address:      address,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type socketProvider struct {
+	providerBase
+	network, address string
+	cfg              SocketConfig
+
+	w *socketWriter
+}
+
+func (p *socketProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	cfg := p.cfg.withDefaults()
+	switch p.network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("socket provider: unsupported network %q", p.network)
+	}
+	tlsCfg, err := cfg.TLS.build()
+	if err != nil {
+		return nil, fmt.Errorf("socket provider: %w", err)
+	}
+	if tlsCfg != nil && p.network != "tcp" && p.network != "tcp4" && p.network != "tcp6" {
+		return nil, fmt.Errorf("socket provider: TLS needs a tcp connection, not %q", p.network)
+	}
+	enc, err := p.tel.buildEncoder(p.encoder(), p.encoderConfig)
+	if err != nil {
+		return nil, err
+	}
+	p.w = &socketWriter{
+		network: p.network, address: p.address, tls: tlsCfg,
+		timeout: cfg.Timeout, max: cfg.BufferSize, backoff: cfg.ReconnectInterval,
+		drops: p.dropped, report: p.report, done: make(chan struct{}),
+	}
+	p.w.connect()
+	return zapcore.NewCore(enc, p.w, level), nil
+}
+
+func (p *socketProvider) close() error {
+	if p.w == nil {
+		return nil
+	}
+	return p.w.close()
+}
+
+func (p *socketProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: p.encoder()}
+}
+
+// socketWriter writes entries to the connection, buffering them while a
+// background loop redials.
+type socketWriter struct {
+	network, address string
+	tls              *tls.Config
+	timeout          time.Duration
+	max              int
+	backoff          time.Duration
+	drops            func(reason DropReason, n int)
+	report           func(err error)
+
+	mu           sync.Mutex
+	conn         net.Conn
+	pending      [][]byte
+	reconnecting bool
+	closed       bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func (w *socketWriter) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: w.timeout}
+	if w.tls != nil {
+		return tls.DialWithDialer(dialer, w.network, w.address, w.tls)
+	}
+	return dialer.Dial(w.network, w.address)
+}
+
+// connect makes the first connection, falling back to the reconnect loop.
+func (w *socketWriter) connect() {
+	conn, err := w.dial()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		w.report(err)
+		w.startReconnectLocked()
+		return
+	}
+	w.conn = conn
+}
+
+// Write sends p, one encoded entry, or buffers it while disconnected.
+func (w *socketWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		err := w.writeLocked(p)
+		if err == nil {
+			return len(p), nil
+		}
+		w.report(err)
+		w.conn.Close()
+		w.conn = nil
+		w.startReconnectLocked()
+	}
+	if w.closed {
+		w.drops(DropProviderError, 1)
+		return len(p), nil
+	}
+	if len(w.pending) >= w.max {
+		w.pending = w.pending[1:]
+		w.drops(DropQueueFull, 1)
+	}
+	w.pending = append(w.pending, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func (w *socketWriter) writeLocked(p []byte) error {
+	w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+	_, err := w.conn.Write(p)
+	return err
+}
+
+func (w *socketWriter) Sync() error { return nil }
+
+func (w *socketWriter) startReconnectLocked() {
+	if w.reconnecting || w.closed {
+		return
+	}
+	w.reconnecting = true
+	w.wg.Add(1)
+	go w.reconnect()
+}
+
+// reconnect redials with exponential backoff, then sends the buffered
+// entries in order before Write resumes using the connection.
+func (w *socketWriter) reconnect() {
+	defer w.wg.Done()
+	backoff := w.backoff
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, socketMaxBackoff)
+		conn, err := w.dial()
+		if err != nil {
+			continue
+		}
+
+		w.mu.Lock()
+		w.conn = conn
+		for len(w.pending) > 0 {
+			if err := w.writeLocked(w.pending[0]); err != nil {
+				break
+			}
+			w.pending = w.pending[1:]
+		}
+		if len(w.pending) == 0 {
+			w.reconnecting = false
+			w.mu.Unlock()
+			return
+		}
+		w.conn.Close()
+		w.conn = nil
+		w.mu.Unlock()
+	}
+}
+
+// close stops reconnecting and counts the entries still buffered as
+// dropped.
+func (w *socketWriter) close() error {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+	close(w.done)
+	w.wg.Wait()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if n := len(w.pending); n > 0 {
+		w.drops(DropProviderError, n)
+		w.report(fmt.Errorf("%d buffered entries not sent", n))
+		w.pending = nil
+	}
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,147 @@
+package golog
+
+import (
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSocketProvider_UnixStream(t *testing.T) {
+	ln, err := net.Listen("unix", filepath.Join(t.TempDir(), "relay.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	got := acceptOne(t, ln, readLines)
+
+	logger, err := NewLogger(WithSocketProvider("unix", ln.Addr().String(), SocketConfig{}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("first", String("user", "ann"))
+	logger.Warn("second")
+	logger.Close()
+
+	lines := <-got
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines: %q", len(lines), lines)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	if entry["msg"] != "first" || entry["user"] != "ann" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+}
+
+func TestSocketProvider_UDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	logger, err := NewLogger(WithSocketProvider("udp", pc.LocalAddr().String(), SocketConfig{}, WithProviderEncoder(ConsoleEncoder)))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Info("datagram")
+
+	buf := make([]byte, 4096)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf[:n]); !strings.Contains(got, "datagram") || !strings.HasSuffix(got, "\n") || strings.HasPrefix(got, "{") {
+		t.Errorf("unexpected datagram %q", got)
+	}
+}
+
+func TestSocketProvider_BuffersUntilReconnected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "relay.sock")
+	logger, err := NewLogger(WithSocketProvider("unix", path, SocketConfig{
+		BufferSize:        2,
+		ReconnectInterval: 10 * time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("NewLogger should tolerate an unreachable address: %v", err)
+	}
+	logger.Info("one")
+	logger.Info("two")
+	logger.Info("three")
+	if n := logger.DroppedEntries()[DropQueueFull]; n != 1 {
+		t.Errorf("queue full drops = %d, want 1", n)
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	got := acceptOne(t, ln, readLines)
+	// Entries written while the buffer drains keep their order.
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if w := socketWriterOf(logger); w != nil {
+			w.mu.Lock()
+			connected := w.conn != nil
+			w.mu.Unlock()
+			if connected {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	logger.Info("four")
+	logger.Close()
+
+	var msgs []string
+	for _, line := range <-got {
+		var entry map[string]interface{}
+		json.Unmarshal([]byte(line), &entry)
+		msgs = append(msgs, entry["msg"].(string))
+	}
+	if strings.Join(msgs, ",") != "two,three,four" {
+		t.Errorf("got %v, want the newest buffered entries then four", msgs)
+	}
+	if n := logger.DroppedEntries()[DropProviderError]; n != 0 {
+		t.Errorf("provider error drops = %d, want 0", n)
+	}
+}
+
+func TestSocketProvider_CloseDropsBuffered(t *testing.T) {
+	logger, err := NewLogger(WithSocketProvider("unix", filepath.Join(t.TempDir(), "none.sock"), SocketConfig{ReconnectInterval: time.Hour}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("lost")
+	logger.Close()
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+}
+
+func TestSocketProvider_Errors(t *testing.T) {
+	if _, err := NewLogger(WithSocketProvider("sctp", "localhost:1", SocketConfig{})); err == nil || !strings.Contains(err.Error(), "unsupported network") {
+		t.Errorf("expected network error, got %v", err)
+	}
+	if _, err := NewLogger(WithSocketProvider("udp", "localhost:1", SocketConfig{TLS: &TLSConfig{}})); err == nil || !strings.Contains(err.Error(), "TLS needs a tcp") {
+		t.Errorf("expected TLS error, got %v", err)
+	}
+}
+
+// socketWriterOf returns the writer of the logger's socket provider.
+func socketWriterOf(l *Logger) *socketWriter {
+	for _, p := range l.closers {
+		if sp, ok := p.(*socketProvider); ok {
+			return sp.w
+		}
+	}
+	return nil
+}
@@ -0,0 +1,418 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                       Splunk HTTP Event Collector Provider                  */
+/* -------------------------------------------------------------------------- */
+
+// SplunkConfig configures WithSplunkProvider. The embedded HTTPConfig
+// covers TLS, proxy, headers, timeout, batching and compression. Zero
+// values fall back to the defaults noted on each field.
+type SplunkConfig struct {
+	HTTPConfig
+	// Token is the HEC token, sent as "Authorization: Splunk <Token>".
+	Token string
+	// Index, Source and SourceType set the event metadata; empty values
+	// leave the token's defaults. SourceType defaults to "_json" for JSON
+	// entries.
+	Index, Source, SourceType string
+	// Host defaults to os.Hostname.
+	Host string
+	// Ack enables indexer acknowledgment: batches are counted as delivered
+	// only once Splunk confirms they were indexed, and as dropped after
+	// AckTimeout. The token must have acknowledgment enabled.
+	Ack bool
+	// Channel is the X-Splunk-Request-Channel GUID, generated when Ack is
+	// set and Channel is empty.
+	Channel string
+	// AckTimeout defaults to 1m, AckInterval, how often to poll, to 1s.
+	AckTimeout, AckInterval time.Duration
+}
+
+func (c SplunkConfig) withDefaults() SplunkConfig {
+	if c.Host == "" {
+		c.Host, _ = os.Hostname()
+	}
+	if c.Ack && c.Channel == "" {
+		c.Channel = newUUID()
+	}
+	if c.AckTimeout <= 0 {
+		c.AckTimeout = time.Minute
+	}
+	if c.AckInterval <= 0 {
+		c.AckInterval = time.Second
+	}
+	return c
+}
+
+// WithSplunkProvider sends entries to the Splunk HTTP Event Collector at
+// endpoint, e.g. "https://splunk.example.com:8088" (the
+// /services/collector/event path is added unless present):
+//
+//	golog.WithSplunkProvider("https://http-inputs-acme.splunkcloud.com", golog.SplunkConfig{
+//		Token:      os.Getenv("SPLUNK_HEC_TOKEN"),
+//		Index:      "app",
+//		SourceType: "_json",
+//	})
+//
+// Each entry becomes an event carrying its time, so the entry's own time
+// key is left out; with WithProviderEncoder the encoded entry is sent as a
+// string event. Batches that fail, and with Ack those that are not
+// acknowledged in time, are dropped and counted as DropProviderError.
+func WithSplunkProvider(endpoint string, cfg SplunkConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&splunkProvider{
+			providerBase: providerBase{name: "splunk:" + endpointName(endpoint), encoders: anyEncoder},
+			endpoint:     endpoint,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type splunkProvider struct {
+	providerBase
+	endpoint string
+	cfg      SplunkConfig
+	batch    *batchWriter
+	acks     *hecAcks
+}
+
+// splunkEncoding drops the entry's time, which the event carries, before
+// the config of WithProviderEncoderConfig applies.
+func splunkEncoding(c *zapcore.EncoderConfig) { c.TimeKey = zapcore.OmitKey }
+
+func (p *splunkProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	cfg := p.cfg.withDefaults()
+	if cfg.Token == "" {
+		return nil, errors.New("splunk provider: token is required")
+	}
+	u, err := url.Parse(p.endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("splunk provider: invalid endpoint %q", p.endpoint)
+	}
+	if !strings.Contains(u.Path, "/services/collector") {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/services/collector/event"
+	}
+	sender, err := newHTTPSender(cfg.HTTPConfig)
+	if err != nil {
+		return nil, fmt.Errorf("splunk provider: %w", err)
+	}
+	own := mergeEncoderConfig(&encoderConfigFuncs{fns: []func(*zapcore.EncoderConfig){splunkEncoding}}, p.encoderConfig.funcs())
+	enc, err := p.tel.buildEncoder(p.encoder(), own)
+	if err != nil {
+		return nil, err
+	}
+	jsonEvent := p.encoder() == JSONEncoder
+	if cfg.SourceType == "" && jsonEvent {
+		cfg.SourceType = "_json"
+	}
+
+	client := &hecClient{endpoint: u.String(), cfg: cfg, sender: sender}
+	if cfg.Ack {
+		ackURL := *u
+		ackURL.Path = ackURL.Path[:strings.Index(ackURL.Path, "/services/collector")] + "/services/collector/ack"
+		client.ackEndpoint = ackURL.String()
+		p.acks = newHECAcks(client, cfg.AckTimeout, cfg.AckInterval, p.dropped, p.report)
+		client.acks = p.acks
+	}
+	p.batch = newBatchWriter(sender.cfg.BatchSize, sender.cfg.FlushInterval, client.send, p.dropped, p.report)
+
+	// The metadata is the same for every event.
+	var meta bytes.Buffer
+	for _, kv := range [][2]string{{"host", cfg.Host}, {"source", cfg.Source}, {"sourcetype", cfg.SourceType}, {"index", cfg.Index}} {
+		if kv[1] != "" {
+			value, _ := json.Marshal(kv[1])
+			meta.WriteString(`,"` + kv[0] + `":`)
+			meta.Write(value)
+		}
+	}
+	return &splunkCore{LevelEnabler: level, enc: enc, jsonEvent: jsonEvent, meta: meta.String(), out: p.batch}, nil
+}
+
+// close sends pending entries and, with Ack, waits for their
+// acknowledgment.
+func (p *splunkProvider) close() error {
+	if p.batch == nil {
+		return nil
+	}
+	err := p.batch.close()
+	if p.acks != nil {
+		p.acks.close()
+	}
+	return err
+}
+
+func (p *splunkProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: p.encoder()}
+}
+
+// splunkCore wraps each encoded entry in an HEC event and writes it to the
+// batch; HEC accepts concatenated events in one request.
+type splunkCore struct {
+	zapcore.LevelEnabler
+	enc       zapcore.Encoder
+	jsonEvent bool
+	meta      string
+	out       *batchWriter
+}
+
+func (c *splunkCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	clone := *c
+	clone.enc = enc
+	return &clone
+}
+
+func (c *splunkCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *splunkCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	encoded, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer encoded.Free()
+	event := bytes.TrimSuffix(encoded.Bytes(), []byte("\n"))
+
+	buf := encoderPool.Get()
+	defer buf.Free()
+	buf.AppendString(`{"time":`)
+	buf.AppendString(strconv.FormatFloat(float64(ent.Time.UnixMicro())/1e6, 'f', -1, 64))
+	buf.AppendString(c.meta)
+	buf.AppendString(`,"event":`)
+	if c.jsonEvent {
+		buf.Write(event)
+	} else {
+		appendJSONString(buf, string(event))
+	}
+	buf.AppendString("}\n")
+	_, err = c.out.Write(buf.Bytes())
+	return err
+}
+
+func (c *splunkCore) Sync() error { return c.out.Sync() }
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b[:])
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+/* -------------------------------------------------------------------------- */
+/*                         HEC Requests & Acknowledgment                       */
+/* -------------------------------------------------------------------------- */
+
+type hecClient struct {
+	endpoint, ackEndpoint string
+	cfg                   SplunkConfig
+	sender                *httpSender
+	acks                  *hecAcks
+}
+
+// hecResponse is the body of HEC responses.
+type hecResponse struct {
+	Text  string `json:"text"`
+	Code  int    `json:"code"`
+	AckID *int64 `json:"ackId"`
+}
+
+// send posts a batch of events. With Ack, the batch is tracked until
+// acknowledged rather than counted as delivered.
+func (c *hecClient) send(body []byte) error {
+	data, err := c.post(c.endpoint, body)
+	if err != nil {
+		return err
+	}
+	if c.acks != nil {
+		var resp hecResponse
+		json.Unmarshal(data, &resp)
+		if resp.AckID == nil {
+			return PermanentError(errors.New("splunk: no ackId in response; enable indexer acknowledgment for the token"))
+		}
+		c.acks.add(*resp.AckID, bytes.Count(body, []byte("\n")))
+	}
+	return nil
+}
+
+// post sends body to endpoint and returns the response body. Client
+// errors other than 408 and 429 are permanent.
+func (c *hecClient) post(endpoint string, body []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.sender.cfg.Timeout)
+	defer cancel()
+
+	cfg := c.sender.cfg
+	payload, err := cfg.Compression.compress(body)
+	if err != nil {
+		return nil, PermanentError(fmt.Errorf("splunk: %w", err))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, PermanentError(fmt.Errorf("splunk: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.Compression != NoCompression {
+		req.Header.Set("Content-Encoding", string(cfg.Compression))
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Authorization", "Splunk "+c.cfg.Token)
+	if c.cfg.Channel != "" {
+		req.Header.Set("X-Splunk-Request-Channel", c.cfg.Channel)
+	}
+
+	resp, err := c.sender.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("splunk: %w", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 == 2 {
+		return data, nil
+	}
+	var result hecResponse
+	json.Unmarshal(data, &result)
+	if result.Text == "" {
+		result.Text = string(bytes.TrimSpace(data))
+	}
+	err = fmt.Errorf("splunk: %s: %s (code %d)", resp.Status, result.Text, result.Code)
+	if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return nil, PermanentError(err)
+	}
+	return nil, err
+}
+
+// hecAcks polls the acknowledgment status of sent batches.
+type hecAcks struct {
+	client   *hecClient
+	timeout  time.Duration
+	interval time.Duration
+	drops    func(DropReason, int)
+	report   func(error)
+
+	mu      sync.Mutex
+	pending map[int64]pendingAck
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+type pendingAck struct {
+	events int
+	sent   time.Time
+}
+
+func newHECAcks(client *hecClient, timeout, interval time.Duration, drops func(DropReason, int), report func(error)) *hecAcks {
+	a := &hecAcks{
+		client: client, timeout: timeout, interval: interval, drops: drops, report: report,
+		pending: map[int64]pendingAck{}, done: make(chan struct{}),
+	}
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.poll()
+			case <-a.done:
+				return
+			}
+		}
+	}()
+	return a
+}
+
+func (a *hecAcks) add(id int64, events int) {
+	a.mu.Lock()
+	a.pending[id] = pendingAck{events: events, sent: time.Now()}
+	a.mu.Unlock()
+}
+
+// poll queries the pending acknowledgments and expires those past the
+// timeout. It reports whether any are still pending.
+func (a *hecAcks) poll() bool {
+	a.mu.Lock()
+	ids := make([]int64, 0, len(a.pending))
+	for id := range a.pending {
+		ids = append(ids, id)
+	}
+	a.mu.Unlock()
+	if len(ids) == 0 {
+		return false
+	}
+
+	body, _ := json.Marshal(map[string][]int64{"acks": ids})
+	var acked struct {
+		Acks map[string]bool `json:"acks"`
+	}
+	data, err := a.client.post(a.client.ackEndpoint, body)
+	if err == nil {
+		err = json.Unmarshal(data, &acked)
+	}
+	if err != nil {
+		a.report(fmt.Errorf("acknowledgment: %w", err))
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	expired := 0
+	for _, id := range ids {
+		p, ok := a.pending[id]
+		if !ok {
+			continue
+		}
+		switch {
+		case acked.Acks[strconv.FormatInt(id, 10)]:
+			delete(a.pending, id)
+		case time.Since(p.sent) > a.timeout:
+			delete(a.pending, id)
+			expired += p.events
+		}
+	}
+	if expired > 0 {
+		a.drops(DropProviderError, expired)
+		a.report(fmt.Errorf("splunk: %d events not acknowledged within %s", expired, a.timeout))
+	}
+	return len(a.pending) > 0
+}
+
+// close stops polling and waits up to the timeout for pending batches.
+func (a *hecAcks) close() {
+	close(a.done)
+	a.wg.Wait()
+	for a.poll() {
+		time.Sleep(a.interval)
+	}
+}
@@ -0,0 +1,189 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeHEC records the events posted to it and answers with ack IDs;
+// acknowledge decides whether an ack ID is reported as indexed.
+type fakeHEC struct {
+	mu          sync.Mutex
+	requests    []http.Header
+	events      [][]map[string]interface{}
+	ackPolls    int
+	status      int
+	acknowledge func(id int64) bool
+}
+
+func (f *fakeHEC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	switch r.URL.Path {
+	case "/services/collector/ack":
+		f.ackPolls++
+		var req struct {
+			Acks []int64 `json:"acks"`
+		}
+		json.Unmarshal(body, &req)
+		acks := map[string]bool{}
+		for _, id := range req.Acks {
+			acks[fmt.Sprint(id)] = f.acknowledge == nil || f.acknowledge(id)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"acks": acks})
+	case "/services/collector/event":
+		if f.status != 0 {
+			w.WriteHeader(f.status)
+			fmt.Fprint(w, `{"text":"Invalid token","code":4}`)
+			return
+		}
+		f.requests = append(f.requests, r.Header.Clone())
+		var events []map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		for dec.More() {
+			var event map[string]interface{}
+			dec.Decode(&event)
+			events = append(events, event)
+		}
+		f.events = append(f.events, events)
+		fmt.Fprintf(w, `{"text":"Success","code":0,"ackId":%d}`, len(f.events)-1)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestSplunkProvider_Events(t *testing.T) {
+	fake := &fakeHEC{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	at := time.Date(2026, 10, 16, 9, 30, 0, 250000000, time.UTC)
+	logger, err := NewLogger(
+		WithSplunkProvider(srv.URL, SplunkConfig{
+			HTTPConfig: HTTPConfig{BatchSize: 2, FlushInterval: time.Hour},
+			Token:      "secret",
+			Index:      "app",
+			Host:       "web-1",
+		}),
+		WithZapOptions(zap.WithClock(fixedClock{at})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("first", String("user", "ann"))
+	logger.Warn("second")
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.requests) != 1 || len(fake.events[0]) != 2 {
+		t.Fatalf("got %d requests with %v", len(fake.requests), fake.events)
+	}
+	if got := fake.requests[0].Get("Authorization"); got != "Splunk secret" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := fake.requests[0].Get("X-Splunk-Request-Channel"); got != "" {
+		t.Errorf("unexpected channel %q", got)
+	}
+	event := fake.events[0][0]
+	if event["time"] != 1792143000.25 || event["host"] != "web-1" || event["index"] != "app" || event["sourcetype"] != "_json" {
+		t.Errorf("unexpected metadata %v", event)
+	}
+	if _, ok := event["source"]; ok {
+		t.Errorf("empty source sent: %v", event)
+	}
+	doc, _ := event["event"].(map[string]interface{})
+	if doc["msg"] != "first" || doc["user"] != "ann" || doc["ts"] != nil {
+		t.Errorf("unexpected event %v", event["event"])
+	}
+}
+
+func TestSplunkProvider_TextEncoder(t *testing.T) {
+	fake := &fakeHEC{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithSplunkProvider(srv.URL+"/services/collector/event", SplunkConfig{
+		Token:      "secret",
+		SourceType: "app:log",
+	}, WithProviderEncoder(ConsoleEncoder)))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Error("failed", Int("attempt", 3))
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.events) != 1 {
+		t.Fatalf("got %d requests", len(fake.events))
+	}
+	event := fake.events[0][0]
+	text, _ := event["event"].(string)
+	if event["sourcetype"] != "app:log" || !strings.Contains(text, "failed") || !strings.Contains(text, `"attempt": 3`) {
+		t.Errorf("unexpected event %v", event)
+	}
+}
+
+func TestSplunkProvider_Ack(t *testing.T) {
+	fake := &fakeHEC{acknowledge: func(id int64) bool { return id == 0 }}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	logger, err := NewLogger(WithSplunkProvider(srv.URL, SplunkConfig{
+		HTTPConfig:  HTTPConfig{BatchSize: 1, FlushInterval: time.Hour},
+		Token:       "secret",
+		Ack:         true,
+		AckTimeout:  50 * time.Millisecond,
+		AckInterval: 10 * time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("indexed")
+	logger.Info("lost")
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	channel := fake.requests[0].Get("X-Splunk-Request-Channel")
+	if len(channel) != 36 || channel != fake.requests[1].Get("X-Splunk-Request-Channel") {
+		t.Errorf("channel = %q", channel)
+	}
+	if fake.ackPolls == 0 {
+		t.Error("acknowledgments were not polled")
+	}
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+}
+
+func TestSplunkProvider_Errors(t *testing.T) {
+	if _, err := NewLogger(WithSplunkProvider("http://splunk:8088", SplunkConfig{})); err == nil || !strings.Contains(err.Error(), "token is required") {
+		t.Errorf("expected token error, got %v", err)
+	}
+	if _, err := NewLogger(WithSplunkProvider("splunk:8088", SplunkConfig{Token: "t"})); err == nil || !strings.Contains(err.Error(), "invalid endpoint") {
+		t.Errorf("expected endpoint error, got %v", err)
+	}
+
+	fake := &fakeHEC{status: http.StatusForbidden}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	client := &hecClient{endpoint: srv.URL + "/services/collector/event", cfg: SplunkConfig{Token: "bad"}}
+	client.sender, _ = newHTTPSender(HTTPConfig{})
+	err := client.send([]byte(`{"event":"x"}` + "\n"))
+	if err == nil || isRetryable(err) || !strings.Contains(err.Error(), "Invalid token") {
+		t.Errorf("expected permanent token error, got %v", err)
+	}
+}
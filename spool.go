@@ -0,0 +1,471 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                     Disk-Backed Spool (Write-Ahead Log)                     */
+/* -------------------------------------------------------------------------- */
+
+// SpoolConfig configures WithSpool. Zero values fall back to the defaults
+// noted on each field.
+type SpoolConfig struct {
+	// Dir holds the spool files; it is created if missing. Required.
+	Dir string
+	// MaxSize bounds each spool file in bytes. Entries arriving while the
+	// spool is full are dropped as DropQueueFull (default 256 MiB).
+	MaxSize int64
+	// Fsync forces an fsync after every append, trading throughput for
+	// durability across power loss (default false: survive process crashes).
+	Fsync bool
+	// RetryInterval is the pause after a failed delivery (default 1s).
+	RetryInterval time.Duration
+}
+
+func (c SpoolConfig) withDefaults() SpoolConfig {
+	if c.MaxSize <= 0 {
+		c.MaxSize = 256 << 20
+	}
+	if c.RetryInterval <= 0 {
+		c.RetryInterval = time.Second
+	}
+	return c
+}
+
+const (
+	// spoolBatch is the number of records delivered between acknowledgements.
+	spoolBatch = 100
+	// spoolReadChunk bounds how much of the WAL is read per batch.
+	spoolReadChunk = 1 << 20
+)
+
+// WithSpool routes every provider registered by opt through a local
+// write-ahead log: entries are appended to disk first and handed to the
+// provider by a background goroutine, which acknowledges them only after the
+// provider's Sync succeeds. Unacknowledged entries are replayed on the next
+// start, giving at-least-once delivery across crashes and restarts.
+func WithSpool(opt LoggerOption, cfg SpoolConfig) LoggerOption {
+	cfg = cfg.withDefaults()
+	return func(lc *loggerConfig) {
+		n := 0
+		wrapProviders(opt, func(p provider) provider {
+			n++
+			return &spoolProvider{inner: p, cfg: cfg, index: n}
+		})(lc)
+	}
+}
+
+type spoolProvider struct {
+	inner provider
+	cfg   SpoolConfig
+	index int
+	tel   *telemetry
+
+	mu      sync.Mutex
+	wal     *os.File
+	ackPath string
+	size    int64 // bytes written to the WAL
+	acked   int64 // bytes delivered and acknowledged
+	pending chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+	inCore  zapcore.Core
+	closed  bool
+}
+
+func (p *spoolProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	if p.cfg.Dir == "" {
+		return nil, errors.New("spool: directory must be set")
+	}
+	inCore, err := p.inner.newCore(level)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(p.cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("spool: %w", err)
+	}
+
+	base := filepath.Join(p.cfg.Dir, fmt.Sprintf("%s-%d", sanitizeFileName(describeProvider(p.inner).Name), p.index))
+	wal, err := os.OpenFile(base+".wal", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("spool: %w", err)
+	}
+	p.wal, p.ackPath, p.inCore = wal, base+".ack", inCore
+	if err := p.recover(); err != nil {
+		_ = wal.Close()
+		p.wal = nil
+		return nil, fmt.Errorf("spool: %w", err)
+	}
+
+	p.pending = make(chan struct{}, 1)
+	p.done = make(chan struct{})
+	p.wg.Add(1)
+	go p.deliver()
+	if p.acked < p.size {
+		p.signal() // replay what a previous run left behind
+	}
+
+	return &spoolCore{LevelEnabler: level, p: p}, nil
+}
+
+// recover restores the write and ack offsets, discarding a torn final record.
+func (p *spoolProvider) recover() error {
+	data, err := io.ReadAll(p.wal)
+	if err != nil {
+		return err
+	}
+	p.size = int64(bytes.LastIndexByte(data, '\n') + 1)
+	if p.size != int64(len(data)) {
+		if err := p.wal.Truncate(p.size); err != nil {
+			return err
+		}
+	}
+	if raw, err := os.ReadFile(p.ackPath); err == nil {
+		if n, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64); err == nil && n <= p.size {
+			p.acked = n
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (p *spoolProvider) signal() {
+	select {
+	case p.pending <- struct{}{}:
+	default:
+	}
+}
+
+// append writes one encoded record to the WAL.
+func (p *spoolProvider) append(rec []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return errors.New("spool: closed")
+	}
+	if p.size+int64(len(rec)) > p.cfg.MaxSize {
+		if p.tel != nil {
+			p.tel.drops.record(DropQueueFull, 1)
+		}
+		return nil
+	}
+	if _, err := p.wal.WriteAt(rec, p.size); err != nil {
+		return fmt.Errorf("spool: append: %w", err)
+	}
+	p.size += int64(len(rec))
+	if p.cfg.Fsync {
+		if err := p.wal.Sync(); err != nil {
+			return fmt.Errorf("spool: fsync: %w", err)
+		}
+	}
+	p.signal()
+	return nil
+}
+
+// deliver forwards spooled records to the wrapped provider until closed.
+func (p *spoolProvider) deliver() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-p.pending:
+		}
+		for {
+			n, err := p.deliverBatch()
+			if err != nil {
+				if p.tel != nil {
+					p.tel.errs.report(fmt.Errorf("spool: delivery to %s failed: %w", describeProvider(p.inner).Name, err))
+				}
+				select {
+				case <-p.done:
+					return
+				case <-time.After(p.cfg.RetryInterval):
+				}
+				continue
+			}
+			if n == 0 {
+				break
+			}
+		}
+	}
+}
+
+// deliverBatch sends up to spoolBatch records, syncs the provider and then
+// acknowledges them. It returns the number of records acknowledged.
+func (p *spoolProvider) deliverBatch() (int, error) {
+	p.mu.Lock()
+	start, end := p.acked, p.size
+	p.mu.Unlock()
+	if start >= end {
+		return 0, nil
+	}
+
+	buf, err := p.readFrom(start, end)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		count    int
+		consumed int64
+	)
+	for count < spoolBatch && len(buf) > 0 {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := buf[:i]
+		buf = buf[i+1:]
+		consumed += int64(i + 1)
+		count++
+
+		ent, fields, err := decodeSpoolRecord(line)
+		if err != nil {
+			// A corrupt record can never be delivered; skip it.
+			if p.tel != nil {
+				p.tel.errs.report(fmt.Errorf("spool: skipping corrupt record: %w", err))
+			}
+			continue
+		}
+		if err := p.inCore.Write(ent, fields); err != nil {
+			return 0, err
+		}
+	}
+	if err := p.inCore.Sync(); err != nil {
+		return 0, err
+	}
+	return count, p.ack(start + consumed)
+}
+
+// readFrom returns WAL bytes in [start, end), capped at spoolReadChunk unless
+// a single record is larger than that.
+func (p *spoolProvider) readFrom(start, end int64) ([]byte, error) {
+	n := end - start
+	if n > spoolReadChunk {
+		n = spoolReadChunk
+	}
+	for {
+		buf := make([]byte, n)
+		if _, err := p.wal.ReadAt(buf, start); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if n == end-start || bytes.IndexByte(buf, '\n') >= 0 {
+			return buf, nil
+		}
+		n = end - start
+	}
+}
+
+// ack persists the delivered offset and compacts the WAL once it is drained.
+func (p *spoolProvider) ack(offset int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.acked = offset
+	if p.acked == p.size {
+		if err := p.wal.Truncate(0); err != nil {
+			return err
+		}
+		p.acked, p.size = 0, 0
+	}
+	tmp := p.ackPath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(p.acked, 10)), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.ackPath)
+}
+
+func (p *spoolProvider) close() error {
+	p.mu.Lock()
+	if p.closed || p.wal == nil {
+		p.mu.Unlock()
+		return p.inner.close()
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	close(p.done)
+	p.wg.Wait()
+	// Make one synchronous attempt to drain; anything still unacknowledged
+	// stays on disk for the next run.
+	for {
+		n, err := p.deliverBatch()
+		if err != nil || n == 0 {
+			break
+		}
+	}
+	return errors.Join(p.wal.Close(), p.inner.close())
+}
+
+func (p *spoolProvider) instrument(t *telemetry) {
+	p.tel = t
+	if ip, ok := p.inner.(instrumentedProvider); ok {
+		ip.instrument(t)
+	}
+}
+
+func (p *spoolProvider) describe() ProviderInfo {
+	info := describeProvider(p.inner)
+	info.Name = "spool(" + info.Name + ")"
+	return info
+}
+
+// spoolCore appends entries to the WAL instead of writing them directly.
+type spoolCore struct {
+	zapcore.LevelEnabler
+	p      *spoolProvider
+	fields []zapcore.Field
+}
+
+func (c *spoolCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+func (c *spoolCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *spoolCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	rec, err := encodeSpoolRecord(ent, c.fields, fields)
+	if err != nil {
+		return fmt.Errorf("spool: encode: %w", err)
+	}
+	return c.p.append(rec)
+}
+
+func (c *spoolCore) Sync() error { return nil }
+
+// spoolRecord is the on-disk form of an entry (one JSON object per line).
+type spoolRecord struct {
+	Time     time.Time              `json:"t"`
+	Level    zapcore.Level          `json:"l"`
+	Logger   string                 `json:"n,omitempty"`
+	Message  string                 `json:"m"`
+	File     string                 `json:"cf,omitempty"`
+	Line     int                    `json:"cl,omitempty"`
+	Function string                 `json:"cn,omitempty"`
+	Stack    string                 `json:"s,omitempty"`
+	Fields   map[string]interface{} `json:"f,omitempty"`
+}
+
+// newSpoolRecord captures ent and its fields in serialisable form.
+func newSpoolRecord(ent zapcore.Entry, ctxFields, fields []zapcore.Field) spoolRecord {
+	rec := spoolRecord{
+		Time:    ent.Time,
+		Level:   ent.Level,
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+		Stack:   ent.Stack,
+		Fields:  decodeEntry(ent, ctxFields, fields).Fields,
+	}
+	if ent.Caller.Defined {
+		rec.File, rec.Line, rec.Function = ent.Caller.File, ent.Caller.Line, ent.Caller.Function
+	}
+	return rec
+}
+
+// entry rebuilds the zap entry and fields. Numbers must have been decoded
+// with json.Decoder.UseNumber so integers survive the round trip.
+func (rec spoolRecord) entry() (zapcore.Entry, []zapcore.Field) {
+	ent := zapcore.Entry{
+		Time:       rec.Time,
+		Level:      rec.Level,
+		LoggerName: rec.Logger,
+		Message:    rec.Message,
+		Stack:      rec.Stack,
+	}
+	if rec.File != "" {
+		ent.Caller = zapcore.EntryCaller{Defined: true, File: rec.File, Line: rec.Line, Function: rec.Function}
+	}
+	return ent, mapToZapFields(rec.Fields)
+}
+
+func encodeSpoolRecord(ent zapcore.Entry, ctxFields, fields []zapcore.Field) ([]byte, error) {
+	b, err := json.Marshal(newSpoolRecord(ent, ctxFields, fields))
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func decodeSpoolRecord(line []byte) (zapcore.Entry, []zapcore.Field, error) {
+	var rec spoolRecord
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&rec); err != nil {
+		return zapcore.Entry{}, nil, err
+	}
+	ent, fields := rec.entry()
+	return ent, fields, nil
+}
+
+// mapToZapFields converts decoded JSON values back into zap fields, sorted by
+// key so replayed entries encode deterministically.
+func mapToZapFields(m map[string]interface{}) []zapcore.Field {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fields := make([]zapcore.Field, len(keys))
+	for i, k := range keys {
+		fields[i] = zap.Any(k, restoreJSONValue(m[k]))
+	}
+	return fields
+}
+
+// restoreJSONValue turns json.Number back into int64/float64, recursively.
+func restoreJSONValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range x {
+			x[k] = restoreJSONValue(e)
+		}
+		return x
+	case []interface{}:
+		for i, e := range x {
+			x[i] = restoreJSONValue(e)
+		}
+		return x
+	default:
+		return v
+	}
+}
+
+// sanitizeFileName maps a provider description to a safe file name.
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
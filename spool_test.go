@@ -0,0 +1,109 @@
+package golog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithSpool_DeliversAndCompacts(t *testing.T) {
+	dir := t.TempDir()
+	var buf concurrentBuffer
+	logger, err := NewLogger(WithSpool(WithWriterProvider(&buf, JSONEncoder), SpoolConfig{Dir: dir}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+
+	logger.Info("spooled", Int("n", 1), String("s", "x"))
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(buf.String(), "spooled") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, exp := range []string{`"msg":"spooled"`, `"n":1`, `"s":"x"`} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected delivered entry to contain %s, got %s", exp, out)
+		}
+	}
+	wal, err := os.ReadFile(filepath.Join(dir, "writer-1.wal"))
+	if err != nil {
+		t.Fatalf("reading wal: %v", err)
+	}
+	if len(wal) != 0 {
+		t.Fatalf("expected the WAL to be compacted after delivery, got %q", wal)
+	}
+}
+
+func TestWithSpool_ReplaysAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	// Simulate a previous run that spooled two entries, delivered the first
+	// and crashed halfway through writing a third.
+	var wal bytes.Buffer
+	for _, msg := range []string{"already delivered", "left behind"} {
+		rec, err := encodeSpoolRecord(zapcore.Entry{Level: zapcore.InfoLevel, Message: msg, Time: time.Now()}, nil, []zapcore.Field{zap.Int("n", 7)})
+		if err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		wal.Write(rec)
+	}
+	first := bytes.IndexByte(wal.Bytes(), '\n') + 1
+	if err := os.WriteFile(filepath.Join(dir, "writer-1.ack"), []byte(strconv.Itoa(first)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wal.WriteString(`{"t":"torn`)
+	if err := os.WriteFile(filepath.Join(dir, "writer-1.wal"), wal.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf concurrentBuffer
+	logger, err := NewLogger(WithSpool(WithWriterProvider(&buf, JSONEncoder), SpoolConfig{Dir: dir}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "already delivered") || !strings.Contains(out, "left behind") || !strings.Contains(out, `"n":7`) {
+		t.Fatalf("unexpected replay output: %s", out)
+	}
+	if strings.Count(strings.TrimSpace(out), "\n") != 0 {
+		t.Fatalf("expected exactly one replayed entry, got: %s", out)
+	}
+}
+
+func TestWithSpool_KeepsEntriesWhileProviderFails(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := NewLogger(
+		WithSpool(WithWriterProvider(failingWriter{}, JSONEncoder), SpoolConfig{Dir: dir, RetryInterval: time.Hour}),
+		WithErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("undeliverable")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	wal, err := os.ReadFile(filepath.Join(dir, "writer-1.wal"))
+	if err != nil {
+		t.Fatalf("reading wal: %v", err)
+	}
+	if !bytes.Contains(wal, []byte("undeliverable")) {
+		t.Fatalf("expected the undelivered entry to remain spooled, got %q", wal)
+	}
+}
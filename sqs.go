@@ -0,0 +1,424 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                              Amazon SQS Provider                            */
+/* -------------------------------------------------------------------------- */
+
+const (
+	// sqsMaxMessages and sqsMaxBatchBytes are the SendMessageBatch limits;
+	// a single message may use the whole payload.
+	sqsMaxMessages   = 10
+	sqsMaxBatchBytes = 256 << 10
+)
+
+// SQSConfig configures WithSQSProvider. Zero values fall back to the
+// defaults noted on each field.
+type SQSConfig struct {
+	// Region is the AWS region (default taken from the queue URL, then
+	// AWS_REGION and AWS_DEFAULT_REGION).
+	Region string
+	// Credentials signs each request (default AWS_ACCESS_KEY_ID,
+	// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN, read per request).
+	Credentials AWSCredentialsProvider
+	// Endpoint overrides the API endpoint, by default the scheme and host
+	// of the queue URL.
+	Endpoint string
+	// MessageGroupField names the field whose value is the message group
+	// of a FIFO queue, so that entries with the same value are delivered in
+	// order. Entries without it use MessageGroupID.
+	MessageGroupField string
+	// MessageGroupID is the message group of FIFO entries without
+	// MessageGroupField (default "golog"), which keeps them all in order.
+	MessageGroupID string
+	// DeduplicationField names the field whose value is the deduplication
+	// ID of FIFO entries. Entries without it use the SHA-256 of the message,
+	// so that a batch resent after a lost response is not delivered twice.
+	DeduplicationField string
+	// BatchSize is the number of messages per call (default and at most
+	// 10). Batches are also sent before exceeding 256 KiB.
+	BatchSize int
+	// FlushInterval sends a partial batch after this long (default 1s).
+	FlushInterval time.Duration
+	// MaxRetries is the number of times throttled or failed messages are
+	// resent, with exponential backoff (default 3).
+	MaxRetries int
+	// Timeout bounds each batch, retries included (default 30s).
+	Timeout time.Duration
+	// TLS and ProxyURL configure the connection as for HTTPConfig.
+	TLS      *TLSConfig
+	ProxyURL string
+}
+
+func (c SQSConfig) withDefaults(queue *url.URL) SQSConfig {
+	if c.Region == "" {
+		// sqs.<region>.amazonaws.com, or the legacy <region>.queue.amazonaws.com.
+		switch host := strings.Split(queue.Hostname(), "."); {
+		case len(host) >= 4 && host[0] == "sqs" && host[2] == "amazonaws":
+			c.Region = host[1]
+		case len(host) >= 4 && host[1] == "queue" && host[2] == "amazonaws":
+			c.Region = host[0]
+		}
+	}
+	if c.Region == "" {
+		c.Region = envAWSRegion()
+	}
+	if c.Credentials == nil {
+		c.Credentials = envAWSCredentials
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = queue.Scheme + "://" + queue.Host
+	}
+	if c.MessageGroupID == "" {
+		c.MessageGroupID = "golog"
+	}
+	if c.BatchSize <= 0 || c.BatchSize > sqsMaxMessages {
+		c.BatchSize = sqsMaxMessages
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = time.Second
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = 3
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 30 * time.Second
+	}
+	return c
+}
+
+// WithSQSProvider sends entries, JSON-encoded unless WithProviderEncoder
+// says otherwise, to an SQS queue with SendMessageBatch:
+//
+//	golog.WithSQSProvider("https://sqs.eu-west-1.amazonaws.com/123456789012/logs.fifo", golog.SQSConfig{
+//		MessageGroupField: "tenant",
+//	})
+//
+// A queue whose name ends in ".fifo" is a FIFO queue: every message gets a
+// message group and a deduplication ID. Messages SQS fails on its side,
+// and calls that are throttled, are resent with backoff up to MaxRetries
+// times, then dropped and counted as DropProviderError. Entries larger than
+// 256 KiB are counted as DropOversized.
+func WithSQSProvider(queueURL string, cfg SQSConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		c.providers = append(c.providers, applyProviderOptions(&sqsProvider{
+			providerBase: providerBase{name: "sqs:" + queueURL, encoders: anyEncoder},
+			queueURL:     queueURL,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+type sqsProvider struct {
+	providerBase
+	queueURL string
+	cfg      SQSConfig
+
+	batch *batcher[sqsMessage]
+}
+
+func (p *sqsProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	queue, err := url.Parse(p.queueURL)
+	if err != nil || queue.Host == "" || (queue.Scheme != "https" && queue.Scheme != "http") {
+		return nil, fmt.Errorf("sqs provider: invalid queue URL %q", p.queueURL)
+	}
+	cfg := p.cfg.withDefaults(queue)
+	if cfg.Region == "" {
+		return nil, errors.New("sqs provider: no region; set SQSConfig.Region or AWS_REGION")
+	}
+	if u, err := url.Parse(cfg.Endpoint); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("sqs provider: invalid endpoint %q", cfg.Endpoint)
+	}
+	sender, err := newHTTPSender(HTTPConfig{TLS: cfg.TLS, ProxyURL: cfg.ProxyURL, Timeout: cfg.Timeout})
+	if err != nil {
+		return nil, fmt.Errorf("sqs provider: %w", err)
+	}
+	enc, err := p.tel.buildEncoder(p.encoder(), p.encoderConfig)
+	if err != nil {
+		return nil, err
+	}
+	client := &sqsClient{queueURL: p.queueURL, cfg: cfg, http: sender.client}
+	p.batch = &batcher[sqsMessage]{
+		limit:    cfg.BatchSize,
+		maxBytes: sqsMaxBatchBytes,
+		measure:  measureSQSMessage,
+		send:     client.send,
+		drops:    p.dropped,
+		report:   p.report,
+	}
+	p.batch.start(cfg.FlushInterval)
+	core := &sqsCore{LevelEnabler: level, enc: enc, batch: p.batch}
+	if strings.HasSuffix(queue.Path, ".fifo") {
+		core.fifo = &sqsFIFO{groupField: cfg.MessageGroupField, group: cfg.MessageGroupID, dedupField: cfg.DeduplicationField}
+	}
+	return core, nil
+}
+
+func (p *sqsProvider) close() error {
+	if p.batch == nil {
+		return nil
+	}
+	return p.batch.close()
+}
+
+func (p *sqsProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: p.encoder()}
+}
+
+// sqsFIFO holds the message group and deduplication settings of a FIFO
+// queue, with the values found among the context fields.
+type sqsFIFO struct {
+	groupField, group string
+	dedupField, dedup string
+}
+
+// sqsCore encodes entries into messages for the batch.
+type sqsCore struct {
+	zapcore.LevelEnabler
+	enc   zapcore.Encoder
+	fifo  *sqsFIFO
+	batch *batcher[sqsMessage]
+}
+
+func (c *sqsCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.enc = c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	if c.fifo != nil {
+		fifo := *c.fifo
+		if group, ok := fieldValueText(fifo.groupField, fields); ok {
+			fifo.group = group
+		}
+		if dedup, ok := fieldValueText(fifo.dedupField, fields); ok {
+			fifo.dedup = dedup
+		}
+		clone.fifo = &fifo
+	}
+	return &clone
+}
+
+func (c *sqsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sqsCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := sqsMessage{MessageBody: strings.TrimSuffix(buf.String(), "\n")}
+	buf.Free()
+	if c.fifo != nil {
+		msg.MessageGroupID, msg.MessageDeduplicationID = c.fifo.group, c.fifo.dedup
+		if group, ok := fieldValueText(c.fifo.groupField, fields); ok {
+			msg.MessageGroupID = group
+		}
+		if dedup, ok := fieldValueText(c.fifo.dedupField, fields); ok {
+			msg.MessageDeduplicationID = dedup
+		}
+		if msg.MessageDeduplicationID == "" {
+			sum := sha256.Sum256([]byte(msg.MessageBody))
+			msg.MessageDeduplicationID = hex.EncodeToString(sum[:])
+		}
+		// Both are limited to 128 characters.
+		msg.MessageGroupID = truncateUTF8(msg.MessageGroupID, 128)
+		msg.MessageDeduplicationID = truncateUTF8(msg.MessageDeduplicationID, 128)
+	}
+	return c.batch.add(msg)
+}
+
+func (c *sqsCore) Sync() error { return c.batch.Sync() }
+
+/* -------------------------------------------------------------------------- */
+/*                               Message Batching                              */
+/* -------------------------------------------------------------------------- */
+
+type sqsMessage struct {
+	ID                     string `json:"Id"`
+	MessageBody            string `json:"MessageBody"`
+	MessageGroupID         string `json:"MessageGroupId,omitempty"`
+	MessageDeduplicationID string `json:"MessageDeduplicationId,omitempty"`
+}
+
+// measureSQSMessage sizes m for the batcher, rejecting messages over the
+// payload limit.
+func measureSQSMessage(m sqsMessage) (int, error) {
+	size := len(m.MessageBody)
+	if size > sqsMaxBatchBytes {
+		return 0, fmt.Errorf("sqs: entry of %d bytes exceeds the %d byte message limit", size, sqsMaxBatchBytes)
+	}
+	return size, nil
+}
+
+/* -------------------------------------------------------------------------- */
+/*                                SQS API Client                               */
+/* -------------------------------------------------------------------------- */
+
+type sqsClient struct {
+	queueURL string
+	cfg      SQSConfig
+	http     *http.Client
+}
+
+// sqsError is an error response of the SQS API.
+type sqsError struct {
+	Status  int
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+func (e *sqsError) Error() string {
+	return fmt.Sprintf("sqs: %d %s: %s", e.Status, e.Type, e.Message)
+}
+
+// retryableSQSError reports whether a call that failed with the error type
+// typ and HTTP status may succeed later.
+func retryableSQSError(typ string, status int) bool {
+	switch typ {
+	case "ThrottlingException", "RequestThrottled", "ServiceUnavailable", "InternalFailure", "KmsThrottled":
+		return true
+	}
+	return status >= 500
+}
+
+// send calls SendMessageBatch, resending the messages that fail on the
+// service's side until they succeed, MaxRetries is reached or the timeout
+// passes. Messages failing by the sender's fault are not resent. The number
+// of messages lost is returned in a batchDropError.
+func (c *sqsClient) send(messages []sqsMessage) error {
+	for i := range messages {
+		messages[i].ID = strconv.Itoa(i)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeout)
+	defer cancel()
+	backoff := 100 * time.Millisecond
+	pending, rejected := messages, 0
+	var lastErr, rejectErr error
+	for attempt := 0; ; attempt++ {
+		var resp struct {
+			Failed []struct {
+				ID          string `json:"Id"`
+				SenderFault bool   `json:"SenderFault"`
+				Code        string `json:"Code"`
+				Message     string `json:"Message"`
+			} `json:"Failed"`
+		}
+		err := c.call(ctx, "SendMessageBatch", map[string]interface{}{"QueueUrl": c.queueURL, "Entries": pending}, &resp)
+		if err != nil {
+			var apiErr *sqsError
+			if errors.As(err, &apiErr) && !retryableSQSError(apiErr.Type, apiErr.Status) {
+				return &batchDropError{n: rejected + len(pending), err: errors.Join(rejectErr, PermanentError(err))}
+			}
+			lastErr = err
+		} else {
+			byID := make(map[string]sqsMessage, len(pending))
+			for _, m := range pending {
+				byID[m.ID] = m
+			}
+			var retry []sqsMessage
+			for _, f := range resp.Failed {
+				m, ok := byID[f.ID]
+				if !ok {
+					continue
+				}
+				failure := fmt.Errorf("sqs: message failed: %s: %s", f.Code, f.Message)
+				if f.SenderFault {
+					rejected++
+					if rejectErr == nil {
+						rejectErr = PermanentError(failure)
+					}
+				} else {
+					retry = append(retry, m)
+					lastErr = failure
+				}
+			}
+			pending = retry
+			if len(pending) == 0 {
+				if rejected > 0 {
+					return &batchDropError{n: rejected, err: rejectErr}
+				}
+				return nil
+			}
+		}
+		if attempt >= c.cfg.MaxRetries {
+			return &batchDropError{n: rejected + len(pending), err: errors.Join(rejectErr, lastErr)}
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return &batchDropError{n: rejected + len(pending), err: errors.Join(rejectErr, lastErr)}
+		}
+		backoff = min(backoff*2, 5*time.Second)
+	}
+}
+
+// call sends the JSON request req for action and decodes the response into
+// resp.
+func (c *sqsClient) call(ctx context.Context, action string, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return PermanentError(fmt.Errorf("sqs: %w", err))
+	}
+	creds, err := c.cfg.Credentials(ctx)
+	if err != nil {
+		return fmt.Errorf("sqs: credentials: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return PermanentError(fmt.Errorf("sqs: %w", err))
+	}
+	httpReq.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	httpReq.Header.Set("X-Amz-Target", "AmazonSQS."+action)
+	signAWSRequest(httpReq, body, creds, c.cfg.Region, "sqs", time.Now())
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("sqs: %w", err)
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("sqs: %w", err)
+	}
+	if httpResp.StatusCode/100 != 2 {
+		apiErr := &sqsError{Status: httpResp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Type == "" {
+			apiErr.Message = string(bytes.TrimSpace(data))
+		}
+		// The query-compatible code, e.g. "AWS.SimpleQueueService.NonExistentQueue;Sender",
+		// is more specific than the type.
+		if code, _, _ := strings.Cut(httpResp.Header.Get("X-Amzn-Query-Error"), ";"); code != "" {
+			apiErr.Type = code
+		}
+		if i := strings.LastIndexByte(apiErr.Type, '#'); i >= 0 {
+			apiErr.Type = apiErr.Type[i+1:]
+		}
+		return apiErr
+	}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("sqs: invalid response: %w", err)
+	}
+	return nil
+}
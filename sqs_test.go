@@ -0,0 +1,175 @@
+package golog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSQS implements SendMessageBatch, failing the messages whose body
+// contains a key of failures once each; "sender" codes are the sender's
+// fault.
+type fakeSQS struct {
+	mu       sync.Mutex
+	calls    int
+	queues   []string
+	messages []sqsMessage
+	failures map[string]string
+	status   int
+}
+
+func (f *fakeSQS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if r.Header.Get("X-Amz-Target") != "AmazonSQS.SendMessageBatch" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if f.status != 0 {
+		w.Header().Set("X-Amzn-Query-Error", "AWS.SimpleQueueService.NonExistentQueue;Sender")
+		w.WriteHeader(f.status)
+		json.NewEncoder(w).Encode(map[string]string{"__type": "com.amazonaws.sqs#QueueDoesNotExist", "message": "no queue"})
+		return
+	}
+	var req struct {
+		QueueURL string       `json:"QueueUrl"`
+		Entries  []sqsMessage `json:"Entries"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	f.queues = append(f.queues, req.QueueURL)
+	type failure struct {
+		ID          string `json:"Id"`
+		SenderFault bool   `json:"SenderFault"`
+		Code        string `json:"Code"`
+	}
+	var failed []failure
+	for _, m := range req.Entries {
+		code := ""
+		for marker, c := range f.failures {
+			if strings.Contains(m.MessageBody, marker) {
+				code = c
+				delete(f.failures, marker)
+			}
+		}
+		if code != "" {
+			failed = append(failed, failure{ID: m.ID, SenderFault: code == "InvalidParameterValue", Code: code})
+			continue
+		}
+		f.messages = append(f.messages, m)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"Failed": failed})
+}
+
+func testSQSConfig(url string) SQSConfig {
+	return SQSConfig{
+		Region:        "us-east-1",
+		Endpoint:      url,
+		FlushInterval: time.Hour,
+		Credentials:   testAWSCredentials,
+	}
+}
+
+func TestSQSProvider_Batches(t *testing.T) {
+	fake := &fakeSQS{failures: map[string]string{"busy": "InternalError", "invalid": "InvalidParameterValue"}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	queue := "https://sqs.us-east-1.amazonaws.com/123456789012/logs"
+	cfg := testSQSConfig(srv.URL)
+	cfg.BatchSize = 3
+	logger, err := NewLogger(WithSQSProvider(queue, cfg))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("busy")
+	logger.Info("invalid")
+	logger.Info("third")
+	logger.Info("fourth")
+	if err := logger.Sync(); err != nil {
+		t.Errorf("sync: %v", err)
+	}
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.calls != 3 || fake.queues[0] != queue {
+		t.Errorf("calls = %d, queues = %v", fake.calls, fake.queues)
+	}
+	var bodies []string
+	for _, m := range fake.messages {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(m.MessageBody), &entry); err != nil {
+			t.Fatalf("body %q: %v", m.MessageBody, err)
+		}
+		bodies = append(bodies, entry["msg"].(string))
+		if m.MessageGroupID != "" || m.MessageDeduplicationID != "" {
+			t.Errorf("standard queue message has FIFO attributes: %+v", m)
+		}
+	}
+	if strings.Join(bodies, ",") != "third,busy,fourth" {
+		t.Errorf("messages = %v", bodies)
+	}
+	if n := logger.DroppedEntries()[DropProviderError]; n != 1 {
+		t.Errorf("provider error drops = %d, want 1", n)
+	}
+}
+
+func TestSQSProvider_FIFO(t *testing.T) {
+	fake := &fakeSQS{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	cfg := testSQSConfig(srv.URL)
+	cfg.MessageGroupField = "tenant"
+	cfg.DeduplicationField = "event_id"
+	logger, err := NewLogger(WithSQSProvider("https://sqs.us-east-1.amazonaws.com/123456789012/logs.fifo", cfg))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.With(String("tenant", "acme")).Info("grouped", String("event_id", "e-1"))
+	logger.Info("default group")
+	logger.Close()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.messages) != 2 {
+		t.Fatalf("got %d messages, want 2", len(fake.messages))
+	}
+	if m := fake.messages[0]; m.MessageGroupID != "acme" || m.MessageDeduplicationID != "e-1" {
+		t.Errorf("first message = %+v", m)
+	}
+	if m := fake.messages[1]; m.MessageGroupID != "golog" || len(m.MessageDeduplicationID) != 64 {
+		t.Errorf("second message = %+v", m)
+	}
+}
+
+func TestSQSProvider_Errors(t *testing.T) {
+	fake := &fakeSQS{status: http.StatusBadRequest}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	testAWSProviderErrors(t, WithSQSProvider(srv.URL+"/123456789012/missing", testSQSConfig(srv.URL)), 256<<10, "AWS.SimpleQueueService.NonExistentQueue", func() int {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return fake.calls
+	})
+
+	if _, err := NewLogger(WithSQSProvider("queue", testSQSConfig(srv.URL))); err == nil {
+		t.Error("expected an error for a relative queue URL")
+	}
+	clearAWSRegion(t)
+	if _, err := NewLogger(WithSQSProvider("http://localhost:4566/000000000000/q", SQSConfig{})); err == nil || !strings.Contains(err.Error(), "region") {
+		t.Errorf("expected a region error, got %v", err)
+	}
+	logger, err := NewLogger(WithSQSProvider("https://sqs.eu-west-1.amazonaws.com/1/q", SQSConfig{}))
+	if err != nil {
+		t.Errorf("region should come from the queue URL: %v", err)
+	} else {
+		logger.Close()
+	}
+}
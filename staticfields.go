@@ -0,0 +1,44 @@
+package golog
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                        Per-Provider Static Fields                           */
+/* -------------------------------------------------------------------------- */
+
+// WithProviderFields adds fields to every entry written by the providers
+// registered by opt, and only to those – e.g. sink "archive" on an archival
+// file provider – independently of fields attached at the call site.
+//
+//	golog.WithProviderFields(golog.WithFileProvider("archive.log", 100, 10, 90, true), golog.String("sink", "archive"))
+func WithProviderFields(opt LoggerOption, fields ...Field) LoggerOption {
+	zf := toZapFields(fields)
+	return wrapProviders(opt, func(p provider) provider {
+		return &staticFieldsProvider{inner: p, fields: zf}
+	})
+}
+
+type staticFieldsProvider struct {
+	inner  provider
+	fields []zapcore.Field
+}
+
+func (p *staticFieldsProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	core, err := p.inner.newCore(level)
+	if err != nil {
+		return nil, err
+	}
+	return core.With(p.fields), nil
+}
+
+func (p *staticFieldsProvider) close() error { return p.inner.close() }
+
+func (p *staticFieldsProvider) instrument(t *telemetry) {
+	if ip, ok := p.inner.(instrumentedProvider); ok {
+		ip.instrument(t)
+	}
+}
+
+func (p *staticFieldsProvider) describe() ProviderInfo { return describeProvider(p.inner) }
@@ -0,0 +1,39 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProviderFields(t *testing.T) {
+	var plain, archive bytes.Buffer
+	logger, err := NewLogger(
+		WithWriterProvider(&plain, JSONEncoder),
+		WithProviderFields(WithWriterProvider(&archive, JSONEncoder), String("sink", "archive"), Int("tier", 2)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("stored", String("id", "42"))
+
+	if out := plain.String(); strings.Contains(out, "sink") {
+		t.Errorf("static fields leaked to other provider: %s", out)
+	}
+	if out := archive.String(); !strings.Contains(out, `"sink":"archive"`) || !strings.Contains(out, `"tier":2`) || !strings.Contains(out, `"id":"42"`) {
+		t.Errorf("unexpected archive output: %s", out)
+	}
+}
+
+func TestNamedProviderFields(t *testing.T) {
+	if _, err := NewLogger(WithNamedProvider("stdout", map[string]any{"fields": "env=prod"})); err == nil {
+		t.Error("expected error for malformed fields param")
+	}
+	logger, err := NewLogger(WithNamedProvider("stdout", map[string]any{"fields": map[string]any{"env": "prod"}}))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Close()
+}
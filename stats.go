@@ -0,0 +1,180 @@
+package golog
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                               Stats Snapshot                                */
+/* -------------------------------------------------------------------------- */
+
+// Stats is a point-in-time view of a logger's activity, suitable for
+// exposing on an application's own admin or metrics endpoint.
+type Stats struct {
+	// Entries counts entries accepted for writing, per level.
+	Entries map[Level]uint64 `json:"entries"`
+	// Providers reports write/error counts per provider, in the order the
+	// providers were configured.
+	Providers []ProviderStats `json:"providers"`
+	// Dropped counts entries that never reached a provider, per reason.
+	Dropped map[DropReason]uint64 `json:"dropped"`
+	// LastError is the most recent internal error, empty if none occurred.
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at,omitempty"`
+}
+
+// ProviderStats holds the counters for a single provider.
+type ProviderStats struct {
+	Name      string    `json:"name"`
+	Writes    uint64    `json:"writes"`
+	Errors    uint64    `json:"errors"`
+	LastError string    `json:"last_error,omitempty"`
+	LastErrAt time.Time `json:"last_error_at,omitempty"`
+}
+
+// providerCounters is the live, concurrently updated form of ProviderStats.
+type providerCounters struct {
+	name   string
+	writes atomic.Uint64
+	errors atomic.Uint64
+
+	mu        sync.Mutex
+	lastErr   error
+	lastErrAt time.Time
+}
+
+func (c *providerCounters) recordError(err error) {
+	c.errors.Add(1)
+	c.mu.Lock()
+	c.lastErr = err
+	c.lastErrAt = time.Now()
+	c.mu.Unlock()
+}
+
+func (c *providerCounters) snapshot() ProviderStats {
+	s := ProviderStats{
+		Name:   c.name,
+		Writes: c.writes.Load(),
+		Errors: c.errors.Load(),
+	}
+	c.mu.Lock()
+	if c.lastErr != nil {
+		s.LastError = c.lastErr.Error()
+		s.LastErrAt = c.lastErrAt
+	}
+	c.mu.Unlock()
+	return s
+}
+
+// statsCollector aggregates everything Stats() reports.
+type statsCollector struct {
+	// levels counts entries per level from Trace, which also counts custom
+	// levels below it, up to Fatal.
+	levels [zapcore.FatalLevel - traceZapLevel + 1]atomic.Uint64
+
+	// mu guards providers, which grows with AddProvider and config reloads
+	// while Stats reads it.
+	mu        sync.Mutex
+	providers []*providerCounters
+}
+
+// countEntry is installed as a zap hook and runs for every entry that passed
+// the level check.
+func (s *statsCollector) countEntry(ent zapcore.Entry) error {
+	lvl := ent.Level
+	if lvl < traceZapLevel {
+		lvl = traceZapLevel
+	}
+	if lvl <= zapcore.FatalLevel {
+		s.levels[lvl-traceZapLevel].Add(1)
+	}
+	return nil
+}
+
+func (s *statsCollector) addProvider(name string) *providerCounters {
+	c := &providerCounters{name: name}
+	s.mu.Lock()
+	s.providers = append(s.providers, c)
+	s.mu.Unlock()
+	return c
+}
+
+// removeProviders drops the counters of detached providers.
+func (s *statsCollector) removeProviders(gone []*providerCounters) {
+	if len(gone) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.providers[:0:0]
+	for _, c := range s.providers {
+		if !slices.Contains(gone, c) {
+			kept = append(kept, c)
+		}
+	}
+	s.providers = kept
+}
+
+func (s *statsCollector) providerStats() []ProviderStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var stats []ProviderStats
+	for _, c := range s.providers {
+		stats = append(stats, c.snapshot())
+	}
+	return stats
+}
+
+// countingCore wraps a provider's core and tallies its writes and failures.
+type countingCore struct {
+	zapcore.Core
+	counters *providerCounters
+}
+
+func (c *countingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &countingCore{Core: c.Core.With(fields), counters: c.counters}
+}
+
+func (c *countingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *countingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(ent, fields)
+	if err != nil {
+		c.counters.recordError(err)
+		return err
+	}
+	c.counters.writes.Add(1)
+	return nil
+}
+
+// Stats returns a snapshot of the logger's counters.
+func (l *Logger) Stats() Stats {
+	tel := l.telemetry
+	st := Stats{
+		Entries: make(map[Level]uint64),
+		Dropped: tel.drops.totals(),
+	}
+	for lvl := TraceLevel; lvl <= FatalLevel; lvl++ {
+		st.Entries[lvl] = tel.stats.levels[toZapLevel(lvl)-traceZapLevel].Load()
+	}
+	// DPanic and Panic entries count as errors, as in Entry.Level.
+	for _, lvl := range []zapcore.Level{zapcore.DPanicLevel, zapcore.PanicLevel} {
+		st.Entries[ErrorLevel] += tel.stats.levels[lvl-traceZapLevel].Load()
+	}
+	st.Providers = tel.stats.providerStats()
+	if err, at := tel.errs.last(); err != nil {
+		st.LastError = err.Error()
+		st.LastErrorAt = at
+	}
+	return st
+}
@@ -0,0 +1,74 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLogger_Stats(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(
+		WithWriterProvider(&buf, JSONEncoder),
+		WithWriterProvider(failingWriter{}, JSONEncoder),
+		WithErrorHandler(func(error) {}),
+		WithLevel(DebugLevel),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Debug("one")
+	logger.Info("two")
+	logger.Info("three")
+	logger.Error("four")
+
+	st := logger.Stats()
+	if st.Entries[DebugLevel] != 1 || st.Entries[InfoLevel] != 2 || st.Entries[ErrorLevel] != 1 || st.Entries[WarnLevel] != 0 {
+		t.Fatalf("unexpected level counts: %v", st.Entries)
+	}
+	if len(st.Providers) != 2 {
+		t.Fatalf("expected 2 providers, got %d", len(st.Providers))
+	}
+	if ok := st.Providers[0]; ok.Name != "writer" || ok.Writes != 4 || ok.Errors != 0 {
+		t.Fatalf("unexpected stats for healthy provider: %+v", ok)
+	}
+	bad := st.Providers[1]
+	if bad.Writes != 0 || bad.Errors != 4 || !strings.Contains(bad.LastError, "disk on fire") {
+		t.Fatalf("unexpected stats for failing provider: %+v", bad)
+	}
+	if !strings.Contains(st.LastError, "disk on fire") || st.LastErrorAt.IsZero() {
+		t.Fatalf("expected last error to be recorded, got %q at %v", st.LastError, st.LastErrorAt)
+	}
+}
+
+func TestLogger_StatsDuringAddProvider(t *testing.T) {
+	logger, err := NewLogger(WithWriterProvider(&concurrentBuffer{}, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			if err := logger.AddProvider(WithWriterProvider(&concurrentBuffer{}, JSONEncoder)); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			if n := len(logger.Stats().Providers); n != 21 {
+				t.Errorf("expected 21 providers, got %d", n)
+			}
+			return
+		default:
+			logger.Stats()
+		}
+	}
+}
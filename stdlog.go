@@ -0,0 +1,71 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"log"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                       Standard-Library Log Bridge                           */
+/* -------------------------------------------------------------------------- */
+
+// StdLogger returns a *log.Logger that logs each message to l at level, for
+// libraries that only accept the standard logger:
+//
+//	srv := &http.Server{ErrorLog: logger.With(golog.String("component", "http")).StdLogger(golog.WarnLevel)}
+//
+// The caller reported is the library's log call. Fields bound with With are
+// attached to every entry.
+func (l *Logger) StdLogger(level Level) *log.Logger {
+	// zap.NewStdLogAt only knows Debug through Fatal, so the bridge is built
+	// here. Between the library's call and stdWriter.Write sit log.Logger's
+	// exported method and its output helper; those two frames are skipped on
+	// top of the Logger method golog already skips.
+	z := l.zapLogger.WithOptions(zap.AddCallerSkip(2))
+	return log.New(&stdWriter{z: z, level: toZapLevel(level)}, "", 0)
+}
+
+// stdWriter receives one formatted message per Write from a *log.Logger and
+// logs it as a single entry, so multi-line messages stay together.
+type stdWriter struct {
+	z     *zap.Logger
+	level zapcore.Level
+}
+
+func (w *stdWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimSuffix(p, []byte{'\n'}))
+	if ce := w.z.Check(w.level, msg); ce != nil {
+		ce.Write()
+	}
+	return len(p), nil
+}
+
+// Writer returns an io.Writer that logs each line written to it as an entry
+// at level, for libraries that take an io.Writer for their diagnostics.
+// Empty lines are skipped and a final line without a newline is logged as
+// is, so each Write should carry whole lines.
+func (l *Logger) Writer(level Level) io.Writer {
+	return &levelWriter{z: l.zapLogger, level: toZapLevel(level)}
+}
+
+type levelWriter struct {
+	z     *zap.Logger
+	level zapcore.Level
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(p, []byte{'\n'}) {
+		line = bytes.TrimRight(line, "\r")
+		if len(line) == 0 {
+			continue
+		}
+		if ce := w.z.Check(w.level, string(line)); ce != nil {
+			ce.Write()
+		}
+	}
+	return len(p), nil
+}
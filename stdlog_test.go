@@ -0,0 +1,76 @@
+package golog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	std := logger.With(String("component", "http")).StdLogger(WarnLevel)
+	std.Printf("http: TLS handshake error from %s", "10.0.0.1")
+
+	out := buf.String()
+	for _, want := range []string{`"level":"warn"`, `"msg":"http: TLS handshake error from 10.0.0.1"`, `"component":"http"`, `stdlog_test.go:`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in output: %s", want, out)
+		}
+	}
+}
+
+func TestStdLoggerTrace(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithLevel(TraceLevel))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	std := logger.StdLogger(TraceLevel)
+	if std == nil {
+		t.Fatal("StdLogger(TraceLevel) returned nil")
+	}
+	std.Print("first\nsecond")
+
+	out := buf.String()
+	for _, want := range []string{`"level":"trace"`, `"msg":"first\nsecond"`, `stdlog_test.go:`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in output: %s", want, out)
+		}
+	}
+	if n := strings.Count(out, "\n"); n != 1 {
+		t.Errorf("expected 1 entry, got %d: %s", n, out)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	w := logger.Writer(ErrorLevel)
+	n, err := fmt.Fprint(w, "first line\r\n\nsecond line\n")
+	if err != nil || n != len("first line\r\n\nsecond line\n") {
+		t.Fatalf("Write returned %d, %v", n, err)
+	}
+	fmt.Fprint(logger.Writer(DebugLevel), "below level\n")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", lines)
+	}
+	if !strings.Contains(lines[0], `"level":"error"`) || !strings.Contains(lines[0], `"msg":"first line"`) || !strings.Contains(lines[1], `"msg":"second line"`) {
+		t.Errorf("unexpected entries: %q", lines)
+	}
+}
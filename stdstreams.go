@@ -0,0 +1,106 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                        Level-Based stdout/stderr Split                      */
+/* -------------------------------------------------------------------------- */
+
+// stderrAll is the stderrFrom of WithStdErrProvider: every level, custom
+// levels below Trace included.
+const stderrAll Level = math.MinInt8
+
+// WithStdErrLevel makes a stdout provider write entries at or above level to
+// stderr and the rest to stdout, for platforms that treat the two streams
+// differently (container runtimes, systemd):
+//
+//	golog.WithStdOutProvider(golog.JSONEncoder, golog.WithStdErrLevel(golog.WarnLevel))
+//
+// Both streams use the provider's encoder, a coloured one falling back to
+// its plain variant on a stream whose console cannot render it. Entries
+// written to different streams may appear out of order where the two are
+// merged again. Other providers fail NewLogger when given it.
+func WithStdErrLevel(level Level) ProviderOption {
+	return func(s *providerSettings) { s.stderrLevel = &level }
+}
+
+// applyStdErrLevel applies WithStdErrLevel to p.
+func applyStdErrLevel(p provider, level Level) provider {
+	sp, ok := p.(*stdOutProvider)
+	if !ok {
+		name := describeProvider(p).Name
+		return errProvider{name: name, err: fmt.Errorf("provider %s: WithStdErrLevel is only supported by stdout providers", name)}
+	}
+	sp.stderrFrom = &level
+	return sp
+}
+
+// newSplitCore returns a core writing entries below stderrFrom to stdout and
+// the others to stderr.
+func (p *stdOutProvider) newSplitCore(level zapcore.Level) (zapcore.Core, error) {
+	from := toZapLevel(*p.stderrFrom)
+	errEnc, err := p.streamEncoder(os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	stderr := zapcore.NewCore(errEnc, zapcore.AddSync(os.Stderr), max(level, from))
+	if from <= level {
+		return stderr, nil
+	}
+	outEnc, err := p.streamEncoder(os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	stdout := zapcore.NewCore(outEnc, zapcore.AddSync(os.Stdout), level)
+	return &splitCore{LevelEnabler: level, below: stdout, above: stderr, from: from}, nil
+}
+
+// splitCore writes entries below from to one core and the others to
+// another. Unlike a tee it routes in Write, which wrapping cores may call
+// without Check.
+type splitCore struct {
+	zapcore.LevelEnabler
+	below, above zapcore.Core
+	from         zapcore.Level
+}
+
+func (c *splitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &splitCore{LevelEnabler: c.LevelEnabler, below: c.below.With(fields), above: c.above.With(fields), from: c.from}
+}
+
+func (c *splitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *splitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level >= c.from {
+		return c.above.Write(ent, fields)
+	}
+	return c.below.Write(ent, fields)
+}
+
+func (c *splitCore) Sync() error {
+	return errors.Join(c.below.Sync(), c.above.Sync())
+}
+
+// streamsName names a stdout provider after the streams it writes to.
+func (p *stdOutProvider) streamsName() string {
+	switch {
+	case p.stderrFrom == nil:
+		return "stdout"
+	case *p.stderrFrom == stderrAll:
+		return "stderr"
+	default:
+		return "stdout+stderr"
+	}
+}
@@ -0,0 +1,95 @@
+package golog
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdStreams runs fn with os.Stdout and os.Stderr redirected and
+// returns what each received.
+func captureStdStreams(t *testing.T, fn func()) (stdout, stderr string) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = orig }()
+
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+	stdout = captureStdout(t, fn)
+	w.Close()
+	return stdout, <-done
+}
+
+func TestWithStdErrLevel_SplitsStreams(t *testing.T) {
+	stdout, stderr := captureStdStreams(t, func() {
+		logger, err := NewLogger(
+			WithLevel(DebugLevel),
+			WithStdOutProvider(JSONEncoder, WithStdErrLevel(WarnLevel)),
+		)
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		logger.Debug("debug")
+		logger.Info("info")
+		logger.Warn("warn")
+		logger.Error("error")
+		logger.Close()
+	})
+	if !strings.Contains(stdout, `"msg":"debug"`) || !strings.Contains(stdout, `"msg":"info"`) ||
+		strings.Contains(stdout, "warn") || strings.Contains(stdout, `"msg":"error"`) {
+		t.Errorf("stdout = %q", stdout)
+	}
+	if !strings.Contains(stderr, `"msg":"warn"`) || !strings.Contains(stderr, `"msg":"error"`) ||
+		strings.Contains(stderr, "info") || strings.Contains(stderr, `"msg":"debug"`) {
+		t.Errorf("stderr = %q", stderr)
+	}
+}
+
+func TestWithStdErrLevel_RespectsLoggerLevel(t *testing.T) {
+	stdout, stderr := captureStdStreams(t, func() {
+		logger, err := NewLogger(
+			WithLevel(ErrorLevel),
+			WithStdOutProvider(ConsoleEncoder, WithStdErrLevel(InfoLevel)),
+		)
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		logger.Info("hidden")
+		logger.Error("shown")
+		logger.Close()
+	})
+	if stdout != "" || strings.Contains(stderr, "hidden") || !strings.Contains(stderr, "shown") {
+		t.Errorf("stdout = %q, stderr = %q", stdout, stderr)
+	}
+}
+
+func TestWithStdErrProvider(t *testing.T) {
+	stdout, stderr := captureStdStreams(t, func() {
+		logger, err := NewLogger(WithLevel(TraceLevel), WithStdErrProvider(JSONEncoder))
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		logger.Trace("trace")
+		logger.Info("info")
+		logger.Close()
+	})
+	if stdout != "" || !strings.Contains(stderr, `"msg":"trace"`) || !strings.Contains(stderr, `"msg":"info"`) {
+		t.Errorf("stdout = %q, stderr = %q", stdout, stderr)
+	}
+
+	if _, err := NewLogger(WithWriterProvider(io.Discard, JSONEncoder, WithStdErrLevel(WarnLevel))); err == nil || !strings.Contains(err.Error(), "WithStdErrLevel") {
+		t.Errorf("expected an error for a writer provider, got %v", err)
+	}
+	if name := describeProvider(&stdOutProvider{stderrFrom: new(Level)}).Name; name != "stdout+stderr" {
+		t.Errorf("name = %q", name)
+	}
+}
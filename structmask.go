@@ -0,0 +1,242 @@
+package golog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                         Struct-Tag Field Masking                            */
+/* -------------------------------------------------------------------------- */
+
+// Structs logged with Any honour a log struct tag on their members, at any
+// depth, so DTOs can carry their own privacy rules:
+//
+//	type SignupRequest struct {
+//		Email    string `json:"email" log:"mask"` // logged as "***"
+//		Password string `json:"password" log:"omit"` // left out
+//		Plan     string `json:"plan"`
+//	}
+//
+// Members are otherwise named and skipped as encoding/json would, and types
+// implementing json.Marshaler keep their own encoding.
+
+// maskTypes caches whether a type contains log tags, by reflect.Type.
+var maskTypes sync.Map
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// maskedField returns the field encoding v with its log tags applied, and
+// false when v's type has none.
+func maskedField(key string, v interface{}) (zapcore.Field, bool) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !hasLogTags(rv.Type()) {
+		return zapcore.Field{}, false
+	}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return zap.Reflect(key, nil), true
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return zap.Array(key, maskedArray{rv}), true
+	case reflect.Map:
+		return zap.Object(key, maskedMap{rv}), true
+	default:
+		return zap.Object(key, maskedStruct{rv}), true
+	}
+}
+
+func hasLogTags(t reflect.Type) bool {
+	if v, ok := maskTypes.Load(t); ok {
+		return v.(bool)
+	}
+	has := findLogTags(t, map[reflect.Type]bool{})
+	maskTypes.Store(t, has)
+	return has
+}
+
+func findLogTags(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+			continue
+		}
+		break
+	}
+	if t.Kind() != reflect.Struct || t.Implements(jsonMarshalerType) || seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !embeddedStruct(f) {
+			continue
+		}
+		if f.Tag.Get("log") != "" || findLogTags(f.Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskable reports whether v needs masking rather than plain reflection,
+// dereferencing pointers; nil pointers yield an invalid value.
+func maskable(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		if v.Type().Implements(jsonMarshalerType) {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	if v.Type().Implements(jsonMarshalerType) || !hasLogTags(v.Type()) {
+		return v, false
+	}
+	return v, true
+}
+
+type maskedStruct struct{ v reflect.Value }
+
+func (m maskedStruct) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	t := m.v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := m.v.Field(i)
+		name, omitEmpty, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+		tag := f.Tag.Get("log")
+		if tag == "omit" || omitEmpty && fv.IsZero() {
+			continue
+		}
+		if tag == "mask" {
+			enc.AddString(name, redactedMask)
+			continue
+		}
+		if f.Anonymous && name == "" {
+			// Embedded structs are flattened, as by encoding/json.
+			if inner, _ := maskable(fv); inner.IsValid() && inner.Kind() == reflect.Struct {
+				if err := (maskedStruct{inner}).MarshalLogObject(enc); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := addMasked(enc, name, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonFieldName names f as encoding/json would. Embedded structs without a
+// json name return "" to be flattened.
+func jsonFieldName(f reflect.StructField) (name string, omitEmpty, ok bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	omitEmpty = strings.Contains(","+opts+",", ",omitempty,")
+	switch {
+	case !f.IsExported():
+		// Only the members of unexported embedded structs are encoded.
+		return "", omitEmpty, embeddedStruct(f)
+	case name != "":
+		return name, omitEmpty, true
+	case f.Anonymous && indirect(f.Type).Kind() == reflect.Struct:
+		return "", omitEmpty, true
+	}
+	return f.Name, omitEmpty, true
+}
+
+// embeddedStruct reports whether f is an embedded struct (not a pointer),
+// whose exported members encoding/json promotes even if f is unexported.
+func embeddedStruct(f reflect.StructField) bool {
+	return f.Anonymous && f.Type.Kind() == reflect.Struct
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+func addMasked(enc zapcore.ObjectEncoder, key string, v reflect.Value) error {
+	inner, ok := maskable(v)
+	if !ok {
+		if !inner.IsValid() {
+			return enc.AddReflected(key, nil)
+		}
+		return enc.AddReflected(key, inner.Interface())
+	}
+	switch inner.Kind() {
+	case reflect.Slice, reflect.Array:
+		return enc.AddArray(key, maskedArray{inner})
+	case reflect.Map:
+		return enc.AddObject(key, maskedMap{inner})
+	default:
+		return enc.AddObject(key, maskedStruct{inner})
+	}
+}
+
+type maskedArray struct{ v reflect.Value }
+
+func (m maskedArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for i := 0; i < m.v.Len(); i++ {
+		inner, ok := maskable(m.v.Index(i))
+		var err error
+		switch {
+		case !inner.IsValid():
+			err = enc.AppendReflected(nil)
+		case !ok:
+			err = enc.AppendReflected(inner.Interface())
+		case inner.Kind() == reflect.Slice || inner.Kind() == reflect.Array:
+			err = enc.AppendArray(maskedArray{inner})
+		case inner.Kind() == reflect.Map:
+			err = enc.AppendObject(maskedMap{inner})
+		default:
+			err = enc.AppendObject(maskedStruct{inner})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type maskedMap struct{ v reflect.Value }
+
+func (m maskedMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := m.v.MapKeys()
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = fmt.Sprint(k.Interface())
+	}
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return names[order[a]] < names[order[b]] })
+	for _, i := range order {
+		if err := addMasked(enc, names[i], m.v.MapIndex(keys[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package golog
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type maskAddress struct {
+	Street string `json:"street" log:"mask"`
+	City   string `json:"city"`
+}
+
+type maskAudit struct {
+	At time.Time `json:"at"`
+}
+
+type maskRequest struct {
+	maskAudit
+	Email    string                 `json:"email" log:"mask"`
+	Password string                 `json:"password" log:"omit"`
+	Plan     string                 `json:"plan"`
+	Note     string                 `json:"note,omitempty" log:"mask"`
+	Home     *maskAddress           `json:"home"`
+	Others   []maskAddress          `json:"others"`
+	ByName   map[string]maskAddress `json:"by_name"`
+	internal string
+}
+
+func TestAnyStructTags(t *testing.T) {
+	logger, buf := newBufferLogger(t, InfoLevel)
+	defer logger.Close()
+
+	req := maskRequest{
+		maskAudit: maskAudit{At: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Email:     "a@example.com",
+		Password:  "hunter2",
+		Plan:      "pro",
+		Home:      &maskAddress{Street: "1 Main St", City: "Springfield"},
+		Others:    []maskAddress{{Street: "2 Side St", City: "Shelbyville"}},
+		ByName:    map[string]maskAddress{"work": {Street: "3 Office Rd", City: "Capital"}},
+		internal:  "x",
+	}
+	logger.Info("signup", Any("req", req), Any("ptr", &req), Any("plain", maskAudit{}))
+
+	out := buf.String()
+	for _, leak := range []string{"a@example.com", "hunter2", "password", "Main St", "Side St", "Office Rd", "note"} {
+		if strings.Contains(out, leak) {
+			t.Errorf("%q leaked: %s", leak, out)
+		}
+	}
+	want := `{"at":"2024-01-02T00:00:00Z","email":"***","plan":"pro","home":{"street":"***","city":"Springfield"},` +
+		`"others":[{"street":"***","city":"Shelbyville"}],"by_name":{"work":{"street":"***","city":"Capital"}}}`
+	if !strings.Contains(out, `"req":`+want) || !strings.Contains(out, `"ptr":`+want) {
+		t.Errorf("unexpected output: %s", out)
+	}
+	if !strings.Contains(out, `"plain":{"at":"0001-01-01T00:00:00Z"}`) {
+		t.Errorf("untagged structs should encode as before: %s", out)
+	}
+}
@@ -0,0 +1,465 @@
+package golog
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                                Syslog Provider                              */
+/* -------------------------------------------------------------------------- */
+
+// SyslogFormat is the message format of the syslog provider.
+type SyslogFormat string
+
+const (
+	// SyslogRFC3164 is the classic BSD format every daemon accepts:
+	//
+	//	<14>Oct 16 09:30:00 host app[42]: served request method=GET status=200
+	SyslogRFC3164 SyslogFormat = "rfc3164"
+	// SyslogRFC5424 carries fields as structured data and a full timestamp:
+	//
+	//	<14>1 2026-10-16T09:30:00.000000Z host app 42 - [golog@32473 method="GET" status="200"] served request
+	SyslogRFC5424 SyslogFormat = "rfc5424"
+)
+
+// SyslogFacility is the syslog facility of every message. The kernel
+// facility (0) is reserved for the kernel, so the zero value means
+// SyslogUser.
+type SyslogFacility int
+
+const (
+	SyslogUser     SyslogFacility = 1
+	SyslogMail     SyslogFacility = 2
+	SyslogDaemon   SyslogFacility = 3
+	SyslogAuth     SyslogFacility = 4
+	SyslogSyslog   SyslogFacility = 5
+	SyslogCron     SyslogFacility = 9
+	SyslogAuthPriv SyslogFacility = 10
+	SyslogLocal0   SyslogFacility = 16
+	SyslogLocal1   SyslogFacility = 17
+	SyslogLocal2   SyslogFacility = 18
+	SyslogLocal3   SyslogFacility = 19
+	SyslogLocal4   SyslogFacility = 20
+	SyslogLocal5   SyslogFacility = 21
+	SyslogLocal6   SyslogFacility = 22
+	SyslogLocal7   SyslogFacility = 23
+)
+
+var syslogFacilityNames = map[string]SyslogFacility{
+	"user": SyslogUser, "mail": SyslogMail, "daemon": SyslogDaemon,
+	"auth": SyslogAuth, "syslog": SyslogSyslog, "cron": SyslogCron,
+	"authpriv": SyslogAuthPriv, "local0": SyslogLocal0, "local1": SyslogLocal1,
+	"local2": SyslogLocal2, "local3": SyslogLocal3, "local4": SyslogLocal4,
+	"local5": SyslogLocal5, "local6": SyslogLocal6, "local7": SyslogLocal7,
+}
+
+// parseSyslogFacility accepts the facility names of syslog.conf.
+func parseSyslogFacility(name string) (SyslogFacility, error) {
+	if f, ok := syslogFacilityNames[strings.ToLower(name)]; ok {
+		return f, nil
+	}
+	return 0, fmt.Errorf("unknown syslog facility %q", name)
+}
+
+// SyslogConfig configures WithSyslogProvider. Zero values fall back to the
+// defaults noted on each field.
+type SyslogConfig struct {
+	// Format defaults to SyslogRFC3164.
+	Format SyslogFormat
+	// Facility defaults to SyslogUser.
+	Facility SyslogFacility
+	// AppName is the tag (RFC 3164) or APP-NAME (RFC 5424); defaults to the
+	// program name.
+	AppName string
+	// Hostname defaults to os.Hostname. It is omitted from RFC 3164
+	// messages to the local daemon, which adds its own.
+	Hostname string
+	// StructuredDataID is the SD-ID of the RFC 5424 element holding the
+	// fields (default "golog@32473", 32473 being the enterprise number
+	// reserved for documentation).
+	StructuredDataID string
+	// TLS enables TLS on a "tcp" connection (RFC 5425).
+	TLS *TLSConfig
+	// Timeout bounds connecting and each write (default 5s).
+	Timeout time.Duration
+}
+
+// WithSyslogProvider sends entries to a syslog daemon. network is "udp",
+// "tcp", "unix" or "unixgram", as for net.Dial; with network and address
+// both empty the local daemon is used (/dev/log, /var/run/syslog or
+// /var/run/log):
+//
+//	golog.WithSyslogProvider("tcp", "logs.example.com:6514", golog.SyslogConfig{
+//		Format:   golog.SyslogRFC5424,
+//		Facility: golog.SyslogLocal0,
+//		TLS:      &golog.TLSConfig{},
+//	})
+//
+// Levels map to severities as Trace and Debug to debug (7), Info to
+// informational (6), Warn to warning (4), Error to error (3), DPanic to
+// critical (2), Panic to alert (1) and Fatal to emergency (0). The message
+// is the entry's message followed by its fields, as key=value pairs in RFC
+// 3164 and as structured data in RFC 5424; with WithProviderEncoder it is
+// the encoded entry instead, e.g. JSON for collectors that parse it. On
+// stream connections messages are framed by octet counting (RFC 6587) in
+// RFC 5424 and by newlines in RFC 3164. A failed write reconnects once
+// before returning the error.
+func WithSyslogProvider(network, address string, cfg SyslogConfig, options ...ProviderOption) LoggerOption {
+	return func(c *loggerConfig) {
+		name := "syslog"
+		if network != "" {
+			name += ":" + network + ":" + address
+		}
+		c.providers = append(c.providers, applyProviderOptions(&syslogProvider{
+			providerBase: providerBase{name: name, encoders: anyEncoder},
+			network:      network,
+			address:      address,
+			cfg:          cfg,
+		}, options))
+	}
+}
+
+func (c SyslogConfig) withDefaults() SyslogConfig {
+	if c.Format == "" {
+		c.Format = SyslogRFC3164
+	}
+	if c.Facility == 0 {
+		c.Facility = SyslogUser
+	}
+	if c.AppName == "" {
+		c.AppName = filepath.Base(os.Args[0])
+	}
+	if c.Hostname == "" {
+		c.Hostname, _ = os.Hostname()
+	}
+	if c.StructuredDataID == "" {
+		c.StructuredDataID = "golog@32473"
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 5 * time.Second
+	}
+	return c
+}
+
+type syslogProvider struct {
+	providerBase
+	network, address string
+	cfg              SyslogConfig
+
+	conn *syslogConn
+}
+
+func (p *syslogProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	cfg := p.cfg.withDefaults()
+	if cfg.Format != SyslogRFC3164 && cfg.Format != SyslogRFC5424 {
+		return nil, fmt.Errorf("syslog provider: unknown format %q", cfg.Format)
+	}
+	if cfg.Facility < 0 || cfg.Facility > SyslogLocal7 {
+		return nil, fmt.Errorf("syslog provider: facility %d out of range", cfg.Facility)
+	}
+	tlsCfg, err := cfg.TLS.build()
+	if err != nil {
+		return nil, fmt.Errorf("syslog provider: %w", err)
+	}
+	conn := &syslogConn{network: p.network, address: p.address, tls: tlsCfg, timeout: cfg.Timeout}
+	switch p.network {
+	case "":
+		if p.address != "" {
+			return nil, errors.New("syslog provider: address requires a network")
+		}
+	case "tcp", "tcp4", "tcp6":
+		conn.stream = true
+		conn.address = withDefaultPort(p.address, tlsCfg != nil)
+	case "udp", "udp4", "udp6":
+		conn.address = withDefaultPort(p.address, false)
+	case "unix":
+		conn.stream = true
+	case "unixgram":
+	default:
+		return nil, fmt.Errorf("syslog provider: unsupported network %q", p.network)
+	}
+	if tlsCfg != nil && !conn.stream {
+		return nil, fmt.Errorf("syslog provider: TLS needs a tcp connection, not %q", p.network)
+	}
+	conn.octetCounting = conn.stream && cfg.Format == SyslogRFC5424
+
+	msg, err := newMessageEncoder(p.tel, p.encoderType, p.encoderConfig)
+	if err != nil {
+		return nil, err
+	}
+	core := &syslogCore{LevelEnabler: level, cfg: cfg, local: p.network == "", conn: conn, msg: msg}
+	// Fail fast without a local daemon; a remote one that is down is
+	// redialled on every write.
+	if err := conn.dial(); err != nil && p.network == "" {
+		return nil, fmt.Errorf("syslog provider: %w", err)
+	}
+	p.conn = conn
+	return core, nil
+}
+
+// withDefaultPort adds the syslog port, 514 or 6514 for TLS, to an address
+// without one.
+func withDefaultPort(address string, useTLS bool) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+	port := "514"
+	if useTLS {
+		port = "6514"
+	}
+	return net.JoinHostPort(strings.Trim(address, "[]"), port)
+}
+
+func (p *syslogProvider) close() error {
+	if p.conn == nil {
+		return nil
+	}
+	return p.conn.close()
+}
+
+func (p *syslogProvider) describe() ProviderInfo {
+	return ProviderInfo{Name: p.name, Encoder: p.encoderType}
+}
+
+/* -------------------------------------------------------------------------- */
+/*                              Message Formatting                             */
+/* -------------------------------------------------------------------------- */
+
+type syslogCore struct {
+	zapcore.LevelEnabler
+	cfg   SyslogConfig
+	local bool
+	conn  *syslogConn
+	msg   messageEncoder
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.msg = c.msg.with(fields)
+	return &clone
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf := encoderPool.Get()
+	defer buf.Free()
+	if err := c.format(buf, ent, fields); err != nil {
+		return err
+	}
+	return c.conn.write(buf.Bytes())
+}
+
+func (c *syslogCore) Sync() error { return nil }
+
+func (c *syslogCore) format(buf *buffer.Buffer, ent zapcore.Entry, fields []zapcore.Field) error {
+	buf.AppendByte('<')
+	buf.AppendInt(int64(c.cfg.Facility)*8 + int64(syslogSeverity(ent.Level)))
+	buf.AppendByte('>')
+
+	if c.cfg.Format == SyslogRFC3164 {
+		buf.AppendString(ent.Time.Format(time.Stamp))
+		buf.AppendByte(' ')
+		if !c.local {
+			buf.AppendString(syslogHeaderField(c.cfg.Hostname, 255))
+			buf.AppendByte(' ')
+		}
+		buf.AppendString(syslogHeaderField(c.cfg.AppName, 32))
+		buf.AppendByte('[')
+		buf.AppendInt(int64(os.Getpid()))
+		buf.AppendString("]: ")
+		return c.msg.appendEntry(buf, ent, fields)
+	}
+
+	buf.AppendString("1 ")
+	buf.AppendString(ent.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+	for _, field := range []string{
+		syslogHeaderField(c.cfg.Hostname, 255),
+		syslogHeaderField(c.cfg.AppName, 48),
+		strconv.Itoa(os.Getpid()),
+		syslogHeaderField(ent.LoggerName, 32),
+	} {
+		buf.AppendByte(' ')
+		buf.AppendString(field)
+	}
+	buf.AppendByte(' ')
+	if c.msg.enc != nil {
+		buf.AppendString("- ")
+		return c.msg.appendEntry(buf, ent, fields)
+	}
+	tree := c.msg.tree.fieldsWith(fields)
+	if len(tree.keys) == 0 {
+		buf.AppendByte('-')
+	} else {
+		buf.AppendByte('[')
+		buf.AppendString(c.cfg.StructuredDataID)
+		tree.flatten("", func(key string, val interface{}) {
+			buf.AppendByte(' ')
+			buf.AppendString(syslogParamName(key))
+			buf.AppendString(`="`)
+			buf.AppendString(sdValueEscaper.Replace(columnText(val)))
+			buf.AppendByte('"')
+		})
+		buf.AppendByte(']')
+	}
+	buf.AppendByte(' ')
+	buf.AppendString(ent.Message)
+	appendStack(buf, ent)
+	return nil
+}
+
+// syslogSeverity maps a level to its RFC 5424 severity.
+func syslogSeverity(lvl zapcore.Level) int {
+	switch {
+	case lvl <= zapcore.DebugLevel:
+		return 7
+	case lvl == zapcore.InfoLevel:
+		return 6
+	case lvl == zapcore.WarnLevel:
+		return 4
+	case lvl == zapcore.ErrorLevel:
+		return 3
+	case lvl == zapcore.DPanicLevel:
+		return 2
+	case lvl == zapcore.PanicLevel:
+		return 1
+	}
+	return 0
+}
+
+// syslogHeaderField makes s a valid header field: printable ASCII without
+// spaces, at most max bytes, "-" when empty.
+func syslogHeaderField(s string, max int) string {
+	if s == "" {
+		return "-"
+	}
+	b := []byte(s)
+	if len(b) > max {
+		b = b[:max]
+	}
+	for i, c := range b {
+		if c < '!' || c > '~' {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// syslogParamName makes key a valid SD-NAME, which also excludes '=', ']'
+// and '"' and is at most 32 bytes.
+func syslogParamName(key string) string {
+	b := []byte(syslogHeaderField(key, 32))
+	for i, c := range b {
+		if c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+var sdValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+/* -------------------------------------------------------------------------- */
+/*                                  Connection                                 */
+/* -------------------------------------------------------------------------- */
+
+// syslogLocalPaths are the sockets of the local daemon on Linux, macOS and
+// the BSDs.
+var syslogLocalPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// syslogConn is a connection to the daemon, re-established after errors.
+type syslogConn struct {
+	network, address string
+	tls              *tls.Config
+	timeout          time.Duration
+	stream           bool
+	octetCounting    bool
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// dial connects if not connected. The caller holds mu, or has exclusive
+// access during setup.
+func (c *syslogConn) dial() error {
+	if c.conn != nil {
+		return nil
+	}
+	if c.network == "" {
+		for _, path := range syslogLocalPaths {
+			for _, network := range []string{"unixgram", "unix"} {
+				if conn, err := net.DialTimeout(network, path, c.timeout); err == nil {
+					c.conn, c.stream = conn, network == "unix"
+					return nil
+				}
+			}
+		}
+		return errors.New("no local syslog daemon found")
+	}
+	dialer := &net.Dialer{Timeout: c.timeout}
+	var err error
+	if c.tls != nil {
+		c.conn, err = tls.DialWithDialer(dialer, c.network, c.address, c.tls)
+	} else {
+		c.conn, err = dialer.Dial(c.network, c.address)
+	}
+	return err
+}
+
+func (c *syslogConn) write(msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if err = c.dial(); err != nil {
+			continue
+		}
+		c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+		if _, err = c.conn.Write(c.frame(msg)); err == nil {
+			return nil
+		}
+		c.conn.Close()
+		c.conn = nil
+	}
+	return fmt.Errorf("syslog: %w", err)
+}
+
+// frame delimits msg for stream connections; datagrams need no framing.
+func (c *syslogConn) frame(msg []byte) []byte {
+	switch {
+	case c.octetCounting:
+		frame := append(strconv.AppendInt(make([]byte, 0, len(msg)+8), int64(len(msg)), 10), ' ')
+		return append(frame, msg...)
+	case c.stream:
+		return append(append(make([]byte, 0, len(msg)+1), msg...), '\n')
+	}
+	return msg
+}
+
+func (c *syslogConn) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
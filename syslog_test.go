@@ -0,0 +1,213 @@
+package golog
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// acceptOne returns a channel receiving the first connection to ln, read in
+// full by read.
+func acceptOne(t *testing.T, ln net.Listener, read func(*bufio.Reader) []string) <-chan []string {
+	t.Helper()
+	got := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			got <- nil
+			return
+		}
+		defer conn.Close()
+		got <- read(bufio.NewReader(conn))
+	}()
+	return got
+}
+
+// readOctetCounted reads RFC 6587 octet-counted frames until EOF.
+func readOctetCounted(r *bufio.Reader) []string {
+	var frames []string
+	for {
+		n, err := r.ReadString(' ')
+		if err != nil {
+			return frames
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(n))
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return frames
+		}
+		frames = append(frames, string(frame))
+	}
+}
+
+func readLines(r *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return lines
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+}
+
+func TestSyslogProvider_RFC3164OverUDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	at := time.Date(2026, 10, 6, 9, 30, 0, 0, time.UTC)
+	logger, err := NewLogger(
+		WithSyslogProvider("udp", pc.LocalAddr().String(), SyslogConfig{
+			Facility: SyslogLocal0,
+			AppName:  "app",
+			Hostname: "host",
+		}),
+		WithZapOptions(zap.WithClock(fixedClock{at})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	logger.Named("api").Info("served request", String("method", "GET"), Int("status", 200), Dict("req", String("path", "/a b")))
+
+	buf := make([]byte, 4096)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<134>Oct  6 09:30:00 host app[" + strconv.Itoa(os.Getpid()) + `]: api: served request method=GET status=200 req.path="/a b"`
+	if got := string(buf[:n]); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestSyslogProvider_RFC5424OverTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	got := acceptOne(t, ln, readOctetCounted)
+
+	at := time.Date(2026, 10, 16, 9, 30, 0, 123456000, time.UTC)
+	logger, err := NewLogger(
+		WithSyslogProvider("tcp", ln.Addr().String(), SyslogConfig{
+			Format:   SyslogRFC5424,
+			AppName:  "app",
+			Hostname: "my host",
+		}),
+		WithZapOptions(zap.WithClock(fixedClock{at})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Named("db").Warn("slow query", String("sql", `select "x" [1]`), Bool("cached", false))
+	logger.Info("no fields")
+	logger.Close()
+
+	pid := strconv.Itoa(os.Getpid())
+	want := []string{
+		`<12>1 2026-10-16T09:30:00.123456Z my_host app ` + pid + ` db [golog@32473 sql="select \"x\" [1\]" cached="false"] slow query`,
+		`<14>1 2026-10-16T09:30:00.123456Z my_host app ` + pid + ` - - no fields`,
+	}
+	frames := <-got
+	if strings.Join(frames, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got  %q\nwant %q", frames, want)
+	}
+}
+
+func TestSyslogProvider_EncoderOverTLS(t *testing.T) {
+	// Borrow httptest's certificate, valid for 127.0.0.1.
+	srv := httptest.NewTLSServer(nil)
+	cert, caPEM := srv.TLS.Certificates[0], pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	srv.Close()
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	got := acceptOne(t, ln, readLines)
+
+	logger, err := NewLogger(WithSyslogProvider("tcp", ln.Addr().String(), SyslogConfig{
+		AppName:  "app",
+		Hostname: "host",
+		TLS:      &TLSConfig{CAPEM: caPEM},
+	}, WithProviderEncoder(JSONEncoder)))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Error("failed", Int("attempt", 3))
+	logger.Close()
+
+	lines := <-got
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines: %q", len(lines), lines)
+	}
+	prefix := "app[" + strconv.Itoa(os.Getpid()) + "]: {"
+	if !strings.HasPrefix(lines[0], "<11>") || !strings.Contains(lines[0], prefix) || !strings.Contains(lines[0], `"attempt":3`) {
+		t.Errorf("unexpected message %q", lines[0])
+	}
+}
+
+func TestSyslogProvider_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		network, address string
+		cfg              SyslogConfig
+		want             string
+	}{
+		{"", "localhost:514", SyslogConfig{}, "requires a network"},
+		{"sctp", "localhost", SyslogConfig{}, "unsupported network"},
+		{"udp", "localhost", SyslogConfig{TLS: &TLSConfig{}}, "TLS needs a tcp connection"},
+		{"udp", "localhost", SyslogConfig{Format: "rfc9999"}, "unknown format"},
+		{"udp", "localhost", SyslogConfig{Facility: 24}, "out of range"},
+	} {
+		_, err := NewLogger(WithSyslogProvider(tc.network, tc.address, tc.cfg))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s %s: expected error containing %q, got %v", tc.network, tc.address, tc.want, err)
+		}
+	}
+	if _, err := NewLogger(WithNamedProvider("syslog", map[string]any{"network": "udp", "address": "localhost", "facility": "local9"})); err == nil || !strings.Contains(err.Error(), "facility") {
+		t.Errorf("expected facility error, got %v", err)
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	for lvl, want := range map[zapcore.Level]int{
+		traceZapLevel:        7,
+		zapcore.DebugLevel:   7,
+		zapcore.InfoLevel:    6,
+		zapcore.WarnLevel:    4,
+		zapcore.ErrorLevel:   3,
+		zapcore.DPanicLevel:  2,
+		zapcore.PanicLevel:   1,
+		zapcore.FatalLevel:   0,
+		traceZapLevel - 10:   7,
+		zapcore.InvalidLevel: 0,
+	} {
+		if got := syslogSeverity(lvl); got != want {
+			t.Errorf("syslogSeverity(%v) = %d, want %d", lvl, got, want)
+		}
+	}
+	if got := withDefaultPort("::1", true); got != "[::1]:6514" {
+		t.Errorf("withDefaultPort = %s", got)
+	}
+	if got := syslogParamName(`a=b"c]d e` + strings.Repeat("x", 40)); got != "a_b_c_d_e"+strings.Repeat("x", 23) {
+		t.Errorf("syslogParamName = %s", got)
+	}
+}
@@ -0,0 +1,271 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                      Tail API for Recent and On-Disk Logs                   */
+/* -------------------------------------------------------------------------- */
+
+// TailOptions selects the sources and filters for Logger.Tail.
+type TailOptions struct {
+	// Ring streams entries logged through this Logger, starting with those
+	// held in the ring buffer. Requires WithRingBuffer.
+	Ring bool
+	// File follows a JSON log file written by golog – typically the file
+	// provider's current file – across rotations.
+	File string
+	// FromStart replays the existing contents of File; by default only
+	// lines appended after Tail is called are streamed.
+	FromStart bool
+	// MinLevel, when set, drops entries below it.
+	MinLevel *Level
+	// Match, when set, drops entries it does not accept.
+	Match Matcher
+	// Buffer is the channel capacity (default 256). Live ring entries are
+	// skipped rather than blocking the logger while it is full.
+	Buffer int
+	// PollInterval is how often File is checked for new data (default
+	// 250ms).
+	PollInterval time.Duration
+}
+
+// Tail streams entries matching opts until ctx is cancelled or the logger is
+// closed, at which point the channel is closed. It is meant for "live logs"
+// pages and websockets that would otherwise shell out to tail.
+func (l *Logger) Tail(ctx context.Context, opts TailOptions) (<-chan Entry, error) {
+	if !opts.Ring && opts.File == "" {
+		return nil, errors.New("tail: no source selected")
+	}
+	if opts.Ring && l.ring == nil {
+		return nil, errors.New("tail: ring source requires WithRingBuffer")
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 256
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 250 * time.Millisecond
+	}
+
+	var follower *fileFollower
+	if opts.File != "" {
+		var err error
+		if follower, err = newFileFollower(opts.File, opts.FromStart); err != nil {
+			return nil, fmt.Errorf("tail: %w", err)
+		}
+	}
+
+	out := make(chan Entry, opts.Buffer)
+	keep := func(e Entry) bool {
+		return (opts.MinLevel == nil || e.Level >= *opts.MinLevel) && (opts.Match == nil || opts.Match(e))
+	}
+	// send delivers e, reporting false once the tail should stop.
+	send := func(e Entry) bool {
+		if !keep(e) {
+			return true
+		}
+		select {
+		case out <- e:
+			return true
+		case <-ctx.Done():
+		case <-l.stop:
+		}
+		return false
+	}
+
+	var wg sync.WaitGroup
+	if opts.Ring {
+		history, live, cancel := l.ring.subscribe(opts.Buffer)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer cancel()
+			for _, e := range history {
+				if !send(e) {
+					return
+				}
+			}
+			for {
+				select {
+				case e := <-live:
+					if !send(e) {
+						return
+					}
+				case <-ctx.Done():
+					return
+				case <-l.stop:
+					return
+				}
+			}
+		}()
+	}
+	if follower != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer follower.close()
+			ticker := time.NewTicker(opts.PollInterval)
+			defer ticker.Stop()
+			for {
+				entries, err := follower.poll()
+				if err != nil {
+					l.telemetry.errs.report(fmt.Errorf("tail %s: %w", opts.File, err))
+				}
+				for _, e := range entries {
+					if !send(e) {
+						return
+					}
+				}
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				case <-l.stop:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out, nil
+}
+
+// fileFollower reads complete lines appended to a file, reopening it when it
+// is rotated (replaced) or truncated.
+type fileFollower struct {
+	path    string
+	f       *os.File
+	info    os.FileInfo
+	offset  int64
+	partial []byte
+}
+
+func newFileFollower(path string, fromStart bool) (*fileFollower, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	ff := &fileFollower{path: path, f: f, info: info}
+	if !fromStart {
+		ff.offset = info.Size()
+	}
+	return ff, nil
+}
+
+// poll returns the entries appended since the last call.
+func (ff *fileFollower) poll() ([]Entry, error) {
+	if info, err := os.Stat(ff.path); err == nil {
+		switch {
+		case !os.SameFile(info, ff.info):
+			// Rotated: drain what is left of the old file, then switch.
+			entries, err := ff.read()
+			if reopenErr := ff.reopen(info); reopenErr != nil {
+				return entries, errors.Join(err, reopenErr)
+			}
+			more, err2 := ff.read()
+			return append(entries, more...), errors.Join(err, err2)
+		case info.Size() < ff.offset:
+			ff.offset, ff.partial = 0, nil
+		}
+	}
+	return ff.read()
+}
+
+func (ff *fileFollower) reopen(info os.FileInfo) error {
+	f, err := os.Open(ff.path)
+	if err != nil {
+		return err
+	}
+	ff.f.Close()
+	ff.f, ff.info, ff.offset, ff.partial = f, info, 0, nil
+	return nil
+}
+
+func (ff *fileFollower) read() ([]Entry, error) {
+	if _, err := ff.f.Seek(ff.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(ff.f)
+	var entries []Entry
+	for {
+		line, err := r.ReadBytes('\n')
+		ff.offset += int64(len(line))
+		if err != nil {
+			// Keep an incomplete trailing line until the writer finishes it.
+			ff.partial = append(ff.partial, line...)
+			if err == io.EOF {
+				return entries, nil
+			}
+			return entries, err
+		}
+		if len(ff.partial) > 0 {
+			line = append(ff.partial, line...)
+			ff.partial = nil
+		}
+		if e, ok := parseJSONEntry(line); ok {
+			entries = append(entries, e)
+		}
+	}
+}
+
+func (ff *fileFollower) close() { ff.f.Close() }
+
+// parseJSONEntry decodes a line produced by golog's JSON encoder.
+func parseJSONEntry(line []byte) (Entry, bool) {
+	var raw map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return Entry{}, false
+	}
+	var e Entry
+	if s, ok := raw["level"].(string); ok {
+		var zl zapcore.Level
+		if zl.UnmarshalText([]byte(s)) == nil {
+			e.Level = fromZapLevel(zl)
+		} else if lvl, err := ParseLevel(s); err == nil {
+			e.Level = lvl
+		}
+	}
+	if n, ok := raw["ts"].(json.Number); ok {
+		if f, err := n.Float64(); err == nil {
+			sec, frac := math.Modf(f)
+			e.Time = time.Unix(int64(sec), int64(frac*1e9))
+		}
+	}
+	e.Message, _ = raw["msg"].(string)
+	e.Caller, _ = raw["caller"].(string)
+	e.Logger, _ = raw["logger"].(string)
+	e.Stack, _ = raw["stacktrace"].(string)
+	for _, k := range []string{"level", "ts", "msg", "caller", "logger", "stacktrace"} {
+		delete(raw, k)
+	}
+	if len(raw) > 0 {
+		e.Fields = make(map[string]interface{}, len(raw))
+		for k, v := range raw {
+			e.Fields[k] = restoreJSONValue(v)
+		}
+	}
+	return e, true
+}
@@ -0,0 +1,134 @@
+package golog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// next receives one entry or fails the test after a timeout.
+func next(t *testing.T, ch <-chan Entry) Entry {
+	t.Helper()
+	select {
+	case e, ok := <-ch:
+		if !ok {
+			t.Fatal("tail channel closed early")
+		}
+		return e
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for entry")
+	}
+	return Entry{}
+}
+
+func TestTail_Ring(t *testing.T) {
+	logger, err := NewLogger(WithWriterProvider(&concurrentBuffer{}, JSONEncoder), WithRingBuffer(10))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("before", String("component", "api"))
+	logger.Info("ignored", String("component", "db"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := logger.Tail(ctx, TailOptions{Ring: true, Match: MatchField("component", "api")})
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+	logger.Info("after", String("component", "api"))
+
+	if e := next(t, ch); e.Message != "before" {
+		t.Errorf("expected buffered entry first, got %q", e.Message)
+	}
+	if e := next(t, ch); e.Message != "after" {
+		t.Errorf("expected live entry, got %q", e.Message)
+	}
+	cancel()
+	for range ch {
+	}
+}
+
+func TestTail_FileFollowsRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := NewLogger(WithFileProvider(path, 10, 1, 1, false))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	logger.Info("old")
+	logger.Sync()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	warn := WarnLevel
+	ch, err := logger.Tail(ctx, TailOptions{File: path, MinLevel: &warn, PollInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+
+	logger.Info("too quiet")
+	logger.Warn("appended", Int("n", 1))
+	e := next(t, ch)
+	if e.Message != "appended" || e.Level != WarnLevel || e.Fields["n"] != int64(1) || e.Time.IsZero() {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+
+	// Simulate external rotation: move the file aside and start a new one.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"level":"error","ts":1700000000,"msg":"rotated"}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if e := next(t, ch); e.Message != "rotated" {
+		t.Errorf("expected entry from new file, got %+v", e)
+	}
+
+	logger.Close()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("expected channel to close with the logger")
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("channel not closed after logger Close")
+	}
+}
+
+func TestTail_NoMinLevelKeepsTrace(t *testing.T) {
+	logger, err := NewLogger(WithWriterProvider(&concurrentBuffer{}, JSONEncoder), WithRingBuffer(10), WithLevel(TraceLevel))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := logger.Tail(ctx, TailOptions{Ring: true})
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+	logger.Trace("below debug")
+	if e := next(t, ch); e.Message != "below debug" || e.Level != TraceLevel {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+}
+
+func TestTail_Errors(t *testing.T) {
+	logger, err := NewLogger(WithWriterProvider(&concurrentBuffer{}, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+	if _, err := logger.Tail(context.Background(), TailOptions{}); err == nil {
+		t.Error("expected error without sources")
+	}
+	if _, err := logger.Tail(context.Background(), TailOptions{Ring: true}); err == nil {
+		t.Error("expected error without ring buffer")
+	}
+	if _, err := logger.Tail(context.Background(), TailOptions{File: filepath.Join(t.TempDir(), "missing.log")}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
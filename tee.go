@@ -0,0 +1,285 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                      Composing Loggers & Late Providers                     */
+/* -------------------------------------------------------------------------- */
+
+// AddProvider attaches the providers registered by opt (WithFileProvider,
+// WithHTTPProvider, WithRetry(…), …) to a running logger, so a plugin can add
+// its own sink to the host's logger. They receive entries logged from then
+// on, through the same level gate, filters, transforms and enrichers as the
+// providers the logger was built with, and are closed with it. Settings in
+// opt other than providers are ignored; routes are rejected.
+func (l *Logger) AddProvider(opt LoggerOption) error {
+	if l.root != nil {
+		return l.root.AddProvider(opt)
+	}
+	e, err := l.added.build("", opt)
+	if err != nil {
+		return fmt.Errorf("AddProvider: %w", err)
+	}
+	if _, err := l.added.replace(nil, []addedEntry{e}); err != nil {
+		l.added.discard(e)
+		return fmt.Errorf("AddProvider: %w", err)
+	}
+	return nil
+}
+
+// providerInfos describes the providers the logger was built with followed
+// by those attached with AddProvider.
+func (l *Logger) providerInfos() []ProviderInfo {
+	return append(append([]ProviderInfo(nil), l.providers...), l.added.infoList()...)
+}
+
+// Tee returns a logger writing every entry to all of loggers, each through
+// its own providers, level, filters and sampling. The result can be extended
+// further with AddProvider. It does not take ownership of loggers: closing it
+// flushes them but they must still be closed on their own.
+//
+// Tee's level, used for its own added providers, is the most verbose of the
+// loggers' levels. Names and fields given to the loggers' children are not
+// carried over; derive children from the Tee instead.
+func Tee(loggers ...*Logger) *Logger {
+	level := FatalLevel
+	enriched := false
+	var (
+		cores     []zapcore.Core
+		providers []ProviderInfo
+	)
+	for _, l := range loggers {
+		cores = append(cores, l.zapLogger.Core())
+		providers = append(providers, l.providerInfos()...)
+		if lvl := l.Level(); lvl < level {
+			level = lvl
+		}
+		enriched = enriched || l.enriched
+	}
+	if len(loggers) == 0 {
+		level = InfoLevel
+	}
+
+	return newComposedLogger(cores, level, providers, enriched)
+}
+
+// newComposedLogger returns a logger writing to cores, which carry their own
+// level gates, plus the providers attached later with AddProvider.
+func newComposedLogger(cores []zapcore.Core, level Level, providers []ProviderInfo, enriched bool) *Logger {
+	tel := &telemetry{
+		errs:  newErrorSink(nil, nil),
+		drops: newDropCounter(nil),
+		stats: &statsCollector{},
+	}
+	levels := newLevelTable(toZapLevel(level))
+	added := &addedProviders{tel: tel, levels: levels}
+	cores = append(cores, &extensionCore{added: added})
+
+	onFatal := &fatalHook{timeout: defaultFatalFlushTimeout, exit: os.Exit}
+	zapLogger := zap.New(zapcore.NewTee(cores...),
+		zap.ErrorOutput(tel.errs), zap.Hooks(tel.stats.countEntry), zap.WithFatalHook(onFatal),
+		zap.AddCaller(), zap.AddCallerSkip(wrapperCallerSkip))
+	l := &Logger{
+		zapLogger: zapLogger,
+		sugared:   zapLogger.Sugar(),
+		telemetry: tel,
+		stop:      make(chan struct{}),
+		providers: providers,
+		levels:    levels,
+		added:     added,
+		enriched:  enriched,
+	}
+	onFatal.logger = l
+	return l
+}
+
+// addedProviders holds the providers attached by AddProvider and those
+// loaded from a config file. It is shared by a logger, its children and
+// every extensionCore derived from it.
+type addedProviders struct {
+	tel    *telemetry
+	levels *levelTable
+
+	// cores is replaced, never modified, so writers read it without locking.
+	cores atomic.Pointer[[]zapcore.Core]
+
+	mu      sync.Mutex
+	entries []addedEntry
+	closed  bool
+}
+
+// addedEntry is the providers registered by one option. Entries with a key
+// can be removed again; AddProvider's have none.
+type addedEntry struct {
+	key      string
+	option   LoggerOption
+	cores    []zapcore.Core
+	infos    []ProviderInfo
+	counters []*providerCounters
+	closers  []provider
+}
+
+// build initialises the providers registered by opt without attaching them.
+func (a *addedProviders) build(key string, opt LoggerOption) (addedEntry, error) {
+	cfg := &loggerConfig{}
+	opt(cfg)
+	if len(cfg.routes) > 0 {
+		return addedEntry{}, errors.New("routes cannot be added to a running logger")
+	}
+	if len(cfg.providers) == 0 {
+		return addedEntry{}, errors.New("option registers no provider")
+	}
+
+	e := addedEntry{key: key, option: opt, closers: cfg.providers}
+	for _, p := range cfg.providers {
+		core, info, counters, err := newProviderCore(p, a.tel, a.levels)
+		if err != nil {
+			a.discard(e)
+			return addedEntry{}, err
+		}
+		e.cores = append(e.cores, core)
+		e.infos = append(e.infos, info)
+		e.counters = append(e.counters, counters)
+	}
+	return e, nil
+}
+
+// discard closes the providers of an entry that was built but not
+// attached, and drops their stats.
+func (a *addedProviders) discard(e addedEntry) {
+	_ = closeProviders(e.closers)
+	a.tel.stats.removeProviders(e.counters)
+}
+
+// replace atomically detaches the entries whose keys are in remove, dropping
+// their stats, and attaches add, returning the detached providers for the
+// caller to close.
+func (a *addedProviders) replace(remove map[string]bool, add []addedEntry) ([]provider, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil, errors.New("logger is closed")
+	}
+	var (
+		kept    []addedEntry
+		removed []provider
+	)
+	for _, e := range a.entries {
+		if e.key != "" && remove[e.key] {
+			removed = append(removed, e.closers...)
+			a.tel.stats.removeProviders(e.counters)
+			continue
+		}
+		kept = append(kept, e)
+	}
+	a.entries = append(kept, add...)
+
+	var next []zapcore.Core
+	for _, e := range a.entries {
+		next = append(next, e.cores...)
+	}
+	a.cores.Store(&next)
+	return removed, nil
+}
+
+func (a *addedProviders) optionList() []LoggerOption {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var options []LoggerOption
+	for _, e := range a.entries {
+		options = append(options, e.option)
+	}
+	return options
+}
+
+func (a *addedProviders) infoList() []ProviderInfo {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var infos []ProviderInfo
+	for _, e := range a.entries {
+		infos = append(infos, e.infos...)
+	}
+	return infos
+}
+
+// detach marks the set closed and hands its providers to the caller for
+// closing.
+func (a *addedProviders) detach() []provider {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	var closers []provider
+	for _, e := range a.entries {
+		closers = append(closers, e.closers...)
+	}
+	a.entries = nil
+	return closers
+}
+
+// extensionCore writes to the providers attached by AddProvider. Cores
+// derived with With keep their fields and apply them to providers attached
+// later, caching the result until the set changes.
+type extensionCore struct {
+	added  *addedProviders
+	fields []zapcore.Field
+	cache  atomic.Pointer[extensionCache]
+}
+
+type extensionCache struct {
+	base  *[]zapcore.Core
+	cores []zapcore.Core
+}
+
+func (c *extensionCore) current() []zapcore.Core {
+	base := c.added.cores.Load()
+	if base == nil {
+		return nil
+	}
+	if len(c.fields) == 0 {
+		return *base
+	}
+	if cached := c.cache.Load(); cached != nil && cached.base == base {
+		return cached.cores
+	}
+	cores := withAll(*base, c.fields)
+	c.cache.Store(&extensionCache{base: base, cores: cores})
+	return cores
+}
+
+func (c *extensionCore) Enabled(lvl zapcore.Level) bool {
+	return anyEnabled(c.current(), lvl)
+}
+
+func (c *extensionCore) enabledFor(ent zapcore.Entry) bool {
+	return anyEntryEnabled(c.current(), ent)
+}
+
+func (c *extensionCore) With(fields []zapcore.Field) zapcore.Core {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(append(all, c.fields...), fields...)
+	return &extensionCore{added: c.added, fields: all}
+}
+
+func (c *extensionCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabledFor(ent) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *extensionCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return writeEnabled(c.current(), ent, fields)
+}
+
+func (c *extensionCore) Sync() error {
+	return syncAll(c.current())
+}
@@ -0,0 +1,96 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAddProvider(t *testing.T) {
+	var host, plugin bytes.Buffer
+	logger, err := NewLogger(
+		WithWriterProvider(&host, JSONEncoder),
+		WithFilter(func(e Entry, _ []Field) bool { return e.Message != "secret" }),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	scoped := logger.zapLogger.With(toZapFields([]Field{String("component", "api")})...)
+	logger.Info("before")
+	if err := logger.Named("plugin").AddProvider(WithWriterProvider(&plugin, JSONEncoder)); err != nil {
+		t.Fatalf("AddProvider: %v", err)
+	}
+	logger.Info("after")
+	logger.Info("secret")
+	logger.Debug("below level")
+	scoped.Info("scoped")
+
+	out := plugin.String()
+	if strings.Contains(out, "before") || !strings.Contains(out, "after") {
+		t.Errorf("added provider should only see later entries: %s", out)
+	}
+	if strings.Contains(out, "secret") || strings.Contains(out, "below level") {
+		t.Errorf("added provider bypassed the filter or level: %s", out)
+	}
+	if !strings.Contains(out, `"component":"api"`) {
+		t.Errorf("fields bound before AddProvider were lost: %s", out)
+	}
+	if !strings.Contains(host.String(), "after") {
+		t.Errorf("host provider stopped receiving entries: %s", host.String())
+	}
+	if n := len(logger.providerInfos()); n != 2 {
+		t.Errorf("expected 2 providers, got %d", n)
+	}
+
+	if err := logger.AddProvider(WithRoute(MatchLevel(ErrorLevel), WithWriterProvider(&plugin, JSONEncoder))); err == nil {
+		t.Error("expected routes to be rejected")
+	}
+	if err := logger.AddProvider(WithLevel(DebugLevel)); err == nil {
+		t.Error("expected an option without providers to be rejected")
+	}
+	logger.Close()
+	if err := logger.AddProvider(WithWriterProvider(&plugin, JSONEncoder)); err == nil {
+		t.Error("expected AddProvider on a closed logger to fail")
+	}
+}
+
+func TestTee(t *testing.T) {
+	var a, b, c bytes.Buffer
+	la, err := NewLogger(WithLevel(InfoLevel), WithWriterProvider(&a, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer la.Close()
+	lb, err := NewLogger(WithLevel(WarnLevel), WithWriterProvider(&b, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer lb.Close()
+
+	tee := Tee(la, lb)
+	if err := tee.AddProvider(WithWriterProvider(&c, JSONEncoder)); err != nil {
+		t.Fatalf("AddProvider: %v", err)
+	}
+	tee.Info("info entry")
+	tee.Warn("warn entry")
+	tee.Debug("debug entry")
+	if err := tee.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if out := a.String(); !strings.Contains(out, "info entry") || !strings.Contains(out, "warn entry") {
+		t.Errorf("first logger missing entries: %s", out)
+	}
+	if out := b.String(); strings.Contains(out, "info entry") || !strings.Contains(out, "warn entry") {
+		t.Errorf("second logger should apply its own level: %s", out)
+	}
+	if out := c.String(); !strings.Contains(out, "info entry") || strings.Contains(out, "debug entry") {
+		t.Errorf("added provider should use the most verbose level: %s", out)
+	}
+	la.Info("still open")
+	if !strings.Contains(a.String(), "still open") {
+		t.Error("closing the tee closed its inputs")
+	}
+}
@@ -0,0 +1,111 @@
+package golog
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                         Timestamp Formats & Zones                           */
+/* -------------------------------------------------------------------------- */
+
+// Time formats accepted by WithTimeFormat and WithProviderTimeFormat besides
+// Go time layouts.
+const (
+	// TimeEpoch is seconds since the Unix epoch with a fraction, e.g.
+	// 1704164645.123456; the default of the JSON and console encoders.
+	TimeEpoch = "epoch"
+	// TimeEpochSeconds, TimeEpochMillis and TimeEpochNanos are whole
+	// seconds, milliseconds and nanoseconds since the Unix epoch.
+	TimeEpochSeconds = "epoch_s"
+	TimeEpochMillis  = "epoch_ms"
+	TimeEpochNanos   = "epoch_ns"
+	// TimeRFC3339 and TimeRFC3339Nano are RFC 3339 strings, with second and
+	// nanosecond precision.
+	TimeRFC3339     = "rfc3339"
+	TimeRFC3339Nano = "rfc3339nano"
+	// TimeISO8601 is ISO 8601 with millisecond precision, e.g.
+	// 2024-01-02T03:04:05.123Z.
+	TimeISO8601 = "iso8601"
+)
+
+// WithTimeFormat sets how the JSON and console encoders, and their presets,
+// write timestamps: one of the Time* formats above or any other string as a
+// Go time layout:
+//
+//	golog.WithTimeFormat(golog.TimeEpochMillis)    // 1704164645123
+//	golog.WithTimeFormat("2006-01-02 15:04:05.000") // 2024-01-02 03:04:05.123
+//
+// WithProviderTimeFormat overrides it for a single provider. The XML,
+// pretty, CSV, LTSV, MessagePack and Protobuf encoders have fixed time
+// formats. An empty format is an error.
+func WithTimeFormat(format string) LoggerOption {
+	return func(cfg *loggerConfig) {
+		if format == "" {
+			cfg.optionErrs = append(cfg.optionErrs, errors.New("WithTimeFormat: empty format"))
+			return
+		}
+		cfg.timeFormat = format
+	}
+}
+
+// WithProviderTimeFormat is WithTimeFormat for a single stdout, writer, file
+// or HTTP provider.
+func WithProviderTimeFormat(format string) ProviderOption {
+	return WithProviderEncoderConfig(timeFormatConfig(format))
+}
+
+// WithTimeZone records entry timestamps in loc instead of the local time
+// zone, for every encoder and provider. Epoch formats are unaffected.
+func WithTimeZone(loc *time.Location) LoggerOption {
+	return func(cfg *loggerConfig) {
+		if loc == nil {
+			cfg.optionErrs = append(cfg.optionErrs, errors.New("WithTimeZone: nil location"))
+			return
+		}
+		cfg.timeZone = loc
+	}
+}
+
+// WithUTC records entry timestamps in UTC.
+func WithUTC() LoggerOption {
+	return WithTimeZone(time.UTC)
+}
+
+// timeFormatConfig returns the encoder config function selecting format.
+func timeFormatConfig(format string) func(*zapcore.EncoderConfig) {
+	enc := timeEncoder(format)
+	return func(c *zapcore.EncoderConfig) { c.EncodeTime = enc }
+}
+
+// timeEncoder returns the zapcore.TimeEncoder for format.
+func timeEncoder(format string) zapcore.TimeEncoder {
+	switch format {
+	case TimeEpoch:
+		return zapcore.EpochTimeEncoder
+	case TimeEpochSeconds:
+		return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) { enc.AppendInt64(t.Unix()) }
+	case TimeEpochMillis:
+		return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) { enc.AppendInt64(t.UnixMilli()) }
+	case TimeEpochNanos:
+		return zapcore.EpochNanosTimeEncoder
+	case TimeRFC3339:
+		return zapcore.RFC3339TimeEncoder
+	case TimeRFC3339Nano:
+		return zapcore.RFC3339NanoTimeEncoder
+	case TimeISO8601:
+		return zapcore.ISO8601TimeEncoder
+	}
+	return zapcore.TimeEncoderOfLayout(format)
+}
+
+// zoneClock stamps entries with the current time in loc.
+type zoneClock struct {
+	loc *time.Location
+}
+
+func (c zoneClock) Now() time.Time { return time.Now().In(c.loc) }
+
+func (c zoneClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
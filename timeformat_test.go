@@ -0,0 +1,114 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fixedClock stamps every entry with t.
+type fixedClock struct{ t time.Time }
+
+func (c fixedClock) Now() time.Time                         { return c.t }
+func (c fixedClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+func TestTimeFormat(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	for format, want := range map[string]interface{}{
+		TimeEpochSeconds:      float64(1704164645),
+		TimeEpochMillis:       float64(1704164645123),
+		TimeRFC3339:           "2024-01-02T03:04:05Z",
+		TimeRFC3339Nano:       "2024-01-02T03:04:05.123456789Z",
+		TimeISO8601:           "2024-01-02T03:04:05.123Z",
+		"2006-01-02 15:04:05": "2024-01-02 03:04:05",
+	} {
+		var buf bytes.Buffer
+		logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithTimeFormat(format),
+			WithZapOptions(zap.WithClock(fixedClock{at})))
+		if err != nil {
+			t.Fatalf("failed to create logger: %v", err)
+		}
+		logger.Info("timed")
+		logger.Close()
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("%s: invalid JSON %q: %v", format, buf.String(), err)
+		}
+		if entry["ts"] != want {
+			t.Errorf("%s: ts = %#v, want %#v", format, entry["ts"], want)
+		}
+	}
+}
+
+func TestProviderTimeFormatOverridesLogger(t *testing.T) {
+	var logged, own bytes.Buffer
+	logger, err := NewLogger(
+		WithTimeFormat(TimeEpochNanos),
+		WithWriterProvider(&logged, JSONEncoder),
+		WithWriterProvider(&own, JSONEncoder, WithProviderTimeFormat("15h")),
+		WithUTC(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("hello")
+	if !strings.Contains(logged.String(), `"ts":1`) {
+		t.Errorf("logger time format not applied: %s", logged.String())
+	}
+	want := `"ts":"` + time.Now().UTC().Format("15h") + `"`
+	if !strings.Contains(own.String(), want) {
+		t.Errorf("provider time format not applied, want %s in %s", want, own.String())
+	}
+}
+
+func TestTimeZone(t *testing.T) {
+	loc := time.FixedZone("X", 5*3600)
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder), WithTimeZone(loc), WithTimeFormat(TimeRFC3339))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("hello")
+	if !strings.Contains(buf.String(), `+05:00"`) {
+		t.Errorf("time zone not applied: %s", buf.String())
+	}
+}
+
+func TestTimeOptionErrors(t *testing.T) {
+	if _, err := NewLogger(WithTimeFormat("")); err == nil {
+		t.Error("expected an error for an empty time format")
+	}
+	if _, err := NewLogger(WithTimeZone(nil)); err == nil {
+		t.Error("expected an error for a nil time zone")
+	}
+}
+
+func TestTimeZoneFromEnvAndConfig(t *testing.T) {
+	env := map[string]string{"GOLOG_TIME_ZONE": "Nowhere/Special"}
+	if _, err := envOptions(func(k string) string { return env[k] }); err == nil || !strings.Contains(err.Error(), "GOLOG_TIME_ZONE") {
+		t.Errorf("expected a GOLOG_TIME_ZONE error, got %v", err)
+	}
+	if _, err := parseFileConfig([]byte("time_zone: Nowhere/Special\n")); err == nil || !strings.Contains(err.Error(), "time_zone") {
+		t.Errorf("expected a time_zone error, got %v", err)
+	}
+	fc, err := parseFileConfig([]byte("time_format: epoch_ms\ntime_zone: UTC\n"))
+	if err != nil {
+		t.Fatalf("parseFileConfig: %v", err)
+	}
+	cfg := &loggerConfig{}
+	for _, opt := range fc.options() {
+		opt(cfg)
+	}
+	if cfg.timeFormat != TimeEpochMillis || cfg.timeZone != time.UTC {
+		t.Errorf("unexpected time settings: %q %v", cfg.timeFormat, cfg.timeZone)
+	}
+}
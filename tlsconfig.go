@@ -0,0 +1,84 @@
+package golog
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                     TLS/mTLS Configuration for Providers                    */
+/* -------------------------------------------------------------------------- */
+
+// TLSConfig is the TLS surface shared by every network provider (socket,
+// HTTP, syslog, …). A nil *TLSConfig means plain text; a zero TLSConfig
+// means TLS with the system roots.
+type TLSConfig struct {
+	// CAFile and CAPEM add trusted roots (PEM). When either is set the
+	// system pool is not used.
+	CAFile string
+	CAPEM  []byte
+	// CertFile and KeyFile enable mutual TLS. They are re-read on every
+	// handshake so rotated certificates are picked up without a restart.
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the SNI / verification host name.
+	ServerName string
+	// InsecureSkipVerify disables server certificate verification. Testing
+	// only.
+	InsecureSkipVerify bool
+	// MinVersion defaults to TLS 1.2.
+	MinVersion uint16
+}
+
+// build turns c into a *tls.Config. It returns nil for a nil c.
+func (c *TLSConfig) build() (*tls.Config, error) {
+	if c == nil {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		MinVersion:         c.MinVersion,
+	}
+	if cfg.MinVersion == 0 {
+		cfg.MinVersion = tls.VersionTLS12
+	}
+
+	if c.CAFile != "" || len(c.CAPEM) > 0 {
+		pool := x509.NewCertPool()
+		if c.CAFile != "" {
+			pem, err := os.ReadFile(c.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("tls: read CA bundle: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("tls: no certificates found in %s", c.CAFile)
+			}
+		}
+		if len(c.CAPEM) > 0 && !pool.AppendCertsFromPEM(c.CAPEM) {
+			return nil, errors.New("tls: no certificates found in CAPEM")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errors.New("tls: CertFile and KeyFile must be set together")
+	}
+	if c.CertFile != "" {
+		// Fail fast on a bad pair rather than at the first handshake.
+		if _, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile); err != nil {
+			return nil, fmt.Errorf("tls: load client certificate: %w", err)
+		}
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("tls: load client certificate: %w", err)
+			}
+			return &cert, nil
+		}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,145 @@
+package golog
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+/* -------------------------------------------------------------------------- */
+/*                          Transform Hook for Entries                         */
+/* -------------------------------------------------------------------------- */
+
+// TransformFunc rewrites an entry before it is encoded. Entry.Fields holds
+// every field attached to the entry (logger-scoped and call-site) and may be
+// modified in place or replaced. Changes to Time, Level, Logger, Message,
+// Stack and Fields are honoured; Caller is informational only.
+type TransformFunc func(*Entry)
+
+// WithTransform rewrites every entry before it reaches any provider, e.g. to
+// rename fields, truncate oversized values, add derived fields or normalise
+// messages. Transforms run in registration order, after WithFilter.
+func WithTransform(fn TransformFunc) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.transforms = append(cfg.transforms, fn)
+	}
+}
+
+// WithProviderTransform applies fns only to the providers registered by opt.
+//
+//	golog.WithProviderTransform(golog.WithStdOutProvider(golog.ConsoleEncoder), golog.TruncateValues(200))
+func WithProviderTransform(opt LoggerOption, fns ...TransformFunc) LoggerOption {
+	return wrapProviders(opt, func(p provider) provider {
+		return &transformProvider{inner: p, fns: fns}
+	})
+}
+
+// RenameField moves the value of field from to field to.
+func RenameField(from, to string) TransformFunc {
+	return func(e *Entry) {
+		if v, ok := e.Fields[from]; ok {
+			delete(e.Fields, from)
+			e.Fields[to] = v
+		}
+	}
+}
+
+// TruncateValues cuts string field values and the message to at most max
+// bytes, backing off so no character is split, and marks the cut with "…".
+// The marker is not counted towards max. A negative max is treated as 0.
+func TruncateValues(max int) TransformFunc {
+	if max < 0 {
+		max = 0
+	}
+	truncate := func(s string) string {
+		if len(s) <= max {
+			return s
+		}
+		return truncateUTF8(s, max) + "…"
+	}
+	return func(e *Entry) {
+		e.Message = truncate(e.Message)
+		for k, v := range e.Fields {
+			if s, ok := v.(string); ok {
+				e.Fields[k] = truncate(s)
+			}
+		}
+	}
+}
+
+type transformProvider struct {
+	inner provider
+	fns   []TransformFunc
+}
+
+func (p *transformProvider) newCore(level zapcore.Level) (zapcore.Core, error) {
+	core, err := p.inner.newCore(level)
+	if err != nil {
+		return nil, err
+	}
+	return &transformCore{cores: []zapcore.Core{core}, fns: p.fns}, nil
+}
+
+func (p *transformProvider) close() error { return p.inner.close() }
+
+func (p *transformProvider) instrument(t *telemetry) {
+	if ip, ok := p.inner.(instrumentedProvider); ok {
+		ip.instrument(t)
+	}
+}
+
+func (p *transformProvider) describe() ProviderInfo { return describeProvider(p.inner) }
+
+// transformCore decodes each entry, runs the transforms and re-encodes the
+// result for the wrapped cores. Context added via With is kept here rather
+// than pushed down, since transforms may rewrite it.
+type transformCore struct {
+	cores  []zapcore.Core
+	fns    []TransformFunc
+	fields []zapcore.Field
+}
+
+func (c *transformCore) Enabled(lvl zapcore.Level) bool {
+	return anyEnabled(c.cores, lvl)
+}
+
+func (c *transformCore) With(fields []zapcore.Field) zapcore.Core {
+	return &transformCore{
+		cores:  c.cores,
+		fns:    c.fns,
+		fields: append(append([]zapcore.Field(nil), c.fields...), fields...),
+	}
+}
+
+func (c *transformCore) enabledFor(ent zapcore.Entry) bool {
+	return anyEntryEnabled(c.cores, ent)
+}
+
+func (c *transformCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabledFor(ent) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *transformCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	e := decodeEntry(ent, c.fields, fields)
+	level := e.Level
+	if e.Fields == nil {
+		e.Fields = map[string]interface{}{}
+	}
+	for _, fn := range c.fns {
+		fn(&e)
+	}
+	ent.Time = e.Time
+	if e.Level != level {
+		// Only remap when changed: Level cannot express DPanic/Panic.
+		ent.Level = toZapLevel(e.Level)
+	}
+	ent.LoggerName = e.Logger
+	ent.Message = e.Message
+	ent.Stack = e.Stack
+	return writeEnabled(c.cores, ent, mapToZapFields(e.Fields))
+}
+
+func (c *transformCore) Sync() error {
+	return syncAll(c.cores)
+}
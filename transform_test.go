@@ -0,0 +1,50 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	var all, short bytes.Buffer
+	logger, err := NewLogger(
+		WithWriterProvider(&all, JSONEncoder),
+		WithProviderTransform(WithWriterProvider(&short, JSONEncoder), TruncateValues(4)),
+		WithTransform(RenameField("msg_id", "message_id")),
+		WithTransform(func(e *Entry) {
+			e.Message = strings.ToLower(e.Message)
+			e.Fields["derived"] = len(e.Message)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("Payment ACCEPTED", String("msg_id", "abcdefgh"))
+
+	if out := all.String(); !strings.Contains(out, `"msg":"payment accepted"`) ||
+		!strings.Contains(out, `"message_id":"abcdefgh"`) ||
+		!strings.Contains(out, `"derived":16`) ||
+		strings.Contains(out, "msg_id") {
+		t.Errorf("unexpected output: %s", out)
+	}
+	if out := short.String(); !strings.Contains(out, `"msg":"paym…"`) || !strings.Contains(out, `"message_id":"abcd…"`) {
+		t.Errorf("unexpected truncated output: %s", out)
+	}
+}
+
+func TestTruncateValuesUTF8(t *testing.T) {
+	e := &Entry{Message: "héllo", Fields: map[string]any{"k": "ab"}}
+	TruncateValues(2)(e)
+	if e.Message != "h…" || e.Fields["k"] != "ab" {
+		t.Errorf("unexpected truncation: %q %q", e.Message, e.Fields["k"])
+	}
+
+	e = &Entry{Message: "hello", Fields: map[string]any{}}
+	TruncateValues(-1)(e)
+	if e.Message != "…" {
+		t.Errorf("negative max: got %q", e.Message)
+	}
+}
@@ -0,0 +1,50 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestXMLEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, XMLEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Named("api").With(String("svc", "a<b")).Info("served & done",
+		Dict("http", String("method", "GET"), Int("status", 200)),
+		Strings("tags", []string{"x", "y"}),
+		Any("meta", map[string]int{"b": 2, "a": 1}),
+		Namespace("req"), Bool("ok", true))
+
+	out := buf.String()
+	for _, want := range []string{
+		`<level>info</level><logger>api</logger><caller>module/xmlencoder_test.go:`,
+		`<message>served &amp; done</message><fields><field name="svc">a&lt;b</field>`,
+		`<field name="http"><field name="method">GET</field><field name="status">200</field></field>`,
+		`<field name="tags"><item>x</item><item>y</item></field>`,
+		`<field name="meta"><field name="a">1</field><field name="b">2</field></field>`,
+		`<field name="req"><field name="ok">true</field></field></fields></event>` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in %s", want, out)
+		}
+	}
+
+	var event struct {
+		Message string `xml:"message"`
+		Fields  []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"fields>field"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if event.Message != "served & done" || len(event.Fields) != 5 {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
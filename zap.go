@@ -0,0 +1,25 @@
+package golog
+
+import "go.uber.org/zap"
+
+/* -------------------------------------------------------------------------- */
+/*                           Underlying zap Logger                             */
+/* -------------------------------------------------------------------------- */
+
+// Zap returns the *zap.Logger behind l, for code that needs zap's own API.
+// Entries logged through it pass the same level gates, filters and
+// providers, and report the caller of the zap method.
+func (l *Logger) Zap() *zap.Logger {
+	return l.zapLogger.WithOptions(zap.AddCallerSkip(-wrapperCallerSkip))
+}
+
+// WithZapOptions applies zap options to the underlying logger after golog's
+// own, e.g. zap.Hooks, zap.WrapCore around the assembled core, or
+// zap.IncreaseLevel. Options that replace the caller, fatal or error output
+// settings override golog's: zap.WithFatalHook skips the pre-exit hooks and
+// zap.ErrorOutput bypasses the error handler.
+func WithZapOptions(options ...zap.Option) LoggerOption {
+	return func(cfg *loggerConfig) {
+		cfg.zapOptions = append(cfg.zapOptions, options...)
+	}
+}
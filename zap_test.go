@@ -0,0 +1,55 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestZap(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(WithWriterProvider(&buf, JSONEncoder))
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Zap().Info("from zap", zap.String("k", "v"))
+	logger.Zap().Debug("hidden")
+	out := buf.String()
+	if !strings.Contains(out, `"msg":"from zap"`) || !strings.Contains(out, "zap_test.go:") {
+		t.Errorf("unexpected zap output: %s", out)
+	}
+	if strings.Contains(out, "hidden") {
+		t.Errorf("zap logger bypassed the level: %s", out)
+	}
+}
+
+func TestWithZapOptions(t *testing.T) {
+	var hooked int
+	observed, logs := observer.New(zapcore.WarnLevel)
+	logger, err := NewLogger(
+		WithWriterProvider(&bytes.Buffer{}, JSONEncoder),
+		WithZapOptions(
+			zap.Hooks(func(zapcore.Entry) error { hooked++; return nil }),
+			zap.WrapCore(func(c zapcore.Core) zapcore.Core { return zapcore.NewTee(c, observed) }),
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("info")
+	logger.Warn("warn")
+	if hooked != 2 {
+		t.Errorf("hook ran %d times, want 2", hooked)
+	}
+	if logs.Len() != 1 || logs.All()[0].Message != "warn" {
+		t.Errorf("wrapped core saw %v", logs.All())
+	}
+}